@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log/slog"
+	"net/netip"
 	"os"
 	"strconv"
 	"strings"
@@ -86,6 +87,26 @@ func newOriginParser(allowList *[]string, defaultVal string) func(s string) erro
 	}
 }
 
+// newNetworkParser parses a comma-separated list of CIDRs, replacing the networks parsed before
+func newNetworkParser(networks *[]netip.Prefix) func(s string) error {
+	return func(s string) error {
+		var parsed []netip.Prefix
+		for _, str := range strings.Split(s, ",") {
+			str = strings.TrimSpace(str)
+			if str == "" {
+				continue
+			}
+			network, err := netip.ParsePrefix(str)
+			if err != nil {
+				return fmt.Errorf("invalid network %q: %w", str, err)
+			}
+			parsed = append(parsed, network.Masked())
+		}
+		*networks = parsed
+		return nil
+	}
+}
+
 func newBundlePathParser(bundlePaths *[]string, defaultVal string) func(s string) error {
 	return func(s string) error {
 		value := defaultVal
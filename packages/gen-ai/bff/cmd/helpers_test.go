@@ -1,9 +1,11 @@
 package main
 
 import (
+	"net/netip"
+	"testing"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"testing"
 )
 
 var _ = Describe("newOriginParser helper function", func() {
@@ -60,6 +62,45 @@ var _ = Describe("newOriginParser helper function", func() {
 
 })
 
+var _ = Describe("newNetworkParser helper function", func() {
+	var networkParser func(s string) error
+	var networks []netip.Prefix
+
+	BeforeEach(func() {
+		networks = nil
+		networkParser = newNetworkParser(&networks)
+	})
+
+	It("should parse a list of networks with extra spaces", func() {
+		expected := []netip.Prefix{netip.MustParsePrefix("10.20.0.0/16"), netip.MustParsePrefix("fd00::/8")}
+
+		err := networkParser("10.20.0.0/16,   fd00::/8")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(networks).To(Equal(expected))
+	})
+
+	It("should mask the host bits of a network", func() {
+		err := networkParser("10.20.30.40/16")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(networks).To(Equal([]netip.Prefix{netip.MustParsePrefix("10.20.0.0/16")}))
+	})
+
+	It("should parse an empty string", func() {
+		err := networkParser("")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(networks).To(BeEmpty())
+	})
+
+	It("should fail on an address without a prefix length", func() {
+		err := networkParser("10.20.30.40")
+
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 func TestMainHelpers(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Main helpers suite")
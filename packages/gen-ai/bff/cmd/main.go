@@ -44,6 +44,21 @@ func main() {
 
 	// Llama Stack configuration
 	flag.StringVar(&cfg.LlamaStackURL, "llama-stack-url", getEnvAsString("LLAMA_STACK_URL", ""), "Llama Stack server URL for proxying requests")
+	flag.IntVar(&cfg.IngestionWorkers, "ingestion-workers", getEnvAsInt("INGESTION_WORKERS", constants.IngestionDefaultWorkers), "Number of documents ingested into vector stores at the same time")
+	allowedNetworksParser := newNetworkParser(&cfg.DocumentSourceAllowedNetworks)
+	if err := allowedNetworksParser(getEnvAsString("DOCUMENT_SOURCE_ALLOWED_NETWORKS", "")); err != nil {
+		fmt.Println("invalid DOCUMENT_SOURCE_ALLOWED_NETWORKS", "error", err)
+		os.Exit(1)
+	}
+	flag.Func("document-source-allowed-networks", "Private networks documents may be fetched from by URL (comma-separated CIDRs)", allowedNetworksParser)
+	dataConnectionNetworksParser := newNetworkParser(&cfg.DataConnectionAllowedNetworks)
+	if err := dataConnectionNetworksParser(getEnvAsString("DATA_CONNECTION_ALLOWED_NETWORKS", "")); err != nil {
+		fmt.Println("invalid DATA_CONNECTION_ALLOWED_NETWORKS", "error", err)
+		os.Exit(1)
+	}
+	flag.Func("data-connection-allowed-networks", "Private networks the endpoints of S3 data connections may be in (comma-separated CIDRs)", dataConnectionNetworksParser)
+	flag.IntVar(&cfg.VectorStoreQuotaStores, "vector-store-quota-stores", getEnvAsInt("VECTOR_STORE_QUOTA_STORES", 0), "Number of vector stores a namespace can have, unlimited when 0")
+	flag.IntVar(&cfg.VectorStoreQuotaFiles, "vector-store-quota-files", getEnvAsInt("VECTOR_STORE_QUOTA_FILES", 0), "Number of files the vector stores of a namespace can hold together, unlimited when 0")
 
 	// MaaS configuration
 	flag.StringVar(&cfg.MaaSURL, "maas-url", getEnvAsString("MAAS_URL", ""), "MaaS server URL for proxying requests")
@@ -55,6 +70,11 @@ func main() {
 	flag.Func("bundle-paths", "CA bundle file paths (comma-separated list)", newBundlePathParser(&cfg.BundlePaths, getEnvAsString("BUNDLE_PATHS", "")))
 	flag.BoolVar(&cfg.InsecureSkipVerify, "insecure-skip-verify", getEnvAsBool("INSECURE_SKIP_VERIFY", false), "Skip TLS certificate verification")
 
+	// MCP configuration
+	flag.StringVar(&cfg.MCPOAuthRedirectURL, "mcp-oauth-redirect-url", getEnvAsString("MCP_OAUTH_REDIRECT_URL", ""), "Public URL of the MCP OAuth callback endpoint (OAuth login to MCP servers is disabled when empty)")
+	flag.StringVar(&cfg.MCPOAuthUIURL, "mcp-oauth-ui-url", getEnvAsString("MCP_OAUTH_UI_URL", ""), "UI page the MCP OAuth callback redirects to, followed by the namespace (the playground when empty)")
+	flag.StringVar(&cfg.MCPBridgeBaseURL, "mcp-bridge-base-url", getEnvAsString("MCP_BRIDGE_BASE_URL", ""), "In-cluster URL at which LlamaStack reaches the BFF, such as its service URL (stdio and WebSocket MCP servers are disabled when empty)")
+
 	// Initialize klog flags before parsing
 	klog.InitFlags(nil)
 
@@ -92,7 +112,7 @@ func main() {
 		Handler:      app.Routes(),
 		IdleTimeout:  time.Minute,
 		ReadTimeout:  8 * time.Minute, // Allow larger file uploads (up to 10MB)
-		WriteTimeout: 8 * time.Minute, // Allow longer processing time for large PDFs
+		WriteTimeout: 8 * time.Minute, // Allow long-running responses and ingestion progress streams
 		ErrorLog:     slog.NewLogLogger(logger.Handler(), slog.LevelError),
 	}
 
@@ -132,6 +152,10 @@ func main() {
 		logger.Error("server shutdown failed", "error", err)
 	}
 
+	if err := app.Shutdown(); err != nil {
+		logger.Error("app shutdown failed", "error", err)
+	}
+
 	logger.Info("server stopped")
 	os.Exit(0)
 
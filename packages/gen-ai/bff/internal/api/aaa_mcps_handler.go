@@ -149,6 +149,11 @@ func (app *App) determineServerStatusFromConfig(config models.MCPServerConfig) s
 		return "error"
 	}
 
+	// Stdio servers are started from their command
+	if config.Transport == constants.TransportTypeStdio && config.Command == "" {
+		return "error"
+	}
+
 	// ConfigMap data is valid, assume server is healthy
 	return "healthy"
 }
@@ -4,11 +4,13 @@ import (
 	"context"
 	"crypto/x509"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
 	"path"
 	"strings"
+	"sync"
 
 	k8s "github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes"
 	"github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes/k8smocks"
@@ -20,6 +22,7 @@ import (
 
 	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
 	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp/mcpmocks"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/sources"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
@@ -43,6 +46,9 @@ type App struct {
 	dashboardNamespace      string
 	memoryStore             cache.MemoryStore
 	rootCAs                 *x509.CertPool
+
+	// vectorStoreMigrations holds the state of the migration of the auto-provisioned vector stores per namespace
+	vectorStoreMigrations *sync.Map
 }
 
 func NewApp(cfg config.EnvConfig, logger *slog.Logger) (*App, error) {
@@ -142,6 +148,10 @@ func NewApp(cfg config.EnvConfig, logger *slog.Logger) (*App, error) {
 		return nil, fmt.Errorf("failed to create Kubernetes client factory: %w", err)
 	}
 
+	// Initialize shared memory store for caching (10 minute cleanup interval)
+	memStore := cache.NewMemoryStore()
+	logger.Info("Initialized shared memory store")
+
 	// Initialize MCP client factory
 	var mcpFactory mcp.MCPClientFactory
 	if cfg.MockMCPClient {
@@ -149,20 +159,30 @@ func NewApp(cfg config.EnvConfig, logger *slog.Logger) (*App, error) {
 		mcpFactory = mcpmocks.NewMockedMCPClientFactory(cfg, logger)
 	} else {
 		var err error
-		mcpFactory, err = mcp.NewMCPClientFactory(cfg, logger, cfg.InsecureSkipVerify, rootCAs)
+		mcpFactory, err = mcp.NewMCPClientFactory(cfg, logger, cfg.InsecureSkipVerify, rootCAs, memStore)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create MCP client factory: %w", err)
 		}
 	}
 
-	// Initialize shared memory store for caching (10 minute cleanup interval)
-	memStore := cache.NewMemoryStore()
-	logger.Info("Initialized shared memory store")
+	repos := repositories.NewRepositoriesWithMCP(mcpFactory, logger)
+	repos.IngestionJobs = repositories.NewIngestionJobsRepository(
+		llamastack.NewIngestionManager(llamastack.DefaultIngestionManagerConfig(cfg.IngestionWorkers), logger),
+	)
+	// Documents ingested from S3-compatible storage or URLs are fetched trusting the same CAs as other services.
+	// Both may only reach private networks that are explicitly allowed, since project editors can create data
+	// connections as well. In-cluster storage is allowed separately, as it is not meant to be fetched from by URL.
+	repos.DocumentSources = repositories.NewDocumentSourcesRepository(
+		sources.NewHTTPClient(cfg.InsecureSkipVerify, rootCAs, sources.AddressPolicy{AllowedNetworks: cfg.DocumentSourceAllowedNetworks}),
+		sources.NewHTTPClient(cfg.InsecureSkipVerify, rootCAs, sources.AddressPolicy{AllowedNetworks: cfg.DataConnectionAllowedNetworks}),
+	)
+	// The usage of responses is kept in the shared memory store
+	repos.Usage = repositories.NewUsageRepository(repositories.NewMemoryUsageStore(memStore))
 
 	app := &App{
 		config:                  cfg,
 		logger:                  logger,
-		repositories:            repositories.NewRepositoriesWithMCP(mcpFactory, logger),
+		repositories:            repos,
 		openAPI:                 openAPIHandler,
 		kubernetesClientFactory: k8sFactory,
 		llamaStackClientFactory: llamaStackClientFactory,
@@ -171,13 +191,25 @@ func NewApp(cfg config.EnvConfig, logger *slog.Logger) (*App, error) {
 		dashboardNamespace:      dashboardNamespace,
 		memoryStore:             memStore,
 		rootCAs:                 rootCAs,
+		vectorStoreMigrations:   &sync.Map{},
 	}
 	return app, nil
 }
 
 func (app *App) Shutdown() error {
 	app.logger.Info("shutting down app...")
-	// Add any cleanup logic here if needed
+
+	// Stop the MCP health prober and close pooled MCP sessions
+	if closer, ok := app.mcpClientFactory.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return fmt.Errorf("failed to close MCP client factory: %w", err)
+		}
+	}
+
+	// Stop the document ingestion workers, interrupting running jobs
+	if err := app.repositories.IngestionJobs.Close(); err != nil {
+		return fmt.Errorf("failed to stop ingestion workers: %w", err)
+	}
 	return nil
 }
 
@@ -200,9 +232,15 @@ func (app *App) Routes() http.Handler {
 	// Router for /api/v1/*
 	apiRouter := httprouter.New()
 
-	apiRouter.NotFound = http.HandlerFunc(app.notFoundResponse)
 	apiRouter.MethodNotAllowed = http.HandlerFunc(app.methodNotAllowedResponse)
 
+	// httprouter cannot register a wildcard next to the static routes of the same path segment, so vector store
+	// resources are served by a router of their own for the paths apiRouter does not match
+	vectorStoreRouter := httprouter.New()
+	vectorStoreRouter.NotFound = http.HandlerFunc(app.notFoundResponse)
+	vectorStoreRouter.MethodNotAllowed = http.HandlerFunc(app.methodNotAllowedResponse)
+	apiRouter.NotFound = vectorStoreRouter
+
 	// LlamaStack API routes
 
 	// Models (LlamaStack)
@@ -210,11 +248,17 @@ func (app *App) Routes() http.Handler {
 
 	// Responses (LlamaStack)
 	apiRouter.POST(constants.ResponsesPath, app.AttachNamespace(app.RequireAccessToService(app.AttachMaaSClient(app.AttachLlamaStackClient(app.LlamaStackCreateResponseHandler)))))
+	apiRouter.POST(constants.ResponsesApprovalPath, app.AttachNamespace(app.RequireAccessToService(app.AttachMaaSClient(app.AttachLlamaStackClient(app.LlamaStackResponseApprovalHandler)))))
+	apiRouter.POST(constants.ResponsesComparePath, app.AttachNamespace(app.RequireAccessToService(app.AttachMaaSClient(app.AttachLlamaStackClient(app.LlamaStackCompareResponsesHandler)))))
 
 	// Vector Stores (LlamaStack)
-	apiRouter.GET(constants.VectorStoresListPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.LlamaStackListVectorStoresHandler))))
-	apiRouter.POST(constants.VectorStoresListPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.LlamaStackCreateVectorStoreHandler))))
-	apiRouter.DELETE(constants.VectorStoresDeletePath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.LlamaStackDeleteVectorStoreHandler))))
+	apiRouter.GET(constants.VectorStoresListPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.MigrateVectorStores(app.LlamaStackListVectorStoresHandler)))))
+	apiRouter.POST(constants.VectorStoresListPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.MigrateVectorStores(app.LlamaStackCreateVectorStoreHandler)))))
+	apiRouter.DELETE(constants.VectorStoresDeletePath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.MigrateVectorStores(app.LlamaStackDeleteVectorStoreHandler)))))
+	vectorStoreRouter.POST(constants.VectorStoreSearchPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.MigrateVectorStores(app.LlamaStackSearchVectorStoreHandler)))))
+	vectorStoreRouter.GET(constants.VectorStorePath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.MigrateVectorStores(app.LlamaStackGetVectorStoreHandler)))))
+	vectorStoreRouter.PATCH(constants.VectorStorePath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.MigrateVectorStores(app.LlamaStackUpdateVectorStoreHandler)))))
+	vectorStoreRouter.DELETE(constants.VectorStorePath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.MigrateVectorStores(app.LlamaStackDeleteVectorStoreByIDHandler)))))
 
 	// Files (LlamaStack)
 	apiRouter.GET(constants.FilesListPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.LlamaStackListFilesHandler))))
@@ -222,9 +266,28 @@ func (app *App) Routes() http.Handler {
 	apiRouter.DELETE(constants.FilesDeletePath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.LlamaStackDeleteFileHandler))))
 
 	// Vector Store Files (LlamaStack)
-	apiRouter.GET(constants.VectorStoreFilesListPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.LlamaStackListVectorStoreFilesHandler))))
-	apiRouter.POST(constants.VectorStoreFilesUploadPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.LlamaStackUploadFileHandler)))) // Alias to FilesUploadPath
-	apiRouter.DELETE(constants.VectorStoreFilesDeletePath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.LlamaStackDeleteVectorStoreFileHandler))))
+	apiRouter.GET(constants.VectorStoreFilesListPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.MigrateVectorStores(app.LlamaStackListVectorStoreFilesHandler)))))
+	apiRouter.POST(constants.VectorStoreFilesUploadPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.MigrateVectorStores(app.LlamaStackUploadFileHandler))))) // Alias to FilesUploadPath
+	apiRouter.DELETE(constants.VectorStoreFilesDeletePath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.MigrateVectorStores(app.LlamaStackDeleteVectorStoreFileHandler)))))
+	apiRouter.GET(constants.VectorStoreFileContentPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.MigrateVectorStores(app.LlamaStackGetVectorStoreFileContentHandler)))))
+	apiRouter.GET(constants.VectorStoreFileChunksPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.MigrateVectorStores(app.LlamaStackListVectorStoreFileChunksHandler)))))
+
+	// Document ingestion jobs (LlamaStack)
+	apiRouter.GET(constants.IngestionJobPath, app.AttachNamespace(app.RequireAccessToService(app.IngestionJobGetHandler)))
+	apiRouter.POST(constants.IngestionJobCancelPath, app.AttachNamespace(app.RequireAccessToService(app.IngestionJobCancelHandler)))
+	apiRouter.POST(constants.IngestionJobRetryPath, app.AttachNamespace(app.RequireAccessToService(app.AttachLlamaStackClient(app.IngestionJobRetryHandler))))
+
+	// Conversation history
+	apiRouter.GET(constants.ConversationsPath, app.AttachNamespace(app.RequireAccessToService(app.ConversationsListHandler)))
+	apiRouter.POST(constants.ConversationsPath, app.AttachNamespace(app.RequireAccessToService(app.ConversationsCreateHandler)))
+	apiRouter.GET(constants.ConversationPath, app.AttachNamespace(app.RequireAccessToService(app.ConversationGetHandler)))
+	apiRouter.PATCH(constants.ConversationPath, app.AttachNamespace(app.RequireAccessToService(app.ConversationUpdateHandler)))
+	apiRouter.DELETE(constants.ConversationPath, app.AttachNamespace(app.RequireAccessToService(app.ConversationDeleteHandler)))
+	apiRouter.GET(constants.ConversationMessagesPath, app.AttachNamespace(app.RequireAccessToService(app.ConversationMessagesListHandler)))
+	apiRouter.POST(constants.ConversationMessagesPath, app.AttachNamespace(app.RequireAccessToService(app.ConversationMessagesCreateHandler)))
+
+	// Usage of responses
+	apiRouter.GET(constants.UsagePath, app.AttachNamespace(app.RequireAccessToService(app.UsageHandler)))
 
 	// Code Exporter (Template-only)
 	apiRouter.POST(constants.CodeExporterPath, app.AttachNamespace(app.RequireAccessToService(app.CodeExporterHandler)))
@@ -251,7 +314,17 @@ func (app *App) Routes() http.Handler {
 
 	// MCP Client endpoints
 	apiRouter.GET(constants.MCPToolsPath, app.AttachNamespace(app.RequireAccessToService(app.MCPToolsHandler)))
+	apiRouter.POST(constants.MCPToolCallPath, app.AttachNamespace(app.RequireAccessToService(app.MCPToolCallHandler)))
 	apiRouter.GET(constants.MCPStatusPath, app.AttachNamespace(app.RequireAccessToService(app.MCPStatusHandler)))
+	apiRouter.GET(constants.MCPResourcesPath, app.AttachNamespace(app.RequireAccessToService(app.MCPResourcesHandler)))
+	apiRouter.GET(constants.MCPResourceReadPath, app.AttachNamespace(app.RequireAccessToService(app.MCPResourceReadHandler)))
+	apiRouter.GET(constants.MCPPromptsPath, app.AttachNamespace(app.RequireAccessToService(app.MCPPromptsHandler)))
+	apiRouter.POST(constants.MCPPromptGetPath, app.AttachNamespace(app.RequireAccessToService(app.MCPPromptGetHandler)))
+	apiRouter.GET(constants.MCPCredentialsPath, app.AttachNamespace(app.RequireAccessToService(app.MCPCredentialsListHandler)))
+	apiRouter.PUT(constants.MCPCredentialPath, app.AttachNamespace(app.RequireAccessToService(app.MCPCredentialSetHandler)))
+	apiRouter.DELETE(constants.MCPCredentialPath, app.AttachNamespace(app.RequireAccessToService(app.MCPCredentialDeleteHandler)))
+	apiRouter.POST(constants.MCPCredentialTestPath, app.AttachNamespace(app.RequireAccessToService(app.MCPCredentialTestHandler)))
+	apiRouter.GET(constants.MCPOAuthCallbackPath, app.AttachNamespace(app.RequireAccessToService(app.MCPOAuthCallbackHandler)))
 	apiRouter.GET(constants.MCPServersListPath, app.AttachNamespace(app.RequireAccessToService(app.MCPListHandler)))
 
 	// MaaS API routes
@@ -317,6 +390,12 @@ func (app *App) Routes() http.Handler {
 	combinedMux.HandleFunc(constants.OpenAPIYAMLPath, app.openAPI.HandleOpenAPIYAMLWrapper)
 	combinedMux.HandleFunc(constants.SwaggerUIPath, app.openAPI.HandleSwaggerUIWrapper)
 
+	// Bridged MCP servers, called by LlamaStack with a per-server bridge token instead of user credentials
+	if bridge := app.mcpBridge(); bridge != nil {
+		combinedMux.Handle(constants.MCPBridgePath, app.RecoverPanic(app.EnableTelemetry(bridge)))
+		combinedMux.Handle(constants.PathPrefix+constants.MCPBridgePath, app.RecoverPanic(app.EnableTelemetry(http.StripPrefix(constants.PathPrefix, bridge))))
+	}
+
 	combinedMux.Handle("/", app.RecoverPanic(app.EnableTelemetry(app.EnableCORS(app.InjectRequestIdentity(appMux)))))
 
 	return combinedMux
@@ -7,6 +7,7 @@ import (
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
 	"github.com/opendatahub-io/gen-ai/internal/models"
 	"github.com/opendatahub-io/gen-ai/internal/repositories"
 )
@@ -89,5 +90,45 @@ func (app *App) validateCodeExportRequest(config models.CodeExportRequest) error
 		}
 	}
 
+	// Validate the generation settings like the responses endpoint, with the tools of the exported code
+	params := llamastack.CreateResponseParams{
+		Temperature:       config.Temperature,
+		TopP:              config.TopP,
+		MaxOutputTokens:   config.MaxOutputTokens,
+		Stop:              config.Stop,
+		Seed:              config.Seed,
+		FrequencyPenalty:  config.FrequencyPenalty,
+		PresencePenalty:   config.PresencePenalty,
+		ParallelToolCalls: config.ParallelToolCalls,
+		ToolChoice:        toolChoiceParam(config.ToolChoice),
+		Truncation:        config.Truncation,
+	}
+	for _, tool := range config.Tools {
+		params.VectorStoreIDs = append(params.VectorStoreIDs, tool.VectorStoreIDs...)
+	}
+	if config.VectorStore != nil {
+		params.VectorStoreIDs = append(params.VectorStoreIDs, config.VectorStore.Name)
+	}
+	for _, server := range config.MCPServers {
+		params.Tools = append(params.Tools, llamastack.MCPServerParam{ServerLabel: server.ServerLabel})
+	}
+	if err := params.ValidateGeneration(); err != nil {
+		return err
+	}
+
+	// Validate the response format like the responses endpoint
+	if format := config.ResponseFormat; format != nil {
+		responseFormat := llamastack.ResponseFormat{
+			Type:        format.Type,
+			Name:        format.Name,
+			Description: format.Description,
+			Schema:      format.Schema,
+			Strict:      format.Strict,
+		}
+		if err := responseFormat.Validate(); err != nil {
+			return fmt.Errorf("invalid response_format: %w", err)
+		}
+	}
+
 	return nil
 }
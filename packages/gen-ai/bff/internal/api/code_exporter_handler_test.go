@@ -208,6 +208,49 @@ func TestCodeExporterHandler(t *testing.T) {
 		}
 		assert.NotEqual(t, http.StatusBadRequest, rr.Code)
 	})
+
+	t.Run("should return error when tool choice names an MCP server that is not configured", func(t *testing.T) {
+		configRequest := models.CodeExportRequest{
+			Input:      "Hello, world!",
+			Model:      "llama3.2:3b",
+			ToolChoice: &models.ToolChoice{Type: "mcp", ServerLabel: "github"},
+		}
+
+		rr := httptest.NewRecorder()
+		reqBody, err := json.Marshal(configRequest)
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, "/gen-ai/api/v1/code-exporter", bytes.NewReader(reqBody))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		app.CodeExporterHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should return error when response format has no schema name", func(t *testing.T) {
+		configRequest := models.CodeExportRequest{
+			Input: "Hello, world!",
+			Model: "llama3.2:3b",
+			ResponseFormat: &models.CodeExportResponseFormat{
+				Type:   "json_schema",
+				Schema: map[string]interface{}{"type": "object"},
+			},
+		}
+
+		rr := httptest.NewRecorder()
+		reqBody, err := json.Marshal(configRequest)
+		assert.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, "/gen-ai/api/v1/code-exporter", bytes.NewReader(reqBody))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		app.CodeExporterHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
 }
 
 func TestGeneratePythonCode(t *testing.T) {
@@ -501,4 +544,81 @@ func TestGeneratePythonCode(t *testing.T) {
 		assert.Contains(t, code, "https://localhost:3000/sse")
 		assert.NotContains(t, code, "headers:")
 	})
+
+	t.Run("should generate Python code with a JSON schema response format", func(t *testing.T) {
+		config := models.CodeExportRequest{
+			Input: "Extract the invoice total",
+			Model: "llama3.2:3b",
+			ResponseFormat: &models.CodeExportResponseFormat{
+				Type:   "json_schema",
+				Name:   "invoice",
+				Schema: map[string]interface{}{"type": "object", "required": []string{"total"}},
+				Strict: true,
+			},
+		}
+
+		code, err := app.generatePythonCode(config, app.repositories.Template)
+
+		if err != nil {
+			t.Skipf("Template system not available in test environment: %v", err)
+		}
+
+		assert.NoError(t, err)
+		assert.Contains(t, code, "import json\nimport os")
+		assert.Contains(t, code, `response_format = json.loads(r"""{`)
+		assert.Contains(t, code, `"name": "invoice"`)
+		assert.Contains(t, code, `"strict": true`)
+		assert.Contains(t, code, `"text": {"format": response_format}`)
+	})
+
+	t.Run("should generate Python code with generation settings", func(t *testing.T) {
+		topP := 0.9
+		maxOutputTokens := int64(256)
+		seed := int64(42)
+		presencePenalty := 1.0
+		parallelToolCalls := false
+		config := models.CodeExportRequest{
+			Input:             "Check my messages",
+			Model:             "llama3.2:3b",
+			TopP:              &topP,
+			MaxOutputTokens:   &maxOutputTokens,
+			Stop:              []string{"END"},
+			Seed:              &seed,
+			PresencePenalty:   &presencePenalty,
+			ParallelToolCalls: &parallelToolCalls,
+			ToolChoice:        &models.ToolChoice{Mode: "required"},
+			Truncation:        "auto",
+		}
+
+		code, err := app.generatePythonCode(config, app.repositories.Template)
+
+		if err != nil {
+			t.Skipf("Template system not available in test environment: %v", err)
+		}
+
+		assert.NoError(t, err)
+		assert.Contains(t, code, "top_p = 0.9\nmax_output_tokens = 256\nparallel_tool_calls = False\ntool_choice = \"required\"\ntruncation = \"auto\"")
+		assert.Contains(t, code, "stop = [\"END\"]\nseed = 42\npresence_penalty = 1.0")
+		assert.Contains(t, code, `"parallel_tool_calls": parallel_tool_calls,`)
+		assert.Contains(t, code, `"truncation": truncation,`)
+		assert.Contains(t, code, "\"extra_body\": {\n        \"stop\": stop,\n        \"seed\": seed,\n        \"presence_penalty\": presence_penalty,\n    }\n}")
+	})
+
+	t.Run("should generate Python code without a response format", func(t *testing.T) {
+		config := models.CodeExportRequest{
+			Input: "Hello",
+			Model: "llama3.2:3b",
+		}
+
+		code, err := app.generatePythonCode(config, app.repositories.Template)
+
+		if err != nil {
+			t.Skipf("Template system not available in test environment: %v", err)
+		}
+
+		assert.NoError(t, err)
+		assert.NotContains(t, code, "import json")
+		assert.NotContains(t, code, "response_format")
+		assert.Contains(t, code, "\n\nimport os")
+	})
 }
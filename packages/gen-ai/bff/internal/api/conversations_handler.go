@@ -0,0 +1,372 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+)
+
+type ConversationsEnvelope = Envelope[[]models.Conversation, None]
+type ConversationEnvelope = Envelope[*models.Conversation, None]
+type ConversationMessagesEnvelope = Envelope[[]models.ConversationMessage, None]
+type ConversationMessageEnvelope = Envelope[*models.ConversationMessage, None]
+
+// maxConversationTitleLength is the maximum allowed length of a conversation title
+const maxConversationTitleLength = 256
+
+// CreateConversationRequest represents the request body for creating a conversation
+type CreateConversationRequest struct {
+	Title string `json:"title,omitempty"` // Optional, derived from the first message when empty
+}
+
+// UpdateConversationRequest represents the request body for renaming a conversation
+type UpdateConversationRequest struct {
+	Title string `json:"title"`
+}
+
+// AddConversationMessageRequest represents the request body for recording a turn in a conversation
+type AddConversationMessageRequest struct {
+	Input    string                      `json:"input"`
+	Settings models.ConversationSettings `json:"settings"`
+	Response *ResponseData               `json:"response,omitempty"`
+}
+
+// ConversationsListHandler handles GET /gen-ai/api/v1/conversations
+func (app *App) ConversationsListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	namespace, owner, ok := app.conversationScope(w, r)
+	if !ok {
+		return
+	}
+
+	opts := models.ConversationListOptions{
+		Query: r.URL.Query().Get("q"),
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			app.badRequestResponse(w, r, fmt.Errorf("invalid limit parameter: %s", limitStr))
+			return
+		}
+		opts.Limit = limit
+	}
+
+	conversations, err := app.repositories.Conversations.ListConversations(ctx, namespace, owner, opts)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	response := ConversationsEnvelope{
+		Data: conversations,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ConversationsCreateHandler handles POST /gen-ai/api/v1/conversations
+func (app *App) ConversationsCreateHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	namespace, owner, ok := app.conversationScope(w, r)
+	if !ok {
+		return
+	}
+
+	var createRequest CreateConversationRequest
+	if err := json.NewDecoder(r.Body).Decode(&createRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	title := strings.TrimSpace(createRequest.Title)
+	if len(title) > maxConversationTitleLength {
+		app.badRequestResponse(w, r, fmt.Errorf("title must be at most %d characters", maxConversationTitleLength))
+		return
+	}
+
+	conversation, err := app.repositories.Conversations.CreateConversation(ctx, namespace, owner, title)
+	if err != nil {
+		app.handleConversationError(w, r, err)
+		return
+	}
+
+	response := ConversationEnvelope{
+		Data: conversation,
+	}
+
+	if err := app.WriteJSON(w, http.StatusCreated, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ConversationGetHandler handles GET /gen-ai/api/v1/conversations/:id
+func (app *App) ConversationGetHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	namespace, owner, ok := app.conversationScope(w, r)
+	if !ok {
+		return
+	}
+
+	conversation, err := app.repositories.Conversations.GetConversation(ctx, namespace, owner, ps.ByName("id"))
+	if err != nil {
+		app.handleConversationError(w, r, err)
+		return
+	}
+
+	response := ConversationEnvelope{
+		Data: conversation,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ConversationUpdateHandler handles PATCH /gen-ai/api/v1/conversations/:id
+func (app *App) ConversationUpdateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	namespace, owner, ok := app.conversationScope(w, r)
+	if !ok {
+		return
+	}
+
+	var updateRequest UpdateConversationRequest
+	if err := json.NewDecoder(r.Body).Decode(&updateRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	title := strings.TrimSpace(updateRequest.Title)
+	if title == "" {
+		app.badRequestResponse(w, r, errors.New("title is required"))
+		return
+	}
+	if len(title) > maxConversationTitleLength {
+		app.badRequestResponse(w, r, fmt.Errorf("title must be at most %d characters", maxConversationTitleLength))
+		return
+	}
+
+	conversation, err := app.repositories.Conversations.RenameConversation(ctx, namespace, owner, ps.ByName("id"), title)
+	if err != nil {
+		app.handleConversationError(w, r, err)
+		return
+	}
+
+	response := ConversationEnvelope{
+		Data: conversation,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ConversationDeleteHandler handles DELETE /gen-ai/api/v1/conversations/:id
+func (app *App) ConversationDeleteHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	namespace, owner, ok := app.conversationScope(w, r)
+	if !ok {
+		return
+	}
+
+	conversationID := ps.ByName("id")
+	if err := app.repositories.Conversations.DeleteConversation(ctx, namespace, owner, conversationID); err != nil {
+		app.handleConversationError(w, r, err)
+		return
+	}
+
+	response := Envelope[map[string]interface{}, None]{
+		Data: map[string]interface{}{
+			"id":      conversationID,
+			"object":  "conversation.deleted",
+			"deleted": true,
+		},
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ConversationMessagesListHandler handles GET /gen-ai/api/v1/conversations/:id/messages
+func (app *App) ConversationMessagesListHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	namespace, owner, ok := app.conversationScope(w, r)
+	if !ok {
+		return
+	}
+
+	messages, err := app.repositories.Conversations.ListMessages(ctx, namespace, owner, ps.ByName("id"))
+	if err != nil {
+		app.handleConversationError(w, r, err)
+		return
+	}
+
+	response := ConversationMessagesEnvelope{
+		Data: messages,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ConversationMessagesCreateHandler handles POST /gen-ai/api/v1/conversations/:id/messages
+// It allows clients to record a turn that was not created through the responses endpoint.
+func (app *App) ConversationMessagesCreateHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	namespace, owner, ok := app.conversationScope(w, r)
+	if !ok {
+		return
+	}
+
+	var addRequest AddConversationMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&addRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if addRequest.Input == "" {
+		app.badRequestResponse(w, r, errors.New("input is required"))
+		return
+	}
+
+	message, err := buildConversationMessage(addRequest.Input, addRequest.Settings, addRequest.Response)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	saved, err := app.repositories.Conversations.AddMessage(ctx, namespace, owner, ps.ByName("id"), message)
+	if err != nil {
+		app.handleConversationError(w, r, err)
+		return
+	}
+
+	response := ConversationMessageEnvelope{
+		Data: saved,
+	}
+
+	if err := app.WriteJSON(w, http.StatusCreated, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// conversationScope resolves the namespace and owner that scope all conversation operations.
+// It writes an error response and returns false when either cannot be determined.
+func (app *App) conversationScope(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	ctx := r.Context()
+
+	namespace, ok := ctx.Value(constants.NamespaceQueryParameterKey).(string)
+	if !ok || namespace == "" {
+		app.badRequestResponse(w, r, fmt.Errorf("missing required query parameter: %s", constants.NamespaceQueryParameterKey))
+		return "", "", false
+	}
+
+	owner, err := app.getRequestUsername(ctx)
+	if err != nil {
+		app.unauthorizedResponse(w, r, err)
+		return "", "", false
+	}
+
+	return namespace, owner, true
+}
+
+// handleConversationError maps conversation repository errors to HTTP responses
+func (app *App) handleConversationError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrConversationNotFound):
+		app.notFoundResponse(w, r)
+	case errors.Is(err, repositories.ErrConversationLimitExceeded):
+		app.forbiddenResponse(w, r, err.Error())
+	case errors.Is(err, repositories.ErrConversationMessageTooLarge):
+		app.errorResponse(w, r, &integrations.HTTPError{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			ErrorResponse: integrations.ErrorResponse{
+				Code:    strconv.Itoa(http.StatusRequestEntityTooLarge),
+				Message: err.Error(),
+			},
+		})
+	default:
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// buildConversationMessage converts a turn into the persisted conversation message format
+func buildConversationMessage(input string, settings models.ConversationSettings, responseData *ResponseData) (models.ConversationMessage, error) {
+	message := models.ConversationMessage{
+		Input:    input,
+		Settings: settings,
+	}
+
+	if responseData == nil {
+		return message, nil
+	}
+
+	responseJSON, err := json.Marshal(responseData)
+	if err != nil {
+		return message, fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	message.Response = responseJSON
+	message.ResponseID = responseData.ID
+	message.OutputText = extractOutputText(responseData)
+	if message.Settings.Model == "" {
+		message.Settings.Model = responseData.Model
+	}
+
+	return message, nil
+}
+
+// extractOutputText concatenates the assistant text content of a response
+func extractOutputText(responseData *ResponseData) string {
+	var parts []string
+	for _, item := range responseData.Output {
+		if item.Type != "message" {
+			continue
+		}
+		for _, content := range item.Content {
+			if content.Text != "" {
+				parts = append(parts, content.Text)
+			}
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// conversationSettingsFromRequest captures the model and tool settings of a responses request
+func conversationSettingsFromRequest(req CreateResponseRequest) models.ConversationSettings {
+	settings := models.ConversationSettings{
+		Model:          req.Model,
+		Temperature:    req.Temperature,
+		TopP:           req.TopP,
+		Instructions:   req.Instructions,
+		VectorStoreIDs: req.VectorStoreIDs,
+		Stream:         req.Stream,
+	}
+	for _, server := range req.MCPServers {
+		settings.MCPServers = append(settings.MCPServers, models.ConversationMCPServer{
+			ServerLabel: server.ServerLabel,
+			ServerURL:   server.ServerURL,
+		})
+	}
+	return settings
+}
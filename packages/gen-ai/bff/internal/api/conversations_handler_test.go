@@ -0,0 +1,307 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/opendatahub-io/gen-ai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversationsHandlers(t *testing.T) {
+	app := App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
+		},
+		llamaStackClientFactory: lsmocks.NewMockClientFactory(),
+		repositories:            repositories.NewRepositories(),
+	}
+
+	newRequest := func(method, path string, payload interface{}) *http.Request {
+		var body bytes.Buffer
+		if payload != nil {
+			require.NoError(t, json.NewEncoder(&body).Encode(payload))
+		}
+		req, err := http.NewRequest(method, path+"?namespace="+testutil.TestNamespace, &body)
+		require.NoError(t, err)
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		return req.WithContext(ctx)
+	}
+
+	idParams := func(id string) httprouter.Params {
+		return httprouter.Params{{Key: "id", Value: id}}
+	}
+
+	createConversation := func(t *testing.T, title string) *models.Conversation {
+		rr := httptest.NewRecorder()
+		app.ConversationsCreateHandler(rr, newRequest(http.MethodPost, "/gen-ai/api/v1/conversations", CreateConversationRequest{Title: title}), nil)
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var response ConversationEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	t.Run("should create and get a conversation", func(t *testing.T) {
+		created := createConversation(t, "Release planning")
+		assert.NotEmpty(t, created.ID)
+		assert.Equal(t, "Release planning", created.Title)
+		assert.Equal(t, testutil.TestNamespace, created.Namespace)
+		assert.Equal(t, anonymousUsername, created.Owner)
+
+		rr := httptest.NewRecorder()
+		app.ConversationGetHandler(rr, newRequest(http.MethodGet, "/gen-ai/api/v1/conversations/"+created.ID, nil), idParams(created.ID))
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response ConversationEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, created.ID, response.Data.ID)
+	})
+
+	t.Run("should return 404 for unknown conversation", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		app.ConversationGetHandler(rr, newRequest(http.MethodGet, "/gen-ai/api/v1/conversations/missing", nil), idParams("missing"))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should rename a conversation", func(t *testing.T) {
+		created := createConversation(t, "Old title")
+
+		rr := httptest.NewRecorder()
+		req := newRequest(http.MethodPatch, "/gen-ai/api/v1/conversations/"+created.ID, UpdateConversationRequest{Title: "New title"})
+		app.ConversationUpdateHandler(rr, req, idParams(created.ID))
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response ConversationEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, "New title", response.Data.Title)
+	})
+
+	t.Run("should reject rename without title", func(t *testing.T) {
+		created := createConversation(t, "Keep me")
+
+		rr := httptest.NewRecorder()
+		req := newRequest(http.MethodPatch, "/gen-ai/api/v1/conversations/"+created.ID, UpdateConversationRequest{Title: "  "})
+		app.ConversationUpdateHandler(rr, req, idParams(created.ID))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should record messages and search by text", func(t *testing.T) {
+		created := createConversation(t, "")
+
+		payload := AddConversationMessageRequest{
+			Input:    "How do I configure vector stores?",
+			Settings: models.ConversationSettings{Model: "llama-3.1-8b"},
+			Response: &ResponseData{
+				ID:    "resp_123",
+				Model: "llama-3.1-8b",
+				Output: []OutputItem{{
+					Type:    "message",
+					Role:    "assistant",
+					Content: []ContentItem{{Type: "output_text", Text: "Use the embedding model of your choice."}},
+				}},
+			},
+		}
+		rr := httptest.NewRecorder()
+		app.ConversationMessagesCreateHandler(rr, newRequest(http.MethodPost, "/gen-ai/api/v1/conversations/"+created.ID+"/messages", payload), idParams(created.ID))
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		rr = httptest.NewRecorder()
+		app.ConversationMessagesListHandler(rr, newRequest(http.MethodGet, "/gen-ai/api/v1/conversations/"+created.ID+"/messages", nil), idParams(created.ID))
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var messages ConversationMessagesEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &messages))
+		require.Len(t, messages.Data, 1)
+		assert.Equal(t, "resp_123", messages.Data[0].ResponseID)
+		assert.Equal(t, "Use the embedding model of your choice.", messages.Data[0].OutputText)
+
+		// Title is derived from the first input and the thread can be continued
+		rr = httptest.NewRecorder()
+		app.ConversationGetHandler(rr, newRequest(http.MethodGet, "/gen-ai/api/v1/conversations/"+created.ID, nil), idParams(created.ID))
+		var conversation ConversationEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &conversation))
+		assert.Equal(t, "How do I configure vector stores?", conversation.Data.Title)
+		assert.Equal(t, "resp_123", conversation.Data.LastResponseID)
+		assert.Equal(t, 1, conversation.Data.MessageCount)
+
+		// Search matches assistant output text
+		req := newRequest(http.MethodGet, "/gen-ai/api/v1/conversations", nil)
+		req.URL.RawQuery += "&q=EMBEDDING"
+		rr = httptest.NewRecorder()
+		app.ConversationsListHandler(rr, req, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var list ConversationsEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+		require.Len(t, list.Data, 1)
+		assert.Equal(t, created.ID, list.Data[0].ID)
+	})
+
+	t.Run("should count every message recorded concurrently while renaming", func(t *testing.T) {
+		created := createConversation(t, "")
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				rr := httptest.NewRecorder()
+				payload := AddConversationMessageRequest{Input: fmt.Sprintf("Question %d", i)}
+				app.ConversationMessagesCreateHandler(rr, newRequest(http.MethodPost, "/gen-ai/api/v1/conversations/"+created.ID+"/messages", payload), idParams(created.ID))
+				assert.Equal(t, http.StatusCreated, rr.Code)
+			}()
+			go func() {
+				defer wg.Done()
+				rr := httptest.NewRecorder()
+				payload := UpdateConversationRequest{Title: fmt.Sprintf("Title %d", i)}
+				app.ConversationUpdateHandler(rr, newRequest(http.MethodPatch, "/gen-ai/api/v1/conversations/"+created.ID, payload), idParams(created.ID))
+				assert.Equal(t, http.StatusOK, rr.Code)
+			}()
+		}
+		wg.Wait()
+
+		rr := httptest.NewRecorder()
+		app.ConversationGetHandler(rr, newRequest(http.MethodGet, "/gen-ai/api/v1/conversations/"+created.ID, nil), idParams(created.ID))
+		var conversation ConversationEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &conversation))
+		assert.Equal(t, 20, conversation.Data.MessageCount)
+	})
+
+	t.Run("should reject invalid limit", func(t *testing.T) {
+		req := newRequest(http.MethodGet, "/gen-ai/api/v1/conversations", nil)
+		req.URL.RawQuery += "&limit=abc"
+		rr := httptest.NewRecorder()
+		app.ConversationsListHandler(rr, req, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should delete a conversation", func(t *testing.T) {
+		created := createConversation(t, "To delete")
+
+		rr := httptest.NewRecorder()
+		app.ConversationDeleteHandler(rr, newRequest(http.MethodDelete, "/gen-ai/api/v1/conversations/"+created.ID, nil), idParams(created.ID))
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		rr = httptest.NewRecorder()
+		app.ConversationDeleteHandler(rr, newRequest(http.MethodDelete, "/gen-ai/api/v1/conversations/"+created.ID, nil), idParams(created.ID))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should record responses created with a conversation_id", func(t *testing.T) {
+		created := createConversation(t, "Chat")
+
+		req := newRequest(http.MethodPost, "/gen-ai/api/v1/responses", CreateResponseRequest{
+			Input:          "Hello, how are you?",
+			Model:          "llama-3.1-8b",
+			ConversationID: created.ID,
+		})
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		req = req.WithContext(context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient))
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		messages, err := app.repositories.Conversations.ListMessages(context.Background(), testutil.TestNamespace, anonymousUsername, created.ID)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, "Hello, how are you?", messages[0].Input)
+		assert.Equal(t, "llama-3.1-8b", messages[0].Settings.Model)
+		assert.Equal(t, "resp_mock123", messages[0].ResponseID)
+	})
+
+	t.Run("should return 404 when responding in unknown conversation", func(t *testing.T) {
+		req := newRequest(http.MethodPost, "/gen-ai/api/v1/responses", CreateResponseRequest{
+			Input:          "Hello",
+			Model:          "llama-3.1-8b",
+			ConversationID: "missing",
+		})
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestConversationLimits(t *testing.T) {
+	app := App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
+		},
+		llamaStackClientFactory: lsmocks.NewMockClientFactory(),
+		repositories:            repositories.NewRepositories(),
+	}
+	ctx := context.Background()
+
+	newRequest := func(method, path string, payload interface{}) *http.Request {
+		var body bytes.Buffer
+		require.NoError(t, json.NewEncoder(&body).Encode(payload))
+		req, err := http.NewRequest(method, path+"?namespace="+testutil.TestNamespace, &body)
+		require.NoError(t, err)
+		req = req.WithContext(context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace))
+		return req.WithContext(context.WithValue(req.Context(), constants.LlamaStackClientKey,
+			app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)))
+	}
+
+	t.Run("should refuse messages larger than the limit", func(t *testing.T) {
+		conversation, err := app.repositories.Conversations.CreateConversation(ctx, testutil.TestNamespace, anonymousUsername, "Large")
+		require.NoError(t, err)
+
+		input := string(bytes.Repeat([]byte("a"), constants.ConversationMessageMaxBytes+1))
+		rr := httptest.NewRecorder()
+		req := newRequest(http.MethodPost, "/gen-ai/api/v1/conversations/"+conversation.ID+"/messages", AddConversationMessageRequest{Input: input})
+		app.ConversationMessagesCreateHandler(rr, req, httprouter.Params{{Key: "id", Value: conversation.ID}})
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
+	t.Run("should refuse messages and responses in full conversations", func(t *testing.T) {
+		conversation, err := app.repositories.Conversations.CreateConversation(ctx, testutil.TestNamespace, anonymousUsername, "Full")
+		require.NoError(t, err)
+		for range constants.ConversationMaxMessages {
+			_, err := app.repositories.Conversations.AddMessage(ctx, testutil.TestNamespace, anonymousUsername, conversation.ID, models.ConversationMessage{Input: "Hello"})
+			require.NoError(t, err)
+		}
+
+		rr := httptest.NewRecorder()
+		req := newRequest(http.MethodPost, "/gen-ai/api/v1/conversations/"+conversation.ID+"/messages", AddConversationMessageRequest{Input: "Hello"})
+		app.ConversationMessagesCreateHandler(rr, req, httprouter.Params{{Key: "id", Value: conversation.ID}})
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = httptest.NewRecorder()
+		req = newRequest(http.MethodPost, "/gen-ai/api/v1/responses", CreateResponseRequest{Input: "Hello", Model: "llama-3.1-8b", ConversationID: conversation.ID})
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should refuse conversations beyond the limit of the owner", func(t *testing.T) {
+		conversations, err := app.repositories.Conversations.ListConversations(ctx, testutil.TestNamespace, anonymousUsername, models.ConversationListOptions{})
+		require.NoError(t, err)
+		for range constants.ConversationMaxPerOwner - len(conversations) {
+			_, err := app.repositories.Conversations.CreateConversation(ctx, testutil.TestNamespace, anonymousUsername, "")
+			require.NoError(t, err)
+		}
+
+		rr := httptest.NewRecorder()
+		app.ConversationsCreateHandler(rr, newRequest(http.MethodPost, "/gen-ai/api/v1/conversations", CreateConversationRequest{Title: "One too many"}), nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		// Other users keep their own allowance
+		_, err = app.repositories.Conversations.CreateConversation(ctx, testutil.TestNamespace, "otherUser", "")
+		assert.NoError(t, err)
+	})
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,8 +10,14 @@ import (
 	"strings"
 
 	"github.com/openai/openai-go/v2"
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
 )
 
+// anonymousUsername is the owner used for user-scoped data when authentication is disabled
+const anonymousUsername = "anonymous"
+
 type Envelope[D any, M any] struct {
 	Data     D `json:"data"`
 	Metadata M `json:"metadata,omitempty"`
@@ -141,3 +148,54 @@ func ModelNotFoundError(err error) bool {
 
 	return false
 }
+
+// getRequestUsername resolves the username of the caller from the request identity in context.
+// When authentication is disabled all requests are attributed to a single anonymous user.
+func (app *App) getRequestUsername(ctx context.Context) (string, error) {
+	if app.config.AuthMethod == config.AuthMethodDisabled {
+		return anonymousUsername, nil
+	}
+
+	identity, ok := ctx.Value(constants.RequestIdentityKey).(*integrations.RequestIdentity)
+	if !ok || identity == nil {
+		return "", errors.New("user identity not found in context")
+	}
+
+	client, err := app.kubernetesClientFactory.GetClient(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	username, err := client.GetUser(ctx, identity)
+	if err != nil {
+		return "", fmt.Errorf("failed to get username: %w", err)
+	}
+	if username == "" {
+		return "", errors.New("username is empty")
+	}
+	return username, nil
+}
+
+// getRequestUserGroups resolves the groups of the caller from the request identity in context.
+// When authentication is disabled the anonymous user belongs to no group.
+func (app *App) getRequestUserGroups(ctx context.Context) ([]string, error) {
+	if app.config.AuthMethod == config.AuthMethodDisabled {
+		return nil, nil
+	}
+
+	identity, ok := ctx.Value(constants.RequestIdentityKey).(*integrations.RequestIdentity)
+	if !ok || identity == nil {
+		return nil, errors.New("user identity not found in context")
+	}
+
+	client, err := app.kubernetesClientFactory.GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes client: %w", err)
+	}
+
+	groups, err := client.GetUserGroups(ctx, identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user groups: %w", err)
+	}
+	return groups, nil
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"path"
+	"strings"
+
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+)
+
+// errArchiveTooCompressed is returned for archives that expand far beyond their size, such as zip bombs
+var errArchiveTooCompressed = fmt.Errorf("archive expands to more than %d times its size", constants.IngestionMaxCompressionRatio)
+
+// uploadedDocuments collects the documents of an upload and enforces the upload limits while reading them
+type uploadedDocuments struct {
+	documents  []llamastack.IngestionDocument
+	totalBytes int64
+}
+
+// readUploadedDocuments reads the file parts of an upload. Archives are expanded into the documents they contain.
+// All errors are caused by the upload and are meant to be reported to the client.
+func readUploadedDocuments(headers []*multipart.FileHeader) ([]llamastack.IngestionDocument, error) {
+	uploaded := &uploadedDocuments{}
+	for _, header := range headers {
+		if err := uploaded.addPart(header); err != nil {
+			return nil, fmt.Errorf("%s: %w", header.Filename, err)
+		}
+	}
+	if len(uploaded.documents) == 0 {
+		return nil, errors.New("the upload does not contain any documents")
+	}
+	return uploaded.documents, nil
+}
+
+func (u *uploadedDocuments) addPart(header *multipart.FileHeader) error {
+	file, err := header.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	defer file.Close()
+
+	name := strings.ToLower(header.Filename)
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return u.addZip(file, header.Size)
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("invalid gzip archive: %w", err)
+		}
+		defer gzipReader.Close()
+		return u.addTar(gzipReader, header.Size)
+	case strings.HasSuffix(name, ".tar"):
+		return u.addTar(file, header.Size)
+	default:
+		return u.add(header.Filename, header.Header.Get("Content-Type"), file)
+	}
+}
+
+func (u *uploadedDocuments) addZip(file multipart.File, size int64) error {
+	archive, err := zip.NewReader(file, size)
+	if err != nil {
+		return fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	maxExpandedBytes := max(size, 1) * constants.IngestionMaxCompressionRatio
+	var expandedBytes int64
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() || skipArchiveEntry(entry.Name) {
+			continue
+		}
+		// The declared sizes are checked first, the limits are enforced again while reading
+		if entry.CompressedSize64 > 0 && entry.UncompressedSize64/entry.CompressedSize64 > constants.IngestionMaxCompressionRatio {
+			return errArchiveTooCompressed
+		}
+		if entry.UncompressedSize64 > constants.IngestionMaxFileBytes {
+			return fmt.Errorf("%s exceeds the maximum file size of %d bytes", entry.Name, constants.IngestionMaxFileBytes)
+		}
+
+		reader, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", entry.Name, err)
+		}
+		before := u.totalBytes
+		err = u.add(entry.Name, "", reader)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+
+		expandedBytes += u.totalBytes - before
+		if expandedBytes > maxExpandedBytes {
+			return errArchiveTooCompressed
+		}
+	}
+	return nil
+}
+
+func (u *uploadedDocuments) addTar(reader io.Reader, size int64) error {
+	// Compressed tar archives only reveal their expanded size while reading, so reading stops at the limit
+	expanded := &io.LimitedReader{R: reader, N: max(size, 1)*constants.IngestionMaxCompressionRatio + 1}
+	archive := tar.NewReader(expanded)
+	for {
+		entry, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if expanded.N <= 0 {
+			return errArchiveTooCompressed
+		}
+		if err != nil {
+			return fmt.Errorf("invalid tar archive: %w", err)
+		}
+		if entry.Typeflag != tar.TypeReg || skipArchiveEntry(entry.Name) {
+			continue
+		}
+		if entry.Size > constants.IngestionMaxFileBytes {
+			return fmt.Errorf("%s exceeds the maximum file size of %d bytes", entry.Name, constants.IngestionMaxFileBytes)
+		}
+
+		if err := u.add(entry.Name, "", archive); err != nil {
+			if expanded.N <= 0 {
+				return errArchiveTooCompressed
+			}
+			return err
+		}
+	}
+}
+
+// add reads a document and checks it against the upload limits
+func (u *uploadedDocuments) add(name, contentType string, reader io.Reader) error {
+	if len(u.documents) >= constants.IngestionMaxUploadFiles {
+		return fmt.Errorf("the upload exceeds the maximum of %d files", constants.IngestionMaxUploadFiles)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(reader, constants.IngestionMaxFileBytes+1))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if len(content) > constants.IngestionMaxFileBytes {
+		return fmt.Errorf("%s exceeds the maximum file size of %d bytes", name, constants.IngestionMaxFileBytes)
+	}
+	u.totalBytes += int64(len(content))
+	if u.totalBytes > constants.IngestionMaxUploadBytes {
+		return fmt.Errorf("the upload exceeds the maximum size of %d bytes", constants.IngestionMaxUploadBytes)
+	}
+
+	// Archive entries are flattened, the vector store only keeps file names
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(name))
+	}
+	u.documents = append(u.documents, llamastack.IngestionDocument{
+		Filename:    name,
+		ContentType: contentType,
+		Content:     content,
+	})
+	return nil
+}
+
+// skipArchiveEntry reports whether an archive entry is metadata rather than a document,
+// such as hidden files or the resource forks added by macOS
+func skipArchiveEntry(name string) bool {
+	name = strings.ReplaceAll(name, "\\", "/")
+	for _, element := range strings.Split(name, "/") {
+		if strings.HasPrefix(element, ".") && element != "." && element != ".." || element == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
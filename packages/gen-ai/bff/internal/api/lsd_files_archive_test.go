@@ -0,0 +1,133 @@
+package api
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uploadFileHeaders builds the file parts of a multipart upload from file names and contents
+func uploadFileHeaders(t *testing.T, files map[string][]byte) []*multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, content := range files {
+		part, err := writer.CreateFormFile("file", name)
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(32 << 20)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = form.RemoveAll() })
+	return form.File["file"]
+}
+
+func zipArchive(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for name, content := range files {
+		entry, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = entry.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func tarGzArchive(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	writer := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := writer.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, gzipWriter.Close())
+	return buf.Bytes()
+}
+
+func documentNames(t *testing.T, files map[string][]byte) []string {
+	documents, err := readUploadedDocuments(uploadFileHeaders(t, files))
+	require.NoError(t, err)
+	var names []string
+	for _, document := range documents {
+		names = append(names, document.Filename)
+	}
+	return names
+}
+
+func TestReadUploadedDocuments(t *testing.T) {
+	t.Run("should read plain files as they are", func(t *testing.T) {
+		documents, err := readUploadedDocuments(uploadFileHeaders(t, map[string][]byte{"guide.pdf": []byte("document content")}))
+		require.NoError(t, err)
+		require.Len(t, documents, 1)
+		assert.Equal(t, "guide.pdf", documents[0].Filename)
+		assert.Equal(t, []byte("document content"), documents[0].Content)
+	})
+
+	t.Run("should expand zip archives and skip metadata entries", func(t *testing.T) {
+		archive := zipArchive(t, map[string][]byte{
+			"docs/intro.md":            []byte("# Intro"),
+			"docs/setup/install.md":    []byte("# Install"),
+			"docs/.DS_Store":           []byte("metadata"),
+			"__MACOSX/docs/._intro.md": []byte("resource fork"),
+		})
+
+		names := documentNames(t, map[string][]byte{"docs.zip": archive, "faq.txt": []byte("questions")})
+		assert.ElementsMatch(t, []string{"intro.md", "install.md", "faq.txt"}, names)
+	})
+
+	t.Run("should expand tar.gz archives", func(t *testing.T) {
+		archive := tarGzArchive(t, map[string][]byte{
+			"guides/a.md": []byte("first guide"),
+			"guides/b.md": []byte("second guide"),
+		})
+
+		names := documentNames(t, map[string][]byte{"guides.tar.gz": archive})
+		assert.ElementsMatch(t, []string{"a.md", "b.md"}, names)
+	})
+
+	t.Run("should reject archives with too many files", func(t *testing.T) {
+		files := map[string][]byte{}
+		for i := 0; i <= 100; i++ {
+			files[fmt.Sprintf("doc-%d.txt", i)] = []byte("content")
+		}
+
+		_, err := readUploadedDocuments(uploadFileHeaders(t, map[string][]byte{"docs.zip": zipArchive(t, files)}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maximum of 100 files")
+	})
+
+	t.Run("should reject archives that expand far beyond their size", func(t *testing.T) {
+		bomb := map[string][]byte{"zeros.txt": bytes.Repeat([]byte{0}, 4<<20)}
+
+		_, err := readUploadedDocuments(uploadFileHeaders(t, map[string][]byte{"bomb.zip": zipArchive(t, bomb)}))
+		assert.ErrorIs(t, err, errArchiveTooCompressed)
+
+		_, err = readUploadedDocuments(uploadFileHeaders(t, map[string][]byte{"bomb.tgz": tarGzArchive(t, bomb)}))
+		assert.ErrorIs(t, err, errArchiveTooCompressed)
+	})
+
+	t.Run("should reject invalid archives and empty uploads", func(t *testing.T) {
+		_, err := readUploadedDocuments(uploadFileHeaders(t, map[string][]byte{"docs.zip": []byte("not a zip")}))
+		require.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "docs.zip: invalid zip archive"))
+
+		_, err = readUploadedDocuments(uploadFileHeaders(t, map[string][]byte{"empty.zip": zipArchive(t, map[string][]byte{".hidden": []byte("x")})}))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not contain any documents")
+	})
+}
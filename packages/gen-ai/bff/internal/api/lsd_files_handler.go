@@ -1,18 +1,35 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/openai/openai-go/v2"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
 	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/sources"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
 )
 
-type FileUploadResponse = llamastack.APIResponse
+// VisionFilesEnvelope is the response of image uploads with purpose vision
+type VisionFilesEnvelope = Envelope[[]*llamastack.FileUploadResult, None]
 
 // LlamaStackUploadFileHandler handles POST /gen-ai/api/v1/files/upload.
+// It accepts one or more file parts, where zip and tar archives are expanded into their documents, or the
+// source_uri of a document in S3-compatible storage or at a URL. It queues the documents for ingestion into
+// the vector store and returns the ingestion job right away. Images uploaded with purpose vision are stored
+// as they are, for use as input_image parts of responses.
 func (app *App) LlamaStackUploadFileHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	ctx := r.Context()
 
@@ -29,12 +46,21 @@ func (app *App) LlamaStackUploadFileHandler(w http.ResponseWriter, r *http.Reque
 		}
 	}()
 
-	file, header, err := r.FormFile("file")
-	if err != nil {
+	if r.FormValue("purpose") == constants.VisionFilePurpose {
+		app.uploadVisionFiles(w, r)
+		return
+	}
+
+	headers := r.MultipartForm.File["file"]
+	sourceURI := strings.TrimSpace(r.FormValue("source_uri"))
+	if len(headers) == 0 && sourceURI == "" {
 		app.badRequestResponse(w, r, errors.New("file is required"))
 		return
 	}
-	defer file.Close()
+	if len(headers) > 0 && sourceURI != "" {
+		app.badRequestResponse(w, r, errors.New("file and source_uri cannot be combined"))
+		return
+	}
 
 	vectorStoreID := r.FormValue("vector_store_id")
 	if vectorStoreID == "" {
@@ -42,6 +68,11 @@ func (app *App) LlamaStackUploadFileHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok || !app.authorizeVectorStore(w, r, caller, vectorStoreID, true) {
+		return
+	}
+
 	purpose := r.FormValue("purpose")
 
 	var chunkingStrategy *llamastack.ChunkingStrategy
@@ -70,50 +101,236 @@ func (app *App) LlamaStackUploadFileHandler(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
-	// Use direct streaming - no temp file needed!
-	uploadParams := llamastack.UploadFileParams{
-		Reader:           file,
-		Filename:         header.Filename,
-		ContentType:      header.Header.Get("Content-Type"),
-		Purpose:          purpose,
+	attributes, err := parseFileAttributes(r.FormValue("attributes"))
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	var documents []llamastack.IngestionDocument
+	var filename string
+	if sourceURI != "" {
+		// One attribute is taken by the source_uri of the document
+		if len(attributes) >= constants.VectorStoreFileMaxAttributes {
+			app.badRequestResponse(w, r, fmt.Errorf("at most %d attributes can be set for documents ingested from a source_uri", constants.VectorStoreFileMaxAttributes-1))
+			return
+		}
+
+		// Remote documents are streamed by the ingestion job, and fetched again when it is retried
+		source, ok := app.resolveDocumentSource(w, r, caller.Namespace, sourceURI, r.FormValue("data_connection"))
+		if !ok {
+			return
+		}
+		documents = []llamastack.IngestionDocument{{Filename: source.Filename(), Source: source}}
+		filename = source.Filename()
+	} else {
+		// The documents are kept in memory by the ingestion job so that it can be retried
+		documents, err = readUploadedDocuments(headers)
+		if err != nil {
+			app.badRequestResponse(w, r, err)
+			return
+		}
+
+		// The job is named after the uploaded file or archive
+		filename = fmt.Sprintf("%d files", len(headers))
+		if len(headers) == 1 {
+			filename = headers[0].Filename
+		}
+	}
+
+	if !app.checkVectorStoreQuota(w, r, caller.Namespace, 0, len(documents)) {
+		return
+	}
+
+	job, err := app.repositories.IngestionJobs.SubmitJob(ctx, llamastack.IngestionJobParams{
+		Namespace:        caller.Namespace,
+		Owner:            caller.Username,
 		VectorStoreID:    vectorStoreID,
+		Filename:         filename,
+		Purpose:          purpose,
 		ChunkingStrategy: chunkingStrategy,
+		Attributes:       attributes,
+		Documents:        documents,
+	})
+	if err != nil {
+		app.handleIngestionJobError(w, r, err)
+		return
 	}
 
-	result, err := app.repositories.Files.UploadFile(ctx, uploadParams)
-	if err != nil {
+	response := IngestionJobEnvelope{
+		Data: job,
+	}
+
+	if err := app.WriteJSON(w, http.StatusAccepted, response, nil); err != nil {
 		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// uploadVisionFiles uploads the images of a multipart form with purpose vision. They are not added to a vector
+// store, and must be images models accept of at most constants.InputContentMaxBytes.
+func (app *App) uploadVisionFiles(w http.ResponseWriter, r *http.Request) {
+	headers := r.MultipartForm.File["file"]
+	if len(headers) == 0 {
+		app.badRequestResponse(w, r, errors.New("file is required"))
+		return
+	}
+	if len(headers) > constants.InputContentMaxParts {
+		app.badRequestResponse(w, r, fmt.Errorf("at most %d images can be uploaded at once", constants.InputContentMaxParts))
+		return
+	}
+	if r.FormValue("source_uri") != "" || r.FormValue("vector_store_id") != "" {
+		app.badRequestResponse(w, r, fmt.Errorf("files with purpose %s are not added to vector stores", constants.VisionFilePurpose))
 		return
 	}
 
-	// Use envelope pattern for consistent response structure
-	response := FileUploadResponse{
-		Data: result,
+	results := make([]*llamastack.FileUploadResult, 0, len(headers))
+	for _, header := range headers {
+		result, err := app.uploadVisionFile(r, header)
+		if err != nil {
+			var badRequest *visionFileError
+			if errors.As(err, &badRequest) {
+				app.badRequestResponse(w, r, err)
+			} else {
+				app.serverErrorResponse(w, r, err)
+			}
+			return
+		}
+		results = append(results, result)
+	}
+
+	response := VisionFilesEnvelope{
+		Data: results,
 	}
 
-	err = app.WriteJSON(w, http.StatusCreated, response, nil)
+	if err := app.WriteJSON(w, http.StatusCreated, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// visionFileError reports an uploaded image that cannot be used
+type visionFileError struct {
+	filename string
+	reason   string
+}
+
+func (e *visionFileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.filename, e.reason)
+}
+
+// uploadVisionFile checks the size and sniffed content type of an image and uploads it with purpose vision
+func (app *App) uploadVisionFile(r *http.Request, header *multipart.FileHeader) (*llamastack.FileUploadResult, error) {
+	filename := header.Filename
+	if header.Size > constants.InputContentMaxBytes {
+		return nil, &visionFileError{filename, fmt.Sprintf("images must be at most %d MB", constants.InputContentMaxBytes>>20)}
+	}
+
+	file, err := header.Open()
 	if err != nil {
+		return nil, &visionFileError{filename, fmt.Sprintf("failed to read file: %v", err)}
+	}
+	defer file.Close()
+
+	// The content type is sniffed rather than taken from the part, which clients set from the file extension
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, &visionFileError{filename, fmt.Sprintf("failed to read file: %v", err)}
+	}
+	contentType := http.DetectContentType(head[:n])
+	if !slices.Contains(constants.InputImageContentTypes, contentType) {
+		return nil, &visionFileError{filename, fmt.Sprintf("images must be one of %v, got: %s", constants.InputImageContentTypes, contentType)}
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind %s: %w", filename, err)
+	}
+
+	return app.repositories.Files.UploadFile(r.Context(), llamastack.UploadFileParams{
+		Reader:      file,
+		Filename:    filename,
+		ContentType: contentType,
+		Purpose:     constants.VisionFilePurpose,
+	})
+}
+
+// parseFileAttributes parses the attributes form field, a JSON object of strings, numbers and booleans that is
+// stored with the vector store files, such as {"department": "finance", "year": 2024}
+func parseFileAttributes(value string) (map[string]interface{}, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var attributes map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &attributes); err != nil {
+		return nil, fmt.Errorf("attributes must be a JSON object: %w", err)
+	}
+	if _, ok := attributes[constants.VectorStoreFileSourceURIAttribute]; ok {
+		return nil, fmt.Errorf("the %s attribute is set for documents ingested from a source_uri", constants.VectorStoreFileSourceURIAttribute)
+	}
+	if err := llamastack.ValidateFileAttributes(attributes); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// resolveDocumentSource returns the source of a document to ingest from S3-compatible storage or a URL.
+// s3:// URIs are read with the credentials of the named data connection in the namespace.
+func (app *App) resolveDocumentSource(w http.ResponseWriter, r *http.Request, namespace, uri, dataConnection string) (llamastack.DocumentSource, bool) {
+	ctx := r.Context()
+
+	var source llamastack.DocumentSource
+	var err error
+	if strings.HasPrefix(uri, "s3://") {
+		if dataConnection == "" {
+			app.badRequestResponse(w, r, errors.New("data_connection is required for s3:// source URIs"))
+			return nil, false
+		}
+
+		identity, ok := ctx.Value(constants.RequestIdentityKey).(*integrations.RequestIdentity)
+		if !ok || identity == nil {
+			app.badRequestResponse(w, r, errors.New("missing RequestIdentity in context"))
+			return nil, false
+		}
+		k8sClient, clientErr := app.kubernetesClientFactory.GetClient(ctx)
+		if clientErr != nil {
+			app.serverErrorResponse(w, r, fmt.Errorf("failed to get Kubernetes client: %w", clientErr))
+			return nil, false
+		}
+
+		source, err = app.repositories.DocumentSources.S3Source(ctx, k8sClient, identity, namespace, dataConnection, uri)
+	} else {
+		source, err = app.repositories.DocumentSources.HTTPSource(uri)
+	}
+
+	switch {
+	case err == nil:
+		return source, true
+	case errors.Is(err, sources.ErrInvalidSource), errors.Is(err, repositories.ErrDataConnectionNotFound):
+		app.badRequestResponse(w, r, err)
+	default:
 		app.serverErrorResponse(w, r, err)
 	}
+	return nil, false
 }
 
 type FilesListResponse = llamastack.APIResponse
 
 // LlamaStackListFilesHandler handles GET /gen-ai/api/v1/lsd/files.
+// Only files in vector stores the caller can read are listed.
 func (app *App) LlamaStackListFilesHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	ctx := r.Context()
 
-	// Parse query parameters
-	params := llamastack.ListFilesParams{}
+	// Ownership is checked by the BFF, so every file is fetched and the limit is applied to the readable ones
+	maxLimit := int64(constants.FilesMaxListLimit)
+	params := llamastack.ListFilesParams{Limit: &maxLimit}
 
 	// Parse limit parameter
+	limit := maxLimit
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if limit, err := strconv.ParseInt(limitStr, 10, 64); err == nil {
-			params.Limit = &limit
-		} else {
+		parsed, err := strconv.ParseInt(limitStr, 10, 64)
+		if err != nil || parsed < 1 || parsed > maxLimit {
 			app.badRequestResponse(w, r, fmt.Errorf("invalid limit parameter: %s", limitStr))
 			return
 		}
+		limit = parsed
 	}
 
 	// Parse order parameter
@@ -126,12 +343,33 @@ func (app *App) LlamaStackListFilesHandler(w http.ResponseWriter, r *http.Reques
 		params.Purpose = purpose
 	}
 
-	result, err := app.repositories.Files.ListFiles(ctx, params)
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok {
+		return
+	}
+
+	fileVectorStores, err := app.fileVectorStores(ctx)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	files, err := app.repositories.Files.ListFiles(ctx, params)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
+	result := make([]openai.FileObject, 0, len(files))
+	for _, file := range files {
+		if int64(len(result)) == limit {
+			break
+		}
+		if slices.ContainsFunc(fileVectorStores[file.ID], func(acl models.VectorStoreACL) bool { return acl.CanRead(caller) }) {
+			result = append(result, file)
+		}
+	}
+
 	// Use envelope pattern for consistent response structure
 	response := FilesListResponse{
 		Data: result,
@@ -144,6 +382,7 @@ func (app *App) LlamaStackListFilesHandler(w http.ResponseWriter, r *http.Reques
 }
 
 // LlamaStackDeleteFileHandler handles DELETE /gen-ai/api/v1/lsd/files/delete.
+// A file can be in several vector stores, so the caller must manage all of them.
 func (app *App) LlamaStackDeleteFileHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	ctx := r.Context()
 
@@ -154,7 +393,29 @@ func (app *App) LlamaStackDeleteFileHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	err := app.repositories.Files.DeleteFile(ctx, fileID)
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok {
+		return
+	}
+
+	fileVectorStores, err := app.fileVectorStores(ctx)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	// Files the caller cannot read are reported as not found so their existence is not disclosed
+	acls := fileVectorStores[fileID]
+	if !slices.ContainsFunc(acls, func(acl models.VectorStoreACL) bool { return acl.CanRead(caller) }) {
+		app.notFoundResponse(w, r)
+		return
+	}
+	if slices.ContainsFunc(acls, func(acl models.VectorStoreACL) bool { return !acl.CanManage(caller) }) {
+		app.forbiddenResponse(w, r, "only the owner of the vector store can change it")
+		return
+	}
+
+	err = app.repositories.Files.DeleteFile(ctx, fileID)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
@@ -174,3 +435,36 @@ func (app *App) LlamaStackDeleteFileHandler(w http.ResponseWriter, r *http.Reque
 		app.serverErrorResponse(w, r, err)
 	}
 }
+
+// fileVectorStores returns the access rules of the vector stores that contain each file. Files outside every
+// vector store, such as vision uploads, cannot be attributed to an owner and are absent from the result.
+func (app *App) fileVectorStores(ctx context.Context) (map[string][]models.VectorStoreACL, error) {
+	vectorStores, err := app.repositories.VectorStores.ListVectorStores(ctx, llamastack.ListVectorStoresParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	fileVectorStores := make(map[string][]models.VectorStoreACL)
+	limit := int64(constants.VectorStoreMaxListLimit)
+	for _, vectorStore := range vectorStores {
+		acl, _ := models.VectorStoreACLFromMetadata(vectorStore.Metadata)
+
+		params := llamastack.ListVectorStoreFilesParams{Limit: &limit}
+		for {
+			files, err := app.repositories.VectorStores.ListVectorStoreFiles(ctx, vectorStore.ID, params)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, file := range files {
+				fileVectorStores[file.ID] = append(fileVectorStores[file.ID], acl)
+			}
+
+			if len(files) < int(limit) {
+				break
+			}
+			params.After = files[len(files)-1].ID
+		}
+	}
+	return fileVectorStores, nil
+}
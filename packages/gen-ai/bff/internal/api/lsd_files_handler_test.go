@@ -13,8 +13,10 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/openai/openai-go/v2"
 	"github.com/opendatahub-io/gen-ai/internal/config"
 	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
 	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
 	"github.com/opendatahub-io/gen-ai/internal/repositories"
 	"github.com/opendatahub-io/gen-ai/internal/testutil"
@@ -103,7 +105,7 @@ func TestLlamaStackUploadFileHandler(t *testing.T) {
 	}
 
 	t.Run("should upload file successfully with required parameters", func(t *testing.T) {
-		body, contentType, err := createMultipartFormData("test.txt", "Test file content", "vs_test123", "", "")
+		body, contentType, err := createMultipartFormData("test.txt", "Test file content", lsmocks.MockPrivateVectorStoreID, "", "")
 		assert.NoError(t, err)
 
 		req, err := http.NewRequest(http.MethodPost, "/gen-ai/api/v1/files/upload?namespace=test-namespace", bytes.NewReader(body))
@@ -118,7 +120,7 @@ func TestLlamaStackUploadFileHandler(t *testing.T) {
 		rr := httptest.NewRecorder()
 		app.LlamaStackUploadFileHandler(rr, req, nil)
 
-		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, http.StatusAccepted, rr.Code)
 
 		respBody, err := io.ReadAll(rr.Result().Body)
 		assert.NoError(t, err)
@@ -132,14 +134,15 @@ func TestLlamaStackUploadFileHandler(t *testing.T) {
 		assert.Contains(t, response, "data")
 		data := response["data"].(map[string]interface{})
 
-		// Verify mock response structure
-		assert.Contains(t, data, "file_id")
-		assert.Equal(t, "file-mock123abc456def", data["file_id"])
-		assert.Contains(t, data, "vector_store_file")
+		// Verify the queued ingestion job
+		assert.NotEmpty(t, data["id"])
+		assert.Equal(t, "queued", data["status"])
+		assert.Equal(t, lsmocks.MockPrivateVectorStoreID, data["vector_store_id"])
+		assert.Equal(t, "test.txt", data["filename"])
 	})
 
 	t.Run("should upload file with optional parameters", func(t *testing.T) {
-		body, contentType, err := createMultipartFormData("test.txt", "Test file content", "vs_test123", "assistants", "auto")
+		body, contentType, err := createMultipartFormData("test.txt", "Test file content", lsmocks.MockPrivateVectorStoreID, "assistants", "auto")
 		assert.NoError(t, err)
 
 		req, err := http.NewRequest(http.MethodPost, "/gen-ai/api/v1/files/upload?namespace=test-namespace", bytes.NewReader(body))
@@ -154,7 +157,7 @@ func TestLlamaStackUploadFileHandler(t *testing.T) {
 		rr := httptest.NewRecorder()
 		app.LlamaStackUploadFileHandler(rr, req, nil)
 
-		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, http.StatusAccepted, rr.Code)
 
 		respBody, err := io.ReadAll(rr.Result().Body)
 		assert.NoError(t, err)
@@ -167,16 +170,12 @@ func TestLlamaStackUploadFileHandler(t *testing.T) {
 		// Verify envelope structure
 		assert.Contains(t, response, "data")
 		data := response["data"].(map[string]interface{})
-		assert.Equal(t, "file-mock123abc456def", data["file_id"])
-
-		// Verify vector store file details
-		vectorStoreFile := data["vector_store_file"].(map[string]interface{})
-		assert.Equal(t, "vs_test123", vectorStoreFile["vector_store_id"])
-		assert.Equal(t, "completed", vectorStoreFile["status"])
+		assert.Equal(t, lsmocks.MockPrivateVectorStoreID, data["vector_store_id"])
+		assert.Equal(t, "queued", data["status"])
 	})
 
 	t.Run("should return error when file is missing", func(t *testing.T) {
-		body, contentType, err := createMultipartFormData("", "", "vs_test123", "", "")
+		body, contentType, err := createMultipartFormData("", "", lsmocks.MockPrivateVectorStoreID, "", "")
 		assert.NoError(t, err)
 
 		req, err := http.NewRequest(http.MethodPost, "/gen-ai/api/v1/files/upload?namespace=test-namespace", bytes.NewReader(body))
@@ -237,9 +236,9 @@ func TestLlamaStackUploadFileHandler(t *testing.T) {
 
 	t.Run("should use unified repository pattern", func(t *testing.T) {
 		assert.NotNil(t, app.repositories)
-		assert.NotNil(t, app.repositories.Files)
+		assert.NotNil(t, app.repositories.IngestionJobs)
 
-		body, contentType, err := createMultipartFormData("test.txt", "Test content", "vs_test123", "assistants", "")
+		body, contentType, err := createMultipartFormData("test.txt", "Test content", lsmocks.MockPrivateVectorStoreID, "assistants", "")
 		assert.NoError(t, err)
 
 		req, err := http.NewRequest(http.MethodPost, "/gen-ai/api/v1/files/upload?namespace=test-namespace", bytes.NewReader(body))
@@ -254,9 +253,9 @@ func TestLlamaStackUploadFileHandler(t *testing.T) {
 		rr := httptest.NewRecorder()
 		app.LlamaStackUploadFileHandler(rr, req, nil)
 
-		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, http.StatusAccepted, rr.Code)
 
-		// Verify response structure matches FileUploadResult
+		// Verify response structure matches IngestionJob
 		var response map[string]interface{}
 		respBody, err := io.ReadAll(rr.Result().Body)
 		assert.NoError(t, err)
@@ -269,12 +268,10 @@ func TestLlamaStackUploadFileHandler(t *testing.T) {
 		assert.Contains(t, response, "data")
 		data := response["data"].(map[string]interface{})
 
-		// Should have FileUploadResult structure
-		assert.Contains(t, data, "file_id")
-		assert.Contains(t, data, "vector_store_file")
-
-		// Verify mock file ID
-		assert.Equal(t, "file-mock123abc456def", data["file_id"])
+		// Should have IngestionJob structure
+		assert.Contains(t, data, "id")
+		assert.Contains(t, data, "status")
+		assert.Contains(t, data, "size_bytes")
 	})
 
 	t.Run("should handle static chunking parameters", func(t *testing.T) {
@@ -286,7 +283,7 @@ func TestLlamaStackUploadFileHandler(t *testing.T) {
 		_, err = fileWriter.Write([]byte("Test content"))
 		assert.NoError(t, err)
 
-		err = writer.WriteField("vector_store_id", "vs_test123")
+		err = writer.WriteField("vector_store_id", lsmocks.MockPrivateVectorStoreID)
 		assert.NoError(t, err)
 		err = writer.WriteField("chunking_type", "static")
 		assert.NoError(t, err)
@@ -308,7 +305,7 @@ func TestLlamaStackUploadFileHandler(t *testing.T) {
 		rr := httptest.NewRecorder()
 		app.LlamaStackUploadFileHandler(rr, req, nil)
 
-		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, http.StatusAccepted, rr.Code)
 
 		responseBody, err := io.ReadAll(rr.Result().Body)
 		assert.NoError(t, err)
@@ -321,7 +318,72 @@ func TestLlamaStackUploadFileHandler(t *testing.T) {
 		// Verify envelope structure
 		assert.Contains(t, response, "data")
 		data := response["data"].(map[string]interface{})
-		assert.Equal(t, "file-mock123abc456def", data["file_id"])
+		assert.Equal(t, "test.txt", data["filename"])
+	})
+}
+
+func TestLlamaStackUploadVisionFiles(t *testing.T) {
+	// Create test app with mock client (lightweight approach)
+	app := App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
+		},
+		llamaStackClientFactory: lsmocks.NewMockClientFactory(),
+		repositories:            repositories.NewRepositories(),
+	}
+
+	upload := func(t *testing.T, files map[string]string, fields map[string]string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for filename, content := range files {
+			fileWriter, err := writer.CreateFormFile("file", filename)
+			assert.NoError(t, err)
+			_, err = fileWriter.Write([]byte(content))
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, writer.WriteField("purpose", constants.VisionFilePurpose))
+		for name, value := range fields {
+			assert.NoError(t, writer.WriteField(name, value))
+		}
+		assert.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/gen-ai/api/v1/files/upload?namespace="+testutil.TestNamespace, &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		req = req.WithContext(context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient))
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackUploadFileHandler(rr, req, nil)
+		return rr
+	}
+
+	t.Run("should upload images without adding them to a vector store", func(t *testing.T) {
+		rr := upload(t, map[string]string{"cat.png": "\x89PNG\r\n\x1a\nmock image"}, nil)
+		assert.Equal(t, http.StatusCreated, rr.Code)
+
+		var response VisionFilesEnvelope
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Len(t, response.Data, 1)
+		assert.Equal(t, "file-mock123abc456def", response.Data[0].FileID)
+		assert.Nil(t, response.Data[0].VectorStoreFile)
+	})
+
+	t.Run("should reject files that are not images", func(t *testing.T) {
+		// The content is sniffed, so the extension does not matter
+		rr := upload(t, map[string]string{"cat.png": "just some text"}, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "images must be one of")
+	})
+
+	t.Run("should reject vision uploads into a vector store", func(t *testing.T) {
+		rr := upload(t, map[string]string{"cat.png": "\x89PNG\r\n\x1a\nmock image"}, map[string]string{"vector_store_id": "vs_mock123"})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should require a file", func(t *testing.T) {
+		rr := upload(t, nil, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	})
 }
 
@@ -330,7 +392,8 @@ func TestLlamaStackListFilesHandler(t *testing.T) {
 	llamaStackClientFactory := lsmocks.NewMockClientFactory()
 	app := App{
 		config: config.EnvConfig{
-			Port: 4000,
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
 		},
 		llamaStackClientFactory: llamaStackClientFactory,
 		repositories:            repositories.NewRepositories(),
@@ -398,6 +461,47 @@ func TestLlamaStackListFilesHandler(t *testing.T) {
 		assert.Len(t, data, 2) // Mock returns 2 files regardless of parameters
 	})
 
+	t.Run("should apply the limit to the readable files", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, constants.FilesListPath+"?namespace=default&limit=1", nil)
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, "default")
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token-mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackListFilesHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response struct {
+			Data []openai.FileObject `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Len(t, response.Data, 1)
+	})
+
+	t.Run("should not list files in vector stores the caller cannot read", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, constants.FilesListPath+"?namespace=default", nil)
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, "default")
+		llamaStackClient := singleVectorStoreClient{
+			LlamaStackClientInterface: app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token-mock", false, nil),
+			owner:                     lsmocks.MockVectorStoreOtherOwner,
+		}
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamastack.LlamaStackClientInterface(llamaStackClient))
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackListFilesHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response struct {
+			Data []openai.FileObject `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Empty(t, response.Data)
+	})
+
 	t.Run("invalid limit parameter", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, constants.FilesListPath+"?namespace=default&limit=invalid", nil)
 		// Simulate AttachNamespace and AttachLlamaStackClient middleware
@@ -418,18 +522,51 @@ func TestLlamaStackDeleteFileHandler(t *testing.T) {
 	llamaStackClientFactory := lsmocks.NewMockClientFactory()
 	app := App{
 		config: config.EnvConfig{
-			Port: 4000,
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
 		},
 		llamaStackClientFactory: llamaStackClientFactory,
 		repositories:            repositories.NewRepositories(),
 	}
 
+	deleteFile := func(fileID string, llamaStackClient llamastack.LlamaStackClientInterface) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, constants.FilesDeletePath+"?namespace=default&file_id="+fileID, nil)
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, "default")
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackDeleteFileHandler(rr, req, nil)
+		return rr
+	}
+
+	t.Run("should not delete files that are in no vector store", func(t *testing.T) {
+		rr := deleteFile("file-test123", app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token-mock", false, nil))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should not delete files in vector stores the caller cannot read", func(t *testing.T) {
+		rr := deleteFile("file-mock123abc456def", singleVectorStoreClient{
+			LlamaStackClientInterface: app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token-mock", false, nil),
+			owner:                     lsmocks.MockVectorStoreOtherOwner,
+		})
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should not delete files that are also in vector stores of other users", func(t *testing.T) {
+		rr := deleteFile("file-mock123abc456def", app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token-mock", false, nil))
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
 	t.Run("successful delete file", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodDelete, constants.FilesDeletePath+"?namespace=default&file_id=file-test123", nil)
+		req := httptest.NewRequest(http.MethodDelete, constants.FilesDeletePath+"?namespace=default&file_id=file-mock123abc456def", nil)
 		// Simulate AttachNamespace and AttachLlamaStackClient middleware
 		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, "default")
-		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token-mock", false, nil)
-		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		llamaStackClient := singleVectorStoreClient{
+			LlamaStackClientInterface: app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token-mock", false, nil),
+			owner:                     anonymousUsername,
+		}
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamastack.LlamaStackClientInterface(llamaStackClient))
 		req = req.WithContext(ctx)
 
 		rr := httptest.NewRecorder()
@@ -448,7 +585,7 @@ func TestLlamaStackDeleteFileHandler(t *testing.T) {
 		// Verify envelope structure
 		assert.Contains(t, response, "data")
 		data := response["data"].(map[string]interface{})
-		assert.Equal(t, "file-test123", data["id"])
+		assert.Equal(t, "file-mock123abc456def", data["id"])
 		assert.Equal(t, "file", data["object"])
 		assert.Equal(t, true, data["deleted"])
 	})
@@ -479,3 +616,20 @@ func TestLlamaStackDeleteFileHandler(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	})
 }
+
+// singleVectorStoreClient is the mock LlamaStack client with a single private vector store of the given owner
+type singleVectorStoreClient struct {
+	llamastack.LlamaStackClientInterface
+	owner string
+}
+
+func (c singleVectorStoreClient) ListVectorStores(ctx context.Context, params llamastack.ListVectorStoresParams) ([]openai.VectorStore, error) {
+	return []openai.VectorStore{{
+		ID:     lsmocks.MockPrivateVectorStoreID,
+		Object: "vector_store",
+		Metadata: map[string]string{
+			constants.VectorStoreOwnerKey:      c.owner,
+			constants.VectorStoreVisibilityKey: constants.VectorStoreVisibilityPrivate,
+		},
+	}}, nil
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+)
+
+type IngestionJobEnvelope = Envelope[*llamastack.IngestionJob, None]
+
+// SSE event names of the streamed ingestion progress
+const (
+	ingestionJobEventProgress = "progress" // The job changed, sent first with the current state
+	ingestionJobEventDone     = "done"     // The job finished, sent last
+)
+
+// ingestionJobKeepAlive is how often a comment is sent on an idle progress stream so proxies keep it open
+const ingestionJobKeepAlive = 15 * time.Second
+
+// IngestionJobGetHandler handles GET /gen-ai/api/v1/lsd/ingestion-jobs/:id.
+// It returns the state of the job, or streams it as server-sent events until the job finishes
+// when the client accepts text/event-stream or sets stream=true.
+func (app *App) IngestionJobGetHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	namespace, owner, ok := app.conversationScope(w, r)
+	if !ok {
+		return
+	}
+
+	if wantsIngestionJobStream(r) {
+		app.streamIngestionJob(w, r, namespace, owner, ps.ByName("id"))
+		return
+	}
+
+	job, err := app.repositories.IngestionJobs.GetJob(namespace, owner, ps.ByName("id"))
+	if err != nil {
+		app.handleIngestionJobError(w, r, err)
+		return
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, IngestionJobEnvelope{Data: job}, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// IngestionJobCancelHandler handles POST /gen-ai/api/v1/lsd/ingestion-jobs/:id/cancel.
+func (app *App) IngestionJobCancelHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	namespace, owner, ok := app.conversationScope(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := app.repositories.IngestionJobs.CancelJob(namespace, owner, ps.ByName("id"))
+	if err != nil {
+		app.handleIngestionJobError(w, r, err)
+		return
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, IngestionJobEnvelope{Data: job}, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// IngestionJobRetryHandler handles POST /gen-ai/api/v1/lsd/ingestion-jobs/:id/retry.
+// The job is retried with the credentials of the current request.
+func (app *App) IngestionJobRetryHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	namespace, owner, ok := app.conversationScope(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := app.repositories.IngestionJobs.RetryJob(r.Context(), namespace, owner, ps.ByName("id"))
+	if err != nil {
+		app.handleIngestionJobError(w, r, err)
+		return
+	}
+
+	if err := app.WriteJSON(w, http.StatusAccepted, IngestionJobEnvelope{Data: job}, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// streamIngestionJob writes a progress event with the current state of the job and one for every change,
+// followed by a done event once the job finished
+func (app *App) streamIngestionJob(w http.ResponseWriter, r *http.Request, namespace, owner, id string) {
+	changes, unwatch, err := app.repositories.IngestionJobs.WatchJob(namespace, owner, id)
+	if err != nil {
+		app.handleIngestionJobError(w, r, err)
+		return
+	}
+	defer unwatch()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported by client", http.StatusNotImplemented)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache, no-transform")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(ingestionJobKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		job, err := app.repositories.IngestionJobs.GetJob(namespace, owner, id)
+		if err != nil {
+			// The job was forgotten while being watched
+			return
+		}
+
+		event := ingestionJobEventProgress
+		if job.Finished() {
+			event = ingestionJobEventDone
+		}
+		if err := writeIngestionJobEvent(w, event, job); err != nil {
+			app.logger.Error("Failed to write ingestion job event", "error", err, "job_id", id)
+			return
+		}
+		flusher.Flush()
+		if job.Finished() {
+			return
+		}
+
+	wait:
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-changes:
+				break wait
+			case <-keepAlive.C:
+				if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// handleIngestionJobError maps ingestion job errors to HTTP responses
+func (app *App) handleIngestionJobError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, llamastack.ErrIngestionJobNotFound):
+		app.notFoundResponse(w, r)
+	case errors.Is(err, llamastack.ErrIngestionJobFinished), errors.Is(err, llamastack.ErrIngestionJobNotRetryable),
+		errors.Is(err, llamastack.ErrIngestionJobExpired):
+		app.errorResponse(w, r, &integrations.HTTPError{
+			StatusCode: http.StatusConflict,
+			ErrorResponse: integrations.ErrorResponse{
+				Code:    strconv.Itoa(http.StatusConflict),
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, llamastack.ErrIngestionQueueFull):
+		app.errorResponse(w, r, &integrations.HTTPError{
+			StatusCode: http.StatusServiceUnavailable,
+			ErrorResponse: integrations.ErrorResponse{
+				Code:    strconv.Itoa(http.StatusServiceUnavailable),
+				Message: err.Error(),
+			},
+		})
+	case errors.Is(err, llamastack.ErrIngestionBufferFull):
+		app.errorResponse(w, r, &integrations.HTTPError{
+			StatusCode: http.StatusTooManyRequests,
+			ErrorResponse: integrations.ErrorResponse{
+				Code:    strconv.Itoa(http.StatusTooManyRequests),
+				Message: err.Error(),
+			},
+		})
+	default:
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// wantsIngestionJobStream reports whether the client asked for the job progress as server-sent events
+func wantsIngestionJobStream(r *http.Request) bool {
+	return r.URL.Query().Get("stream") == "true" || strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+func writeIngestionJobEvent(w io.Writer, event string, job *llamastack.IngestionJob) error {
+	eventData, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ingestion job event: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, eventData); err != nil {
+		return fmt.Errorf("failed to write ingestion job event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/opendatahub-io/gen-ai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngestionJobHandlers(t *testing.T) {
+	llamaStackClientFactory := lsmocks.NewMockClientFactory()
+	repos := repositories.NewRepositories()
+	repos.IngestionJobs = repositories.NewIngestionJobsRepository(llamastack.NewIngestionManager(llamastack.IngestionManagerConfig{
+		Workers:      1,
+		QueueSize:    10,
+		PollInterval: time.Millisecond,
+		Timeout:      time.Minute,
+		Retention:    time.Hour,
+	}, nil))
+	t.Cleanup(func() { _ = repos.IngestionJobs.Close() })
+
+	app := App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
+		},
+		llamaStackClientFactory: llamaStackClientFactory,
+		repositories:            repos,
+	}
+
+	// Helper function to call a handler with the middleware context in place
+	serve := func(handler httprouter.Handle, method, target string, body *bytes.Buffer, contentType, jobID string) *httptest.ResponseRecorder {
+		if body == nil {
+			body = &bytes.Buffer{}
+		}
+		req := httptest.NewRequest(method, target, body)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		handler(rr, req, httprouter.Params{{Key: "id", Value: jobID}})
+		return rr
+	}
+
+	upload := func(t *testing.T) llamastack.IngestionJob {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		fileWriter, err := writer.CreateFormFile("file", "guide.pdf")
+		require.NoError(t, err)
+		_, err = fileWriter.Write([]byte("document content"))
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteField("vector_store_id", "vs_test123"))
+		require.NoError(t, writer.Close())
+
+		rr := serve(app.LlamaStackUploadFileHandler, http.MethodPost, constants.FilesUploadPath+"?namespace="+testutil.TestNamespace, &body, writer.FormDataContentType(), "")
+		require.Equal(t, http.StatusAccepted, rr.Code)
+
+		var response IngestionJobEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return *response.Data
+	}
+
+	getJob := func(t *testing.T, id string) (int, *llamastack.IngestionJob) {
+		rr := serve(app.IngestionJobGetHandler, http.MethodGet, "/api/v1/lsd/ingestion-jobs/"+id+"?namespace="+testutil.TestNamespace, nil, "", id)
+		if rr.Code != http.StatusOK {
+			return rr.Code, nil
+		}
+		var response IngestionJobEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return rr.Code, response.Data
+	}
+
+	t.Run("should return a job right away and complete it in the background", func(t *testing.T) {
+		job := upload(t)
+		assert.NotEmpty(t, job.ID)
+		assert.Equal(t, "guide.pdf", job.Filename)
+		assert.Equal(t, "vs_test123", job.VectorStoreID)
+		assert.Equal(t, int64(len("document content")), job.SizeBytes)
+
+		var completed *llamastack.IngestionJob
+		require.Eventually(t, func() bool {
+			code, current := getJob(t, job.ID)
+			require.Equal(t, http.StatusOK, code)
+			completed = current
+			return current.Status == llamastack.IngestionJobCompleted
+		}, 5*time.Second, 5*time.Millisecond)
+
+		assert.Equal(t, "file-mock123abc456def", completed.FileID)
+		assert.Equal(t, 3, completed.ChunkCount)
+		assert.Equal(t, int64(2048), completed.UsageBytes)
+	})
+
+	t.Run("should ingest several files as one batch and report each file", func(t *testing.T) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for _, name := range []string{"intro.md", "setup.md"} {
+			fileWriter, err := writer.CreateFormFile("file", name)
+			require.NoError(t, err)
+			_, err = fileWriter.Write([]byte("content of " + name))
+			require.NoError(t, err)
+		}
+		require.NoError(t, writer.WriteField("vector_store_id", "vs_test123"))
+		require.NoError(t, writer.Close())
+
+		rr := serve(app.LlamaStackUploadFileHandler, http.MethodPost, constants.FilesUploadPath+"?namespace="+testutil.TestNamespace, &body, writer.FormDataContentType(), "")
+		require.Equal(t, http.StatusAccepted, rr.Code)
+
+		var response IngestionJobEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		job := response.Data
+		assert.Equal(t, "2 files", job.Filename)
+		require.Len(t, job.Files, 2)
+		assert.Equal(t, "intro.md", job.Files[0].Filename)
+
+		require.Eventually(t, func() bool {
+			_, job = getJob(t, job.ID)
+			return job.Status == llamastack.IngestionJobCompleted
+		}, 5*time.Second, 5*time.Millisecond)
+		assert.Equal(t, lsmocks.MockFileBatchID, job.BatchID)
+		for _, file := range job.Files {
+			assert.Equal(t, llamastack.IngestionJobCompleted, file.Status)
+			assert.Equal(t, 3, file.ChunkCount)
+		}
+	})
+
+	t.Run("should ingest a document from a URL", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<h1>Onboarding</h1>"))
+		}))
+		defer server.Close()
+
+		// The test server listens on loopback, which the default client refuses to fetch from
+		repos.DocumentSources = repositories.NewDocumentSourcesRepository(server.Client(), server.Client())
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		require.NoError(t, writer.WriteField("source_uri", server.URL+"/wiki/onboarding.html"))
+		require.NoError(t, writer.WriteField("vector_store_id", "vs_test123"))
+		require.NoError(t, writer.Close())
+
+		rr := serve(app.LlamaStackUploadFileHandler, http.MethodPost, constants.FilesUploadPath+"?namespace="+testutil.TestNamespace, &body, writer.FormDataContentType(), "")
+		require.Equal(t, http.StatusAccepted, rr.Code)
+
+		var response IngestionJobEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		job := response.Data
+		assert.Equal(t, "onboarding.html", job.Filename)
+		assert.Equal(t, server.URL+"/wiki/onboarding.html", job.SourceURI)
+
+		require.Eventually(t, func() bool {
+			_, job = getJob(t, job.ID)
+			return job.Status == llamastack.IngestionJobCompleted
+		}, 5*time.Second, 5*time.Millisecond)
+		assert.Equal(t, int64(len("<h1>Onboarding</h1>")), job.SizeBytes)
+	})
+
+	t.Run("should reject invalid source URIs", func(t *testing.T) {
+		for _, sourceURI := range []string{"ftp://example.com/guide.pdf", "s3://docs/guide.pdf"} {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			require.NoError(t, writer.WriteField("source_uri", sourceURI))
+			require.NoError(t, writer.WriteField("vector_store_id", "vs_test123"))
+			require.NoError(t, writer.Close())
+
+			rr := serve(app.LlamaStackUploadFileHandler, http.MethodPost, constants.FilesUploadPath+"?namespace="+testutil.TestNamespace, &body, writer.FormDataContentType(), "")
+			assert.Equal(t, http.StatusBadRequest, rr.Code, sourceURI)
+		}
+	})
+
+	t.Run("should tag the documents with the given attributes", func(t *testing.T) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		fileWriter, err := writer.CreateFormFile("file", "expenses.pdf")
+		require.NoError(t, err)
+		_, err = fileWriter.Write([]byte("document content"))
+		require.NoError(t, err)
+		require.NoError(t, writer.WriteField("vector_store_id", "vs_test123"))
+		require.NoError(t, writer.WriteField("attributes", `{"department": "finance", "doc_type": "policy", "year": 2024}`))
+		require.NoError(t, writer.Close())
+
+		rr := serve(app.LlamaStackUploadFileHandler, http.MethodPost, constants.FilesUploadPath+"?namespace="+testutil.TestNamespace, &body, writer.FormDataContentType(), "")
+		require.Equal(t, http.StatusAccepted, rr.Code)
+
+		var response IngestionJobEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, map[string]interface{}{"department": "finance", "doc_type": "policy", "year": float64(2024)}, response.Data.Attributes)
+	})
+
+	t.Run("should reject invalid attributes", func(t *testing.T) {
+		for _, attributes := range []string{`["finance"]`, `{"tags": ["a", "b"]}`, `{"source_uri": "https://example.com"}`} {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			fileWriter, err := writer.CreateFormFile("file", "expenses.pdf")
+			require.NoError(t, err)
+			_, err = fileWriter.Write([]byte("document content"))
+			require.NoError(t, err)
+			require.NoError(t, writer.WriteField("vector_store_id", "vs_test123"))
+			require.NoError(t, writer.WriteField("attributes", attributes))
+			require.NoError(t, writer.Close())
+
+			rr := serve(app.LlamaStackUploadFileHandler, http.MethodPost, constants.FilesUploadPath+"?namespace="+testutil.TestNamespace, &body, writer.FormDataContentType(), "")
+			assert.Equal(t, http.StatusBadRequest, rr.Code, attributes)
+		}
+	})
+
+	t.Run("should reject uploads without documents", func(t *testing.T) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		require.NoError(t, writer.WriteField("vector_store_id", "vs_test123"))
+		require.NoError(t, writer.Close())
+
+		rr := serve(app.LlamaStackUploadFileHandler, http.MethodPost, constants.FilesUploadPath+"?namespace="+testutil.TestNamespace, &body, writer.FormDataContentType(), "")
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should stream progress until the job finished", func(t *testing.T) {
+		job := upload(t)
+
+		rr := serve(app.IngestionJobGetHandler, http.MethodGet, "/api/v1/lsd/ingestion-jobs/"+job.ID+"?namespace="+testutil.TestNamespace+"&stream=true", nil, "", job.ID)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/event-stream; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), "event: progress\n")
+		assert.Contains(t, rr.Body.String(), "event: done\ndata: ")
+		assert.Contains(t, rr.Body.String(), `"status":"completed"`)
+	})
+
+	t.Run("should not cancel or retry a completed job", func(t *testing.T) {
+		job := upload(t)
+		require.Eventually(t, func() bool {
+			_, current := getJob(t, job.ID)
+			return current.Status == llamastack.IngestionJobCompleted
+		}, 5*time.Second, 5*time.Millisecond)
+
+		rr := serve(app.IngestionJobCancelHandler, http.MethodPost, "/api/v1/lsd/ingestion-jobs/"+job.ID+"/cancel?namespace="+testutil.TestNamespace, nil, "", job.ID)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+
+		rr = serve(app.IngestionJobRetryHandler, http.MethodPost, "/api/v1/lsd/ingestion-jobs/"+job.ID+"/retry?namespace="+testutil.TestNamespace, nil, "", job.ID)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("should return 404 for unknown jobs", func(t *testing.T) {
+		code, _ := getJob(t, "unknown")
+		assert.Equal(t, http.StatusNotFound, code)
+
+		rr := serve(app.IngestionJobCancelHandler, http.MethodPost, "/api/v1/lsd/ingestion-jobs/unknown/cancel?namespace="+testutil.TestNamespace, nil, "", "unknown")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+)
+
+// mcpApprovalRequestType is the output item type of an MCP tool call waiting for approval
+const mcpApprovalRequestType = "mcp_approval_request"
+
+// MCPApprovalRequest represents the request body for approving or denying a pending MCP tool call.
+// The continuation is a new response, so it takes the settings of a response request, which mean the same as there.
+// The model defaults to the model of the pending response, and the pending response is the previous response.
+type MCPApprovalRequest struct {
+	CreateResponseRequest
+	ResponseID        string `json:"response_id"`         // Response that is waiting for approval
+	ApprovalRequestID string `json:"approval_request_id"` // ID of the mcp_approval_request output item
+	Approve           *bool  `json:"approve"`             // true runs the tool call, false denies it
+	Reason            string `json:"reason,omitempty"`    // Optional explanation passed to the model
+}
+
+// LlamaStackResponseApprovalHandler handles POST /gen-ai/api/v1/lsd/responses/approvals
+func (app *App) LlamaStackResponseApprovalHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	var approvalRequest MCPApprovalRequest
+	if !app.decodeResponseRequest(w, r, &approvalRequest) {
+		return
+	}
+	if err := approvalRequest.validate(); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	// Look up the pending tool call in the response that is waiting for approval
+	pendingResponse, err := app.repositories.Responses.GetResponse(ctx, approvalRequest.ResponseID)
+	if err != nil {
+		app.badRequestResponse(w, r, fmt.Errorf("invalid response ID: %w", err))
+		return
+	}
+	responseData := convertToResponseData(pendingResponse)
+
+	var pendingCall *OutputItem
+	for i := range responseData.Output {
+		item := &responseData.Output[i]
+		if item.Type == mcpApprovalRequestType && item.ID == approvalRequest.ApprovalRequestID {
+			pendingCall = item
+			break
+		}
+	}
+	if pendingCall == nil {
+		app.badRequestResponse(w, r, fmt.Errorf("approval request %s not found in response %s", approvalRequest.ApprovalRequestID, approvalRequest.ResponseID))
+		return
+	}
+
+	// LlamaStack runs the approved call against the tools of the new request
+	serverConfigured := false
+	for _, server := range approvalRequest.MCPServers {
+		if server.ServerLabel == pendingCall.ServerLabel {
+			serverConfigured = true
+			break
+		}
+	}
+	if !serverConfigured {
+		app.badRequestResponse(w, r, fmt.Errorf("mcp_servers must include server %s of the pending tool call", pendingCall.ServerLabel))
+		return
+	}
+
+	createRequest := approvalRequest.CreateResponseRequest
+	if createRequest.Model == "" {
+		createRequest.Model = responseData.Model
+	}
+	createRequest.PreviousResponseID = approvalRequest.ResponseID
+	createRequest.mcpApprovals = []llamastack.MCPApprovalResponseParam{
+		{
+			ApprovalRequestID: approvalRequest.ApprovalRequestID,
+			Approve:           *approvalRequest.Approve,
+			Reason:            approvalRequest.Reason,
+		},
+	}
+	app.createResponse(w, r, createRequest)
+}
+
+// validate checks the approval decision and the fields that differ from a response request
+func (req *MCPApprovalRequest) validate() error {
+	if req.ResponseID == "" {
+		return errors.New("response_id is required")
+	}
+	if req.ApprovalRequestID == "" {
+		return errors.New("approval_request_id is required")
+	}
+	if req.Approve == nil {
+		return errors.New("approve is required")
+	}
+
+	// The continuation answers the pending tool call of the previous response and has no input of its own
+	if req.PreviousResponseID != "" {
+		return errors.New("previous_response_id cannot be used to answer an approval, use response_id")
+	}
+	if req.Input != "" || len(req.InputContent) > 0 || len(req.ChatContext) > 0 {
+		return errors.New("input, input_content and chat_context cannot be used to answer an approval")
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go/v2/responses"
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/opendatahub-io/gen-ai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLlamaStackResponseApprovalHandler(t *testing.T) {
+	mockClient := lsmocks.NewMockLlamaStackClient()
+	llamaStackClientFactory := lsmocks.NewMockClientFactory()
+	llamaStackClientFactory.SetMockClient(mockClient)
+	app := App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
+		},
+		llamaStackClientFactory: llamaStackClientFactory,
+		repositories:            repositories.NewRepositories(),
+	}
+
+	githubServer := []MCPServer{
+		{
+			ServerLabel:     "github",
+			ServerURL:       "https://api.githubcopilot.com/mcp/x/repos/readonly",
+			RequireApproval: &MCPApprovalPolicy{Setting: "always"},
+		},
+	}
+
+	// Helper function to send an approval request with the mock client in context
+	sendApproval := func(t *testing.T, payload interface{}) *httptest.ResponseRecorder {
+		jsonData, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, "/gen-ai/api/v1/lsd/responses/approvals?namespace="+testutil.TestNamespace, bytes.NewBuffer(jsonData))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackResponseApprovalHandler(rr, req, nil)
+		return rr
+	}
+
+	decodeResponseData := func(t *testing.T, rr *httptest.ResponseRecorder) ResponseData {
+		var data ResponseData
+		response := llamastack.APIResponse{Data: &data}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return data
+	}
+
+	approve := true
+	deny := false
+
+	t.Run("should run the tool call and continue the response when approved", func(t *testing.T) {
+		rr := sendApproval(t, MCPApprovalRequest{
+			ResponseID:        lsmocks.MockApprovalResponseID,
+			ApprovalRequestID: lsmocks.MockApprovalRequestID,
+			Approve:           &approve,
+			CreateResponseRequest: CreateResponseRequest{
+				MCPServers: githubServer,
+			},
+		})
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		data := decodeResponseData(t, rr)
+		assert.Equal(t, lsmocks.MockApprovalResponseID, data.PreviousResponseID)
+		assert.Equal(t, "llama-3.1-8b", data.Model, "model should default to the pending response's model")
+		assert.False(t, data.RequiresApproval)
+
+		var hasToolCall bool
+		for _, item := range data.Output {
+			if item.Type == "mcp_call" {
+				hasToolCall = true
+				assert.Equal(t, "get_latest_release", item.Name)
+			}
+		}
+		assert.True(t, hasToolCall, "approved tool call should run")
+		assert.Contains(t, extractOutputText(&data), "approved GitHub MCP tool call")
+	})
+
+	t.Run("should continue without the tool call when denied", func(t *testing.T) {
+		rr := sendApproval(t, MCPApprovalRequest{
+			ResponseID:        lsmocks.MockApprovalResponseID,
+			ApprovalRequestID: lsmocks.MockApprovalRequestID,
+			Approve:           &deny,
+			Reason:            "Not allowed to query GitHub",
+			CreateResponseRequest: CreateResponseRequest{
+				MCPServers: githubServer,
+			},
+		})
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		data := decodeResponseData(t, rr)
+		for _, item := range data.Output {
+			assert.NotEqual(t, "mcp_call", item.Type, "denied tool call must not run")
+		}
+		assert.Contains(t, extractOutputText(&data), "was denied")
+	})
+
+	t.Run("should continue with the generation settings of the approval request", func(t *testing.T) {
+		temperature := 0.2
+		seed := int64(42)
+		rr := sendApproval(t, MCPApprovalRequest{
+			ResponseID:        lsmocks.MockApprovalResponseID,
+			ApprovalRequestID: lsmocks.MockApprovalRequestID,
+			Approve:           &approve,
+			CreateResponseRequest: CreateResponseRequest{
+				MCPServers:     githubServer,
+				Instructions:   "Answer in one sentence",
+				VectorStoreIDs: []string{lsmocks.MockSharedVectorStoreID},
+				Temperature:    &temperature,
+				Stop:           []string{"END"},
+				Seed:           &seed,
+			},
+		})
+
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		data := decodeResponseData(t, rr)
+		require.NotNil(t, data.Temperature)
+		assert.Equal(t, temperature, *data.Temperature)
+		assert.Equal(t, []string{"END"}, data.Stop)
+		require.NotNil(t, data.Seed)
+		assert.Equal(t, seed, *data.Seed)
+	})
+
+	t.Run("should check the vector stores and settings of the continuation", func(t *testing.T) {
+		rr := sendApproval(t, MCPApprovalRequest{
+			ResponseID:        lsmocks.MockApprovalResponseID,
+			ApprovalRequestID: lsmocks.MockApprovalRequestID,
+			Approve:           &approve,
+			CreateResponseRequest: CreateResponseRequest{
+				MCPServers:     githubServer,
+				VectorStoreIDs: []string{lsmocks.MockOtherUserVectorStoreID},
+			},
+		})
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr = sendApproval(t, MCPApprovalRequest{
+			ResponseID:        lsmocks.MockApprovalResponseID,
+			ApprovalRequestID: lsmocks.MockApprovalRequestID,
+			Approve:           &approve,
+			CreateResponseRequest: CreateResponseRequest{
+				MCPServers: githubServer,
+				Stop:       []string{"a", "b", "c", "d", "e"},
+			},
+		})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should find approval requests in responses returned by LlamaStack", func(t *testing.T) {
+		mockClient.SetGetResponseResult("resp_pending_jira", &lsmocks.MockResponse{
+			ID:     "resp_pending_jira",
+			Model:  "granite-3.3",
+			Status: "completed",
+			Output: []responses.ResponseOutputItemUnion{
+				{ID: "mcpr_jira1", Type: "mcp_approval_request", ServerLabel: "jira", Name: "create_issue", Arguments: "{}"},
+			},
+		})
+
+		rr := sendApproval(t, MCPApprovalRequest{
+			ResponseID:        "resp_pending_jira",
+			ApprovalRequestID: "mcpr_jira1",
+			Approve:           &approve,
+			CreateResponseRequest: CreateResponseRequest{
+				MCPServers: []MCPServer{{ServerLabel: "jira", ServerURL: "https://jira.example.com/mcp"}},
+			},
+		})
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		data := decodeResponseData(t, rr)
+		assert.Equal(t, "granite-3.3", data.Model)
+		assert.Equal(t, "resp_pending_jira", data.PreviousResponseID)
+	})
+
+	t.Run("should reject requests with missing or conflicting fields", func(t *testing.T) {
+		invalidRequests := map[string]MCPApprovalRequest{
+			"missing response_id":         {ApprovalRequestID: lsmocks.MockApprovalRequestID, Approve: &approve, CreateResponseRequest: CreateResponseRequest{MCPServers: githubServer}},
+			"missing approval_request_id": {ResponseID: lsmocks.MockApprovalResponseID, Approve: &approve, CreateResponseRequest: CreateResponseRequest{MCPServers: githubServer}},
+			"missing approve":             {ResponseID: lsmocks.MockApprovalResponseID, ApprovalRequestID: lsmocks.MockApprovalRequestID, CreateResponseRequest: CreateResponseRequest{MCPServers: githubServer}},
+			"a previous response":         {ResponseID: lsmocks.MockApprovalResponseID, ApprovalRequestID: lsmocks.MockApprovalRequestID, Approve: &approve, CreateResponseRequest: CreateResponseRequest{MCPServers: githubServer, PreviousResponseID: "resp_1"}},
+			"an input":                    {ResponseID: lsmocks.MockApprovalResponseID, ApprovalRequestID: lsmocks.MockApprovalRequestID, Approve: &approve, CreateResponseRequest: CreateResponseRequest{MCPServers: githubServer, Input: "Hi"}},
+		}
+
+		for name, payload := range invalidRequests {
+			t.Run(name, func(t *testing.T) {
+				rr := sendApproval(t, payload)
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
+			})
+		}
+	})
+
+	t.Run("should reject unknown approval request IDs", func(t *testing.T) {
+		rr := sendApproval(t, MCPApprovalRequest{
+			ResponseID:        lsmocks.MockApprovalResponseID,
+			ApprovalRequestID: "mcpr_unknown",
+			Approve:           &approve,
+			CreateResponseRequest: CreateResponseRequest{
+				MCPServers: githubServer,
+			},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "mcpr_unknown")
+	})
+
+	t.Run("should reject responses that cannot be found", func(t *testing.T) {
+		mockClient.SetGetResponseError("resp_missing", assert.AnError)
+
+		rr := sendApproval(t, MCPApprovalRequest{
+			ResponseID:        "resp_missing",
+			ApprovalRequestID: lsmocks.MockApprovalRequestID,
+			Approve:           &approve,
+			CreateResponseRequest: CreateResponseRequest{
+				MCPServers: githubServer,
+			},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should require the MCP server of the pending tool call", func(t *testing.T) {
+		rr := sendApproval(t, MCPApprovalRequest{
+			ResponseID:        lsmocks.MockApprovalResponseID,
+			ApprovalRequestID: lsmocks.MockApprovalRequestID,
+			Approve:           &approve,
+			CreateResponseRequest: CreateResponseRequest{
+				MCPServers: []MCPServer{{ServerLabel: "jira", ServerURL: "https://jira.example.com/mcp"}},
+			},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "github")
+	})
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+)
+
+// Annotation is a citation of the source of output text. file_citation and file_path annotations set the
+// FileCitation fields, url_citation annotations set the URLCitation fields.
+type Annotation struct {
+	Type string `json:"type"` // file_citation, url_citation, container_file_citation or file_path
+	FileCitation
+	URLCitation
+}
+
+// FileCitation cites a vector store file the text was retrieved from
+type FileCitation struct {
+	FileID   string `json:"file_id,omitempty"`
+	Filename string `json:"filename,omitempty"` // Resolved from the file when LlamaStack does not set it
+	Index    *int   `json:"index,omitempty"`    // Position in the text the citation belongs to
+	Text     string `json:"text,omitempty"`     // Best scoring chunk retrieved from the file, when search results are included
+}
+
+// URLCitation cites a web page the text is based on
+type URLCitation struct {
+	URL        string `json:"url,omitempty"`
+	Title      string `json:"title,omitempty"`
+	StartIndex *int   `json:"start_index,omitempty"` // First character of the cited text
+	EndIndex   *int   `json:"end_index,omitempty"`   // Character after the cited text
+}
+
+// citationResolver completes the citations and file search results of a response: it resolves the filenames of
+// files LlamaStack only reports by ID, and attaches the chunk retrieved from the cited file to file citations.
+// Filenames are looked up once per response, so one resolver is used for all events of a stream.
+type citationResolver struct {
+	ctx       context.Context
+	app       *App
+	filenames map[string]string
+	chunks    map[string]SearchResult // Best scoring search result of each file
+}
+
+func (app *App) newCitationResolver(ctx context.Context) *citationResolver {
+	return &citationResolver{
+		ctx:       ctx,
+		app:       app,
+		filenames: make(map[string]string),
+		chunks:    make(map[string]SearchResult),
+	}
+}
+
+// resolveResponse completes all output items of a response. Search results are collected first so that
+// citations can refer to them regardless of the order of the items.
+func (c *citationResolver) resolveResponse(response *ResponseData) {
+	if response == nil {
+		return
+	}
+	for i := range response.Output {
+		c.resolveSearchResults(&response.Output[i])
+	}
+	for i := range response.Output {
+		c.resolveContent(response.Output[i].Content)
+	}
+}
+
+// resolveEvent completes the item, content part, annotation or response a streaming event carries
+func (c *citationResolver) resolveEvent(event *StreamingEvent) {
+	if event.Item != nil {
+		c.resolveSearchResults(event.Item)
+		c.resolveContent(event.Item.Content)
+	}
+	if event.Part != nil {
+		c.resolveContent([]ContentItem{*event.Part})
+	}
+	if event.Annotation != nil {
+		c.resolveAnnotation(event.Annotation)
+	}
+	c.resolveResponse(event.Response)
+}
+
+// resolveSearchResults fills in the filenames of the results of a file search call and remembers the best
+// scoring result of each file for its citations
+func (c *citationResolver) resolveSearchResults(item *OutputItem) {
+	for i := range item.Results {
+		result := &item.Results[i]
+		if result.FileID == "" {
+			continue
+		}
+		if result.Filename == "" {
+			result.Filename = c.filename(result.FileID)
+		} else {
+			c.filenames[result.FileID] = result.Filename
+		}
+		if best, ok := c.chunks[result.FileID]; !ok || result.Score > best.Score {
+			c.chunks[result.FileID] = *result
+		}
+	}
+}
+
+// resolveContent completes the annotations of content parts, which share their annotations with the caller
+func (c *citationResolver) resolveContent(content []ContentItem) {
+	for i := range content {
+		for j := range content[i].Annotations {
+			c.resolveAnnotation(&content[i].Annotations[j])
+		}
+	}
+}
+
+// resolveAnnotation completes a file citation with its filename and retrieved chunk
+func (c *citationResolver) resolveAnnotation(annotation *Annotation) {
+	if annotation.FileID == "" {
+		return
+	}
+	if annotation.Filename == "" {
+		annotation.Filename = c.filename(annotation.FileID)
+	}
+	if annotation.Text == "" {
+		annotation.Text = c.chunks[annotation.FileID].Text
+	}
+}
+
+// filename returns the name of a file. Files that cannot be read keep an empty name rather than failing the
+// response, and are not looked up again.
+func (c *citationResolver) filename(fileID string) string {
+	if filename, ok := c.filenames[fileID]; ok {
+		return filename
+	}
+
+	var filename string
+	if file, err := c.app.repositories.Files.GetFile(c.ctx, fileID); err != nil {
+		c.app.logger.Debug("Failed to resolve the filename of a cited file", "file_id", fileID, "error", err)
+	} else {
+		filename = file.Filename
+	}
+	c.filenames[fileID] = filename
+	return filename
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/opendatahub-io/gen-ai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCitationResolver(t *testing.T) {
+	llamaStackClientFactory := lsmocks.NewMockClientFactory()
+	app := App{
+		logger:                  slog.Default(),
+		llamaStackClientFactory: llamaStackClientFactory,
+		repositories:            repositories.NewRepositories(),
+	}
+	llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+	ctx := context.WithValue(context.Background(), constants.LlamaStackClientKey, llamaStackClient)
+
+	decodeResponse := func(t *testing.T, raw string) ResponseData {
+		var response ResponseData
+		require.NoError(t, json.Unmarshal([]byte(raw), &response))
+		return response
+	}
+
+	t.Run("should resolve filenames and attach the retrieved chunks to file citations", func(t *testing.T) {
+		response := decodeResponse(t, `{"id":"resp_1","output":[
+			{"id":"fs_1","type":"file_search_call","results":[
+				{"file_id":"file-mock123abc456def","score":0.42,"text":"Older chunk"},
+				{"file_id":"file-mock123abc456def","score":0.87,"text":"Travel is booked through the portal","attributes":{"page":3}}
+			]},
+			{"id":"msg_1","type":"message","content":[{"type":"output_text","text":"Book travel in the portal.","annotations":[
+				{"type":"file_citation","file_id":"file-mock123abc456def","index":26},
+				{"type":"file_citation","file_id":"file-mock789ghi012jkl","filename":"policy.pdf","index":26},
+				{"type":"url_citation","url":"https://example.com/travel","title":"Travel","start_index":0,"end_index":26}
+			]}]}
+		]}`)
+
+		app.newCitationResolver(ctx).resolveResponse(&response)
+
+		results := response.Output[0].Results
+		assert.Equal(t, "mock_document.txt", results[0].Filename)
+		assert.Equal(t, map[string]interface{}{"page": float64(3)}, results[1].Attributes)
+
+		annotations := response.Output[1].Content[0].Annotations
+		assert.Equal(t, "mock_document.txt", annotations[0].Filename)
+		assert.Equal(t, "Travel is booked through the portal", annotations[0].Text)
+		assert.Equal(t, 26, *annotations[0].Index)
+		assert.Equal(t, "policy.pdf", annotations[1].Filename, "filenames set by LlamaStack are kept")
+		assert.Empty(t, annotations[1].Text, "files without search results have no chunk")
+		assert.Equal(t, "https://example.com/travel", annotations[2].URL)
+		assert.Empty(t, annotations[2].Filename)
+	})
+
+	t.Run("should complete citations across the events of a stream", func(t *testing.T) {
+		citations := app.newCitationResolver(ctx)
+
+		searchEvent := &StreamingEvent{Type: "response.output_item.done", Item: &OutputItem{Type: "file_search_call", Results: []SearchResult{
+			{FileID: "file-mock789ghi012jkl", Score: 0.9, Text: "Expenses are reimbursed monthly"},
+		}}}
+		citations.resolveEvent(searchEvent)
+		assert.Equal(t, "mock_data.pdf", searchEvent.Item.Results[0].Filename)
+
+		index := 10
+		annotationEvent := &StreamingEvent{Type: "response.output_text.annotation.added", Annotation: &Annotation{
+			Type:         "file_citation",
+			FileCitation: FileCitation{FileID: "file-mock789ghi012jkl", Index: &index},
+		}}
+		citations.resolveEvent(annotationEvent)
+		assert.Equal(t, "mock_data.pdf", annotationEvent.Annotation.Filename)
+		assert.Equal(t, "Expenses are reimbursed monthly", annotationEvent.Annotation.Text)
+	})
+
+	t.Run("should leave the filename of unreadable files empty", func(t *testing.T) {
+		// Without a LlamaStack client in the context the file cannot be read
+		citations := app.newCitationResolver(context.Background())
+		annotation := &Annotation{Type: "file_citation", FileCitation: FileCitation{FileID: "file-mock123abc456def"}}
+
+		citations.resolveAnnotation(annotation)
+		assert.Empty(t, annotation.Filename)
+		assert.Contains(t, citations.filenames, "file-mock123abc456def", "failed lookups are not repeated")
+	})
+}
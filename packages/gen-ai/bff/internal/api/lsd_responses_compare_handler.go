@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
+)
+
+// CompareResponsesRequest represents the request body for comparing the responses of several models to one input.
+// It takes the fields of a response request, including RAG and MCP servers, other than the model and the
+// conversation to continue.
+type CompareResponsesRequest struct {
+	CreateResponseRequest
+	Models []string `json:"models"` // Distinct models to send the input to, in the order of the results
+}
+
+// ModelComparison is the response of one model of a comparison, with its usage and latency, or why it failed
+type ModelComparison struct {
+	Model    string         `json:"model"`
+	Response *ResponseData  `json:"response,omitempty"`
+	Error    *ResponseError `json:"error,omitempty"`
+}
+
+type ModelComparisonsEnvelope = Envelope[[]ModelComparison, None]
+
+// LlamaStackCompareResponsesHandler handles POST /gen-ai/api/v1/lsd/responses/compare.
+// It sends the same input to every model concurrently. Non-streaming comparisons return the responses in the order
+// of the models; streaming comparisons interleave the events of all models on one stream, tagged with their model.
+func (app *App) LlamaStackCompareResponsesHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	var compareRequest CompareResponsesRequest
+	if !app.decodeResponseRequest(w, r, &compareRequest) {
+		return
+	}
+	if err := compareRequest.validate(); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	// The request is validated once, for the first model
+	createRequest := compareRequest.CreateResponseRequest
+	createRequest.Model = compareRequest.Models[0]
+	params, _, ok := app.prepareResponse(w, r, createRequest)
+	if !ok {
+		return
+	}
+
+	// Each model gets the same params, with the MaaS token of its own model
+	modelParams := make([]llamastack.CreateResponseParams, len(compareRequest.Models))
+	for i, model := range compareRequest.Models {
+		modelParams[i] = params
+		if model != params.Model {
+			modelParams[i].Model = model
+			modelParams[i].ProviderData = app.getMaaSProviderData(ctx, model)
+		}
+	}
+
+	if compareRequest.Stream {
+		app.streamComparison(w, ctx, modelParams)
+	} else {
+		app.compareResponses(w, r, ctx, modelParams)
+	}
+}
+
+// validate checks the fields that differ from a response request
+func (req *CompareResponsesRequest) validate() error {
+	if req.Model != "" {
+		return errors.New("model cannot be used to compare responses, use models")
+	}
+	if len(req.Models) < constants.CompareMinModels || len(req.Models) > constants.CompareMaxModels {
+		return fmt.Errorf("models must list %d to %d models, got: %d", constants.CompareMinModels, constants.CompareMaxModels, len(req.Models))
+	}
+	seen := make(map[string]bool, len(req.Models))
+	for _, model := range req.Models {
+		if model == "" {
+			return errors.New("models cannot contain an empty model")
+		}
+		if seen[model] {
+			return fmt.Errorf("models must be distinct, got %q twice", model)
+		}
+		seen[model] = true
+	}
+
+	// Compared responses are not recorded, so there is no thread to continue
+	if req.ConversationID != "" {
+		return errors.New("conversation_id cannot be used to compare responses")
+	}
+	if req.PreviousResponseID != "" {
+		return errors.New("previous_response_id cannot be used to compare responses, use chat_context for conversation history")
+	}
+	return nil
+}
+
+// compareResponses generates the response of every model and returns them together
+func (app *App) compareResponses(w http.ResponseWriter, r *http.Request, ctx context.Context, modelParams []llamastack.CreateResponseParams) {
+	comparisons := make([]ModelComparison, len(modelParams))
+	var wg sync.WaitGroup
+	for i, params := range modelParams {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			comparisons[i] = ModelComparison{Model: params.Model}
+			responseData, err := app.generateResponse(ctx, params)
+			if err != nil {
+				comparisons[i].Error = app.comparisonError(params.Model, err)
+				return
+			}
+			comparisons[i].Response = responseData
+		}()
+	}
+	wg.Wait()
+
+	response := ModelComparisonsEnvelope{
+		Data: comparisons,
+	}
+
+	if err := app.WriteJSON(w, http.StatusCreated, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// streamComparison streams the responses of every model over one SSE stream. The events of a model keep their
+// order and sequence numbers, and its stream ends with its final response event or an error event.
+func (app *App) streamComparison(w http.ResponseWriter, ctx context.Context, modelParams []llamastack.CreateResponseParams) {
+	// Check if ResponseWriter supports streaming - fail fast if not
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported by client", http.StatusNotImplemented)
+		return
+	}
+
+	// The models fail independently, so the stream is opened before any of them responds
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache, no-transform")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Events of different models are written one at a time
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, params := range modelParams {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.streamComparedModel(ctx, params, func(event *StreamingEvent) error {
+				event.Model = params.Model
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err := writeStreamingEvent(w, event); err != nil {
+					return err
+				}
+				flusher.Flush()
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+}
+
+// streamComparedModel streams the response of one model of a comparison to emit
+func (app *App) streamComparedModel(ctx context.Context, params llamastack.CreateResponseParams, emit func(*StreamingEvent) error) {
+	meter := newResponseMeter()
+	stream, err := app.repositories.Responses.CreateResponseStream(ctx, params)
+	if _, ok := err.(*lsmocks.MockStreamError); ok {
+		// The mock client writes its events to the response itself, so its responses are sent whole instead
+		responseData, err := app.generateResponse(ctx, params)
+		if err != nil {
+			_ = emit(&StreamingEvent{Type: "error", Kind: StreamingEventKindError, Error: app.comparisonError(params.Model, err)})
+			return
+		}
+		_ = emit(&StreamingEvent{Type: "response.completed", Kind: StreamingEventKindLifecycle, Response: responseData})
+		return
+	}
+	if err != nil {
+		if !ModelNotFoundError(err) {
+			app.recordResponseUsage(ctx, params.Model, meter, nil)
+		}
+		_ = emit(&StreamingEvent{Type: "error", Kind: StreamingEventKindError, Error: app.comparisonError(params.Model, err)})
+		return
+	}
+	defer stream.Close()
+
+	app.forwardResponseStream(ctx, stream, params, meter, emit)
+}
+
+// comparisonError describes why a model of a comparison failed to respond. Server errors are logged rather than
+// returned, like the message of a server error response.
+func (app *App) comparisonError(model string, err error) *ResponseError {
+	if ModelNotFoundError(err) {
+		return &ResponseError{
+			Code:    strconv.Itoa(http.StatusNotFound),
+			Message: fmt.Sprintf("model '%s' not found or is not available", model),
+		}
+	}
+	app.logger.Error("Failed to generate compared response", "model", model, "error", err)
+	return &ResponseError{
+		Code:    strconv.Itoa(http.StatusInternalServerError),
+		Message: "the server encountered a problem and could not process your request",
+	}
+}
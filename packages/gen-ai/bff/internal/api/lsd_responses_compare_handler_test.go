@@ -0,0 +1,140 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/opendatahub-io/gen-ai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLlamaStackCompareResponsesHandler(t *testing.T) {
+	app := App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
+		},
+		llamaStackClientFactory: lsmocks.NewMockClientFactory(),
+		repositories:            repositories.NewRepositories(),
+	}
+
+	compare := func(t *testing.T, payload CompareResponsesRequest) *httptest.ResponseRecorder {
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/gen-ai/api/v1/lsd/responses/compare?namespace="+testutil.TestNamespace, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Simulate the namespace and AttachLlamaStackClient middleware
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCompareResponsesHandler(rr, req.WithContext(ctx), nil)
+		return rr
+	}
+
+	t.Run("should return the response of every model in order with usage and latency", func(t *testing.T) {
+		rr := compare(t, CompareResponsesRequest{
+			CreateResponseRequest: CreateResponseRequest{
+				Input:          "Tell me about AI",
+				VectorStoreIDs: []string{"vs_mock123"},
+			},
+			Models: []string{"llama-3.1-8b", "granite-3.3-8b", "mistral-7b"},
+		})
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+		var response ModelComparisonsEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.Len(t, response.Data, 3)
+		for i, model := range []string{"llama-3.1-8b", "granite-3.3-8b", "mistral-7b"} {
+			comparison := response.Data[i]
+			assert.Equal(t, model, comparison.Model)
+			assert.Nil(t, comparison.Error)
+			require.NotNil(t, comparison.Response)
+			assert.Equal(t, model, comparison.Response.Model)
+			require.NotNil(t, comparison.Response.Usage)
+			assert.Greater(t, comparison.Response.Usage.TotalTokens, int64(0))
+			assert.NotNil(t, comparison.Response.Latency)
+		}
+
+		// Every model is accounted for in the usage of the caller
+		report, err := app.repositories.Usage.GetUsageReport(context.Background(), testutil.TestNamespace, anonymousUsername, models.UsageReportOptions{})
+		require.NoError(t, err)
+		assert.Len(t, report.User.Models, 3)
+	})
+
+	t.Run("should reject invalid comparisons", func(t *testing.T) {
+		tests := map[string]CompareResponsesRequest{
+			"model instead of models":  {CreateResponseRequest: CreateResponseRequest{Input: "Hi", Model: "llama-3.1-8b"}, Models: []string{"llama-3.1-8b", "granite-3.3-8b"}},
+			"a single model":           {CreateResponseRequest: CreateResponseRequest{Input: "Hi"}, Models: []string{"llama-3.1-8b"}},
+			"too many models":          {CreateResponseRequest: CreateResponseRequest{Input: "Hi"}, Models: []string{"a", "b", "c", "d", "e"}},
+			"the same model twice":     {CreateResponseRequest: CreateResponseRequest{Input: "Hi"}, Models: []string{"llama-3.1-8b", "llama-3.1-8b"}},
+			"an empty model":           {CreateResponseRequest: CreateResponseRequest{Input: "Hi"}, Models: []string{"llama-3.1-8b", ""}},
+			"a conversation":           {CreateResponseRequest: CreateResponseRequest{Input: "Hi", ConversationID: "conv_1"}, Models: []string{"llama-3.1-8b", "granite-3.3-8b"}},
+			"a previous response":      {CreateResponseRequest: CreateResponseRequest{Input: "Hi", PreviousResponseID: "resp_1"}, Models: []string{"llama-3.1-8b", "granite-3.3-8b"}},
+			"no input":                 {Models: []string{"llama-3.1-8b", "granite-3.3-8b"}},
+			"an invalid response size": {CreateResponseRequest: CreateResponseRequest{Input: "Hi", MaxOutputTokens: new(int64)}, Models: []string{"llama-3.1-8b", "granite-3.3-8b"}},
+			"too many content parts": {CreateResponseRequest: CreateResponseRequest{Input: "Hi", ChatContext: []ChatContextMessage{
+				{Role: "user", Content: "First batch", ContentParts: imageParts(constants.InputContentMaxParts)},
+				{Role: "user", Content: "Second batch", ContentParts: imageParts(constants.InputContentMaxParts)},
+				{Role: "user", Content: "Third batch", ContentParts: imageParts(constants.InputContentMaxParts)},
+			}}, Models: []string{"llama-3.1-8b", "granite-3.3-8b"}},
+		}
+		for name, payload := range tests {
+			rr := compare(t, payload)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, name)
+		}
+	})
+
+	t.Run("should stream the responses of every model tagged by model", func(t *testing.T) {
+		payload := CompareResponsesRequest{
+			CreateResponseRequest: CreateResponseRequest{Input: "Hello", Stream: true},
+			Models:                []string{"llama-3.1-8b", "granite-3.3-8b"},
+		}
+		rr := compare(t, payload)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/event-stream; charset=utf-8", rr.Header().Get("Content-Type"))
+
+		completed := map[string]*ResponseData{}
+		for _, line := range strings.Split(rr.Body.String(), "\n") {
+			data, ok := strings.CutPrefix(line, "data: ")
+			if !ok {
+				continue
+			}
+			var event StreamingEvent
+			require.NoError(t, json.Unmarshal([]byte(data), &event))
+			if event.Type == "response.completed" {
+				completed[event.Model] = event.Response
+			}
+		}
+		require.Len(t, completed, 2)
+		for _, model := range payload.Models {
+			require.NotNil(t, completed[model], model)
+			assert.Equal(t, model, completed[model].Model)
+			assert.NotNil(t, completed[model].Latency)
+		}
+	})
+}
+
+// imageParts returns n image content parts referenced by file ID
+func imageParts(n int) []llamastack.InputContentPart {
+	parts := make([]llamastack.InputContentPart, n)
+	for i := range parts {
+		parts[i] = llamastack.InputContentPart{Type: "input_image", FileID: fmt.Sprintf("file-abc%03d", i)}
+	}
+	return parts
+}
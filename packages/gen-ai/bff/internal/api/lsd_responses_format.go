@@ -0,0 +1,148 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+)
+
+// responseFormatErrorType is the type of the output item that reports output not matching the response format
+const responseFormatErrorType = "response_format_error"
+
+// ResponseFormat asks the model for JSON output, like the text.format of the OpenAI Responses API.
+// json_object formats accept any JSON object, json_schema formats JSON matching the named schema.
+type ResponseFormat struct {
+	Type        string                 `json:"type"`                  // text, json_object or json_schema
+	Name        string                 `json:"name,omitempty"`        // Name of the schema (json_schema)
+	Description string                 `json:"description,omitempty"` // What the schema is for (json_schema)
+	Schema      map[string]interface{} `json:"schema,omitempty"`      // JSON schema of the output (json_schema)
+	Strict      bool                   `json:"strict,omitempty"`      // Follow the schema exactly (json_schema)
+}
+
+// params converts the format to its client form, and checks that its schema can validate output
+func (f *ResponseFormat) params() (*llamastack.ResponseFormat, error) {
+	if f == nil {
+		return nil, nil
+	}
+	format := &llamastack.ResponseFormat{
+		Type:        f.Type,
+		Name:        f.Name,
+		Description: f.Description,
+		Schema:      f.Schema,
+		Strict:      f.Strict,
+	}
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := newResponseFormatValidator(format); err != nil {
+		return nil, err
+	}
+	return format, nil
+}
+
+// responseFormatValidator checks that the text of completed responses matches their response format. The model
+// is asked for the format, but only constrained decoding guarantees it, which not every model server supports.
+type responseFormatValidator struct {
+	format *llamastack.ResponseFormat
+	schema *jsonschema.Resolved // Only set for json_schema formats
+}
+
+// newResponseFormatValidator compiles the schema of a format. A nil validator is returned for text output.
+func newResponseFormatValidator(format *llamastack.ResponseFormat) (*responseFormatValidator, error) {
+	if format == nil || format.Type == llamastack.ResponseFormatText {
+		return nil, nil
+	}
+
+	validator := &responseFormatValidator{format: format}
+	if format.Type == llamastack.ResponseFormatJSONSchema {
+		raw, err := json.Marshal(format.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+		var schema jsonschema.Schema
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+		if validator.schema, err = schema.Resolve(nil); err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+	}
+	return validator, nil
+}
+
+// validate checks the output text of a completed response. When it does not match the format, a
+// response_format_error item describing the violation is appended to the output and returned.
+func (v *responseFormatValidator) validate(response *ResponseData) *OutputItem {
+	if v == nil || response == nil || response.Status != "completed" || response.RequiresApproval {
+		return nil
+	}
+
+	text, ok := responseOutputText(response)
+	if !ok {
+		return nil
+	}
+
+	var violation string
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		violation = fmt.Sprintf("output is not valid JSON: %v", err)
+	} else if v.schema != nil {
+		if err := v.schema.Validate(value); err != nil {
+			violation = fmt.Sprintf("output does not match schema %s: %v", v.format.Name, err)
+		}
+	} else if _, isObject := value.(map[string]interface{}); !isObject {
+		violation = fmt.Sprintf("output must be a JSON object, got %s", jsonTypeName(value))
+	}
+	if violation == "" {
+		return nil
+	}
+
+	item := OutputItem{
+		Type:   responseFormatErrorType,
+		Status: "failed",
+		Name:   v.format.Name,
+		Error:  violation,
+		Output: text,
+	}
+	response.Output = append(response.Output, item)
+	return &response.Output[len(response.Output)-1]
+}
+
+// responseOutputText returns the output text of the last message of a response, where the model answers
+func responseOutputText(response *ResponseData) (string, bool) {
+	for i := len(response.Output) - 1; i >= 0; i-- {
+		item := response.Output[i]
+		if item.Type != "message" {
+			continue
+		}
+		var text strings.Builder
+		for _, content := range item.Content {
+			if content.Type == "output_text" {
+				text.WriteString(content.Text)
+			}
+		}
+		return strings.TrimSpace(text.String()), true
+	}
+	return "", false
+}
+
+// jsonTypeName returns the JSON type of a decoded JSON value
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseFormatValidator(t *testing.T) {
+	completed := func(text string) *ResponseData {
+		return &ResponseData{
+			Status: "completed",
+			Output: []OutputItem{{Type: "message", Role: "assistant", Content: []ContentItem{{Type: "output_text", Text: text}}}},
+		}
+	}
+
+	invoice, err := newResponseFormatValidator(&llamastack.ResponseFormat{
+		Type: llamastack.ResponseFormatJSONSchema,
+		Name: "invoice",
+		Schema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"total": map[string]interface{}{"type": "number"}},
+			"required":   []interface{}{"total"},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("should accept output matching the schema", func(t *testing.T) {
+		response := completed(`{"total": 12.5}`)
+		assert.Nil(t, invoice.validate(response))
+		assert.Len(t, response.Output, 1)
+	})
+
+	t.Run("should append an error item for output not matching the schema", func(t *testing.T) {
+		for name, text := range map[string]string{
+			"missing property": `{"amount": 12.5}`,
+			"wrong type":       `{"total": "12.5"}`,
+			"not JSON":         "The total is 12.5",
+		} {
+			response := completed(text)
+			item := invoice.validate(response)
+			require.NotNil(t, item, name)
+			assert.Equal(t, responseFormatErrorType, item.Type, name)
+			assert.Equal(t, "invoice", item.Name, name)
+			assert.Equal(t, text, item.Output, name)
+			assert.Len(t, response.Output, 2, name)
+		}
+	})
+
+	t.Run("should require an object for JSON object output", func(t *testing.T) {
+		object, err := newResponseFormatValidator(&llamastack.ResponseFormat{Type: llamastack.ResponseFormatJSONObject})
+		require.NoError(t, err)
+
+		assert.Nil(t, object.validate(completed(`{"any": ["thing"]}`)))
+		item := object.validate(completed(`["not", "an", "object"]`))
+		require.NotNil(t, item)
+		assert.Equal(t, "output must be a JSON object, got an array", item.Error)
+	})
+
+	t.Run("should not check text output or unfinished responses", func(t *testing.T) {
+		text, err := newResponseFormatValidator(&llamastack.ResponseFormat{Type: llamastack.ResponseFormatText})
+		require.NoError(t, err)
+		assert.Nil(t, text.validate(completed("plain text")))
+
+		response := completed(`{"amount": 1}`)
+		response.Status = "incomplete"
+		assert.Nil(t, invoice.validate(response))
+	})
+
+	t.Run("should reject schemas that cannot be compiled", func(t *testing.T) {
+		_, err := newResponseFormatValidator(&llamastack.ResponseFormat{
+			Type:   llamastack.ResponseFormatJSONSchema,
+			Name:   "invoice",
+			Schema: map[string]interface{}{"type": 42},
+		})
+		assert.ErrorContains(t, err, "invalid schema")
+	})
+}
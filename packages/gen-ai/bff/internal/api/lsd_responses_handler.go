@@ -6,58 +6,59 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/openai/openai-go/v2/packages/ssestream"
+	"github.com/openai/openai-go/v2/responses"
 	"github.com/opendatahub-io/gen-ai/internal/constants"
 	"github.com/opendatahub-io/gen-ai/internal/integrations"
 	k8s "github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes"
 	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
 	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
 	"github.com/opendatahub-io/gen-ai/internal/models"
 )
 
-// Supported streaming event types that we want to process for the Gen AI API
-// These events represent the core response generation lifecycle that clients need to track.
-// Other LlamaStack events (connection, debug, etc.) are filtered out to reduce
-// noise and bandwidth while maintaining the essential streaming response functionality.
-var supportedEventTypes = map[string]bool{
-	"response.created":            true, // Response generation started
-	"response.content_part.added": true, // New content part added to response
-	"response.output_text.delta":  true, // Text delta/chunk for streaming text
-	"response.content_part.done":  true, // Content part completed
-	"response.completed":          true, // Response generation completed
-}
-
-// isEventTypeSupported checks if the given event type should be processed
-func isEventTypeSupported(eventType string) bool {
-	return supportedEventTypes[eventType]
-}
-
 // ChatContextMessage represents a message in chat context history
 type ChatContextMessage struct {
-	Role    string `json:"role"`    // "user" or "assistant"
-	Content string `json:"content"` // Message content
-}
-
-// StreamingEvent represents a streaming event
-type StreamingEvent struct {
-	Delta          string        `json:"delta"`
-	SequenceNumber int64         `json:"sequence_number"`
-	Type           string        `json:"type"`
-	ItemID         string        `json:"item_id"`
-	OutputIndex    int           `json:"output_index"`
-	Response       *ResponseData `json:"response,omitempty"`
+	Role         string                        `json:"role"`                    // "user" or "assistant"
+	Content      string                        `json:"content"`                 // Message content
+	ContentParts []llamastack.InputContentPart `json:"content_parts,omitempty"` // Images and files of user messages
 }
 
 // ResponseData represents the response structure for both streaming and non-streaming
 type ResponseData struct {
-	ID                 string       `json:"id"`
-	Model              string       `json:"model"`
-	Status             string       `json:"status"`
-	CreatedAt          int64        `json:"created_at"`
-	Output             []OutputItem `json:"output,omitempty"`
-	PreviousResponseID string       `json:"previous_response_id,omitempty"` // Reference to previous response in conversation thread
+	ID                 string           `json:"id"`
+	Model              string           `json:"model"`
+	Status             string           `json:"status"`
+	CreatedAt          int64            `json:"created_at"`
+	Output             []OutputItem     `json:"output,omitempty"`
+	PreviousResponseID string           `json:"previous_response_id,omitempty"` // Reference to previous response in conversation thread
+	Error              *ResponseError   `json:"error,omitempty"`                // Set when the response failed
+	RequiresApproval   bool             `json:"requires_approval,omitempty"`    // Set when MCP tool calls are waiting for approval
+	Usage              *ResponseUsage   `json:"usage,omitempty"`                // Tokens used, once the response finished
+	Latency            *ResponseLatency `json:"latency,omitempty"`              // Time taken, once the response finished
+
+	// Sampling and generation settings the response was created with
+	Temperature       *float64           `json:"temperature,omitempty"`
+	TopP              *float64           `json:"top_p,omitempty"`
+	MaxOutputTokens   *int64             `json:"max_output_tokens,omitempty"`
+	Stop              []string           `json:"stop,omitempty"`
+	Seed              *int64             `json:"seed,omitempty"`
+	FrequencyPenalty  *float64           `json:"frequency_penalty,omitempty"`
+	PresencePenalty   *float64           `json:"presence_penalty,omitempty"`
+	ParallelToolCalls *bool              `json:"parallel_tool_calls,omitempty"`
+	ToolChoice        *models.ToolChoice `json:"tool_choice,omitempty"`
+	Truncation        string             `json:"truncation,omitempty"`
+}
+
+// ResponseError describes why a response or stream failed
+type ResponseError struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+	Param   string `json:"param,omitempty"`
 }
 
 // OutputItem represents an output item with essential fields
@@ -76,65 +77,136 @@ type OutputItem struct {
 	Name        string      `json:"name,omitempty"`
 	Error       string      `json:"error,omitempty"`
 	Output      interface{} `json:"output,omitempty"`
+
+	// Function call fields
+	CallID string `json:"call_id,omitempty"`
 }
 
 // ContentItem represents content with essential fields
 type ContentItem struct {
-	Type        string        `json:"type"`
-	Text        string        `json:"text"`
-	Annotations []interface{} `json:"annotations,omitempty"` // For content annotations
+	Type        string       `json:"type"`
+	Text        string       `json:"text"`
+	Annotations []Annotation `json:"annotations,omitempty"` // Citations of the sources of the text
 }
 
 // SearchResult represents search results with essential fields
 type SearchResult struct {
-	Score    float64 `json:"score"`
-	Text     string  `json:"text"`
-	Filename string  `json:"filename,omitempty"`
+	Score      float64                `json:"score"`
+	Text       string                 `json:"text"`
+	Filename   string                 `json:"filename,omitempty"`
+	FileID     string                 `json:"file_id,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"` // Attributes of the vector store file
 }
 
 // MCPServer represents MCP server configuration for responses
 type MCPServer struct {
-	ServerLabel string            `json:"server_label"` // Label identifier for the MCP server
-	ServerURL   string            `json:"server_url"`   // URL endpoint for the MCP server
-	Headers     map[string]string `json:"headers"`      // Custom headers for MCP server authentication
+	ServerLabel     string             `json:"server_label"`               // Label identifier for the MCP server
+	ServerURL       string             `json:"server_url"`                 // URL endpoint for the MCP server
+	Headers         map[string]string  `json:"headers"`                    // Custom headers for MCP server authentication
+	AllowedTools    []string           `json:"allowed_tools,omitempty"`    // Restrict the tools the model may call
+	RequireApproval *MCPApprovalPolicy `json:"require_approval,omitempty"` // Tool calls that need user approval before running
+}
+
+// MCPApprovalPolicy configures which MCP tool calls require user approval.
+// In JSON it is either "always"/"never" for the whole server, or an object with per-tool lists:
+// {"always": ["create_issue"], "never": ["get_latest_release"]}. Tools in neither list require approval.
+type MCPApprovalPolicy struct {
+	Setting string   `json:"-"`
+	Always  []string `json:"always,omitempty"`
+	Never   []string `json:"never,omitempty"`
+}
+
+// UnmarshalJSON accepts both the string and the per-tool object form
+func (p *MCPApprovalPolicy) UnmarshalJSON(data []byte) error {
+	var setting string
+	if err := json.Unmarshal(data, &setting); err == nil {
+		*p = MCPApprovalPolicy{Setting: setting}
+		return nil
+	}
+
+	type toolLists MCPApprovalPolicy
+	var lists toolLists
+	if err := json.Unmarshal(data, &lists); err != nil {
+		return fmt.Errorf("require_approval must be \"always\", \"never\" or an object with always/never tool lists: %w", err)
+	}
+	*p = MCPApprovalPolicy(lists)
+	return nil
+}
+
+// MarshalJSON writes the policy in the same form it was given
+func (p MCPApprovalPolicy) MarshalJSON() ([]byte, error) {
+	if p.Setting != "" {
+		return json.Marshal(p.Setting)
+	}
+	type toolLists MCPApprovalPolicy
+	return json.Marshal(toolLists(p))
+}
+
+// toParam converts the policy to the LlamaStack client representation
+func (p *MCPApprovalPolicy) toParam() *llamastack.MCPApprovalPolicy {
+	if p == nil {
+		return nil
+	}
+	return &llamastack.MCPApprovalPolicy{
+		Setting:     p.Setting,
+		AlwaysTools: p.Always,
+		NeverTools:  p.Never,
+	}
 }
 
 // CreateResponseRequest represents the request body for creating a response
 type CreateResponseRequest struct {
-	Input string `json:"input"`
-	Model string `json:"model"`
+	Input        string                        `json:"input"`
+	InputContent []llamastack.InputContentPart `json:"input_content,omitempty"` // Images and files after the input text
+	Model        string                        `json:"model"`
 
 	VectorStoreIDs     []string             `json:"vector_store_ids,omitempty"`     // Enables RAG
+	FileSearch         *FileSearchOptions   `json:"file_search,omitempty"`          // Tunes RAG retrieval
 	ChatContext        []ChatContextMessage `json:"chat_context,omitempty"`         // Conversation history
 	Temperature        *float64             `json:"temperature,omitempty"`          // Controls creativity (0.0-2.0)
 	TopP               *float64             `json:"top_p,omitempty"`                // Controls randomness (0.0-1.0)
+	MaxOutputTokens    *int64               `json:"max_output_tokens,omitempty"`    // Limits the generated tokens (at least 1)
+	Stop               []string             `json:"stop,omitempty"`                 // Up to 4 sequences that stop generation
+	Seed               *int64               `json:"seed,omitempty"`                 // Makes sampling repeatable
+	FrequencyPenalty   *float64             `json:"frequency_penalty,omitempty"`    // Penalizes repeated tokens (-2.0-2.0)
+	PresencePenalty    *float64             `json:"presence_penalty,omitempty"`     // Penalizes tokens already used (-2.0-2.0)
+	ParallelToolCalls  *bool                `json:"parallel_tool_calls,omitempty"`  // Allows several tool calls at once
+	ToolChoice         *models.ToolChoice   `json:"tool_choice,omitempty"`          // auto, none, required or a tool to call
+	Truncation         string               `json:"truncation,omitempty"`           // auto or disabled
 	Instructions       string               `json:"instructions,omitempty"`         // System message/behavior
 	Stream             bool                 `json:"stream,omitempty"`               // Enable streaming response
 	MCPServers         []MCPServer          `json:"mcp_servers,omitempty"`          // MCP server configurations
 	PreviousResponseID string               `json:"previous_response_id,omitempty"` // Link to previous response for conversation continuity
+	ConversationID     string               `json:"conversation_id,omitempty"`      // Record this turn in a stored conversation
+	Include            []string             `json:"include,omitempty"`              // Additional output, such as file_search_call.results
+	ResponseFormat     *ResponseFormat      `json:"response_format,omitempty"`      // JSON object or JSON schema output
+
+	// mcpApprovals answers pending MCP approval requests. It is only set by the approvals endpoint,
+	// which validates the decisions against the previous response.
+	mcpApprovals []llamastack.MCPApprovalResponseParam
 }
 
-// convertToStreamingEvent converts a LlamaStack event to our clean StreamingEvent schema
-func convertToStreamingEvent(event interface{}) *StreamingEvent {
-	// Direct marshal to our clean schema - Go JSON ignores extra fields automatically!
-	eventJSON, err := json.Marshal(event)
-	if err != nil {
-		return nil
-	}
+// FileSearchOptions tunes the retrieval from the vector stores of a response, like a vector store search
+type FileSearchOptions struct {
+	MaxNumResults  *int64                              `json:"max_num_results,omitempty"` // 1-50
+	RankingOptions *VectorStoreSearchRankingOptions    `json:"ranking_options,omitempty"`
+	Filters        *llamastack.VectorStoreSearchFilter `json:"filters,omitempty"` // On file attributes
+}
 
-	var streamingEvent StreamingEvent
-	if err := json.Unmarshal(eventJSON, &streamingEvent); err != nil {
+// params converts the options to their client form
+func (o *FileSearchOptions) params() *llamastack.FileSearchOptions {
+	if o == nil {
 		return nil
 	}
-
-	// Only process the supported event types, ignore all others
-	if !isEventTypeSupported(streamingEvent.Type) {
-		// Skip some events types to reduce noise.
-		// Full list of events: https://platform.openai.com/docs/api-reference/responses-streaming
-		return nil
+	params := &llamastack.FileSearchOptions{
+		MaxNumResults: o.MaxNumResults,
+		Filters:       o.Filters,
 	}
-
-	return &streamingEvent
+	if o.RankingOptions != nil {
+		params.Ranker = o.RankingOptions.Ranker
+		params.ScoreThreshold = o.RankingOptions.ScoreThreshold
+	}
+	return params
 }
 
 // convertToResponseData converts a LlamaStack response to our clean ResponseData schema
@@ -142,122 +214,332 @@ func convertToResponseData(llamaResponse interface{}) ResponseData {
 	// Direct marshal to our clean schema - JSON unmarshaling ignores extra fields automatically
 	var responseData ResponseData
 
-	responseJSON, err := json.Marshal(llamaResponse)
-	if err != nil {
-		// Marshal failed - return zero-value ResponseData
-		return responseData
+	// Prefer the raw server JSON when the response was decoded from the API
+	var responseJSON []byte
+	if raw, ok := llamaResponse.(rawJSONEvent); ok && raw.RawJSON() != "" {
+		responseJSON = []byte(raw.RawJSON())
+	} else {
+		var err error
+		responseJSON, err = json.Marshal(llamaResponse)
+		if err != nil {
+			// Marshal failed - return zero-value ResponseData
+			return responseData
+		}
 	}
 
 	// Attempt unmarshal - ignore errors as responseData will keep zero values if it fails
 	// This is expected to be rare since we're marshaling from a valid Go struct
 	_ = json.Unmarshal(responseJSON, &responseData)
+	responseData.normalize()
 
 	return responseData
 }
 
+// normalize drops the zero-value error object, usage, tool choice and token limit that SDK structs marshal for
+// successful responses and flags responses that stopped to wait for MCP tool approval
+func (r *ResponseData) normalize() {
+	if r.Error != nil && r.Error.Code == "" && r.Error.Message == "" {
+		r.Error = nil
+	}
+	if r.Usage != nil && *r.Usage == (ResponseUsage{}) {
+		r.Usage = nil
+	}
+	if r.ToolChoice != nil && r.ToolChoice.IsZero() {
+		r.ToolChoice = nil
+	}
+	if r.MaxOutputTokens != nil && *r.MaxOutputTokens == 0 {
+		r.MaxOutputTokens = nil
+	}
+	r.RequiresApproval = false
+	for _, item := range r.Output {
+		if item.Type == mcpApprovalRequestType {
+			r.RequiresApproval = true
+			break
+		}
+	}
+}
+
+// applyGenerationSettings sets the generation settings of the request on the response. LlamaStack does not
+// echo every setting it was sent, such as the stop sequences and seed, so the requested values are reported.
+func (r *ResponseData) applyGenerationSettings(params llamastack.CreateResponseParams) {
+	if r == nil {
+		return
+	}
+	if params.Temperature != nil {
+		r.Temperature = params.Temperature
+	}
+	if params.TopP != nil {
+		r.TopP = params.TopP
+	}
+	if params.MaxOutputTokens != nil {
+		r.MaxOutputTokens = params.MaxOutputTokens
+	}
+	if len(params.Stop) > 0 {
+		r.Stop = params.Stop
+	}
+	if params.Seed != nil {
+		r.Seed = params.Seed
+	}
+	if params.FrequencyPenalty != nil {
+		r.FrequencyPenalty = params.FrequencyPenalty
+	}
+	if params.PresencePenalty != nil {
+		r.PresencePenalty = params.PresencePenalty
+	}
+	if params.ParallelToolCalls != nil {
+		r.ParallelToolCalls = params.ParallelToolCalls
+	}
+	if params.ToolChoice != nil {
+		r.ToolChoice = &models.ToolChoice{
+			Mode:        params.ToolChoice.Mode,
+			Type:        params.ToolChoice.Type,
+			ServerLabel: params.ToolChoice.ServerLabel,
+			Name:        params.ToolChoice.Name,
+		}
+	}
+	if params.Truncation != "" {
+		r.Truncation = params.Truncation
+	}
+}
+
+// toolChoiceParam converts a tool choice to the LlamaStack client representation
+func toolChoiceParam(choice *models.ToolChoice) *llamastack.ToolChoice {
+	if choice == nil {
+		return nil
+	}
+	return &llamastack.ToolChoice{
+		Mode:        choice.Mode,
+		Type:        choice.Type,
+		ServerLabel: choice.ServerLabel,
+		Name:        choice.Name,
+	}
+}
+
 // LlamaStackCreateResponseHandler handles POST /gen-ai/api/v1/responses
 func (app *App) LlamaStackCreateResponseHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	ctx := r.Context()
-
 	// Parse the request body
 	var createRequest CreateResponseRequest
-	if err := json.NewDecoder(r.Body).Decode(&createRequest); err != nil {
-		app.badRequestResponse(w, r, err)
+	if !app.decodeResponseRequest(w, r, &createRequest) {
 		return
 	}
 
+	app.createResponse(w, r, createRequest)
+}
+
+// decodeResponseRequest decodes the body of a responses request of at most constants.ResponseRequestMaxBytes into
+// dst. It writes the error response and returns false when the body cannot be decoded.
+func (app *App) decodeResponseRequest(w http.ResponseWriter, r *http.Request, dst any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, constants.ResponseRequestMaxBytes)
+	err := json.NewDecoder(r.Body).Decode(dst)
+	if err == nil {
+		return true
+	}
+
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		app.errorResponse(w, r, &integrations.HTTPError{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			ErrorResponse: integrations.ErrorResponse{
+				Code:    strconv.Itoa(http.StatusRequestEntityTooLarge),
+				Message: fmt.Sprintf("body must not be larger than %d MB; send images and files as file IDs", maxBytesError.Limit>>20),
+			},
+		})
+		return false
+	}
+	app.badRequestResponse(w, r, err)
+	return false
+}
+
+// createResponse validates a responses request and generates the response, streaming it when requested
+func (app *App) createResponse(w http.ResponseWriter, r *http.Request, createRequest CreateResponseRequest) {
+	params, turn, ok := app.prepareResponse(w, r, createRequest)
+	if !ok {
+		return
+	}
+
+	// Handle streaming vs non-streaming responses
+	if createRequest.Stream {
+		app.handleStreamingResponse(w, r, r.Context(), params, turn)
+	} else {
+		app.handleNonStreamingResponse(w, r, r.Context(), params, turn)
+	}
+}
+
+// prepareResponse validates a responses request and converts it to client params, resolving the stored conversation
+// the turn is recorded in. It writes the error response and returns false when the request is invalid.
+func (app *App) prepareResponse(w http.ResponseWriter, r *http.Request, createRequest CreateResponseRequest) (llamastack.CreateResponseParams, *conversationTurn, bool) {
+	ctx := r.Context()
+
 	// Validate required fields
-	if createRequest.Input == "" {
+	if createRequest.Input == "" && len(createRequest.InputContent) == 0 && len(createRequest.mcpApprovals) == 0 {
 		app.badRequestResponse(w, r, errors.New("input is required"))
-		return
+		return llamastack.CreateResponseParams{}, nil, false
+	}
+	if err := llamastack.ValidateInputContent(createRequest.InputContent); err != nil {
+		app.badRequestResponse(w, r, fmt.Errorf("invalid input_content: %w", err))
+		return llamastack.CreateResponseParams{}, nil, false
 	}
 	if createRequest.Model == "" {
 		app.badRequestResponse(w, r, errors.New("model is required"))
-		return
+		return llamastack.CreateResponseParams{}, nil, false
 	}
 
 	// Convert chat context format
 	var chatContext []llamastack.ChatContextMessage
+	contentParts := len(createRequest.InputContent)
 	for _, msg := range createRequest.ChatContext {
+		contentParts += len(msg.ContentParts)
+		if contentParts > constants.ResponseRequestMaxContentParts {
+			app.badRequestResponse(w, r, fmt.Errorf("a request can have at most %d content parts across input_content and chat_context", constants.ResponseRequestMaxContentParts))
+			return llamastack.CreateResponseParams{}, nil, false
+		}
+		if len(msg.ContentParts) > 0 {
+			if msg.Role != "user" {
+				app.badRequestResponse(w, r, errors.New("only user messages of chat_context can have content_parts"))
+				return llamastack.CreateResponseParams{}, nil, false
+			}
+			if err := llamastack.ValidateInputContent(msg.ContentParts); err != nil {
+				app.badRequestResponse(w, r, fmt.Errorf("invalid chat_context content_parts: %w", err))
+				return llamastack.CreateResponseParams{}, nil, false
+			}
+		}
 		chatContext = append(chatContext, llamastack.ChatContextMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
+			Role:         msg.Role,
+			Content:      msg.Content,
+			ContentParts: msg.ContentParts,
 		})
 	}
 
-	// Convert MCP servers to LlamaStack tool parameters
-	var mcpServerParams []llamastack.MCPServerParam
-	if len(createRequest.MCPServers) > 0 {
-		for _, server := range createRequest.MCPServers {
-			// Validate MCP server parameters
-			if server.ServerLabel == "" {
-				app.badRequestResponse(w, r, errors.New("server_label is required for MCP server"))
-				return
-			}
-			if server.ServerURL == "" {
-				app.badRequestResponse(w, r, errors.New("server_url is required for MCP server"))
-				return
-			}
-
-			// Create MCP server parameter for LlamaStack
-			mcpServerParam := llamastack.MCPServerParam{
-				ServerLabel: server.ServerLabel,
-				ServerURL:   server.ServerURL,
-				Headers:     make(map[string]string),
-			}
+	// Authenticate MCP servers with the user's stored credentials unless the request provides headers
+	app.applyStoredMCPHeaders(ctx, createRequest.MCPServers)
 
-			// Copy provided headers
-			if server.Headers != nil {
-				for k, v := range server.Headers {
-					mcpServerParam.Headers[k] = v
-				}
-			}
+	// Point LlamaStack at the bridge for stdio and WebSocket MCP servers
+	if err := app.bridgeMCPServers(r, createRequest.MCPServers); err != nil {
+		app.errorResponse(w, r, err)
+		return llamastack.CreateResponseParams{}, nil, false
+	}
 
-			mcpServerParams = append(mcpServerParams, mcpServerParam)
-		}
+	// Convert MCP servers to LlamaStack tool parameters
+	mcpServerParams, err := buildMCPServerParams(createRequest.MCPServers)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return llamastack.CreateResponseParams{}, nil, false
 	}
 
 	// Validate that chat_context and previous_response_id are not used together
 	if len(createRequest.ChatContext) > 0 && createRequest.PreviousResponseID != "" {
 		app.badRequestResponse(w, r, errors.New("chat_context and previous_response_id cannot be used together. Use either chat_context for manual conversation history or previous_response_id for automatic conversation threading"))
-		return
+		return llamastack.CreateResponseParams{}, nil, false
+	}
+
+	// Resolve the stored conversation this turn should be recorded in
+	var turn *conversationTurn
+	if createRequest.ConversationID != "" {
+		namespace, owner, ok := app.conversationScope(w, r)
+		if !ok {
+			return llamastack.CreateResponseParams{}, nil, false
+		}
+		conversation, err := app.repositories.Conversations.GetConversation(ctx, namespace, owner, createRequest.ConversationID)
+		if err == nil {
+			// Refuse the turn up front rather than generating a response that cannot be recorded
+			err = app.repositories.Conversations.CheckMessageLimit(conversation)
+		}
+		if err != nil {
+			app.handleConversationError(w, r, err)
+			return llamastack.CreateResponseParams{}, nil, false
+		}
+
+		// Continue the stored thread unless the client manages history itself
+		if len(createRequest.ChatContext) == 0 && createRequest.PreviousResponseID == "" {
+			createRequest.PreviousResponseID = conversation.LastResponseID
+		}
+
+		turn = &conversationTurn{
+			namespace:      namespace,
+			owner:          owner,
+			conversationID: conversation.ID,
+			input:          createRequest.Input,
+			settings:       conversationSettingsFromRequest(createRequest),
+		}
 	}
 
 	// Validate previous response ID if provided
 	if createRequest.PreviousResponseID != "" {
 		if err := app.validatePreviousResponse(ctx, createRequest.PreviousResponseID); err != nil {
 			app.badRequestResponse(w, r, fmt.Errorf("invalid previous response ID: %w", err))
-			return
+			return llamastack.CreateResponseParams{}, nil, false
 		}
 	}
 
+	// Only vector stores the caller can read may be searched
+	if !app.authorizeVectorStores(w, r, createRequest.VectorStoreIDs) {
+		return llamastack.CreateResponseParams{}, nil, false
+	}
+
+	for _, include := range createRequest.Include {
+		if include != llamastack.IncludeFileSearchResults {
+			app.badRequestResponse(w, r, fmt.Errorf("include must only contain %s, got: %q", llamastack.IncludeFileSearchResults, include))
+			return llamastack.CreateResponseParams{}, nil, false
+		}
+	}
+
+	fileSearch := createRequest.FileSearch.params()
+	if fileSearch != nil {
+		if len(createRequest.VectorStoreIDs) == 0 {
+			app.badRequestResponse(w, r, errors.New("file_search requires vector_store_ids"))
+			return llamastack.CreateResponseParams{}, nil, false
+		}
+		if err := fileSearch.Validate(); err != nil {
+			app.badRequestResponse(w, r, fmt.Errorf("invalid file_search: %w", err))
+			return llamastack.CreateResponseParams{}, nil, false
+		}
+	}
+
+	responseFormat, err := createRequest.ResponseFormat.params()
+	if err != nil {
+		app.badRequestResponse(w, r, fmt.Errorf("invalid response_format: %w", err))
+		return llamastack.CreateResponseParams{}, nil, false
+	}
+
 	// Retrieve and inject MaaS provider data for custom headers
 	providerData := app.getMaaSProviderData(ctx, createRequest.Model)
 
 	// Convert to client params (only working parameters)
 	params := llamastack.CreateResponseParams{
-		Input:              createRequest.Input,
-		Model:              createRequest.Model,
-		VectorStoreIDs:     createRequest.VectorStoreIDs,
-		ChatContext:        chatContext,
-		Temperature:        createRequest.Temperature,
-		TopP:               createRequest.TopP,
-		Instructions:       createRequest.Instructions,
-		Tools:              mcpServerParams,
-		PreviousResponseID: createRequest.PreviousResponseID,
-		ProviderData:       providerData,
+		Input:                createRequest.Input,
+		InputContent:         createRequest.InputContent,
+		Model:                createRequest.Model,
+		VectorStoreIDs:       createRequest.VectorStoreIDs,
+		FileSearch:           fileSearch,
+		ChatContext:          chatContext,
+		Temperature:          createRequest.Temperature,
+		TopP:                 createRequest.TopP,
+		MaxOutputTokens:      createRequest.MaxOutputTokens,
+		Stop:                 createRequest.Stop,
+		Seed:                 createRequest.Seed,
+		FrequencyPenalty:     createRequest.FrequencyPenalty,
+		PresencePenalty:      createRequest.PresencePenalty,
+		ParallelToolCalls:    createRequest.ParallelToolCalls,
+		ToolChoice:           toolChoiceParam(createRequest.ToolChoice),
+		Truncation:           createRequest.Truncation,
+		Instructions:         createRequest.Instructions,
+		Tools:                mcpServerParams,
+		PreviousResponseID:   createRequest.PreviousResponseID,
+		MCPApprovalResponses: createRequest.mcpApprovals,
+		Include:              createRequest.Include,
+		ResponseFormat:       responseFormat,
+		ProviderData:         providerData,
 	}
-
-	// Handle streaming vs non-streaming responses
-	if createRequest.Stream {
-		app.handleStreamingResponse(w, r, ctx, params)
-	} else {
-		app.handleNonStreamingResponse(w, r, ctx, params)
+	if err := params.ValidateGeneration(); err != nil {
+		app.badRequestResponse(w, r, err)
+		return llamastack.CreateResponseParams{}, nil, false
 	}
+
+	return params, turn, true
 }
 
 // handleStreamingResponse handles streaming response creation
-func (app *App) handleStreamingResponse(w http.ResponseWriter, r *http.Request, ctx context.Context, params llamastack.CreateResponseParams) {
+func (app *App) handleStreamingResponse(w http.ResponseWriter, r *http.Request, ctx context.Context, params llamastack.CreateResponseParams, turn *conversationTurn) {
 	// Check if ResponseWriter supports streaming - fail fast if not
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -266,6 +548,7 @@ func (app *App) handleStreamingResponse(w http.ResponseWriter, r *http.Request,
 	}
 
 	// Create streaming response
+	meter := newResponseMeter()
 	stream, err := app.repositories.Responses.CreateResponseStream(ctx, params)
 	if err != nil {
 		// Check if this is a mock streaming error - delegate to mock client
@@ -283,6 +566,7 @@ func (app *App) handleStreamingResponse(w http.ResponseWriter, r *http.Request,
 			return
 		}
 		app.serverErrorResponse(w, r, err)
+		app.recordResponseUsage(ctx, params.Model, meter, nil)
 		return
 	}
 	defer stream.Close()
@@ -294,6 +578,40 @@ func (app *App) handleStreamingResponse(w http.ResponseWriter, r *http.Request,
 	w.Header().Set("X-Accel-Buffering", "no")
 
 	// Stream events to client
+	app.forwardResponseStream(ctx, stream, params, meter, func(streamingEvent *StreamingEvent) error {
+		// Write SSE format with the event kind as the SSE event name
+		if err := writeStreamingEvent(w, streamingEvent); err != nil {
+			app.logger.Error("Failed to write streaming event",
+				"error", err,
+				"event_type", streamingEvent.Type,
+				"item_id", streamingEvent.ItemID,
+				"sequence", streamingEvent.SequenceNumber)
+			return err
+		}
+
+		// Flush the response to send data immediately
+		flusher.Flush()
+
+		// Record the completed response in the stored conversation
+		if streamingEvent.Type == "response.completed" && streamingEvent.Response != nil {
+			app.recordConversationTurn(ctx, turn, streamingEvent.Response)
+		}
+		return nil
+	})
+}
+
+// forwardResponseStream converts the events of a LlamaStack stream and passes them to emit, until the stream ends
+// or emit fails. Citations, generation settings, output that does not match the response format and the latency
+// of the finished response are resolved on the way, and the usage of the response is recorded at the end.
+func (app *App) forwardResponseStream(ctx context.Context, stream *ssestream.Stream[responses.ResponseStreamEventUnion], params llamastack.CreateResponseParams, meter *responseMeter, emit func(*StreamingEvent) error) {
+	// Account for the finished response, or a failed request when the stream ended without one
+	var finalResponse *ResponseData
+	defer func() {
+		app.recordResponseUsage(ctx, params.Model, meter, finalResponse)
+	}()
+
+	citations := app.newCitationResolver(ctx)
+	formatCheck, _ := newResponseFormatValidator(params.ResponseFormat)
 	for stream.Next() {
 		event := stream.Current()
 
@@ -303,47 +621,55 @@ func (app *App) handleStreamingResponse(w http.ResponseWriter, r *http.Request,
 			// Skip events we don't care about
 			continue
 		}
+		citations.resolveEvent(streamingEvent)
+		streamingEvent.Response.applyGenerationSettings(params)
+		meter.observe(streamingEvent)
+		if isFinalStreamingEvent(streamingEvent.Type) && streamingEvent.Response != nil {
+			meter.measure(streamingEvent.Response)
+			finalResponse = streamingEvent.Response
+		}
 
-		// Convert clean streaming event to JSON
-		eventData, err := json.Marshal(streamingEvent)
-		if err != nil {
-			app.logger.Error("Failed to marshal streaming event",
-				"error", err,
-				"event_type", streamingEvent.Type,
-				"item_id", streamingEvent.ItemID,
-				"sequence", streamingEvent.SequenceNumber)
-			continue
+		// Report output that does not match the response format before the response completes. The added
+		// event shares the sequence number of the completed event, as LlamaStack did not send it.
+		if streamingEvent.Type == "response.completed" {
+			if item := formatCheck.validate(streamingEvent.Response); item != nil {
+				formatErrorEvent := &StreamingEvent{
+					Type:           "response.output_item.done",
+					Kind:           StreamingEventKindOutputItem,
+					SequenceNumber: streamingEvent.SequenceNumber,
+					OutputIndex:    len(streamingEvent.Response.Output) - 1,
+					Item:           item,
+				}
+				if err := emit(formatErrorEvent); err != nil {
+					return
+				}
+			}
 		}
 
-		// Write SSE format
-		_, err = fmt.Fprintf(w, "data: %s\n\n", eventData)
-		if err != nil {
-			app.logger.Error("Failed to write streaming event", "error", err)
+		if err := emit(streamingEvent); err != nil {
 			return
 		}
-
-		// Flush the response to send data immediately
-		flusher.Flush()
 	}
 
 	// Check for stream errors
-	if err = stream.Err(); err != nil {
+	if err := stream.Err(); err != nil {
 		app.logger.Error("Streaming error", "error", err)
+		finalResponse = nil
 		// Send error event
-		errorData := map[string]interface{}{
-			"error": map[string]interface{}{
-				"message": "Streaming error occurred",
-				"code":    "500",
+		_ = emit(&StreamingEvent{
+			Type: "error",
+			Kind: StreamingEventKindError,
+			Error: &ResponseError{
+				Code:    "500",
+				Message: "Streaming error occurred",
 			},
-		}
-		errorJSON, _ := json.Marshal(errorData)
-		fmt.Fprintf(w, "data: %s\n\n", errorJSON)
+		})
 	}
 }
 
 // handleNonStreamingResponse handles regular (non-streaming) response creation
-func (app *App) handleNonStreamingResponse(w http.ResponseWriter, r *http.Request, ctx context.Context, params llamastack.CreateResponseParams) {
-	llamaResponse, err := app.repositories.Responses.CreateResponse(ctx, params)
+func (app *App) handleNonStreamingResponse(w http.ResponseWriter, r *http.Request, ctx context.Context, params llamastack.CreateResponseParams, turn *conversationTurn) {
+	responseData, err := app.generateResponse(ctx, params)
 	if err != nil {
 		// Check if this is a model not found error
 		if ModelNotFoundError(err) {
@@ -354,21 +680,279 @@ func (app *App) handleNonStreamingResponse(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Convert to clean response data
+	app.recordConversationTurn(ctx, turn, responseData)
+
+	apiResponse := llamastack.APIResponse{
+		Data: responseData,
+	}
+
+	err = app.WriteJSON(w, http.StatusCreated, apiResponse, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// generateResponse creates a response and converts it, resolving its citations, generation settings, output that
+// does not match the response format and latency. The usage of the response is recorded, as is a failed request
+// unless the model does not exist.
+func (app *App) generateResponse(ctx context.Context, params llamastack.CreateResponseParams) (*ResponseData, error) {
+	meter := newResponseMeter()
+	llamaResponse, err := app.repositories.Responses.CreateResponse(ctx, params)
+	if err != nil {
+		if !ModelNotFoundError(err) {
+			app.recordResponseUsage(ctx, params.Model, meter, nil)
+		}
+		return nil, err
+	}
+
+	// Convert to clean response data, with the filenames and retrieved chunks of its citations
 	responseData := convertToResponseData(llamaResponse)
+	app.newCitationResolver(ctx).resolveResponse(&responseData)
+	responseData.applyGenerationSettings(params)
+
+	// Report output that does not match the response format
+	formatCheck, _ := newResponseFormatValidator(params.ResponseFormat)
+	formatCheck.validate(&responseData)
 
 	// Add previous response ID to response data if provided
 	if params.PreviousResponseID != "" {
 		responseData.PreviousResponseID = params.PreviousResponseID
 	}
 
-	apiResponse := llamastack.APIResponse{
-		Data: responseData,
+	meter.measure(&responseData)
+	app.recordResponseUsage(ctx, params.Model, meter, &responseData)
+	return &responseData, nil
+}
+
+// buildMCPServerParams validates MCP server configurations and converts them to LlamaStack tool parameters
+func buildMCPServerParams(servers []MCPServer) ([]llamastack.MCPServerParam, error) {
+	var mcpServerParams []llamastack.MCPServerParam
+	for _, server := range servers {
+		// Validate MCP server parameters
+		if server.ServerLabel == "" {
+			return nil, errors.New("server_label is required for MCP server")
+		}
+		if server.ServerURL == "" {
+			return nil, errors.New("server_url is required for MCP server")
+		}
+
+		// Create MCP server parameter for LlamaStack
+		mcpServerParam := llamastack.MCPServerParam{
+			ServerLabel:     server.ServerLabel,
+			ServerURL:       server.ServerURL,
+			Headers:         make(map[string]string),
+			AllowedTools:    server.AllowedTools,
+			RequireApproval: server.RequireApproval.toParam(),
+		}
+
+		// Copy provided headers
+		for k, v := range server.Headers {
+			mcpServerParam.Headers[k] = v
+		}
+
+		if mcpServerParam.RequireApproval != nil {
+			if err := mcpServerParam.RequireApproval.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid require_approval for MCP server %s: %w", server.ServerLabel, err)
+			}
+		}
+
+		mcpServerParams = append(mcpServerParams, mcpServerParam)
 	}
+	return mcpServerParams, nil
+}
 
-	err = app.WriteJSON(w, http.StatusCreated, apiResponse, nil)
+// applyStoredMCPHeaders adds an Authorization header with the user's stored credential to every MCP server
+// sent without one. Servers are matched to the dashboard MCP ConfigMap by URL; lookup failures leave the server unauthenticated.
+func (app *App) applyStoredMCPHeaders(ctx context.Context, servers []MCPServer) {
+	var pending []int
+	for i, server := range servers {
+		if !hasAuthorizationHeader(server.Headers) {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	identity, ok := ctx.Value(constants.RequestIdentityKey).(*integrations.RequestIdentity)
+	if !ok || identity == nil {
+		return
+	}
+	namespace, ok := ctx.Value(constants.NamespaceQueryParameterKey).(string)
+	if !ok || namespace == "" || app.kubernetesClientFactory == nil || app.repositories.MCPClient == nil {
+		return
+	}
+
+	k8sClient, err := app.kubernetesClientFactory.GetClient(ctx)
 	if err != nil {
-		app.serverErrorResponse(w, r, err)
+		app.logger.Warn("Failed to get Kubernetes client for MCP credential lookup", "error", err)
+		return
+	}
+
+	configuredServers, err := app.repositories.MCPClient.GetMCPServersFromConfig(k8sClient, ctx, identity, app.dashboardNamespace, constants.MCPServerName)
+	if err != nil {
+		app.logger.Debug("MCP server ConfigMap not available for credential lookup", "error", err)
+		return
+	}
+	serverNames := make(map[string]string, len(configuredServers))
+	for _, configured := range configuredServers {
+		serverNames[configured.Config.URL] = configured.Name
+	}
+
+	for _, i := range pending {
+		serverName, ok := serverNames[servers[i].ServerURL]
+		if !ok {
+			continue
+		}
+
+		// Resolve the credential on a copy so the request identity keeps its own MCP token
+		serverIdentity := *identity
+		serverIdentity.MCPToken = ""
+		app.applyStoredMCPCredential(ctx, k8sClient, &serverIdentity, namespace, serverName)
+		if serverIdentity.MCPToken == "" {
+			continue
+		}
+
+		if servers[i].Headers == nil {
+			servers[i].Headers = make(map[string]string)
+		}
+		servers[i].Headers["Authorization"] = "Bearer " + serverIdentity.MCPToken
+	}
+}
+
+// bridgeMCPServers replaces the URLs of stdio and WebSocket servers, which LlamaStack cannot reach, with their
+// bridge endpoints and adds the bridge token. Bridged servers are started for the namespace when needed; they are
+// matched to the dashboard MCP ConfigMap by URL, so only configured servers can be bridged.
+func (app *App) bridgeMCPServers(r *http.Request, servers []MCPServer) *integrations.HTTPError {
+	var pending []int
+	for i, server := range servers {
+		if isBridgedMCPServerURL(server.ServerURL) {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	bridge := app.mcpBridge()
+	if bridge == nil {
+		return mcpBridgeError(http.StatusBadRequest, "stdio and WebSocket MCP servers are not supported by this deployment")
+	}
+
+	ctx := r.Context()
+	identity, ok := ctx.Value(constants.RequestIdentityKey).(*integrations.RequestIdentity)
+	if !ok || identity == nil {
+		return mcpBridgeError(http.StatusBadRequest, "missing request identity")
+	}
+	namespace, ok := ctx.Value(constants.NamespaceQueryParameterKey).(string)
+	if !ok || namespace == "" {
+		return mcpBridgeError(http.StatusBadRequest, "missing namespace")
+	}
+	if app.kubernetesClientFactory == nil || app.repositories.MCPClient == nil {
+		return mcpBridgeError(http.StatusServiceUnavailable, "MCP server configuration is not available")
+	}
+
+	k8sClient, err := app.kubernetesClientFactory.GetClient(ctx)
+	if err != nil {
+		app.logger.Warn("Failed to get Kubernetes client for MCP bridge", "error", err)
+		return mcpBridgeError(http.StatusServiceUnavailable, "MCP server configuration is not available")
+	}
+
+	configuredServers, err := app.repositories.MCPClient.GetMCPServersFromConfig(k8sClient, ctx, identity, app.dashboardNamespace, constants.MCPServerName)
+	if err != nil {
+		app.logger.Warn("MCP server ConfigMap not available for MCP bridge", "error", err)
+		return mcpBridgeError(http.StatusServiceUnavailable, "MCP server configuration is not available")
+	}
+	serverConfigs := make(map[string]models.MCPServerConfig, len(configuredServers))
+	for _, configured := range configuredServers {
+		serverConfigs[configured.Config.URL] = configured.Config
+	}
+
+	baseURL := strings.TrimSuffix(app.config.MCPBridgeBaseURL, "/")
+	for _, i := range pending {
+		serverConfig, ok := serverConfigs[servers[i].ServerURL]
+		if !ok || !mcp.RequiresBridge(serverConfig.Transport) {
+			return mcpBridgeError(http.StatusBadRequest, fmt.Sprintf("MCP server %s is not a configured stdio or WebSocket server", servers[i].ServerURL))
+		}
+
+		path, token, err := bridge.Endpoint(ctx, namespace, serverConfig)
+		if err != nil {
+			app.logger.Error("Failed to start bridged MCP server", "server_name", serverConfig.Name, "namespace", namespace, "error", err)
+			return mcpBridgeError(http.StatusServiceUnavailable, fmt.Sprintf("MCP server %s is not available: %v", serverConfig.Name, err))
+		}
+
+		servers[i].ServerURL = baseURL + path
+		if servers[i].Headers == nil {
+			servers[i].Headers = make(map[string]string)
+		}
+		servers[i].Headers[constants.MCPBridgeTokenHeader] = token
+	}
+	return nil
+}
+
+// isBridgedMCPServerURL reports whether the URL belongs to a server that is only reachable through the bridge
+func isBridgedMCPServerURL(serverURL string) bool {
+	for _, scheme := range []string{constants.MCPStdioURLScheme, "ws://", "wss://"} {
+		if strings.HasPrefix(strings.ToLower(serverURL), scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// mcpBridge returns the bridge for stdio and WebSocket servers, or nil when bridging is disabled. Bridging
+// needs the in-cluster URL at which LlamaStack reaches the BFF; the host of a user request is not trusted for it.
+func (app *App) mcpBridge() *mcp.Bridge {
+	if app.mcpClientFactory == nil || app.config.MCPBridgeBaseURL == "" {
+		return nil
+	}
+	return app.mcpClientFactory.GetBridge()
+}
+
+func mcpBridgeError(statusCode int, message string) *integrations.HTTPError {
+	return &integrations.HTTPError{
+		StatusCode: statusCode,
+		ErrorResponse: integrations.ErrorResponse{
+			Code:    strconv.Itoa(statusCode),
+			Message: message,
+		},
+	}
+}
+
+// hasAuthorizationHeader reports whether the headers already carry credentials
+func hasAuthorizationHeader(headers map[string]string) bool {
+	for name, value := range headers {
+		if strings.EqualFold(name, "Authorization") && value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// conversationTurn identifies the stored conversation a response should be recorded in
+type conversationTurn struct {
+	namespace      string
+	owner          string
+	conversationID string
+	input          string
+	settings       models.ConversationSettings
+}
+
+// recordConversationTurn stores a completed response in its conversation.
+// Failures are logged rather than returned so that history never blocks a response.
+func (app *App) recordConversationTurn(ctx context.Context, turn *conversationTurn, responseData *ResponseData) {
+	if turn == nil {
+		return
+	}
+
+	message, err := buildConversationMessage(turn.input, turn.settings, responseData)
+	if err != nil {
+		app.logger.Warn("Failed to build conversation message", "conversation_id", turn.conversationID, "error", err)
+		return
+	}
+
+	if _, err := app.repositories.Conversations.AddMessage(ctx, turn.namespace, turn.owner, turn.conversationID, message); err != nil {
+		app.logger.Warn("Failed to record conversation turn", "conversation_id", turn.conversationID, "error", err)
 	}
 }
 
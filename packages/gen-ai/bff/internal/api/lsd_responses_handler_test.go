@@ -7,14 +7,18 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/opendatahub-io/gen-ai/internal/config"
 	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
 	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
+	"github.com/opendatahub-io/gen-ai/internal/models"
 	"github.com/opendatahub-io/gen-ai/internal/repositories"
 	"github.com/opendatahub-io/gen-ai/internal/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLlamaStackCreateResponseHandler(t *testing.T) {
@@ -22,7 +26,8 @@ func TestLlamaStackCreateResponseHandler(t *testing.T) {
 	llamaStackClientFactory := lsmocks.NewMockClientFactory()
 	app := App{
 		config: config.EnvConfig{
-			Port: 4000,
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
 		},
 		llamaStackClientFactory: llamaStackClientFactory,
 		repositories:            repositories.NewRepositories(),
@@ -40,6 +45,8 @@ func TestLlamaStackCreateResponseHandler(t *testing.T) {
 			return nil, err
 		}
 		req.Header.Set("Content-Type", "application/json")
+		// Simulate the namespace middleware
+		req = req.WithContext(context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace))
 		return req, nil
 	}
 
@@ -91,6 +98,26 @@ func TestLlamaStackCreateResponseHandler(t *testing.T) {
 		assert.Contains(t, messageItem, "content")
 	})
 
+	t.Run("should reject vector stores the caller cannot read", func(t *testing.T) {
+		payload := CreateResponseRequest{
+			Input:          "Tell me about AI",
+			Model:          "llama-3.1-8b",
+			VectorStoreIDs: []string{lsmocks.MockOtherUserVectorStoreID},
+		}
+
+		req, err := createJSONRequest(payload)
+		assert.NoError(t, err)
+
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx := context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
 	t.Run("should create response with all optional parameters", func(t *testing.T) {
 		temperature := 0.7
 		topP := 0.9
@@ -135,6 +162,149 @@ func TestLlamaStackCreateResponseHandler(t *testing.T) {
 		assert.Equal(t, "completed", data["status"])
 	})
 
+	t.Run("should create response with generation settings and report them", func(t *testing.T) {
+		maxOutputTokens := int64(256)
+		seed := int64(42)
+		presencePenalty := 0.5
+		parallelToolCalls := false
+
+		payload := CreateResponseRequest{
+			Input:             "Tell me about AI",
+			Model:             "llama-3.1-8b",
+			VectorStoreIDs:    []string{"vs_test123"},
+			MaxOutputTokens:   &maxOutputTokens,
+			Stop:              []string{"\n\n"},
+			Seed:              &seed,
+			PresencePenalty:   &presencePenalty,
+			ParallelToolCalls: &parallelToolCalls,
+			ToolChoice:        &models.ToolChoice{Type: "file_search"},
+			Truncation:        "auto",
+		}
+
+		req, err := createJSONRequest(payload)
+		assert.NoError(t, err)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		req = req.WithContext(context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient))
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var response map[string]map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		data := response["data"]
+		assert.Equal(t, float64(256), data["max_output_tokens"])
+		assert.Equal(t, []interface{}{"\n\n"}, data["stop"])
+		assert.Equal(t, float64(42), data["seed"])
+		assert.Equal(t, 0.5, data["presence_penalty"])
+		assert.Equal(t, false, data["parallel_tool_calls"])
+		assert.Equal(t, map[string]interface{}{"type": "file_search"}, data["tool_choice"])
+		assert.Equal(t, "auto", data["truncation"])
+	})
+
+	t.Run("should reject invalid generation settings", func(t *testing.T) {
+		zero := int64(0)
+		penalty := 2.5
+		temperature := 3.0
+		for name, payload := range map[string]CreateResponseRequest{
+			"temperature out of range":   {Temperature: &temperature},
+			"no output tokens":           {MaxOutputTokens: &zero},
+			"too many stop sequences":    {Stop: []string{"a", "b", "c", "d", "e"}},
+			"penalty out of range":       {FrequencyPenalty: &penalty},
+			"unknown truncation":         {Truncation: "middle"},
+			"unknown tool choice mode":   {ToolChoice: &models.ToolChoice{Mode: "always"}},
+			"file search without stores": {ToolChoice: &models.ToolChoice{Type: "file_search"}},
+			"unknown MCP server":         {ToolChoice: &models.ToolChoice{Type: "mcp", ServerLabel: "github"}},
+		} {
+			payload.Input = "Hello"
+			payload.Model = "llama-3.1-8b"
+			req, err := createJSONRequest(payload)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, name)
+		}
+	})
+
+	t.Run("should create response from images without input text", func(t *testing.T) {
+		payload := CreateResponseRequest{
+			Model: "llava",
+			InputContent: []llamastack.InputContentPart{
+				{Type: "input_image", ImageURL: "https://example.com/cat.png"},
+				{Type: "input_image", FileID: "file-abc123"},
+			},
+		}
+
+		req, err := createJSONRequest(payload)
+		assert.NoError(t, err)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		req = req.WithContext(context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient))
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		assert.Contains(t, rr.Body.String(), "looked at 2 images and 0 files")
+	})
+
+	t.Run("should reject invalid content parts", func(t *testing.T) {
+		for name, payload := range map[string]CreateResponseRequest{
+			"image of unsupported type": {
+				Input:        "What is this?",
+				InputContent: []llamastack.InputContentPart{{Type: "input_image", ImageURL: "data:image/svg+xml;base64,PHN2Zz4="}},
+			},
+			"parts on assistant messages": {
+				Input: "What is this?",
+				ChatContext: []ChatContextMessage{
+					{Role: "assistant", Content: "A cat.", ContentParts: []llamastack.InputContentPart{{Type: "input_image", FileID: "file-abc123"}}},
+				},
+			},
+		} {
+			payload.Model = "llava"
+			req, err := createJSONRequest(payload)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, name)
+		}
+	})
+
+	t.Run("should reject too many content parts across chat_context", func(t *testing.T) {
+		parts := imageParts(constants.InputContentMaxParts)
+		payload := CreateResponseRequest{
+			Input: "What changed between these?",
+			Model: "llava",
+			ChatContext: []ChatContextMessage{
+				{Role: "user", Content: "First batch", ContentParts: parts},
+				{Role: "user", Content: "Second batch", ContentParts: parts},
+				{Role: "user", Content: "Third batch", ContentParts: parts},
+			},
+		}
+		req, err := createJSONRequest(payload)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "content parts across input_content and chat_context")
+	})
+
+	t.Run("should reject bodies larger than the request cap", func(t *testing.T) {
+		// JSON allows any amount of whitespace before the request
+		body := io.MultiReader(bytes.NewReader(bytes.Repeat([]byte(" "), constants.ResponseRequestMaxBytes)), strings.NewReader(`{"input":"Hi","model":"llama-3.1-8b"}`))
+		req := httptest.NewRequest(http.MethodPost, "/gen-ai/api/v1/responses?namespace="+testutil.TestNamespace, body)
+		req = req.WithContext(context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace))
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+
 	t.Run("should return error when input is missing", func(t *testing.T) {
 		payload := CreateResponseRequest{
 			Model: "llama-3.1-8b",
@@ -255,6 +425,198 @@ func TestLlamaStackCreateResponseHandler(t *testing.T) {
 		assert.Equal(t, "completed", data["status"])
 	})
 
+	t.Run("should cite retrieved files by name with their chunks", func(t *testing.T) {
+		payload := CreateResponseRequest{
+			Input:          "Search for information about AI",
+			Model:          "llama-3.1-8b",
+			VectorStoreIDs: []string{"vs_documents"},
+			Include:        []string{"file_search_call.results"},
+		}
+
+		req, err := createJSONRequest(payload)
+		assert.NoError(t, err)
+
+		// Simulate AttachLlamaStackClient middleware: create client and add to context
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx := context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var response struct {
+			Data ResponseData `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		message := response.Data.Output[len(response.Data.Output)-1]
+		require.Len(t, message.Content, 1)
+		require.Len(t, message.Content[0].Annotations, 1)
+
+		citation := message.Content[0].Annotations[0]
+		assert.Equal(t, "file_citation", citation.Type)
+		assert.Equal(t, "file-mock123abc456def", citation.FileID)
+		assert.Equal(t, "mock_document.txt", citation.Filename)
+		assert.Contains(t, citation.Text, "mock retrieved content")
+		assert.Equal(t, len(message.Content[0].Text), *citation.Index)
+	})
+
+	t.Run("should reject unsupported include values", func(t *testing.T) {
+		payload := CreateResponseRequest{
+			Input:   "Hello",
+			Model:   "llama-3.1-8b",
+			Include: []string{"reasoning.encrypted_content"},
+		}
+
+		req, err := createJSONRequest(payload)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should create response with file search options", func(t *testing.T) {
+		maxNumResults := int64(5)
+		payload := CreateResponseRequest{
+			Input:          "What is the travel policy?",
+			Model:          "llama-3.1-8b",
+			VectorStoreIDs: []string{"vs_documents"},
+			FileSearch: &FileSearchOptions{
+				MaxNumResults:  &maxNumResults,
+				RankingOptions: &VectorStoreSearchRankingOptions{Ranker: "auto"},
+				Filters:        &llamastack.VectorStoreSearchFilter{Type: "eq", Key: "department", Value: "finance"},
+			},
+		}
+
+		req, err := createJSONRequest(payload)
+		assert.NoError(t, err)
+
+		// Simulate AttachLlamaStackClient middleware: create client and add to context
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx := context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+	})
+
+	t.Run("should reject invalid file search options", func(t *testing.T) {
+		for name, payload := range map[string]CreateResponseRequest{
+			"without vector stores": {
+				Input:      "What is the travel policy?",
+				Model:      "llama-3.1-8b",
+				FileSearch: &FileSearchOptions{Filters: &llamastack.VectorStoreSearchFilter{Type: "eq", Key: "department", Value: "finance"}},
+			},
+			"invalid filter": {
+				Input:          "What is the travel policy?",
+				Model:          "llama-3.1-8b",
+				VectorStoreIDs: []string{"vs_documents"},
+				FileSearch:     &FileSearchOptions{Filters: &llamastack.VectorStoreSearchFilter{Type: "contains", Key: "department", Value: "fin"}},
+			},
+		} {
+			req, err := createJSONRequest(payload)
+			assert.NoError(t, err)
+
+			llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+			ctx := context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, name)
+		}
+	})
+
+	t.Run("should return output matching the JSON schema without a format error", func(t *testing.T) {
+		payload := CreateResponseRequest{
+			Input: "Summarize the travel policy",
+			Model: "llama-3.1-8b",
+			ResponseFormat: &ResponseFormat{
+				Type: "json_schema",
+				Name: "summary",
+				Schema: map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"answer": map[string]interface{}{"type": "string"}},
+					"required":   []string{"answer"},
+				},
+				Strict: true,
+			},
+		}
+
+		req, err := createJSONRequest(payload)
+		assert.NoError(t, err)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		req = req.WithContext(context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient))
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var response struct {
+			Data ResponseData `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		for _, item := range response.Data.Output {
+			assert.NotEqual(t, responseFormatErrorType, item.Type)
+		}
+	})
+
+	t.Run("should report output that does not match the JSON schema", func(t *testing.T) {
+		payload := CreateResponseRequest{
+			Input: "Extract the invoice",
+			Model: "llama-3.1-8b",
+			ResponseFormat: &ResponseFormat{
+				Type: "json_schema",
+				Name: "invoice",
+				Schema: map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"total": map[string]interface{}{"type": "number"}},
+					"required":   []string{"total"},
+				},
+			},
+		}
+
+		req, err := createJSONRequest(payload)
+		assert.NoError(t, err)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		req = req.WithContext(context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient))
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var response struct {
+			Data ResponseData `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.NotEmpty(t, response.Data.Output)
+		item := response.Data.Output[len(response.Data.Output)-1]
+		assert.Equal(t, responseFormatErrorType, item.Type)
+		assert.Equal(t, "invoice", item.Name)
+		assert.Contains(t, item.Error, "total")
+	})
+
+	t.Run("should reject invalid response formats", func(t *testing.T) {
+		for name, format := range map[string]*ResponseFormat{
+			"unknown type":   {Type: "yaml"},
+			"missing name":   {Type: "json_schema", Schema: map[string]interface{}{"type": "object"}},
+			"invalid schema": {Type: "json_schema", Name: "invoice", Schema: map[string]interface{}{"type": 42}},
+		} {
+			req, err := createJSONRequest(CreateResponseRequest{Input: "Hello", Model: "llama-3.1-8b", ResponseFormat: format})
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, name)
+		}
+	})
+
 	t.Run("should use unified repository pattern", func(t *testing.T) {
 		assert.NotNil(t, app.repositories)
 		assert.NotNil(t, app.repositories.Responses)
@@ -699,4 +1061,210 @@ func TestLlamaStackCreateResponseHandler(t *testing.T) {
 		text := firstContent["text"].(string)
 		assert.Contains(t, text, "Continuing from previous response prev-response-123")
 	})
+
+	t.Run("should stop with an approval request when the MCP tool requires approval", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"input": "What is the latest llama-stack release?",
+			"model": "llama-3.1-8b",
+			"mcp_servers": []map[string]interface{}{
+				{
+					"server_label":     "github",
+					"server_url":       "https://api.githubcopilot.com/mcp/x/repos/readonly",
+					"headers":          map[string]string{},
+					"require_approval": map[string]interface{}{"always": []string{"get_latest_release"}},
+				},
+			},
+		}
+
+		req, err := createJSONRequest(payload)
+		assert.NoError(t, err)
+
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx := context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+
+		var data ResponseData
+		err = json.Unmarshal(rr.Body.Bytes(), &llamastack.APIResponse{Data: &data})
+		assert.NoError(t, err)
+
+		assert.Equal(t, lsmocks.MockApprovalResponseID, data.ID)
+		assert.True(t, data.RequiresApproval)
+
+		var approvalItem *OutputItem
+		for i := range data.Output {
+			assert.NotEqual(t, "mcp_call", data.Output[i].Type, "tool must not run before approval")
+			if data.Output[i].Type == "mcp_approval_request" {
+				approvalItem = &data.Output[i]
+			}
+		}
+		if assert.NotNil(t, approvalItem, "Should have mcp_approval_request output item") {
+			assert.Equal(t, lsmocks.MockApprovalRequestID, approvalItem.ID)
+			assert.Equal(t, "github", approvalItem.ServerLabel)
+			assert.Equal(t, "get_latest_release", approvalItem.Name)
+			assert.NotEmpty(t, approvalItem.Arguments)
+		}
+	})
+
+	t.Run("should run the MCP tool without approval when it is in the never list", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"input": "What is the latest llama-stack release?",
+			"model": "llama-3.1-8b",
+			"mcp_servers": []map[string]interface{}{
+				{
+					"server_label":     "github",
+					"server_url":       "https://api.githubcopilot.com/mcp/x/repos/readonly",
+					"headers":          map[string]string{},
+					"require_approval": map[string]interface{}{"never": []string{"get_latest_release"}},
+				},
+			},
+		}
+
+		req, err := createJSONRequest(payload)
+		assert.NoError(t, err)
+
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx := context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+
+		var data ResponseData
+		err = json.Unmarshal(rr.Body.Bytes(), &llamastack.APIResponse{Data: &data})
+		assert.NoError(t, err)
+		assert.False(t, data.RequiresApproval)
+
+		var outputTypes []string
+		for _, item := range data.Output {
+			outputTypes = append(outputTypes, item.Type)
+		}
+		assert.Contains(t, outputTypes, "mcp_call")
+		assert.NotContains(t, outputTypes, "mcp_approval_request")
+	})
+
+	t.Run("should not call MCP tools excluded by allowed_tools", func(t *testing.T) {
+		payload := CreateResponseRequest{
+			Input: "What is the latest llama-stack release?",
+			Model: "llama-3.1-8b",
+			MCPServers: []MCPServer{
+				{
+					ServerLabel:  "github",
+					ServerURL:    "https://api.githubcopilot.com/mcp/x/repos/readonly",
+					AllowedTools: []string{"list_issues"},
+				},
+			},
+		}
+
+		req, err := createJSONRequest(payload)
+		assert.NoError(t, err)
+
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx := context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+
+		var data ResponseData
+		err = json.Unmarshal(rr.Body.Bytes(), &llamastack.APIResponse{Data: &data})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, data.Output)
+		for _, item := range data.Output {
+			assert.NotEqual(t, "mcp_call", item.Type, "tool outside allowed_tools must not be called")
+		}
+	})
+
+	t.Run("should reject invalid approval policies", func(t *testing.T) {
+		invalidPolicies := map[string]interface{}{
+			"unknown setting":     "sometimes",
+			"empty tool lists":    map[string]interface{}{},
+			"tool in both lists":  map[string]interface{}{"always": []string{"create_issue"}, "never": []string{"create_issue"}},
+			"wrong type of value": 42,
+		}
+
+		for name, policy := range invalidPolicies {
+			t.Run(name, func(t *testing.T) {
+				payload := map[string]interface{}{
+					"input": "Hello",
+					"model": "llama-3.1-8b",
+					"mcp_servers": []map[string]interface{}{
+						{
+							"server_label":     "github",
+							"server_url":       "https://api.githubcopilot.com/mcp/x/repos/readonly",
+							"require_approval": policy,
+						},
+					},
+				}
+
+				req, err := createJSONRequest(payload)
+				assert.NoError(t, err)
+
+				llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+				ctx := context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient)
+				req = req.WithContext(ctx)
+
+				rr := httptest.NewRecorder()
+				app.LlamaStackCreateResponseHandler(rr, req, nil)
+
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
+			})
+		}
+	})
+}
+
+func TestMCPApprovalPolicyJSON(t *testing.T) {
+	t.Run("should decode a server-wide setting", func(t *testing.T) {
+		var policy MCPApprovalPolicy
+		assert.NoError(t, json.Unmarshal([]byte(`"always"`), &policy))
+		assert.Equal(t, MCPApprovalPolicy{Setting: "always"}, policy)
+
+		encoded, err := json.Marshal(policy)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `"always"`, string(encoded))
+	})
+
+	t.Run("should decode per-tool lists", func(t *testing.T) {
+		var policy MCPApprovalPolicy
+		assert.NoError(t, json.Unmarshal([]byte(`{"always":["create_issue"],"never":["get_latest_release"]}`), &policy))
+		assert.Equal(t, []string{"create_issue"}, policy.Always)
+		assert.Equal(t, []string{"get_latest_release"}, policy.Never)
+
+		param := policy.toParam()
+		assert.Equal(t, []string{"create_issue"}, param.AlwaysTools)
+		assert.Equal(t, []string{"get_latest_release"}, param.NeverTools)
+	})
+
+	t.Run("should map a missing policy to nil", func(t *testing.T) {
+		var policy *MCPApprovalPolicy
+		assert.Nil(t, policy.toParam())
+	})
+}
+
+func TestBridgeMCPServers(t *testing.T) {
+	app := App{repositories: repositories.NewRepositories()}
+	req := httptest.NewRequest(http.MethodPost, "/gen-ai/api/v1/lsd/responses?namespace="+testutil.TestNamespace, nil)
+
+	t.Run("should leave HTTP servers untouched", func(t *testing.T) {
+		servers := []MCPServer{{ServerLabel: "github", ServerURL: "http://localhost:9090/sse"}}
+		assert.Nil(t, app.bridgeMCPServers(req, servers))
+		assert.Equal(t, "http://localhost:9090/sse", servers[0].ServerURL)
+	})
+
+	t.Run("should reject stdio and WebSocket servers without a bridge", func(t *testing.T) {
+		for _, serverURL := range []string{"stdio://filesystem", "wss://notes.example.com/mcp"} {
+			httpErr := app.bridgeMCPServers(req, []MCPServer{{ServerLabel: "bridged", ServerURL: serverURL}})
+			if assert.NotNil(t, httpErr) {
+				assert.Equal(t, http.StatusBadRequest, httpErr.StatusCode)
+			}
+		}
+	})
 }
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// StreamingEventKind is the SSE event name a LlamaStack Responses stream event is forwarded under.
+// Related event types share a kind so clients can subscribe to e.g. all MCP call progress at once,
+// while the original event type is preserved in the event payload.
+type StreamingEventKind string
+
+const (
+	StreamingEventKindLifecycle    StreamingEventKind = "response"       // Response created, in progress, completed, incomplete
+	StreamingEventKindOutputItem   StreamingEventKind = "output_item"    // Output item added or done (carries the full item)
+	StreamingEventKindContentPart  StreamingEventKind = "content_part"   // Content part added or done
+	StreamingEventKindText         StreamingEventKind = "text"           // Output text deltas and final text
+	StreamingEventKindAnnotation   StreamingEventKind = "annotation"     // Output text annotation added
+	StreamingEventKindRefusal      StreamingEventKind = "refusal"        // Refusal deltas and final refusal
+	StreamingEventKindReasoning    StreamingEventKind = "reasoning"      // Reasoning text and summary deltas
+	StreamingEventKindFunctionCall StreamingEventKind = "function_call"  // Function/custom tool call arguments
+	StreamingEventKindMCPCall      StreamingEventKind = "mcp_call"       // MCP tool call arguments and progress
+	StreamingEventKindMCPListTools StreamingEventKind = "mcp_list_tools" // MCP tool discovery progress
+	StreamingEventKindFileSearch   StreamingEventKind = "file_search"    // File search (RAG) progress
+	StreamingEventKindWebSearch    StreamingEventKind = "web_search"     // Web search progress
+	StreamingEventKindError        StreamingEventKind = "error"          // Stream errors and failed responses
+
+	// StreamingEventKindMCPApprovalRequest is used instead of output_item when a completed output item
+	// is an MCP tool call waiting for approval, so clients can prompt the user without inspecting items
+	StreamingEventKindMCPApprovalRequest StreamingEventKind = "mcp_approval_request"
+)
+
+// streamingEventKinds maps every Responses stream event type we forward to its SSE event name.
+// Audio, image generation and code interpreter events are not produced for the playground and are dropped.
+// Full list of events: https://platform.openai.com/docs/api-reference/responses-streaming
+var streamingEventKinds = map[string]StreamingEventKind{
+	"response.created":     StreamingEventKindLifecycle,
+	"response.queued":      StreamingEventKindLifecycle,
+	"response.in_progress": StreamingEventKindLifecycle,
+	"response.completed":   StreamingEventKindLifecycle,
+	"response.incomplete":  StreamingEventKindLifecycle,
+
+	"response.output_item.added": StreamingEventKindOutputItem,
+	"response.output_item.done":  StreamingEventKindOutputItem,
+
+	"response.content_part.added": StreamingEventKindContentPart,
+	"response.content_part.done":  StreamingEventKindContentPart,
+
+	"response.output_text.delta": StreamingEventKindText,
+	"response.output_text.done":  StreamingEventKindText,
+
+	"response.output_text.annotation.added": StreamingEventKindAnnotation,
+
+	"response.refusal.delta": StreamingEventKindRefusal,
+	"response.refusal.done":  StreamingEventKindRefusal,
+
+	"response.reasoning_text.delta":         StreamingEventKindReasoning,
+	"response.reasoning_text.done":          StreamingEventKindReasoning,
+	"response.reasoning_summary_part.added": StreamingEventKindReasoning,
+	"response.reasoning_summary_part.done":  StreamingEventKindReasoning,
+	"response.reasoning_summary_text.delta": StreamingEventKindReasoning,
+	"response.reasoning_summary_text.done":  StreamingEventKindReasoning,
+
+	"response.function_call_arguments.delta": StreamingEventKindFunctionCall,
+	"response.function_call_arguments.done":  StreamingEventKindFunctionCall,
+	"response.custom_tool_call_input.delta":  StreamingEventKindFunctionCall,
+	"response.custom_tool_call_input.done":   StreamingEventKindFunctionCall,
+
+	"response.mcp_call_arguments.delta": StreamingEventKindMCPCall,
+	"response.mcp_call_arguments.done":  StreamingEventKindMCPCall,
+	"response.mcp_call.in_progress":     StreamingEventKindMCPCall,
+	"response.mcp_call.completed":       StreamingEventKindMCPCall,
+	"response.mcp_call.failed":          StreamingEventKindMCPCall,
+
+	"response.mcp_list_tools.in_progress": StreamingEventKindMCPListTools,
+	"response.mcp_list_tools.completed":   StreamingEventKindMCPListTools,
+	"response.mcp_list_tools.failed":      StreamingEventKindMCPListTools,
+
+	"response.file_search_call.in_progress": StreamingEventKindFileSearch,
+	"response.file_search_call.searching":   StreamingEventKindFileSearch,
+	"response.file_search_call.completed":   StreamingEventKindFileSearch,
+
+	"response.web_search_call.in_progress": StreamingEventKindWebSearch,
+	"response.web_search_call.searching":   StreamingEventKindWebSearch,
+	"response.web_search_call.completed":   StreamingEventKindWebSearch,
+
+	"error":           StreamingEventKindError,
+	"response.failed": StreamingEventKindError,
+}
+
+// streamingEventKindFor returns the SSE event name for an event type and whether it should be forwarded
+func streamingEventKindFor(eventType string) (StreamingEventKind, bool) {
+	kind, ok := streamingEventKinds[eventType]
+	return kind, ok
+}
+
+// StreamingEvent represents a streaming event.
+// Only the fields relevant to the event type are populated; see StreamingEventKind for the grouping.
+type StreamingEvent struct {
+	Delta          string             `json:"delta"`
+	SequenceNumber int64              `json:"sequence_number"`
+	Type           string             `json:"type"`
+	Kind           StreamingEventKind `json:"kind"`
+	ItemID         string             `json:"item_id"`
+	OutputIndex    int                `json:"output_index"`
+	ContentIndex   int                `json:"content_index,omitempty"`
+	Response       *ResponseData      `json:"response,omitempty"`
+
+	// Item is the full output item for output_item events (tool calls with arguments and output,
+	// file search calls with queries and results, messages)
+	Item *OutputItem `json:"item,omitempty"`
+
+	// Part is the content part for content_part and reasoning_summary_part events
+	Part *ContentItem `json:"part,omitempty"`
+
+	// Final values for *.done events
+	Text      string `json:"text,omitempty"`      // output_text.done, reasoning text done
+	Arguments string `json:"arguments,omitempty"` // function/MCP call arguments done
+	Refusal   string `json:"refusal,omitempty"`   // refusal.done
+
+	// Annotation fields for output_text.annotation.added events
+	Annotation      *Annotation `json:"annotation,omitempty"`
+	AnnotationIndex int         `json:"annotation_index,omitempty"`
+
+	// Error is set for error and response.failed events
+	Error *ResponseError `json:"error,omitempty"`
+
+	// Model is set on the events of compared responses, which share one stream
+	Model string `json:"model,omitempty"`
+}
+
+// llamaStackStreamEvent is the wire format of a LlamaStack stream event.
+// Error events carry their details at the top level rather than in a nested object.
+type llamaStackStreamEvent struct {
+	StreamingEvent
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Param   string `json:"param"`
+}
+
+// rawJSONEvent is implemented by OpenAI SDK types, which keep the exact JSON received from the server
+type rawJSONEvent interface {
+	RawJSON() string
+}
+
+// convertToStreamingEvent converts a LlamaStack event to our clean StreamingEvent schema.
+// It returns nil for event types that are not forwarded to clients.
+func convertToStreamingEvent(event interface{}) *StreamingEvent {
+	// Prefer the raw server JSON: marshaling SDK unions directly would emit zero values for every variant field
+	var eventJSON []byte
+	if raw, ok := event.(rawJSONEvent); ok && raw.RawJSON() != "" {
+		eventJSON = []byte(raw.RawJSON())
+	} else {
+		var err error
+		eventJSON, err = json.Marshal(event)
+		if err != nil {
+			return nil
+		}
+	}
+
+	// Go JSON ignores extra fields automatically
+	var wireEvent llamaStackStreamEvent
+	if err := json.Unmarshal(eventJSON, &wireEvent); err != nil {
+		return nil
+	}
+
+	kind, ok := streamingEventKindFor(wireEvent.Type)
+	if !ok {
+		return nil
+	}
+
+	streamingEvent := wireEvent.StreamingEvent
+	streamingEvent.Kind = kind
+	if streamingEvent.Response != nil {
+		streamingEvent.Response.normalize()
+	}
+	if streamingEvent.Type == "response.output_item.done" && streamingEvent.Item != nil && streamingEvent.Item.Type == mcpApprovalRequestType {
+		streamingEvent.Kind = StreamingEventKindMCPApprovalRequest
+	}
+
+	switch streamingEvent.Type {
+	case "error":
+		streamingEvent.Error = &ResponseError{
+			Code:    wireEvent.Code,
+			Message: wireEvent.Message,
+			Param:   wireEvent.Param,
+		}
+	case "response.failed":
+		if streamingEvent.Response != nil && streamingEvent.Response.Error != nil {
+			streamingEvent.Error = streamingEvent.Response.Error
+		} else {
+			streamingEvent.Error = &ResponseError{Message: "response generation failed"}
+		}
+	}
+
+	return &streamingEvent
+}
+
+// writeStreamingEvent writes a streaming event in SSE format, using its kind as the SSE event name
+func writeStreamingEvent(w io.Writer, event *StreamingEvent) error {
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal streaming event: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Kind, eventData); err != nil {
+		return fmt.Errorf("failed to write streaming event: %w", err)
+	}
+	return nil
+}
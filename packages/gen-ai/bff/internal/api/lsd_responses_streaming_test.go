@@ -0,0 +1,199 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/openai/openai-go/v2/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeStreamEvent decodes an event the same way the OpenAI SDK does when reading a LlamaStack stream
+func decodeStreamEvent(t *testing.T, raw string) responses.ResponseStreamEventUnion {
+	var event responses.ResponseStreamEventUnion
+	require.NoError(t, json.Unmarshal([]byte(raw), &event))
+	return event
+}
+
+func TestConvertToStreamingEvent(t *testing.T) {
+	t.Run("should forward text deltas with the text kind", func(t *testing.T) {
+		event := decodeStreamEvent(t, `{"type":"response.output_text.delta","sequence_number":4,"item_id":"msg_1","output_index":0,"content_index":0,"delta":"Hello"}`)
+
+		streamingEvent := convertToStreamingEvent(event)
+		require.NotNil(t, streamingEvent)
+		assert.Equal(t, StreamingEventKindText, streamingEvent.Kind)
+		assert.Equal(t, "Hello", streamingEvent.Delta)
+		assert.Equal(t, int64(4), streamingEvent.SequenceNumber)
+		assert.Nil(t, streamingEvent.Response)
+		assert.Nil(t, streamingEvent.Item)
+	})
+
+	t.Run("should forward MCP call arguments", func(t *testing.T) {
+		event := decodeStreamEvent(t, `{"type":"response.mcp_call_arguments.done","sequence_number":7,"item_id":"mcp_1","output_index":1,"arguments":"{\"repo\":\"llama-stack\"}"}`)
+
+		streamingEvent := convertToStreamingEvent(event)
+		require.NotNil(t, streamingEvent)
+		assert.Equal(t, StreamingEventKindMCPCall, streamingEvent.Kind)
+		assert.Equal(t, `{"repo":"llama-stack"}`, streamingEvent.Arguments)
+		assert.Equal(t, "mcp_1", streamingEvent.ItemID)
+	})
+
+	t.Run("should forward completed MCP call items with their output", func(t *testing.T) {
+		event := decodeStreamEvent(t, `{"type":"response.output_item.done","sequence_number":9,"output_index":1,"item":{"id":"mcp_1","type":"mcp_call","server_label":"github","name":"get_latest_release","arguments":"{}","output":"v1.95.0","error":"partial failure"}}`)
+
+		streamingEvent := convertToStreamingEvent(event)
+		require.NotNil(t, streamingEvent)
+		assert.Equal(t, StreamingEventKindOutputItem, streamingEvent.Kind)
+		require.NotNil(t, streamingEvent.Item)
+		assert.Equal(t, "mcp_call", streamingEvent.Item.Type)
+		assert.Equal(t, "github", streamingEvent.Item.ServerLabel)
+		assert.Equal(t, "get_latest_release", streamingEvent.Item.Name)
+		assert.Equal(t, "v1.95.0", streamingEvent.Item.Output)
+		assert.Equal(t, "partial failure", streamingEvent.Item.Error)
+	})
+
+	t.Run("should forward MCP approval requests under their own kind", func(t *testing.T) {
+		event := decodeStreamEvent(t, `{"type":"response.output_item.done","sequence_number":6,"output_index":1,"item":{"id":"mcpr_1","type":"mcp_approval_request","server_label":"github","name":"create_issue","arguments":"{\"title\":\"Bug\"}"}}`)
+
+		streamingEvent := convertToStreamingEvent(event)
+		require.NotNil(t, streamingEvent)
+		assert.Equal(t, StreamingEventKindMCPApprovalRequest, streamingEvent.Kind)
+		require.NotNil(t, streamingEvent.Item)
+		assert.Equal(t, "mcpr_1", streamingEvent.Item.ID)
+		assert.Equal(t, "create_issue", streamingEvent.Item.Name)
+
+		added := convertToStreamingEvent(decodeStreamEvent(t, `{"type":"response.output_item.added","sequence_number":5,"output_index":1,"item":{"id":"mcpr_1","type":"mcp_approval_request","server_label":"github","name":"create_issue","arguments":""}}`))
+		require.NotNil(t, added)
+		assert.Equal(t, StreamingEventKindOutputItem, added.Kind)
+	})
+
+	t.Run("should flag completed responses waiting for approval", func(t *testing.T) {
+		event := decodeStreamEvent(t, `{"type":"response.completed","sequence_number":7,"response":{"id":"resp_1","model":"llama","status":"completed","created_at":1,"output":[{"id":"mcpr_1","type":"mcp_approval_request","server_label":"github","name":"create_issue","arguments":"{}"}]}}`)
+
+		streamingEvent := convertToStreamingEvent(event)
+		require.NotNil(t, streamingEvent)
+		require.NotNil(t, streamingEvent.Response)
+		assert.True(t, streamingEvent.Response.RequiresApproval)
+	})
+
+	t.Run("should forward file search results with scores and filenames", func(t *testing.T) {
+		event := decodeStreamEvent(t, `{"type":"response.output_item.done","sequence_number":5,"output_index":0,"item":{"id":"fs_1","type":"file_search_call","status":"completed","queries":["what is rag"],"results":[{"file_id":"file-1","filename":"guide.pdf","score":0.91,"text":"RAG combines retrieval and generation"}]}}`)
+
+		streamingEvent := convertToStreamingEvent(event)
+		require.NotNil(t, streamingEvent)
+		require.NotNil(t, streamingEvent.Item)
+		assert.Equal(t, []string{"what is rag"}, streamingEvent.Item.Queries)
+		require.Len(t, streamingEvent.Item.Results, 1)
+		assert.Equal(t, "guide.pdf", streamingEvent.Item.Results[0].Filename)
+		assert.Equal(t, "file-1", streamingEvent.Item.Results[0].FileID)
+		assert.InDelta(t, 0.91, streamingEvent.Item.Results[0].Score, 0.0001)
+	})
+
+	t.Run("should forward typed file and URL citations", func(t *testing.T) {
+		event := decodeStreamEvent(t, `{"type":"response.output_text.annotation.added","sequence_number":9,"item_id":"msg_1","output_index":1,"content_index":0,"annotation_index":1,"annotation":{"type":"file_citation","file_id":"file-1","filename":"","index":0}}`)
+
+		streamingEvent := convertToStreamingEvent(event)
+		require.NotNil(t, streamingEvent)
+		assert.Equal(t, StreamingEventKindAnnotation, streamingEvent.Kind)
+		assert.Equal(t, 1, streamingEvent.AnnotationIndex)
+		require.NotNil(t, streamingEvent.Annotation)
+		assert.Equal(t, "file_citation", streamingEvent.Annotation.Type)
+		assert.Equal(t, "file-1", streamingEvent.Annotation.FileID)
+		require.NotNil(t, streamingEvent.Annotation.Index)
+		assert.Equal(t, 0, *streamingEvent.Annotation.Index)
+
+		event = decodeStreamEvent(t, `{"type":"response.output_text.annotation.added","sequence_number":10,"item_id":"msg_1","output_index":1,"content_index":0,"annotation_index":2,"annotation":{"type":"url_citation","url":"https://example.com/rag","title":"RAG","start_index":4,"end_index":18}}`)
+
+		streamingEvent = convertToStreamingEvent(event)
+		require.NotNil(t, streamingEvent)
+		require.NotNil(t, streamingEvent.Annotation)
+		assert.Equal(t, "https://example.com/rag", streamingEvent.Annotation.URL)
+		assert.Equal(t, "RAG", streamingEvent.Annotation.Title)
+		assert.Equal(t, 4, *streamingEvent.Annotation.StartIndex)
+		assert.Equal(t, 18, *streamingEvent.Annotation.EndIndex)
+		assert.Nil(t, streamingEvent.Annotation.Index)
+	})
+
+	t.Run("should forward file search progress events", func(t *testing.T) {
+		event := decodeStreamEvent(t, `{"type":"response.file_search_call.searching","sequence_number":3,"item_id":"fs_1","output_index":0}`)
+
+		streamingEvent := convertToStreamingEvent(event)
+		require.NotNil(t, streamingEvent)
+		assert.Equal(t, StreamingEventKindFileSearch, streamingEvent.Kind)
+	})
+
+	t.Run("should forward reasoning and refusal events", func(t *testing.T) {
+		reasoning := convertToStreamingEvent(decodeStreamEvent(t, `{"type":"response.reasoning_text.delta","sequence_number":2,"item_id":"rs_1","output_index":0,"content_index":0,"delta":"Thinking"}`))
+		require.NotNil(t, reasoning)
+		assert.Equal(t, StreamingEventKindReasoning, reasoning.Kind)
+		assert.Equal(t, "Thinking", reasoning.Delta)
+
+		refusal := convertToStreamingEvent(decodeStreamEvent(t, `{"type":"response.refusal.done","sequence_number":3,"item_id":"msg_1","output_index":0,"content_index":0,"refusal":"I can't help with that"}`))
+		require.NotNil(t, refusal)
+		assert.Equal(t, StreamingEventKindRefusal, refusal.Kind)
+		assert.Equal(t, "I can't help with that", refusal.Refusal)
+	})
+
+	t.Run("should map error events to a structured error", func(t *testing.T) {
+		event := decodeStreamEvent(t, `{"type":"error","sequence_number":8,"code":"rate_limit_exceeded","message":"Too many requests","param":"model"}`)
+
+		streamingEvent := convertToStreamingEvent(event)
+		require.NotNil(t, streamingEvent)
+		assert.Equal(t, StreamingEventKindError, streamingEvent.Kind)
+		require.NotNil(t, streamingEvent.Error)
+		assert.Equal(t, "rate_limit_exceeded", streamingEvent.Error.Code)
+		assert.Equal(t, "Too many requests", streamingEvent.Error.Message)
+		assert.Equal(t, "model", streamingEvent.Error.Param)
+	})
+
+	t.Run("should surface the response error for failed responses", func(t *testing.T) {
+		event := decodeStreamEvent(t, `{"type":"response.failed","sequence_number":8,"response":{"id":"resp_1","model":"llama","status":"failed","created_at":1,"error":{"code":"server_error","message":"Model crashed"}}}`)
+
+		streamingEvent := convertToStreamingEvent(event)
+		require.NotNil(t, streamingEvent)
+		assert.Equal(t, StreamingEventKindError, streamingEvent.Kind)
+		require.NotNil(t, streamingEvent.Error)
+		assert.Equal(t, "server_error", streamingEvent.Error.Code)
+		assert.Equal(t, "Model crashed", streamingEvent.Error.Message)
+	})
+
+	t.Run("should not report an error for completed responses", func(t *testing.T) {
+		event := map[string]interface{}{
+			"type":            "response.completed",
+			"sequence_number": 10,
+			"response": map[string]interface{}{
+				"id":     "resp_1",
+				"status": "completed",
+				"error":  map[string]interface{}{"code": "", "message": ""},
+			},
+		}
+
+		streamingEvent := convertToStreamingEvent(event)
+		require.NotNil(t, streamingEvent)
+		require.NotNil(t, streamingEvent.Response)
+		assert.Nil(t, streamingEvent.Response.Error)
+		assert.Nil(t, streamingEvent.Error)
+	})
+
+	t.Run("should drop event types that are not forwarded", func(t *testing.T) {
+		event := decodeStreamEvent(t, `{"type":"response.audio.delta","sequence_number":1,"delta":"AAAA"}`)
+		assert.Nil(t, convertToStreamingEvent(event))
+	})
+}
+
+func TestWriteStreamingEvent(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeStreamingEvent(&buf, &StreamingEvent{
+		Type:  "response.output_text.delta",
+		Kind:  StreamingEventKindText,
+		Delta: "Hi",
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "event: text\ndata: {")
+	assert.Contains(t, buf.String(), `"delta":"Hi"`)
+	assert.Contains(t, buf.String(), `"kind":"text"`)
+	assert.True(t, bytes.HasSuffix(buf.Bytes(), []byte("\n\n")))
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+)
+
+// ResponseUsage is the number of tokens a response used, as reported by LlamaStack
+type ResponseUsage struct {
+	InputTokens  int64 `json:"input_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+	TotalTokens  int64 `json:"total_tokens"`
+}
+
+// ResponseLatency is how long a response took, measured by the BFF from the time it was requested from LlamaStack
+type ResponseLatency struct {
+	TimeToFirstTokenMs *int64 `json:"time_to_first_token_ms,omitempty"` // Until the first delta, streaming responses only
+	TotalMs            int64  `json:"total_ms"`
+}
+
+// responseMeter measures the latency of a response
+type responseMeter struct {
+	start      time.Time
+	firstToken time.Time
+}
+
+func newResponseMeter() *responseMeter {
+	return &responseMeter{start: time.Now()}
+}
+
+// observe notes when the first text, reasoning or tool call delta of a stream arrives
+func (m *responseMeter) observe(event *StreamingEvent) {
+	if m.firstToken.IsZero() && strings.HasSuffix(event.Type, ".delta") {
+		m.firstToken = time.Now()
+	}
+}
+
+// latency returns the latency of the response so far
+func (m *responseMeter) latency() *ResponseLatency {
+	latency := &ResponseLatency{TotalMs: time.Since(m.start).Milliseconds()}
+	if !m.firstToken.IsZero() {
+		timeToFirstToken := m.firstToken.Sub(m.start).Milliseconds()
+		latency.TimeToFirstTokenMs = &timeToFirstToken
+	}
+	return latency
+}
+
+// measure sets the latency of a finished response
+func (m *responseMeter) measure(r *ResponseData) {
+	if r != nil {
+		r.Latency = m.latency()
+	}
+}
+
+// isFinalStreamingEvent reports whether an event carries the finished response
+func isFinalStreamingEvent(eventType string) bool {
+	return eventType == "response.completed" || eventType == "response.incomplete" || eventType == "response.failed"
+}
+
+// recordResponseUsage adds the tokens and latency of a response to the usage of the caller and the namespace,
+// under the requested model. A nil response records a failed request. Failures are logged rather than returned
+// so that accounting never blocks a response.
+func (app *App) recordResponseUsage(ctx context.Context, model string, meter *responseMeter, responseData *ResponseData) {
+	// Usage is recorded after the response was written, possibly to a client that went away
+	ctx = context.WithoutCancel(ctx)
+
+	namespace, ok := ctx.Value(constants.NamespaceQueryParameterKey).(string)
+	if !ok || namespace == "" {
+		return
+	}
+	username, err := app.getRequestUsername(ctx)
+	if err != nil {
+		app.logger.Warn("Failed to resolve user for response usage", "error", err)
+		return
+	}
+
+	latency := meter.latency()
+	record := models.UsageRecord{
+		Namespace: namespace,
+		Username:  username,
+		Model:     model,
+		Failed:    responseData == nil || responseData.Status == "failed",
+	}
+	if responseData != nil {
+		if responseData.Latency != nil {
+			latency = responseData.Latency
+		}
+		if responseData.Usage != nil {
+			record.InputTokens = responseData.Usage.InputTokens
+			record.OutputTokens = responseData.Usage.OutputTokens
+			record.TotalTokens = responseData.Usage.TotalTokens
+		}
+	}
+	record.LatencyMs = latency.TotalMs
+	record.TimeToFirstTokenMs = latency.TimeToFirstTokenMs
+
+	if err := app.repositories.Usage.RecordUsage(ctx, record); err != nil {
+		app.logger.Warn("Failed to record response usage", "model", model, "error", err)
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/opendatahub-io/gen-ai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseMeter(t *testing.T) {
+	t.Run("should measure the time to the first delta of a stream", func(t *testing.T) {
+		meter := &responseMeter{start: time.Now().Add(-2 * time.Second)}
+		meter.observe(&StreamingEvent{Type: "response.created"})
+		assert.True(t, meter.firstToken.IsZero())
+
+		meter.observe(&StreamingEvent{Type: "response.output_text.delta", Delta: "Hello"})
+		firstToken := meter.firstToken
+		meter.observe(&StreamingEvent{Type: "response.output_text.delta", Delta: " world"})
+		assert.Equal(t, firstToken, meter.firstToken)
+
+		var response ResponseData
+		meter.measure(&response)
+		require.NotNil(t, response.Latency)
+		require.NotNil(t, response.Latency.TimeToFirstTokenMs)
+		assert.GreaterOrEqual(t, *response.Latency.TimeToFirstTokenMs, int64(2000))
+		assert.GreaterOrEqual(t, response.Latency.TotalMs, *response.Latency.TimeToFirstTokenMs)
+	})
+
+	t.Run("should only measure the total latency without deltas", func(t *testing.T) {
+		meter := &responseMeter{start: time.Now().Add(-time.Second)}
+
+		var response ResponseData
+		meter.measure(&response)
+		assert.Nil(t, response.Latency.TimeToFirstTokenMs)
+		assert.GreaterOrEqual(t, response.Latency.TotalMs, int64(1000))
+	})
+
+	t.Run("should keep the usage reported by LlamaStack and drop zero usage", func(t *testing.T) {
+		var response ResponseData
+		require.NoError(t, json.Unmarshal([]byte(`{"id": "resp_1", "usage": {"input_tokens": 12, "output_tokens": 30, "total_tokens": 42, "output_tokens_details": {"reasoning_tokens": 0}}}`), &response))
+		response.normalize()
+		assert.Equal(t, &ResponseUsage{InputTokens: 12, OutputTokens: 30, TotalTokens: 42}, response.Usage)
+
+		response = ResponseData{}
+		require.NoError(t, json.Unmarshal([]byte(`{"id": "resp_1", "usage": {"input_tokens": 0, "output_tokens": 0, "total_tokens": 0}}`), &response))
+		response.normalize()
+		assert.Nil(t, response.Usage)
+	})
+}
+
+func TestRecordResponseUsage(t *testing.T) {
+	app := App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
+		},
+		repositories: repositories.NewRepositories(),
+	}
+	ctx := context.WithValue(context.Background(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+
+	timeToFirstToken := int64(200)
+	app.recordResponseUsage(ctx, "llama-3.1-8b", newResponseMeter(), &ResponseData{
+		Status:  "completed",
+		Usage:   &ResponseUsage{InputTokens: 10, OutputTokens: 20, TotalTokens: 30},
+		Latency: &ResponseLatency{TimeToFirstTokenMs: &timeToFirstToken, TotalMs: 1000},
+	})
+	app.recordResponseUsage(ctx, "llama-3.1-8b", newResponseMeter(), nil)
+
+	// Without a namespace nothing is recorded
+	app.recordResponseUsage(context.Background(), "llama-3.1-8b", newResponseMeter(), &ResponseData{Status: "completed"})
+
+	report, err := app.repositories.Usage.GetUsageReport(ctx, testutil.TestNamespace, anonymousUsername, models.UsageReportOptions{})
+	require.NoError(t, err)
+	total := report.User.Total
+	assert.Equal(t, int64(2), total.Requests)
+	assert.Equal(t, int64(1), total.FailedRequests)
+	assert.Equal(t, int64(10), total.InputTokens)
+	assert.Equal(t, int64(20), total.OutputTokens)
+	assert.Equal(t, int64(30), total.TotalTokens)
+	assert.Equal(t, int64(1), total.StreamedRequests)
+	assert.Equal(t, int64(200), total.AverageTimeToFirstTokenMs)
+	assert.GreaterOrEqual(t, total.LatencyMs, int64(1000))
+	assert.Equal(t, report.User.Total, report.AllUsers.Total)
+}
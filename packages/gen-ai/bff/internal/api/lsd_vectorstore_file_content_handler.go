@@ -0,0 +1,274 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/openai/openai-go/v2"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+)
+
+// VectorStoreFileChunk is a piece of a vector store file as it was indexed
+type VectorStoreFileChunk struct {
+	Index      int    `json:"index"` // Position of the chunk in the file
+	Type       string `json:"type"`
+	Text       string `json:"text"`
+	TokenCount *int64 `json:"token_count,omitempty"` // Only present when LlamaStack reports it
+}
+
+// VectorStoreFileChunksData is a page of the chunks of a vector store file
+type VectorStoreFileChunksData struct {
+	VectorStoreID string                 `json:"vector_store_id"`
+	FileID        string                 `json:"file_id"`
+	Filename      string                 `json:"filename,omitempty"`
+	Status        string                 `json:"status"` // Processing status of the file, chunks are complete once it is completed
+	Chunks        []VectorStoreFileChunk `json:"chunks"`
+}
+
+// VectorStoreFileChunksPage describes the page of a chunk listing
+type VectorStoreFileChunksPage struct {
+	Offset  int  `json:"offset"`
+	Limit   int  `json:"limit"`
+	Total   int  `json:"total"`
+	HasMore bool `json:"has_more"`
+}
+
+type VectorStoreFileChunksEnvelope = Envelope[*VectorStoreFileChunksData, *VectorStoreFileChunksPage]
+
+// LlamaStackGetVectorStoreFileContentHandler handles GET /gen-ai/api/v1/lsd/vectorstores/files/content?vector_store_id=<>&file_id=<>.
+// It returns the original document with the content type of its filename, and supports range requests so that
+// large documents can be read in parts.
+func (app *App) LlamaStackGetVectorStoreFileContentHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	vectorStoreID, fileID, ok := app.vectorStoreFileParams(w, r)
+	if !ok {
+		return
+	}
+
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok || !app.authorizeVectorStore(w, r, caller, vectorStoreID, false) {
+		return
+	}
+	if _, ok := app.vectorStoreFile(w, r, vectorStoreID, fileID); !ok {
+		return
+	}
+
+	file, err := app.repositories.Files.GetFile(ctx, fileID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	content, err := app.repositories.Files.GetFileContent(ctx, fileID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	defer content.Body.Close()
+
+	// Documents up to the upload limit are buffered to serve range requests, larger ones are streamed whole
+	data, err := io.ReadAll(io.LimitReader(content.Body, constants.IngestionMaxFileBytes+1))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", fileContentType(file.Filename, content.ContentType, data))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": file.Filename}))
+	// Uploaded documents are untrusted: browsers must not sniff them into HTML or run their scripts
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "sandbox")
+
+	if len(data) > constants.IngestionMaxFileBytes {
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, io.MultiReader(bytes.NewReader(data), content.Body)); err != nil {
+			app.logger.Debug("Failed to stream file content", "file_id", fileID, "error", err)
+		}
+		return
+	}
+	http.ServeContent(w, r, file.Filename, time.Unix(file.CreatedAt, 0), bytes.NewReader(data))
+}
+
+// LlamaStackListVectorStoreFileChunksHandler handles GET /gen-ai/api/v1/lsd/vectorstores/files/chunks?vector_store_id=<>&file_id=<>.
+// It returns a page of the chunks the file was split into, as JSON or, when text/plain is accepted, as the
+// extracted text of the chunks separated by blank lines.
+func (app *App) LlamaStackListVectorStoreFileChunksHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	vectorStoreID, fileID, ok := app.vectorStoreFileParams(w, r)
+	if !ok {
+		return
+	}
+
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		app.badRequestResponse(w, r, fmt.Errorf("invalid offset parameter: %s", r.URL.Query().Get("offset")))
+		return
+	}
+	limit, err := queryInt(r, "limit", constants.VectorStoreFileChunksDefaultLimit)
+	if err != nil || limit < 1 || limit > constants.VectorStoreFileChunksMaxLimit {
+		app.badRequestResponse(w, r, fmt.Errorf("invalid limit parameter: must be between 1 and %d", constants.VectorStoreFileChunksMaxLimit))
+		return
+	}
+
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok || !app.authorizeVectorStore(w, r, caller, vectorStoreID, false) {
+		return
+	}
+	vectorStoreFile, ok := app.vectorStoreFile(w, r, vectorStoreID, fileID)
+	if !ok {
+		return
+	}
+
+	chunks, err := app.repositories.VectorStores.ListVectorStoreFileContent(ctx, vectorStoreID, fileID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	start, end := min(offset, len(chunks)), min(offset+limit, len(chunks))
+	page := &VectorStoreFileChunksPage{
+		Offset:  offset,
+		Limit:   limit,
+		Total:   len(chunks),
+		HasMore: end < len(chunks),
+	}
+
+	if wantsPlainText(r) {
+		texts := make([]string, 0, end-start)
+		for _, chunk := range chunks[start:end] {
+			texts = append(texts, chunk.Text)
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.WriteString(w, strings.Join(texts, "\n\n")); err != nil {
+			app.logger.Debug("Failed to write extracted text", "file_id", fileID, "error", err)
+		}
+		return
+	}
+
+	data := &VectorStoreFileChunksData{
+		VectorStoreID: vectorStoreID,
+		FileID:        fileID,
+		Status:        string(vectorStoreFile.Status),
+		Chunks:        make([]VectorStoreFileChunk, 0, end-start),
+	}
+	for i, chunk := range chunks[start:end] {
+		data.Chunks = append(data.Chunks, VectorStoreFileChunk{
+			Index:      start + i,
+			Type:       chunk.Type,
+			Text:       chunk.Text,
+			TokenCount: chunkTokenCount(chunk),
+		})
+	}
+
+	// The filename is informative, so failing to read it does not fail the listing
+	if file, err := app.repositories.Files.GetFile(ctx, fileID); err != nil {
+		app.logger.Warn("Failed to fetch file details for chunks", "file_id", fileID, "error", err)
+	} else {
+		data.Filename = file.Filename
+	}
+
+	response := VectorStoreFileChunksEnvelope{
+		Data:     data,
+		Metadata: page,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// vectorStoreFileParams reads the required vector_store_id and file_id query parameters.
+// It writes the error response and returns false when one is missing.
+func (app *App) vectorStoreFileParams(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	vectorStoreID := r.URL.Query().Get("vector_store_id")
+	if vectorStoreID == "" {
+		app.badRequestResponse(w, r, errors.New("vector_store_id query parameter is required"))
+		return "", "", false
+	}
+	fileID := r.URL.Query().Get("file_id")
+	if fileID == "" {
+		app.badRequestResponse(w, r, errors.New("file_id query parameter is required"))
+		return "", "", false
+	}
+	return vectorStoreID, fileID, true
+}
+
+// vectorStoreFile retrieves a file of a vector store, so that only files of stores the caller can read are returned.
+// It writes the error response and returns false when the file is not in the store.
+func (app *App) vectorStoreFile(w http.ResponseWriter, r *http.Request, vectorStoreID, fileID string) (*openai.VectorStoreFile, bool) {
+	vectorStoreFile, err := app.repositories.VectorStores.GetVectorStoreFile(r.Context(), vectorStoreID, fileID)
+	if err != nil {
+		var apiErr *openai.Error
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			app.notFoundResponse(w, r)
+			return nil, false
+		}
+		app.serverErrorResponse(w, r, err)
+		return nil, false
+	}
+	return vectorStoreFile, true
+}
+
+// queryInt returns the integer value of a query parameter, or fallback when it is not set
+func queryInt(r *http.Request, name string, fallback int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// wantsPlainText reports whether the client asked for text rather than JSON
+func wantsPlainText(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "text" || strings.Contains(r.Header.Get("Accept"), "text/plain")
+}
+
+// fileContentType returns the content type of a document. LlamaStack stores files as opaque bytes, so the type
+// it reports is only used when it is specific, otherwise the type follows from the filename or the content.
+func fileContentType(filename, reported string, data []byte) string {
+	if mediaType, _, err := mime.ParseMediaType(reported); err == nil &&
+		mediaType != "application/octet-stream" && mediaType != "application/binary" {
+		return reported
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(filename)); contentType != "" {
+		return contentType
+	}
+	return http.DetectContentType(data)
+}
+
+// chunkTokenCount returns the token count LlamaStack reports for a chunk, in its chunk metadata or, for chunks
+// stored by older versions, in its metadata
+func chunkTokenCount(chunk openai.VectorStoreFileContentResponse) *int64 {
+	var chunkMetadata struct {
+		ContentTokenCount *int64 `json:"content_token_count"`
+	}
+	if field, ok := chunk.JSON.ExtraFields["chunk_metadata"]; ok {
+		if err := json.Unmarshal([]byte(field.Raw()), &chunkMetadata); err == nil && chunkMetadata.ContentTokenCount != nil {
+			return chunkMetadata.ContentTokenCount
+		}
+	}
+
+	var metadata struct {
+		TokenCount *int64 `json:"token_count"`
+	}
+	if field, ok := chunk.JSON.ExtraFields["metadata"]; ok {
+		if err := json.Unmarshal([]byte(field.Raw()), &metadata); err == nil {
+			return metadata.TokenCount
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/opendatahub-io/gen-ai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLlamaStackVectorStoreFileContentHandlers(t *testing.T) {
+	llamaStackClientFactory := lsmocks.NewMockClientFactory()
+	app := App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
+		},
+		llamaStackClientFactory: llamaStackClientFactory,
+		repositories:            repositories.NewRepositories(),
+	}
+
+	// Helper function to call a handler with the middleware context in place
+	get := func(t *testing.T, handler httprouter.Handle, path string, query url.Values, header http.Header) *httptest.ResponseRecorder {
+		query.Set("namespace", testutil.TestNamespace)
+		req := httptest.NewRequest(http.MethodGet, path+"?"+query.Encode(), nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		handler(rr, req, nil)
+		return rr
+	}
+
+	fileQuery := func(vectorStoreID, fileID string) url.Values {
+		return url.Values{"vector_store_id": {vectorStoreID}, "file_id": {fileID}}
+	}
+
+	t.Run("should return the document with the content type of its filename", func(t *testing.T) {
+		rr := get(t, app.LlamaStackGetVectorStoreFileContentHandler, constants.VectorStoreFileContentPath, fileQuery("vs-test123", "file-mock123abc456def"), nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		assert.Equal(t, "text/plain; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Equal(t, `inline; filename=mock_document.txt`, rr.Header().Get("Content-Disposition"))
+		assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "sandbox", rr.Header().Get("Content-Security-Policy"))
+		assert.Contains(t, rr.Body.String(), "Mock content of file-mock123abc456def")
+	})
+
+	t.Run("should serve byte ranges of the document", func(t *testing.T) {
+		rr := get(t, app.LlamaStackGetVectorStoreFileContentHandler, constants.VectorStoreFileContentPath,
+			fileQuery("vs-test123", "file-mock123abc456def"), http.Header{"Range": {"bytes=0-11"}})
+		require.Equal(t, http.StatusPartialContent, rr.Code)
+
+		assert.Equal(t, "Mock content", rr.Body.String())
+		assert.Regexp(t, `^bytes 0-11/\d+$`, rr.Header().Get("Content-Range"))
+	})
+
+	t.Run("should page the chunks of a file with their token counts", func(t *testing.T) {
+		rr := get(t, app.LlamaStackListVectorStoreFileChunksHandler, constants.VectorStoreFileChunksPath,
+			url.Values{"vector_store_id": {"vs-test123"}, "file_id": {"file-mock123abc456def"}, "offset": {"1"}, "limit": {"1"}}, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response VectorStoreFileChunksEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+
+		assert.Equal(t, "mock_document.txt", response.Data.Filename)
+		assert.Equal(t, "completed", response.Data.Status)
+		require.Len(t, response.Data.Chunks, 1)
+		chunk := response.Data.Chunks[0]
+		assert.Equal(t, 1, chunk.Index)
+		assert.Equal(t, "Mock chunk 2 of file-mock123abc456def", chunk.Text)
+		require.NotNil(t, chunk.TokenCount)
+		assert.Equal(t, int64(5), *chunk.TokenCount)
+		assert.Equal(t, &VectorStoreFileChunksPage{Offset: 1, Limit: 1, Total: 3, HasMore: true}, response.Metadata)
+	})
+
+	t.Run("should return the extracted text when text is accepted", func(t *testing.T) {
+		rr := get(t, app.LlamaStackListVectorStoreFileChunksHandler, constants.VectorStoreFileChunksPath,
+			fileQuery("vs-test123", "file-mock123abc456def"), http.Header{"Accept": {"text/plain"}})
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		assert.Equal(t, "text/plain; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "3", rr.Header().Get("X-Total-Count"))
+		assert.Equal(t, "Mock chunk 1 of file-mock123abc456def\n\nMock chunk 2 of file-mock123abc456def\n\nMock chunk 3 of file-mock123abc456def", rr.Body.String())
+	})
+
+	t.Run("should reject invalid paging parameters", func(t *testing.T) {
+		for name, paging := range map[string]url.Values{
+			"negative offset": {"offset": {"-1"}},
+			"zero limit":      {"limit": {"0"}},
+			"limit above max": {"limit": {"101"}},
+			"limit not a int": {"limit": {"many"}},
+		} {
+			query := fileQuery("vs-test123", "file-mock123abc456def")
+			for key, values := range paging {
+				query[key] = values
+			}
+			rr := get(t, app.LlamaStackListVectorStoreFileChunksHandler, constants.VectorStoreFileChunksPath, query, nil)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, name)
+		}
+	})
+
+	t.Run("should require vector_store_id and file_id", func(t *testing.T) {
+		for _, handler := range []httprouter.Handle{app.LlamaStackGetVectorStoreFileContentHandler, app.LlamaStackListVectorStoreFileChunksHandler} {
+			assert.Equal(t, http.StatusBadRequest, get(t, handler, constants.VectorStoreFileChunksPath, fileQuery("", "file-mock123abc456def"), nil).Code)
+			assert.Equal(t, http.StatusBadRequest, get(t, handler, constants.VectorStoreFileChunksPath, fileQuery("vs-test123", ""), nil).Code)
+		}
+	})
+
+	t.Run("should not return files of vector stores the caller cannot read", func(t *testing.T) {
+		for _, handler := range []httprouter.Handle{app.LlamaStackGetVectorStoreFileContentHandler, app.LlamaStackListVectorStoreFileChunksHandler} {
+			rr := get(t, handler, constants.VectorStoreFileChunksPath, fileQuery(lsmocks.MockOtherUserVectorStoreID, "file-mock123abc456def"), nil)
+			assert.Equal(t, http.StatusNotFound, rr.Code)
+		}
+	})
+
+	t.Run("should not return files that are not in the vector store", func(t *testing.T) {
+		for _, handler := range []httprouter.Handle{app.LlamaStackGetVectorStoreFileContentHandler, app.LlamaStackListVectorStoreFileChunksHandler} {
+			rr := get(t, handler, constants.VectorStoreFileChunksPath, fileQuery("vs-test123", lsmocks.MockMissingFileID), nil)
+			assert.Equal(t, http.StatusNotFound, rr.Code)
+		}
+	})
+}
@@ -0,0 +1,314 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/openai/openai-go/v2"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+)
+
+// vectorStoreMaxNameLength is the maximum length of a vector store name
+const vectorStoreMaxNameLength = 256
+
+// UpdateVectorStoreRequest represents the request body for updating a vector store. Omitted fields are unchanged.
+type UpdateVectorStoreRequest struct {
+	// Name: New name for the vector store (1-256 chars)
+	Name *string `json:"name,omitempty"`
+	// Metadata: Replaces the metadata of the vector store, with the same limits and reserved keys as on creation
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// ExpiresAfter: Expiration policy, or null to remove it
+	ExpiresAfter json.RawMessage `json:"expires_after,omitempty"`
+	// Visibility: private, namespace or groups. Replaces the sharing of the vector store along with SharedGroups.
+	Visibility *string `json:"visibility,omitempty"`
+	// SharedGroups: Groups the vector store is shared with, required for groups visibility
+	SharedGroups []string `json:"shared_groups,omitempty"`
+}
+
+// VectorStoreExpiresAfter is the expiration policy of a vector store
+type VectorStoreExpiresAfter struct {
+	Anchor string `json:"anchor,omitempty"` // Only last_active_at is supported
+	Days   int64  `json:"days"`             // 1-365
+}
+
+// LlamaStackGetVectorStoreHandler handles GET /gen-ai/api/v1/lsd/vectorstores/:id.
+// The file counts and usage bytes of the store are computed from its files.
+func (app *App) LlamaStackGetVectorStoreHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok {
+		return
+	}
+	vectorStore, ok := app.authorizedVectorStore(w, r, caller, ps.ByName("id"), false)
+	if !ok {
+		return
+	}
+
+	if err := app.updateVectorStoreUsage(r.Context(), vectorStore); err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	response := VectorStoreResponse{
+		Data: vectorStore,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// LlamaStackUpdateVectorStoreHandler handles PATCH /gen-ai/api/v1/lsd/vectorstores/:id.
+// Only the owner can rename the store, replace its metadata, change its expiration policy or share it.
+func (app *App) LlamaStackUpdateVectorStoreHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	var updateRequest UpdateVectorStoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&updateRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	params, err := updateRequest.params()
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok {
+		return
+	}
+	vectorStore, ok := app.authorizedVectorStore(w, r, caller, ps.ByName("id"), true)
+	if !ok {
+		return
+	}
+
+	// The ownership and the embedding model are kept when the metadata is replaced
+	if params.Metadata != nil {
+		if acl, owned := models.VectorStoreACLFromMetadata(vectorStore.Metadata); owned {
+			acl.ApplyTo(params.Metadata)
+		}
+		for _, key := range []string{constants.VectorStoreEmbeddingModelKey, constants.VectorStoreEmbeddingDimensionKey} {
+			if value, ok := vectorStore.Metadata[key]; ok {
+				params.Metadata[key] = value
+			}
+		}
+	}
+
+	// The sharing is replaced on the ownership of the store, which only its owner can reach here
+	if updateRequest.Visibility != nil {
+		current, _ := models.VectorStoreACLFromMetadata(vectorStore.Metadata)
+		acl, err := newVectorStoreACL(current.Owner, current.Namespace, *updateRequest.Visibility, updateRequest.SharedGroups)
+		if err != nil {
+			app.badRequestResponse(w, r, err)
+			return
+		}
+		if params.Metadata == nil {
+			params.Metadata = make(map[string]string, len(vectorStore.Metadata)+1)
+			for key, value := range vectorStore.Metadata {
+				params.Metadata[key] = value
+			}
+		}
+		acl.ApplyTo(params.Metadata)
+	}
+
+	updated, err := app.repositories.VectorStores.UpdateVectorStore(ctx, vectorStore.ID, params)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	response := VectorStoreResponse{
+		Data: updated,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// LlamaStackDeleteVectorStoreByIDHandler handles DELETE /gen-ai/api/v1/lsd/vectorstores/:id.
+func (app *App) LlamaStackDeleteVectorStoreByIDHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	app.deleteVectorStore(w, r, ps.ByName("id"))
+}
+
+// params validates the update and converts it to client params
+func (req UpdateVectorStoreRequest) params() (llamastack.UpdateVectorStoreParams, error) {
+	var params llamastack.UpdateVectorStoreParams
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return params, errors.New("name cannot be empty")
+		}
+		if len(name) > vectorStoreMaxNameLength {
+			return params, fmt.Errorf("name must be at most %d characters", vectorStoreMaxNameLength)
+		}
+		params.Name = &name
+	}
+
+	if req.Metadata != nil {
+		if len(req.Metadata) > vectorStoreMaxUserMetadataPairs {
+			return params, fmt.Errorf("metadata must have at most %d pairs", vectorStoreMaxUserMetadataPairs)
+		}
+		params.Metadata = make(map[string]string, len(req.Metadata)+4)
+		for key, value := range req.Metadata {
+			if models.IsReservedVectorStoreMetadataKey(key) {
+				return params, fmt.Errorf("metadata key %q is reserved", key)
+			}
+			params.Metadata[key] = value
+		}
+	}
+
+	if len(req.ExpiresAfter) > 0 {
+		var expiresAfter *VectorStoreExpiresAfter
+		if err := json.Unmarshal(req.ExpiresAfter, &expiresAfter); err != nil {
+			return params, fmt.Errorf("invalid expires_after: %w", err)
+		}
+		days := int64(0)
+		if expiresAfter != nil {
+			if expiresAfter.Anchor != "" && expiresAfter.Anchor != constants.VectorStoreExpiresAfterAnchor {
+				return params, fmt.Errorf("expires_after anchor must be %s", constants.VectorStoreExpiresAfterAnchor)
+			}
+			if expiresAfter.Days < 1 || expiresAfter.Days > constants.VectorStoreMaxExpiresAfterDays {
+				return params, fmt.Errorf("expires_after days must be between 1 and %d", constants.VectorStoreMaxExpiresAfterDays)
+			}
+			days = expiresAfter.Days
+		}
+		params.ExpiresAfterDays = &days
+	}
+
+	// The sharing is validated here, and applied once the owner of the store is known
+	if req.Visibility != nil {
+		if _, err := newVectorStoreACL("", "", *req.Visibility, req.SharedGroups); err != nil {
+			return params, err
+		}
+	} else if len(req.SharedGroups) > 0 {
+		return params, errors.New("shared_groups requires visibility")
+	}
+
+	if params.Name == nil && params.Metadata == nil && params.ExpiresAfterDays == nil && req.Visibility == nil {
+		return params, errors.New("name, metadata, expires_after or visibility is required")
+	}
+	return params, nil
+}
+
+// updateVectorStoreUsage sets the file counts and usage bytes of a vector store from its files. LlamaStack does
+// not track how many bytes a store uses, so the size of the files whose vector store file does not report it is
+// read from the Files API.
+func (app *App) updateVectorStoreUsage(ctx context.Context, vectorStore *openai.VectorStore) error {
+	var counts openai.VectorStoreFileCounts
+	var usageBytes int64
+	var unsized []string
+
+	limit := int64(constants.VectorStoreMaxListLimit)
+	params := llamastack.ListVectorStoreFilesParams{Limit: &limit}
+	for {
+		files, err := app.repositories.VectorStores.ListVectorStoreFiles(ctx, vectorStore.ID, params)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			counts.Total++
+			switch file.Status {
+			case openai.VectorStoreFileStatusInProgress:
+				counts.InProgress++
+			case openai.VectorStoreFileStatusCompleted:
+				counts.Completed++
+			case openai.VectorStoreFileStatusFailed:
+				counts.Failed++
+			case openai.VectorStoreFileStatusCancelled:
+				counts.Cancelled++
+			}
+
+			if file.UsageBytes > 0 {
+				usageBytes += file.UsageBytes
+			} else {
+				unsized = append(unsized, file.ID)
+			}
+		}
+
+		if len(files) < int(limit) {
+			break
+		}
+		params.After = files[len(files)-1].ID
+	}
+
+	vectorStore.FileCounts = counts
+	vectorStore.UsageBytes = usageBytes + app.fileBytes(ctx, unsized)
+	return nil
+}
+
+// fileBytes returns the total size of the files with the given IDs. The sizes are taken from one listing of
+// the Files API, and only files missing from it are fetched one by one. Files whose size cannot be read are
+// left out.
+func (app *App) fileBytes(ctx context.Context, fileIDs []string) int64 {
+	if len(fileIDs) == 0 {
+		return 0
+	}
+
+	sizes := make(map[string]int64)
+	limit := int64(constants.FilesMaxListLimit)
+	if files, err := app.repositories.Files.ListFiles(ctx, llamastack.ListFilesParams{Limit: &limit}); err != nil {
+		app.logger.Warn("Failed to list files for usage", "error", err)
+	} else {
+		for _, file := range files {
+			sizes[file.ID] = file.Bytes
+		}
+	}
+
+	var total int64
+	for _, fileID := range fileIDs {
+		if size, ok := sizes[fileID]; ok {
+			total += size
+		} else if details, err := app.repositories.Files.GetFile(ctx, fileID); err != nil {
+			app.logger.Warn("Failed to fetch file details for usage", "file_id", fileID, "error", err)
+		} else {
+			total += details.Bytes
+		}
+	}
+	return total
+}
+
+// checkVectorStoreQuota checks that the namespace can have stores more vector stores and files more files.
+// LlamaStack is deployed per namespace, so every store it holds counts against the quotas of the namespace, and
+// so do the documents of its ingestion jobs that are not in a vector store yet.
+// It writes the error response and returns false when a quota would be exceeded.
+func (app *App) checkVectorStoreQuota(w http.ResponseWriter, r *http.Request, namespace string, stores, files int) bool {
+	quotaStores, quotaFiles := app.config.VectorStoreQuotaStores, app.config.VectorStoreQuotaFiles
+	if quotaStores <= 0 && quotaFiles <= 0 {
+		return true
+	}
+
+	vectorStores, err := app.repositories.VectorStores.ListVectorStores(r.Context(), llamastack.ListVectorStoresParams{})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return false
+	}
+
+	if quotaStores > 0 && len(vectorStores)+stores > quotaStores {
+		app.forbiddenResponse(w, r, fmt.Sprintf("the namespace quota of %d vector stores is exceeded", quotaStores))
+		return false
+	}
+
+	usedFiles := 0
+	for _, vectorStore := range vectorStores {
+		usedFiles += int(vectorStore.FileCounts.Total)
+	}
+	if app.repositories.IngestionJobs != nil {
+		usedFiles += app.repositories.IngestionJobs.PendingDocuments(namespace)
+	}
+	if quotaFiles > 0 && usedFiles+files > quotaFiles {
+		app.forbiddenResponse(w, r, fmt.Sprintf("the namespace quota of %d vector store files is exceeded, %d files are stored or being ingested", quotaFiles, usedFiles))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,283 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/openai/openai-go/v2"
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/opendatahub-io/gen-ai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLlamaStackVectorStoreHandlers(t *testing.T) {
+	llamaStackClientFactory := lsmocks.NewMockClientFactory()
+	app := App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
+		},
+		llamaStackClientFactory: llamaStackClientFactory,
+		repositories:            repositories.NewRepositories(),
+		logger:                  slog.Default(),
+	}
+
+	// Helper function to call a vector store handler with the middleware context in place. With authentication
+	// disabled the caller is anonymous, who owns the vector stores the mock does not list.
+	call := func(t *testing.T, handler httprouter.Handle, method, vectorStoreID string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			require.NoError(t, json.NewEncoder(&body).Encode(payload))
+		}
+
+		req := httptest.NewRequest(method, "/gen-ai/api/v1/lsd/vectorstores/"+vectorStoreID+"?namespace="+testutil.TestNamespace, &body)
+		identity := &integrations.RequestIdentity{Token: "test-token"}
+		ctx := context.WithValue(req.Context(), constants.RequestIdentityKey, identity)
+		ctx = context.WithValue(ctx, constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		handler(rr, req, httprouter.Params{{Key: "id", Value: vectorStoreID}})
+		return rr
+	}
+
+	const ownedVectorStoreID = "vs_anonymous123"
+
+	decodeVectorStore := func(t *testing.T, rr *httptest.ResponseRecorder) openai.VectorStore {
+		var response struct {
+			Data openai.VectorStore `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	t.Run("should return the vector store with the file counts and bytes of its files", func(t *testing.T) {
+		rr := call(t, app.LlamaStackGetVectorStoreHandler, http.MethodGet, lsmocks.MockVectorStoreID, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		vectorStore := decodeVectorStore(t, rr)
+		assert.Equal(t, lsmocks.MockVectorStoreID, vectorStore.ID)
+		assert.Equal(t, int64(2), vectorStore.FileCounts.Total)
+		assert.Equal(t, int64(2), vectorStore.FileCounts.Completed)
+		assert.Equal(t, int64(1024+2048), vectorStore.UsageBytes)
+	})
+
+	t.Run("should not return vector stores the caller cannot read", func(t *testing.T) {
+		rr := call(t, app.LlamaStackGetVectorStoreHandler, http.MethodGet, lsmocks.MockOtherUserVectorStoreID, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		// Auto-provisioned stores are private to their user before they are migrated
+		rr = call(t, app.LlamaStackGetVectorStoreHandler, http.MethodGet, lsmocks.MockLegacyVectorStoreID, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should rename the vector store and replace its metadata", func(t *testing.T) {
+		rr := call(t, app.LlamaStackUpdateVectorStoreHandler, http.MethodPatch, ownedVectorStoreID, map[string]interface{}{
+			"name":     "  Renamed store ",
+			"metadata": map[string]string{"project": "handbook"},
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		vectorStore := decodeVectorStore(t, rr)
+		assert.Equal(t, "Renamed store", vectorStore.Name)
+		assert.Equal(t, "handbook", vectorStore.Metadata["project"])
+		assert.Equal(t, anonymousUsername, vectorStore.Metadata[constants.VectorStoreOwnerKey])
+	})
+
+	t.Run("should keep the embedding model when replacing metadata", func(t *testing.T) {
+		rr := call(t, app.LlamaStackUpdateVectorStoreHandler, http.MethodPatch, lsmocks.MockEmbeddingVectorStoreID, map[string]interface{}{
+			"metadata": map[string]string{"project": "handbook"},
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		vectorStore := decodeVectorStore(t, rr)
+		assert.Equal(t, "handbook", vectorStore.Metadata["project"])
+		assert.Equal(t, "ollama/all-minilm:l6-v2", vectorStore.Metadata[constants.VectorStoreEmbeddingModelKey])
+		assert.Equal(t, "384", vectorStore.Metadata[constants.VectorStoreEmbeddingDimensionKey])
+	})
+
+	t.Run("should change who the vector store is shared with", func(t *testing.T) {
+		rr := call(t, app.LlamaStackUpdateVectorStoreHandler, http.MethodPatch, ownedVectorStoreID, map[string]interface{}{
+			"visibility":    "groups",
+			"shared_groups": []string{"team-a", " team-b "},
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		vectorStore := decodeVectorStore(t, rr)
+		assert.Equal(t, constants.VectorStoreVisibilityGroups, vectorStore.Metadata[constants.VectorStoreVisibilityKey])
+		assert.Equal(t, "team-a,team-b", vectorStore.Metadata[constants.VectorStoreSharedGroupsKey])
+		assert.Equal(t, anonymousUsername, vectorStore.Metadata[constants.VectorStoreOwnerKey])
+		assert.Equal(t, lsmocks.MockVectorStoreNamespace, vectorStore.Metadata[constants.VectorStoreNamespaceKey])
+
+		rr = call(t, app.LlamaStackUpdateVectorStoreHandler, http.MethodPatch, ownedVectorStoreID, map[string]interface{}{
+			"visibility": "private",
+			"metadata":   map[string]string{"project": "handbook"},
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		vectorStore = decodeVectorStore(t, rr)
+		assert.Equal(t, constants.VectorStoreVisibilityPrivate, vectorStore.Metadata[constants.VectorStoreVisibilityKey])
+		assert.NotContains(t, vectorStore.Metadata, constants.VectorStoreSharedGroupsKey)
+		assert.Equal(t, "handbook", vectorStore.Metadata["project"])
+	})
+
+	t.Run("should set and remove the expiration policy", func(t *testing.T) {
+		rr := call(t, app.LlamaStackUpdateVectorStoreHandler, http.MethodPatch, ownedVectorStoreID, map[string]interface{}{
+			"expires_after": map[string]interface{}{"anchor": "last_active_at", "days": 7},
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+		vectorStore := decodeVectorStore(t, rr)
+		assert.Equal(t, int64(7), vectorStore.ExpiresAfter.Days)
+		assert.Equal(t, vectorStore.LastActiveAt+7*24*60*60, vectorStore.ExpiresAt)
+
+		rr = call(t, app.LlamaStackUpdateVectorStoreHandler, http.MethodPatch, ownedVectorStoreID, map[string]interface{}{
+			"expires_after": nil,
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+		vectorStore = decodeVectorStore(t, rr)
+		assert.Equal(t, int64(0), vectorStore.ExpiresAfter.Days)
+		assert.Equal(t, int64(0), vectorStore.ExpiresAt)
+	})
+
+	t.Run("should reject invalid updates", func(t *testing.T) {
+		for name, payload := range map[string]interface{}{
+			"empty update":         map[string]interface{}{},
+			"empty name":           map[string]interface{}{"name": " "},
+			"reserved metadata":    map[string]interface{}{"metadata": map[string]string{constants.VectorStoreOwnerKey: "someone"}},
+			"expiry too long":      map[string]interface{}{"expires_after": map[string]interface{}{"days": 366}},
+			"expiry without days":  map[string]interface{}{"expires_after": map[string]interface{}{"anchor": "last_active_at"}},
+			"unsupported anchor":   map[string]interface{}{"expires_after": map[string]interface{}{"anchor": "created_at", "days": 7}},
+			"expiry not an object": map[string]interface{}{"expires_after": 7},
+			"unknown visibility":   map[string]interface{}{"visibility": "public"},
+			"groups without group": map[string]interface{}{"visibility": "groups"},
+			"groups not shared":    map[string]interface{}{"visibility": "namespace", "shared_groups": []string{"team-a"}},
+			"groups alone":         map[string]interface{}{"shared_groups": []string{"team-a"}},
+		} {
+			rr := call(t, app.LlamaStackUpdateVectorStoreHandler, http.MethodPatch, ownedVectorStoreID, payload)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, name)
+		}
+	})
+
+	t.Run("should only let the owner update or delete the vector store", func(t *testing.T) {
+		rr := call(t, app.LlamaStackUpdateVectorStoreHandler, http.MethodPatch, lsmocks.MockSharedVectorStoreID, map[string]interface{}{"name": "Mine now"})
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = call(t, app.LlamaStackUpdateVectorStoreHandler, http.MethodPatch, lsmocks.MockSharedVectorStoreID, map[string]interface{}{"visibility": "private"})
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = call(t, app.LlamaStackDeleteVectorStoreByIDHandler, http.MethodDelete, lsmocks.MockSharedVectorStoreID, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should not let anyone update or delete a vector store without an owner", func(t *testing.T) {
+		rr := call(t, app.LlamaStackUpdateVectorStoreHandler, http.MethodPatch, lsmocks.MockVectorStoreID, map[string]interface{}{"name": "Mine now"})
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = call(t, app.LlamaStackDeleteVectorStoreByIDHandler, http.MethodDelete, lsmocks.MockVectorStoreID, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("should delete the vector store", func(t *testing.T) {
+		rr := call(t, app.LlamaStackDeleteVectorStoreByIDHandler, http.MethodDelete, ownedVectorStoreID, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response map[string]map[string]interface{}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, ownedVectorStoreID, response["data"]["id"])
+		assert.Equal(t, true, response["data"]["deleted"])
+	})
+}
+
+func TestVectorStoreQuota(t *testing.T) {
+	// The mock LlamaStack has 5 vector stores holding one file each
+	newApp := func(quotaStores, quotaFiles int) *App {
+		return &App{
+			config: config.EnvConfig{
+				Port:                   4000,
+				AuthMethod:             config.AuthMethodDisabled,
+				VectorStoreQuotaStores: quotaStores,
+				VectorStoreQuotaFiles:  quotaFiles,
+			},
+			llamaStackClientFactory: lsmocks.NewMockClientFactory(),
+			repositories:            repositories.NewRepositories(),
+		}
+	}
+
+	checkQuota := func(app *App, stores, files int) (bool, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, constants.VectorStoresListPath+"?namespace="+testutil.TestNamespace, nil)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		req = req.WithContext(context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient))
+
+		rr := httptest.NewRecorder()
+		return app.checkVectorStoreQuota(rr, req, testutil.TestNamespace, stores, files), rr
+	}
+
+	t.Run("should allow everything without quotas", func(t *testing.T) {
+		ok, _ := checkQuota(newApp(0, 0), 100, 1000)
+		assert.True(t, ok)
+	})
+
+	t.Run("should refuse vector stores beyond the quota", func(t *testing.T) {
+		ok, _ := checkQuota(newApp(6, 0), 1, 0)
+		assert.True(t, ok)
+
+		ok, rr := checkQuota(newApp(5, 0), 1, 0)
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Contains(t, rr.Body.String(), "quota of 5 vector stores")
+	})
+
+	t.Run("should refuse files beyond the quota", func(t *testing.T) {
+		ok, _ := checkQuota(newApp(0, 8), 0, 3)
+		assert.True(t, ok)
+
+		ok, rr := checkQuota(newApp(0, 8), 0, 4)
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Contains(t, rr.Body.String(), "5 files are stored")
+	})
+
+	t.Run("should count documents waiting to be ingested against the file quota", func(t *testing.T) {
+		app := newApp(0, 8)
+		// Without workers the jobs stay queued
+		manager := llamastack.NewIngestionManager(llamastack.IngestionManagerConfig{QueueSize: 4, Retention: time.Hour}, nil)
+		t.Cleanup(func() { _ = manager.Close() })
+		app.repositories.IngestionJobs = repositories.NewIngestionJobsRepository(manager)
+
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx := context.WithValue(context.Background(), constants.LlamaStackClientKey, llamaStackClient)
+		_, err := app.repositories.IngestionJobs.SubmitJob(ctx, llamastack.IngestionJobParams{
+			Namespace:     testutil.TestNamespace,
+			Owner:         anonymousUsername,
+			VectorStoreID: lsmocks.MockVectorStoreID,
+			Documents: []llamastack.IngestionDocument{
+				{Filename: "intro.md", Content: []byte("intro")},
+				{Filename: "setup.md", Content: []byte("setup guide")},
+			},
+		})
+		require.NoError(t, err)
+
+		ok, _ := checkQuota(app, 0, 1)
+		assert.True(t, ok)
+
+		ok, rr := checkQuota(app, 0, 2)
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Contains(t, rr.Body.String(), "7 files are stored or being ingested")
+	})
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/openai/openai-go/v2"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+)
+
+// SearchVectorStoreRequest represents the request body for searching a vector store
+type SearchVectorStoreRequest struct {
+	Query          string                              `json:"query"`                     // Required
+	MaxNumResults  *int64                              `json:"max_num_results,omitempty"` // 1-50, default 10
+	RankingOptions *VectorStoreSearchRankingOptions    `json:"ranking_options,omitempty"`
+	RewriteQuery   *bool                               `json:"rewrite_query,omitempty"`
+	Filters        *llamastack.VectorStoreSearchFilter `json:"filters,omitempty"` // On file attributes
+}
+
+// VectorStoreSearchRankingOptions controls the ranking of the chunks
+type VectorStoreSearchRankingOptions struct {
+	Ranker         string   `json:"ranker,omitempty"`          // "none" disables re-ranking
+	ScoreThreshold *float64 `json:"score_threshold,omitempty"` // 0-1
+}
+
+// VectorStoreSearchResult is a chunk of a vector store file matching the query
+type VectorStoreSearchResult struct {
+	FileID     string                 `json:"file_id"`
+	Filename   string                 `json:"filename"`
+	Score      float64                `json:"score"`
+	Attributes map[string]interface{} `json:"attributes"`
+	Content    []string               `json:"content"`
+}
+
+// VectorStoreSearchData is the outcome of a vector store search, best scoring chunks first
+type VectorStoreSearchData struct {
+	VectorStoreID string                    `json:"vector_store_id"`
+	Query         string                    `json:"query"`
+	Results       []VectorStoreSearchResult `json:"results"`
+}
+
+type VectorStoreSearchEnvelope = Envelope[*VectorStoreSearchData, None]
+
+// LlamaStackSearchVectorStoreHandler handles POST /gen-ai/api/v1/lsd/vectorstores/:id/search.
+// It runs the retrieval step of RAG on its own, so that retrieval can be checked apart from generation.
+func (app *App) LlamaStackSearchVectorStoreHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+	vectorStoreID := ps.ByName("id")
+
+	var searchRequest SearchVectorStoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&searchRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	params := llamastack.SearchVectorStoreParams{
+		Query:         searchRequest.Query,
+		MaxNumResults: searchRequest.MaxNumResults,
+		RewriteQuery:  searchRequest.RewriteQuery,
+		Filters:       searchRequest.Filters,
+	}
+	if searchRequest.RankingOptions != nil {
+		params.Ranker = searchRequest.RankingOptions.Ranker
+		params.ScoreThreshold = searchRequest.RankingOptions.ScoreThreshold
+	}
+	if err := params.Validate(); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok || !app.authorizeVectorStore(w, r, caller, vectorStoreID, false) {
+		return
+	}
+
+	results, err := app.repositories.VectorStores.SearchVectorStore(ctx, vectorStoreID, params)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	data := &VectorStoreSearchData{
+		VectorStoreID: vectorStoreID,
+		Query:         searchRequest.Query,
+		Results:       make([]VectorStoreSearchResult, 0, len(results)),
+	}
+	for _, result := range results {
+		data.Results = append(data.Results, vectorStoreSearchResult(result))
+	}
+
+	response := VectorStoreSearchEnvelope{
+		Data: data,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// vectorStoreSearchResult converts a search result to its BFF form with plain attribute values and chunk texts
+func vectorStoreSearchResult(result openai.VectorStoreSearchResponse) VectorStoreSearchResult {
+	converted := VectorStoreSearchResult{
+		FileID:     result.FileID,
+		Filename:   result.Filename,
+		Score:      result.Score,
+		Attributes: attributeValues(result.Attributes, searchAttributeValue),
+		Content:    make([]string, 0, len(result.Content)),
+	}
+	for _, content := range result.Content {
+		converted.Content = append(converted.Content, content.Text)
+	}
+	return converted
+}
+
+// attributeValues returns the strings, numbers and booleans held by file attributes, using value to convert the
+// attribute union of the endpoint
+func attributeValues[T any](attributes map[string]T, value func(T) interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(attributes))
+	for key, attribute := range attributes {
+		values[key] = value(attribute)
+	}
+	return values
+}
+
+// searchAttributeValue returns the string, number or boolean held by a search result attribute
+func searchAttributeValue(attribute openai.VectorStoreSearchResponseAttributeUnion) interface{} {
+	return attributeValue(attribute.RawJSON(), attribute.OfString, attribute.OfFloat, attribute.OfBool)
+}
+
+// fileAttributeValue returns the string, number or boolean held by a vector store file attribute
+func fileAttributeValue(attribute openai.VectorStoreFileAttributeUnion) interface{} {
+	return attributeValue(attribute.RawJSON(), attribute.OfString, attribute.OfFloat, attribute.OfBool)
+}
+
+// attributeValue returns the value of an attribute union from its raw JSON, or from its fields when it was built
+// in code rather than decoded from a response
+func attributeValue(raw, ofString string, ofFloat float64, ofBool bool) interface{} {
+	if raw != "" {
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err == nil {
+			return value
+		}
+	}
+
+	switch {
+	case ofString != "":
+		return ofString
+	case ofBool:
+		return ofBool
+	default:
+		return ofFloat
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/opendatahub-io/gen-ai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLlamaStackSearchVectorStoreHandler(t *testing.T) {
+	llamaStackClientFactory := lsmocks.NewMockClientFactory()
+	app := App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
+		},
+		llamaStackClientFactory: llamaStackClientFactory,
+		repositories:            repositories.NewRepositories(),
+	}
+
+	// Helper function to search a vector store with the middleware context in place
+	search := func(t *testing.T, vectorStoreID string, payload interface{}) *httptest.ResponseRecorder {
+		jsonData, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/gen-ai/api/v1/lsd/vectorstores/"+vectorStoreID+"/search?namespace="+testutil.TestNamespace, bytes.NewBuffer(jsonData))
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackSearchVectorStoreHandler(rr, req, httprouter.Params{{Key: "id", Value: vectorStoreID}})
+		return rr
+	}
+
+	t.Run("should return chunks with scores, files and attributes", func(t *testing.T) {
+		rr := search(t, "vs-test123", SearchVectorStoreRequest{Query: "How do I reset my password?"})
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response VectorStoreSearchEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+
+		assert.Equal(t, "vs-test123", response.Data.VectorStoreID)
+		assert.Equal(t, "How do I reset my password?", response.Data.Query)
+		require.Len(t, response.Data.Results, 3)
+
+		first := response.Data.Results[0]
+		assert.Equal(t, "file-mock123abc456def", first.FileID)
+		assert.Equal(t, "mock-document.pdf", first.Filename)
+		assert.Equal(t, 0.92, first.Score)
+		assert.Equal(t, "guide", first.Attributes["category"])
+		assert.Equal(t, []string{"Mock chunk 1 relevant to: How do I reset my password?"}, first.Content)
+	})
+
+	t.Run("should apply max_num_results and score_threshold", func(t *testing.T) {
+		maxNumResults := int64(1)
+		scoreThreshold := 0.5
+		rr := search(t, "vs-test123", SearchVectorStoreRequest{
+			Query:          "password",
+			MaxNumResults:  &maxNumResults,
+			RankingOptions: &VectorStoreSearchRankingOptions{ScoreThreshold: &scoreThreshold},
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response VectorStoreSearchEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.Len(t, response.Data.Results, 1)
+		assert.Equal(t, 0.92, response.Data.Results[0].Score)
+	})
+
+	t.Run("should accept attribute filters", func(t *testing.T) {
+		rr := search(t, "vs-test123", map[string]interface{}{
+			"query": "password",
+			"filters": map[string]interface{}{
+				"type": "and",
+				"filters": []map[string]interface{}{
+					{"type": "eq", "key": "category", "value": "guide"},
+					{"type": "gte", "key": "version", "value": 2},
+				},
+			},
+		})
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("should reject invalid search parameters", func(t *testing.T) {
+		for name, payload := range map[string]interface{}{
+			"missing query":           map[string]interface{}{},
+			"too many results":        map[string]interface{}{"query": "password", "max_num_results": 51},
+			"score threshold above 1": map[string]interface{}{"query": "password", "ranking_options": map[string]interface{}{"score_threshold": 1.5}},
+			"unknown filter type":     map[string]interface{}{"query": "password", "filters": map[string]interface{}{"type": "contains", "key": "category", "value": "guide"}},
+			"filter without value":    map[string]interface{}{"query": "password", "filters": map[string]interface{}{"type": "eq", "key": "category"}},
+		} {
+			rr := search(t, "vs-test123", payload)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, name)
+		}
+	})
+
+	t.Run("should not search vector stores the caller cannot read", func(t *testing.T) {
+		rr := search(t, lsmocks.MockOtherUserVectorStoreID, SearchVectorStoreRequest{Query: "password"})
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
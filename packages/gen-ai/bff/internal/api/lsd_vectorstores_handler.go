@@ -1,138 +1,325 @@
 package api
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/openai/openai-go/v2"
 	"github.com/opendatahub-io/gen-ai/internal/constants"
-	"github.com/opendatahub-io/gen-ai/internal/integrations"
 	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+	"github.com/opendatahub-io/gen-ai/internal/models"
 )
 
 type VectorStoresResponse = llamastack.APIResponse
 type VectorStoreResponse = llamastack.APIResponse
 
+// VectorStoresPage describes the page of a vector store or vector store file listing
+type VectorStoresPage struct {
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+	HasMore bool   `json:"has_more"`
+}
+
+type VectorStoresListEnvelope = Envelope[[]openai.VectorStore, *VectorStoresPage]
+
+// vectorStoreMaxUserMetadataPairs is the number of metadata pairs left to users once ownership and the embedding
+// model are recorded
+const vectorStoreMaxUserMetadataPairs = 10
+
+// vectorStoreMaxMetadataValueLength is the maximum length of a vector store metadata value
+const vectorStoreMaxMetadataValueLength = 512
+
+// migratedVectorStoreName replaces the hashed username the auto-provisioning named the store of a user with
+const migratedVectorStoreName = "My documents"
+
 // CreateVectorStoreRequest represents the request body for creating a vector store
 type CreateVectorStoreRequest struct {
 	// Name: Required name for the vector store (1-256 chars)
 	Name string `json:"name"`
-	// Metadata: Set of 16 key-value pairs, keys max 64 chars, values max 512 chars (optional)
+	// Metadata: Set of 10 key-value pairs, keys max 64 chars, values max 512 chars (optional).
+	// The ownership keys owner, namespace, visibility and shared_groups are reserved, as are
+	// embedding_model and embedding_dimension.
 	Metadata map[string]string `json:"metadata,omitempty"`
+	// EmbeddingModel: Embedding model registered in LlamaStack (optional, defaults to granite-embedding-125m)
+	EmbeddingModel string `json:"embedding_model,omitempty"`
+	// EmbeddingDimension: Dimension of the vectors of the embedding model (optional, defaults to the dimension LlamaStack reports)
+	EmbeddingDimension *int64 `json:"embedding_dimension,omitempty"`
+	// ProviderID: vector_io provider of LlamaStack that holds the store (optional, defaults to milvus)
+	ProviderID string `json:"provider_id,omitempty"`
+	// Visibility: private (default), namespace or groups
+	Visibility string `json:"visibility,omitempty"`
+	// SharedGroups: Groups the vector store is shared with, required for groups visibility
+	SharedGroups []string `json:"shared_groups,omitempty"`
 }
 
-// TEMPORARY: hashUsername creates a deterministic hash for username-based vectorstore isolation
-// TODO: Remove this function when proper multi-tenant vectorstore support is implemented
-func hashUsername(username string) string {
+// legacyVectorStoreName is the name the auto-provisioning gave the store of a user
+func legacyVectorStoreName(username string) string {
 	hash := sha256.Sum256([]byte(username))
 	// Use first 128 bits (16 bytes) results in 32 hex characters instead of 64
 	return hex.EncodeToString(hash[:16])
 }
 
-// LlamaStackListVectorStoresHandler handles GET /gen-ai/api/v1/vectorstores
-// TEMPORARY: This handler implements username-based vectorstore isolation by filtering
-// the list to return only the vectorstore matching the hashed username.
-// TODO: Replace with proper multi-tenant vectorstore support when available
+// LlamaStackListVectorStoresHandler handles GET /gen-ai/api/v1/vectorstores.
+// It returns the vector stores the caller can read, newest first unless order=asc,
+// paginated with limit (1-100, default 20) and the after/before cursors.
 func (app *App) LlamaStackListVectorStoresHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	ctx := r.Context()
 
-	// TEMPORARY: Get username from context for vectorstore isolation
-	identity, ok := ctx.Value(constants.RequestIdentityKey).(*integrations.RequestIdentity)
-	if !ok || identity == nil {
-		app.unauthorizedResponse(w, r, errors.New("user identity not found in context"))
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok {
 		return
 	}
 
-	// Get the Kubernetes client to fetch username (uses identity from context)
-	client, err := app.kubernetesClientFactory.GetClient(ctx)
-	if err != nil {
-		app.serverErrorResponse(w, r, err)
+	query := r.URL.Query()
+	limit := constants.VectorStoreDefaultListLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > constants.VectorStoreMaxListLimit {
+			app.badRequestResponse(w, r, fmt.Errorf("invalid limit parameter: %s", limitStr))
+			return
+		}
+		limit = parsed
+	}
+
+	// Ownership is checked by the BFF, so every store is fetched and the readable ones are paginated here
+	params := llamastack.ListVectorStoresParams{}
+	if order := query.Get("order"); order != "" {
+		if order != "asc" && order != "desc" {
+			app.badRequestResponse(w, r, fmt.Errorf("invalid order parameter: %s", order))
+			return
+		}
+		params.Order = order
+	}
+
+	after, before := query.Get("after"), query.Get("before")
+	if after != "" && before != "" {
+		app.badRequestResponse(w, r, errors.New("after and before cannot be used together"))
 		return
 	}
 
-	// Fetch the username from the Kubernetes API
-	username, err := client.GetUser(ctx, identity)
+	vectorStores, err := app.repositories.VectorStores.ListVectorStores(ctx, params)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	hashedUsername := hashUsername(username)
+	readable := make([]openai.VectorStore, 0, len(vectorStores))
+	for _, vectorStore := range vectorStores {
+		if acl, _ := models.VectorStoreACLFromMetadata(vectorStore.Metadata); acl.CanRead(caller) {
+			readable = append(readable, vectorStore)
+		}
+	}
 
-	// TEMPORARY: Ignore limit and order parameters from request
-	// We always return exactly 1 vectorstore (user's own), so we need to fetch all
-	// vectorstores to find the match. Empty params means fetch all with defaults.
-	params := llamastack.ListVectorStoresParams{}
+	page, meta := paginateVectorStores(readable, limit, after, before)
 
-	// // Parse limit parameter (1-100, default 20)
-	// if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-	// 	if limit, err := strconv.ParseInt(limitStr, 10, 64); err == nil {
-	// 		if limit >= 1 && limit <= 100 {
-	// 			params.Limit = &limit
-	// 		}
-	// 	}
-	// }
-
-	// // Parse order parameter ("asc" or "desc")
-	// if order := r.URL.Query().Get("order"); order == "asc" || order == "desc" {
-	// 	params.Order = order
-	// }
-
-	// Get all vectorstores
-	vectorStores, err := app.repositories.VectorStores.ListVectorStores(ctx, params)
+	response := VectorStoresListEnvelope{
+		Data:     page,
+		Metadata: meta,
+	}
+
+	err = app.WriteJSON(w, http.StatusOK, response, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
-		return
 	}
+}
 
-	// TEMPORARY: Filter vectorstores to find the one matching hashed username
-	var userVectorStore *openai.VectorStore
-	foundUserVectorStore := false
+// paginateVectorStores returns at most limit stores following the after cursor or preceding the before cursor.
+// An unknown cursor yields an empty page.
+func paginateVectorStores(vectorStores []openai.VectorStore, limit int, after, before string) ([]openai.VectorStore, *VectorStoresPage) {
+	start, end := 0, len(vectorStores)
+	if cursor := after + before; cursor != "" {
+		index := slices.IndexFunc(vectorStores, func(vectorStore openai.VectorStore) bool { return vectorStore.ID == cursor })
+		switch {
+		case index < 0:
+			start, end = 0, 0
+		case after != "":
+			start = index + 1
+		default:
+			end = index
+		}
+	}
 
-	for i := range vectorStores {
-		if vectorStores[i].Name == hashedUsername {
-			userVectorStore = &vectorStores[i]
-			foundUserVectorStore = true
-			break
+	hasMore := end-start > limit
+	if hasMore {
+		if before != "" {
+			start = end - limit
+		} else {
+			end = start + limit
 		}
 	}
 
-	// TEMPORARY: If no vectorstore found for this user, create one automatically
-	if !foundUserVectorStore {
-		createParams := llamastack.CreateVectorStoreParams{
-			Name:       hashedUsername,
-			ProviderID: constants.DefaultVectorStoreProvider,
-			Metadata: map[string]string{
-				"created_by": "auto-provisioning",
-				"username":   username,
-			},
+	page := vectorStores[start:end]
+	meta := &VectorStoresPage{HasMore: hasMore}
+	if len(page) > 0 {
+		meta.FirstID = page[0].ID
+		meta.LastID = page[len(page)-1].ID
+	}
+	return page, meta
+}
+
+// States of App.vectorStoreMigrations. A namespace whose migration failed holds the time.Time it is retried after.
+const (
+	vectorStoreMigrationRunning = "running"
+	vectorStoreMigrationDone    = "done"
+)
+
+// ensureVectorStoresMigrated migrates the auto-provisioned vector stores of the namespace on its first request.
+// Only one request of a namespace runs the migration; concurrent requests go on without waiting for it, since the
+// stores are private to their user before and after. A failed migration is logged and retried by the first request
+// after constants.VectorStoreMigrationRetryInterval.
+func (app *App) ensureVectorStoresMigrated(ctx context.Context, logger *slog.Logger, namespace string) {
+	if app.vectorStoreMigrations == nil || app.repositories == nil {
+		return
+	}
+	state, running := app.vectorStoreMigrations.LoadOrStore(namespace, vectorStoreMigrationRunning)
+	if running {
+		retryAfter, failed := state.(time.Time)
+		if !failed || time.Now().Before(retryAfter) {
+			return
+		}
+		// Of the requests that see the failure, only the one that swaps it out retries
+		if !app.vectorStoreMigrations.CompareAndSwap(namespace, state, vectorStoreMigrationRunning) {
+			return
 		}
+	}
 
-		newVectorStore, err := app.repositories.VectorStores.CreateVectorStore(ctx, createParams)
+	migrated, err := app.migrateAutoProvisionedVectorStores(ctx, namespace)
+	if err != nil {
+		logger.Warn("Failed to migrate auto-provisioned vector stores", "namespace", namespace, "error", err)
+		app.vectorStoreMigrations.Store(namespace, time.Now().Add(constants.VectorStoreMigrationRetryInterval))
+		return
+	}
+	for _, vectorStore := range migrated {
+		logger.Info("Migrated auto-provisioned vector store", "namespace", namespace, "vector_store_id", vectorStore.ID, "owner", vectorStore.Metadata[constants.VectorStoreOwnerKey])
+	}
+	app.vectorStoreMigrations.Store(namespace, vectorStoreMigrationDone)
+}
+
+// migrateAutoProvisionedVectorStores gives the stores created by the removed per-user auto-provisioning a private
+// owner and a readable name, and returns the migrated stores. Stores that are already owned are left unchanged.
+// It stops at the first store that fails to migrate; the stores migrated before it stay migrated.
+func (app *App) migrateAutoProvisionedVectorStores(ctx context.Context, namespace string) ([]openai.VectorStore, error) {
+	vectorStores, err := app.repositories.VectorStores.ListVectorStores(ctx, llamastack.ListVectorStoresParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	var migrated []openai.VectorStore
+	for _, vectorStore := range vectorStores {
+		username := vectorStore.Metadata[constants.VectorStoreAutoProvisionedUsernameKey]
+		if vectorStore.Metadata[constants.VectorStoreCreatedByKey] != constants.VectorStoreAutoProvisionedCreatedBy || username == "" {
+			continue
+		}
+		if vectorStore.Metadata[constants.VectorStoreOwnerKey] != "" {
+			continue
+		}
+
+		metadata := make(map[string]string, len(vectorStore.Metadata)+3)
+		for key, value := range vectorStore.Metadata {
+			if key != constants.VectorStoreAutoProvisionedUsernameKey {
+				metadata[key] = value
+			}
+		}
+		models.VectorStoreACL{
+			Owner:      username,
+			Namespace:  namespace,
+			Visibility: constants.VectorStoreVisibilityPrivate,
+		}.ApplyTo(metadata)
+
+		params := llamastack.UpdateVectorStoreParams{Metadata: metadata}
+		if vectorStore.Name == legacyVectorStoreName(username) {
+			name := migratedVectorStoreName
+			params.Name = &name
+		}
+
+		updated, err := app.repositories.VectorStores.UpdateVectorStore(ctx, vectorStore.ID, params)
 		if err != nil {
-			app.serverErrorResponse(w, r, err)
-			return
+			return migrated, fmt.Errorf("failed to migrate vector store %s: %w", vectorStore.ID, err)
 		}
+		migrated = append(migrated, *updated)
+	}
+	return migrated, nil
+}
+
+// vectorStoreCaller resolves the user, groups and namespace vector store access is checked for.
+// It writes the error response and returns false when they cannot be resolved.
+func (app *App) vectorStoreCaller(w http.ResponseWriter, r *http.Request) (models.VectorStoreCaller, bool) {
+	ctx := r.Context()
 
-		userVectorStore = newVectorStore
+	namespace, username, ok := app.conversationScope(w, r)
+	if !ok {
+		return models.VectorStoreCaller{}, false
 	}
 
-	// TEMPORARY: Return only the user's vectorstore as a single-item array
-	response := VectorStoresResponse{
-		Data: []openai.VectorStore{*userVectorStore},
+	groups, err := app.getRequestUserGroups(ctx)
+	if err != nil {
+		app.unauthorizedResponse(w, r, err)
+		return models.VectorStoreCaller{}, false
 	}
 
-	err = app.WriteJSON(w, http.StatusOK, response, nil)
+	return models.VectorStoreCaller{Username: username, Groups: groups, Namespace: namespace}, true
+}
+
+// authorizeVectorStore checks that the caller can read the vector store, and manage it when manage is set.
+// Stores the caller cannot read are reported as not found so their existence is not disclosed.
+// It writes the error response and returns false when access is denied.
+func (app *App) authorizeVectorStore(w http.ResponseWriter, r *http.Request, caller models.VectorStoreCaller, vectorStoreID string, manage bool) bool {
+	_, ok := app.authorizedVectorStore(w, r, caller, vectorStoreID, manage)
+	return ok
+}
+
+// authorizedVectorStore is authorizeVectorStore for handlers that need the vector store itself.
+func (app *App) authorizedVectorStore(w http.ResponseWriter, r *http.Request, caller models.VectorStoreCaller, vectorStoreID string, manage bool) (*openai.VectorStore, bool) {
+	vectorStore, err := app.repositories.VectorStores.GetVectorStore(r.Context(), vectorStoreID)
 	if err != nil {
+		var apiErr *openai.Error
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			app.notFoundResponse(w, r)
+			return nil, false
+		}
 		app.serverErrorResponse(w, r, err)
+		return nil, false
+	}
+
+	acl, _ := models.VectorStoreACLFromMetadata(vectorStore.Metadata)
+	if !acl.CanRead(caller) {
+		app.notFoundResponse(w, r)
+		return nil, false
+	}
+	if manage && !acl.CanManage(caller) {
+		app.forbiddenResponse(w, r, "only the owner of the vector store can change it")
+		return nil, false
 	}
+	return vectorStore, true
+}
+
+// authorizeVectorStores checks that the caller can read every vector store
+func (app *App) authorizeVectorStores(w http.ResponseWriter, r *http.Request, vectorStoreIDs []string) bool {
+	if len(vectorStoreIDs) == 0 {
+		return true
+	}
+
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok {
+		return false
+	}
+	for _, vectorStoreID := range vectorStoreIDs {
+		if !app.authorizeVectorStore(w, r, caller, vectorStoreID, false) {
+			return false
+		}
+	}
+	return true
 }
 
 // LlamaStackCreateVectorStoreHandler handles POST /gen-ai/api/v1/vectorstores
@@ -152,10 +339,41 @@ func (app *App) LlamaStackCreateVectorStoreHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
-	// Convert to client params (only working parameters)
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok {
+		return
+	}
+
+	acl, err := createRequest.acl(caller)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if len(createRequest.Metadata) > vectorStoreMaxUserMetadataPairs {
+		app.badRequestResponse(w, r, fmt.Errorf("metadata must have at most %d pairs", vectorStoreMaxUserMetadataPairs))
+		return
+	}
+	metadata := make(map[string]string, len(createRequest.Metadata)+4)
+	for key, value := range createRequest.Metadata {
+		if models.IsReservedVectorStoreMetadataKey(key) {
+			app.badRequestResponse(w, r, fmt.Errorf("metadata key %q is reserved", key))
+			return
+		}
+		metadata[key] = value
+	}
+	acl.ApplyTo(metadata)
+
 	params := llamastack.CreateVectorStoreParams{
 		Name:     createRequest.Name,
-		Metadata: createRequest.Metadata,
+		Metadata: metadata,
+	}
+	if !app.resolveVectorStoreEmbedding(w, r, createRequest, &params) {
+		return
+	}
+
+	if !app.checkVectorStoreQuota(w, r, caller.Namespace, 1, 0) {
+		return
 	}
 
 	vectorStore, err := app.repositories.VectorStores.CreateVectorStore(ctx, params)
@@ -174,10 +392,138 @@ func (app *App) LlamaStackCreateVectorStoreHandler(w http.ResponseWriter, r *htt
 	}
 }
 
-// LlamaStackDeleteVectorStoreHandler handles DELETE /gen-ai/api/v1/lsd/vectorstores/delete.
-func (app *App) LlamaStackDeleteVectorStoreHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+// resolveVectorStoreEmbedding checks the embedding model, its dimension and the vector_io provider of the vector
+// store to create against what the LlamaStack distribution serves, and sets them in the params. The embedding model
+// is recorded in the store metadata; LlamaStack embeds the files added to the store and its search queries with it.
+// It writes the error response and returns false when the choice is not available.
+func (app *App) resolveVectorStoreEmbedding(w http.ResponseWriter, r *http.Request, req CreateVectorStoreRequest, params *llamastack.CreateVectorStoreParams) bool {
 	ctx := r.Context()
 
+	providers, err := app.repositories.VectorStores.ListVectorIOProviders(ctx)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return false
+	}
+	providerIDs := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		providerIDs = append(providerIDs, provider.ProviderID)
+	}
+
+	// Without a choice, the default provider is used, or the first one when the distribution does not serve it
+	providerID := strings.TrimSpace(req.ProviderID)
+	switch {
+	case providerID != "" && !slices.Contains(providerIDs, providerID):
+		app.badRequestResponse(w, r, fmt.Errorf("provider_id %q is not a vector_io provider of LlamaStack, available providers: %s", providerID, strings.Join(providerIDs, ", ")))
+		return false
+	case providerID == "" && len(providerIDs) == 0:
+		app.badRequestResponse(w, r, errors.New("LlamaStack has no vector_io provider"))
+		return false
+	case providerID == "" && slices.Contains(providerIDs, constants.DefaultVectorStoreProvider):
+		providerID = constants.DefaultVectorStoreProvider
+	case providerID == "":
+		providerID = providerIDs[0]
+	}
+
+	allModels, err := app.repositories.Models.ListModels(ctx)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return false
+	}
+	// Older LlamaStack versions do not report model types, their models are all accepted
+	var embeddingModels []openai.Model
+	for _, model := range allModels {
+		if modelType := llamastack.ModelType(model); modelType == "" || modelType == llamastack.EmbeddingModelType {
+			embeddingModels = append(embeddingModels, model)
+		}
+	}
+	modelIDs := make([]string, 0, len(embeddingModels))
+	for _, model := range embeddingModels {
+		modelIDs = append(modelIDs, model.ID)
+	}
+
+	// Without a choice, the default model is used, or the first one when the distribution does not serve it
+	modelID := strings.TrimSpace(req.EmbeddingModel)
+	switch {
+	case modelID != "" && !slices.Contains(modelIDs, modelID):
+		app.badRequestResponse(w, r, fmt.Errorf("embedding_model %q is not an embedding model of LlamaStack, available models: %s", modelID, strings.Join(modelIDs, ", ")))
+		return false
+	case modelID == "" && len(modelIDs) == 0:
+		app.badRequestResponse(w, r, errors.New("LlamaStack has no embedding model"))
+		return false
+	case modelID == "" && slices.Contains(modelIDs, constants.DefaultEmbeddingModel.ModelID):
+		modelID = constants.DefaultEmbeddingModel.ModelID
+	case modelID == "":
+		modelID = modelIDs[0]
+	}
+
+	// The vectors of the store must have the dimension of the model, which LlamaStack may not report
+	dimension := llamastack.ModelEmbeddingDimension(embeddingModels[slices.Index(modelIDs, modelID)])
+	if dimension == 0 && modelID == constants.DefaultEmbeddingModel.ModelID {
+		dimension = constants.DefaultEmbeddingModel.EmbeddingDimension
+	}
+	switch {
+	case req.EmbeddingDimension != nil && *req.EmbeddingDimension < 1:
+		app.badRequestResponse(w, r, errors.New("embedding_dimension must be positive"))
+		return false
+	case req.EmbeddingDimension != nil && dimension != 0 && *req.EmbeddingDimension != dimension:
+		app.badRequestResponse(w, r, fmt.Errorf("embedding_dimension %d does not match the %d dimensions of %s", *req.EmbeddingDimension, dimension, modelID))
+		return false
+	case req.EmbeddingDimension != nil:
+		dimension = *req.EmbeddingDimension
+	case dimension == 0:
+		app.badRequestResponse(w, r, fmt.Errorf("embedding_dimension is required, LlamaStack does not report the dimension of %s", modelID))
+		return false
+	}
+
+	params.ProviderID = providerID
+	params.EmbeddingModel = modelID
+	params.EmbeddingDimension = &dimension
+	params.Metadata[constants.VectorStoreEmbeddingModelKey] = modelID
+	params.Metadata[constants.VectorStoreEmbeddingDimensionKey] = strconv.FormatInt(dimension, 10)
+	return true
+}
+
+// acl returns the ownership of the vector store to create, owned by the caller in its namespace
+func (req CreateVectorStoreRequest) acl(caller models.VectorStoreCaller) (models.VectorStoreACL, error) {
+	return newVectorStoreACL(caller.Username, caller.Namespace, req.Visibility, req.SharedGroups)
+}
+
+// newVectorStoreACL validates the visibility and shared groups of a vector store and returns its ownership.
+// The visibility defaults to private.
+func newVectorStoreACL(owner, namespace, visibility string, sharedGroups []string) (models.VectorStoreACL, error) {
+	acl := models.VectorStoreACL{
+		Owner:      owner,
+		Namespace:  namespace,
+		Visibility: visibility,
+	}
+	if acl.Visibility == "" {
+		acl.Visibility = constants.VectorStoreVisibilityPrivate
+	}
+	if !models.IsValidVectorStoreVisibility(acl.Visibility) {
+		return acl, fmt.Errorf("visibility must be one of %s, %s or %s", constants.VectorStoreVisibilityPrivate, constants.VectorStoreVisibilityNamespace, constants.VectorStoreVisibilityGroups)
+	}
+
+	for _, group := range sharedGroups {
+		group = strings.TrimSpace(group)
+		if group == "" || strings.Contains(group, ",") {
+			return acl, fmt.Errorf("invalid shared group: %q", group)
+		}
+		acl.SharedGroups = append(acl.SharedGroups, group)
+	}
+	switch {
+	case acl.Visibility == constants.VectorStoreVisibilityGroups && len(acl.SharedGroups) == 0:
+		return acl, errors.New("shared_groups is required for groups visibility")
+	case acl.Visibility != constants.VectorStoreVisibilityGroups && len(acl.SharedGroups) > 0:
+		return acl, errors.New("shared_groups requires groups visibility")
+	case len(strings.Join(acl.SharedGroups, ",")) > vectorStoreMaxMetadataValueLength:
+		return acl, fmt.Errorf("shared_groups must be at most %d characters in total", vectorStoreMaxMetadataValueLength)
+	}
+	return acl, nil
+}
+
+// LlamaStackDeleteVectorStoreHandler handles DELETE /gen-ai/api/v1/lsd/vectorstores/delete.
+// Deprecated: use DELETE /gen-ai/api/v1/lsd/vectorstores/:id.
+func (app *App) LlamaStackDeleteVectorStoreHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	// Get vector_store_id from query parameter
 	vectorStoreID := r.URL.Query().Get("vector_store_id")
 	if vectorStoreID == "" {
@@ -185,6 +531,18 @@ func (app *App) LlamaStackDeleteVectorStoreHandler(w http.ResponseWriter, r *htt
 		return
 	}
 
+	app.deleteVectorStore(w, r, vectorStoreID)
+}
+
+// deleteVectorStore deletes a vector store the caller owns
+func (app *App) deleteVectorStore(w http.ResponseWriter, r *http.Request, vectorStoreID string) {
+	ctx := r.Context()
+
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok || !app.authorizeVectorStore(w, r, caller, vectorStoreID, true) {
+		return
+	}
+
 	err := app.repositories.VectorStores.DeleteVectorStore(ctx, vectorStoreID)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
@@ -206,9 +564,11 @@ func (app *App) LlamaStackDeleteVectorStoreHandler(w http.ResponseWriter, r *htt
 	}
 }
 
-type VectorStoreFilesListResponse = llamastack.APIResponse
+type VectorStoreFilesListResponse = Envelope[[]map[string]interface{}, *VectorStoresPage]
 
 // LlamaStackListVectorStoreFilesHandler handles GET /gen-ai/api/v1/lsd/vectorstores/files.
+// It returns limit files (1-100, default 20) following the after cursor. LlamaStack cannot filter on attributes,
+// so with filters its pages are read until limit matching files are found; last_id is the cursor of the next page.
 func (app *App) LlamaStackListVectorStoreFilesHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	ctx := r.Context()
 
@@ -219,17 +579,25 @@ func (app *App) LlamaStackListVectorStoreFilesHandler(w http.ResponseWriter, r *
 		return
 	}
 
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok || !app.authorizeVectorStore(w, r, caller, vectorStoreID, false) {
+		return
+	}
+
 	// Parse query parameters
-	params := llamastack.ListVectorStoreFilesParams{}
+	params := llamastack.ListVectorStoreFilesParams{
+		After: r.URL.Query().Get("after"),
+	}
 
 	// Parse limit parameter
+	limit := int64(constants.VectorStoreDefaultListLimit)
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if limit, err := strconv.ParseInt(limitStr, 10, 64); err == nil {
-			params.Limit = &limit
-		} else {
+		parsed, err := strconv.ParseInt(limitStr, 10, 64)
+		if err != nil || parsed < 1 || parsed > constants.VectorStoreMaxListLimit {
 			app.badRequestResponse(w, r, fmt.Errorf("invalid limit parameter: %s", limitStr))
 			return
 		}
+		limit = parsed
 	}
 
 	// Parse order parameter
@@ -242,15 +610,61 @@ func (app *App) LlamaStackListVectorStoreFilesHandler(w http.ResponseWriter, r *
 		params.Filter = filter
 	}
 
-	result, err := app.repositories.VectorStores.ListVectorStoreFiles(ctx, vectorStoreID, params)
-	if err != nil {
-		app.serverErrorResponse(w, r, err)
-		return
+	// Parse filters parameter, a JSON filter expression on the file attributes
+	var attributeFilters *llamastack.VectorStoreSearchFilter
+	if filters := r.URL.Query().Get("filters"); filters != "" {
+		var filter llamastack.VectorStoreSearchFilter
+		if err := json.Unmarshal([]byte(filters), &filter); err != nil {
+			app.badRequestResponse(w, r, fmt.Errorf("invalid filters parameter: %w", err))
+			return
+		}
+		if err := filter.Validate(); err != nil {
+			app.badRequestResponse(w, r, fmt.Errorf("invalid filters parameter: %w", err))
+			return
+		}
+		attributeFilters = &filter
+	}
+
+	// One file more than the page is collected to tell whether there is a next page. Filtered listings read
+	// the largest pages LlamaStack serves, as most of their files may not match.
+	pageSize := min(limit+1, constants.VectorStoreMaxListLimit)
+	if attributeFilters != nil {
+		pageSize = constants.VectorStoreMaxListLimit
+	}
+	params.Limit = &pageSize
+
+	var matching []openai.VectorStoreFile
+	for int64(len(matching)) <= limit {
+		files, err := app.repositories.VectorStores.ListVectorStoreFiles(ctx, vectorStoreID, params)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		for _, vsFile := range files {
+			if attributeFilters == nil || attributeFilters.Matches(attributeValues(vsFile.Attributes, fileAttributeValue)) {
+				matching = append(matching, vsFile)
+			}
+		}
+
+		if int64(len(files)) < pageSize {
+			break
+		}
+		params.After = files[len(files)-1].ID
+	}
+
+	meta := &VectorStoresPage{HasMore: int64(len(matching)) > limit}
+	if meta.HasMore {
+		matching = matching[:limit]
+	}
+	if len(matching) > 0 {
+		meta.FirstID = matching[0].ID
+		meta.LastID = matching[len(matching)-1].ID
 	}
 
 	// Enrich each vectorstore file with filename information
-	enrichedFiles := make([]map[string]interface{}, len(result))
-	for i, vsFile := range result {
+	enrichedFiles := make([]map[string]interface{}, 0, len(matching))
+	for _, vsFile := range matching {
 		// Convert VectorStoreFile to map for enrichment
 		enrichedFile := map[string]interface{}{
 			"id":                vsFile.ID,
@@ -260,7 +674,7 @@ func (app *App) LlamaStackListVectorStoreFilesHandler(w http.ResponseWriter, r *
 			"status":            vsFile.Status,
 			"usage_bytes":       vsFile.UsageBytes,
 			"vector_store_id":   vsFile.VectorStoreID,
-			"attributes":        vsFile.Attributes,
+			"attributes":        attributeValues(vsFile.Attributes, fileAttributeValue),
 			"chunking_strategy": vsFile.ChunkingStrategy,
 		}
 
@@ -278,15 +692,16 @@ func (app *App) LlamaStackListVectorStoreFilesHandler(w http.ResponseWriter, r *
 			enrichedFile["purpose"] = fileDetails.Purpose
 		}
 
-		enrichedFiles[i] = enrichedFile
+		enrichedFiles = append(enrichedFiles, enrichedFile)
 	}
 
 	// Use envelope pattern for consistent response structure
 	response := VectorStoreFilesListResponse{
-		Data: enrichedFiles,
+		Data:     enrichedFiles,
+		Metadata: meta,
 	}
 
-	err = app.WriteJSON(w, http.StatusOK, response, nil)
+	err := app.WriteJSON(w, http.StatusOK, response, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -314,6 +729,11 @@ func (app *App) LlamaStackDeleteVectorStoreFileHandler(w http.ResponseWriter, r
 		return
 	}
 
+	caller, ok := app.vectorStoreCaller(w, r)
+	if !ok || !app.authorizeVectorStore(w, r, caller, vectorStoreID, true) {
+		return
+	}
+
 	// Step 1: Remove file from vectorstore
 	err := app.repositories.VectorStores.DeleteVectorStoreFile(ctx, vectorStoreID, fileID)
 	if err != nil {
@@ -8,7 +8,10 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/opendatahub-io/gen-ai/internal/config"
 	"github.com/opendatahub-io/gen-ai/internal/constants"
@@ -21,6 +24,64 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestMigrateAutoProvisionedVectorStores(t *testing.T) {
+	app := App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
+		},
+		llamaStackClientFactory: lsmocks.NewMockClientFactory(),
+		repositories:            repositories.NewRepositories(),
+		logger:                  slog.Default(),
+		vectorStoreMigrations:   &sync.Map{},
+	}
+	llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+	ctx := context.WithValue(context.Background(), constants.LlamaStackClientKey, llamaStackClient)
+
+	t.Run("should give auto-provisioned vector stores a private owner", func(t *testing.T) {
+		migrated, err := app.migrateAutoProvisionedVectorStores(ctx, testutil.TestNamespace)
+		require.NoError(t, err)
+		require.Len(t, migrated, 1)
+
+		assert.Equal(t, lsmocks.MockLegacyVectorStoreID, migrated[0].ID)
+		assert.Equal(t, "My documents", migrated[0].Name)
+		metadata := migrated[0].Metadata
+		assert.Equal(t, lsmocks.MockVectorStoreOwner, metadata[constants.VectorStoreOwnerKey])
+		assert.Equal(t, testutil.TestNamespace, metadata[constants.VectorStoreNamespaceKey])
+		assert.Equal(t, constants.VectorStoreVisibilityPrivate, metadata[constants.VectorStoreVisibilityKey])
+		assert.NotContains(t, metadata, "username")
+	})
+
+	t.Run("should migrate a namespace once", func(t *testing.T) {
+		app.ensureVectorStoresMigrated(ctx, slog.Default(), testutil.TestNamespace)
+
+		state, found := app.vectorStoreMigrations.Load(testutil.TestNamespace)
+		require.True(t, found)
+		assert.Equal(t, vectorStoreMigrationDone, state)
+	})
+
+	t.Run("should not run a migration that is already running", func(t *testing.T) {
+		app.vectorStoreMigrations.Store("running-namespace", vectorStoreMigrationRunning)
+		app.ensureVectorStoresMigrated(ctx, slog.Default(), "running-namespace")
+
+		state, _ := app.vectorStoreMigrations.Load("running-namespace")
+		assert.Equal(t, vectorStoreMigrationRunning, state)
+	})
+
+	t.Run("should retry a failed migration once the retry interval passed", func(t *testing.T) {
+		retryAfter := time.Now().Add(time.Minute)
+		app.vectorStoreMigrations.Store("failed-namespace", retryAfter)
+		app.ensureVectorStoresMigrated(ctx, slog.Default(), "failed-namespace")
+		state, _ := app.vectorStoreMigrations.Load("failed-namespace")
+		assert.Equal(t, retryAfter, state)
+
+		app.vectorStoreMigrations.Store("failed-namespace", time.Now().Add(-time.Second))
+		app.ensureVectorStoresMigrated(ctx, slog.Default(), "failed-namespace")
+		state, _ = app.vectorStoreMigrations.Load("failed-namespace")
+		assert.Equal(t, vectorStoreMigrationDone, state)
+	})
+}
+
 func TestLlamaStackListVectorStoresHandler(t *testing.T) {
 	// Setup test environment
 	ctx, cancel := context.WithCancel(context.Background())
@@ -54,178 +115,110 @@ func TestLlamaStackListVectorStoresHandler(t *testing.T) {
 		logger:                  slog.Default(),
 	}
 
-	t.Run("should list vector stores without parameters", func(t *testing.T) {
-		req, err := http.NewRequest(http.MethodGet, "/gen-ai/api/v1/vectorstores?namespace="+testutil.TestNamespace, nil)
-		assert.NoError(t, err)
+	type listResponse struct {
+		Data     []map[string]interface{} `json:"data"`
+		Metadata VectorStoresPage         `json:"metadata"`
+	}
 
-		// Simulate middleware: add RequestIdentity and LlamaStack client to context
+	// Helper function to list vector stores as the mock user
+	listVectorStores := func(t *testing.T, query string) (int, listResponse) {
+		req, err := http.NewRequest(http.MethodGet, "/gen-ai/api/v1/vectorstores?namespace="+testutil.TestNamespace+query, nil)
+		require.NoError(t, err)
+
+		// Simulate middleware: add RequestIdentity, namespace, and LlamaStack client to context
 		identity := &integrations.RequestIdentity{Token: "test-token"}
 		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
 		ctx := context.WithValue(req.Context(), constants.RequestIdentityKey, identity)
+		ctx = context.WithValue(ctx, constants.NamespaceQueryParameterKey, testutil.TestNamespace)
 		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
 		req = req.WithContext(ctx)
 
 		rr := httptest.NewRecorder()
 		app.LlamaStackListVectorStoresHandler(rr, req, nil)
 
-		assert.Equal(t, http.StatusOK, rr.Code)
-
-		body, err := io.ReadAll(rr.Result().Body)
-		assert.NoError(t, err)
-		defer rr.Result().Body.Close()
-
-		var response VectorStoresResponse
-		err = json.Unmarshal(body, &response)
-		assert.NoError(t, err)
+		var response listResponse
+		if rr.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		}
+		return rr.Code, response
+	}
 
-		// TEMPORARY: Handler now returns user-specific vectorstore (auto-provisioned if needed)
-		vectorStores := response.Data.([]interface{})
-		assert.Len(t, vectorStores, 1)
+	vectorStoreIDs := func(response listResponse) []string {
+		ids := make([]string, 0, len(response.Data))
+		for _, vectorStore := range response.Data {
+			ids = append(ids, vectorStore["id"].(string))
+		}
+		return ids
+	}
 
-		// Verify the returned vector store (will be auto-provisioned with hashed username)
-		firstStore := vectorStores[0].(map[string]interface{})
-		assert.NotEmpty(t, firstStore["id"])
-		// Name should be hashed username (32 char hex from SHA256)
-		assert.Len(t, firstStore["name"].(string), 32)
-		assert.Contains(t, []string{"completed", "in_progress"}, firstStore["status"])
+	t.Run("should list the vector stores the user can read", func(t *testing.T) {
+		status, response := listVectorStores(t, "")
+
+		assert.Equal(t, http.StatusOK, status)
+		// Own, namespace-shared, auto-provisioned and unowned stores, newest first; another user's private store is hidden
+		assert.Equal(t, []string{
+			lsmocks.MockPrivateVectorStoreID,
+			lsmocks.MockSharedVectorStoreID,
+			lsmocks.MockLegacyVectorStoreID,
+			lsmocks.MockVectorStoreID,
+		}, vectorStoreIDs(response))
+		assert.False(t, response.Metadata.HasMore)
+		assert.Equal(t, lsmocks.MockPrivateVectorStoreID, response.Metadata.FirstID)
+		assert.Equal(t, lsmocks.MockVectorStoreID, response.Metadata.LastID)
 	})
 
 	t.Run("should list vector stores with limit parameter", func(t *testing.T) {
-		req, err := http.NewRequest(http.MethodGet, "/gen-ai/api/v1/vectorstores?namespace="+testutil.TestNamespace+"&limit=5", nil)
-		assert.NoError(t, err)
-
-		// Simulate middleware: add RequestIdentity and LlamaStack client to context
-		identity := &integrations.RequestIdentity{Token: "test-token"}
-		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
-		ctx := context.WithValue(req.Context(), constants.RequestIdentityKey, identity)
-		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
-		req = req.WithContext(ctx)
-
-		rr := httptest.NewRecorder()
-		app.LlamaStackListVectorStoresHandler(rr, req, nil)
-
-		assert.Equal(t, http.StatusOK, rr.Code)
+		status, response := listVectorStores(t, "&limit=2")
 
-		body, err := io.ReadAll(rr.Result().Body)
-		assert.NoError(t, err)
-		defer rr.Result().Body.Close()
-
-		var response VectorStoresResponse
-		err = json.Unmarshal(body, &response)
-		assert.NoError(t, err)
-
-		vectorStores := response.Data.([]interface{})
-		assert.Len(t, vectorStores, 1) // Mock always returns 1
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{lsmocks.MockPrivateVectorStoreID, lsmocks.MockSharedVectorStoreID}, vectorStoreIDs(response))
+		assert.True(t, response.Metadata.HasMore)
+		assert.Equal(t, lsmocks.MockSharedVectorStoreID, response.Metadata.LastID)
 	})
 
-	t.Run("should list vector stores with order parameter", func(t *testing.T) {
-		req, err := http.NewRequest(http.MethodGet, "/gen-ai/api/v1/vectorstores?namespace="+testutil.TestNamespace+"&order=desc", nil)
-		assert.NoError(t, err)
-
-		// Simulate middleware: add RequestIdentity and LlamaStack client to context
-		identity := &integrations.RequestIdentity{Token: "test-token"}
-		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
-		ctx := context.WithValue(req.Context(), constants.RequestIdentityKey, identity)
-		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
-		req = req.WithContext(ctx)
-
-		rr := httptest.NewRecorder()
-		app.LlamaStackListVectorStoresHandler(rr, req, nil)
-
-		assert.Equal(t, http.StatusOK, rr.Code)
-
-		body, err := io.ReadAll(rr.Result().Body)
-		assert.NoError(t, err)
-		defer rr.Result().Body.Close()
-
-		var response VectorStoresResponse
-		err = json.Unmarshal(body, &response)
-		assert.NoError(t, err)
+	t.Run("should list the next page with after parameter", func(t *testing.T) {
+		status, response := listVectorStores(t, "&limit=2&after="+lsmocks.MockSharedVectorStoreID)
 
-		vectorStores := response.Data.([]interface{})
-		assert.Len(t, vectorStores, 1)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{lsmocks.MockLegacyVectorStoreID, lsmocks.MockVectorStoreID}, vectorStoreIDs(response))
+		assert.False(t, response.Metadata.HasMore)
 	})
 
-	t.Run("should list vector stores with both limit and order parameters", func(t *testing.T) {
-		req, err := http.NewRequest(http.MethodGet, "/gen-ai/api/v1/vectorstores?namespace="+testutil.TestNamespace+"&limit=10&order=asc", nil)
-		assert.NoError(t, err)
-
-		// Simulate middleware: add RequestIdentity and LlamaStack client to context
-		identity := &integrations.RequestIdentity{Token: "test-token"}
-		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
-		ctx := context.WithValue(req.Context(), constants.RequestIdentityKey, identity)
-		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
-		req = req.WithContext(ctx)
-
-		rr := httptest.NewRecorder()
-		app.LlamaStackListVectorStoresHandler(rr, req, nil)
-
-		assert.Equal(t, http.StatusOK, rr.Code)
-
-		body, err := io.ReadAll(rr.Result().Body)
-		assert.NoError(t, err)
-		defer rr.Result().Body.Close()
-
-		var response VectorStoresResponse
-		err = json.Unmarshal(body, &response)
-		assert.NoError(t, err)
+	t.Run("should list the previous page with before parameter", func(t *testing.T) {
+		status, response := listVectorStores(t, "&limit=1&before="+lsmocks.MockLegacyVectorStoreID)
 
-		vectorStores := response.Data.([]interface{})
-		assert.Len(t, vectorStores, 1)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []string{lsmocks.MockSharedVectorStoreID}, vectorStoreIDs(response))
+		assert.True(t, response.Metadata.HasMore)
 	})
 
-	t.Run("should ignore invalid limit parameter", func(t *testing.T) {
-		req, err := http.NewRequest(http.MethodGet, "/gen-ai/api/v1/vectorstores?namespace="+testutil.TestNamespace+"&limit=invalid", nil)
-		assert.NoError(t, err)
-
-		// Simulate middleware: add RequestIdentity and LlamaStack client to context
-		identity := &integrations.RequestIdentity{Token: "test-token"}
-		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
-		ctx := context.WithValue(req.Context(), constants.RequestIdentityKey, identity)
-		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
-		req = req.WithContext(ctx)
-
-		rr := httptest.NewRecorder()
-		app.LlamaStackListVectorStoresHandler(rr, req, nil)
+	t.Run("should list vector stores with order parameter", func(t *testing.T) {
+		status, response := listVectorStores(t, "&order=asc")
 
-		assert.Equal(t, http.StatusOK, rr.Code) // Should still work, ignore invalid param
+		assert.Equal(t, http.StatusOK, status)
+		require.Len(t, response.Data, 4)
+		assert.Equal(t, lsmocks.MockVectorStoreID, response.Data[0]["id"])
 	})
 
-	t.Run("should ignore invalid order parameter", func(t *testing.T) {
-		req, err := http.NewRequest(http.MethodGet, "/gen-ai/api/v1/vectorstores?namespace="+testutil.TestNamespace+"&order=invalid", nil)
-		assert.NoError(t, err)
-
-		// Simulate middleware: add RequestIdentity and LlamaStack client to context
-		identity := &integrations.RequestIdentity{Token: "test-token"}
-		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
-		ctx := context.WithValue(req.Context(), constants.RequestIdentityKey, identity)
-		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
-		req = req.WithContext(ctx)
-
-		rr := httptest.NewRecorder()
-		app.LlamaStackListVectorStoresHandler(rr, req, nil)
-
-		assert.Equal(t, http.StatusOK, rr.Code) // Should still work, ignore invalid param
+	t.Run("should reject invalid pagination parameters", func(t *testing.T) {
+		for _, query := range []string{
+			"&limit=invalid",
+			"&limit=0",
+			"&limit=101",
+			"&order=invalid",
+			"&after=" + lsmocks.MockVectorStoreID + "&before=" + lsmocks.MockPrivateVectorStoreID,
+		} {
+			status, _ := listVectorStores(t, query)
+			assert.Equal(t, http.StatusBadRequest, status, query)
+		}
 	})
 
 	t.Run("should use unified repository pattern", func(t *testing.T) {
 		assert.NotNil(t, app.repositories)
 		assert.NotNil(t, app.repositories.VectorStores)
 
-		req, err := http.NewRequest(http.MethodGet, "/gen-ai/api/v1/vectorstores?namespace="+testutil.TestNamespace, nil)
-		assert.NoError(t, err)
-
-		// Simulate middleware: add RequestIdentity and LlamaStack client to context
-		identity := &integrations.RequestIdentity{Token: "test-token"}
-		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
-		ctx := context.WithValue(req.Context(), constants.RequestIdentityKey, identity)
-		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
-		req = req.WithContext(ctx)
-
-		rr := httptest.NewRecorder()
-		app.LlamaStackListVectorStoresHandler(rr, req, nil)
-
-		assert.Equal(t, http.StatusOK, rr.Code)
+		status, _ := listVectorStores(t, "")
+		assert.Equal(t, http.StatusOK, status)
 	})
 }
 
@@ -234,7 +227,8 @@ func TestLlamaStackCreateVectorStoreHandler(t *testing.T) {
 	llamaStackClientFactory := lsmocks.NewMockClientFactory()
 	app := App{
 		config: config.EnvConfig{
-			Port: 4000,
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
 		},
 		llamaStackClientFactory: llamaStackClientFactory,
 		repositories:            repositories.NewRepositories(),
@@ -252,6 +246,8 @@ func TestLlamaStackCreateVectorStoreHandler(t *testing.T) {
 			return nil, err
 		}
 		req.Header.Set("Content-Type", "application/json")
+		// Simulate the namespace middleware
+		req = req.WithContext(context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace))
 		return req, nil
 	}
 
@@ -503,6 +499,109 @@ func TestLlamaStackCreateVectorStoreHandler(t *testing.T) {
 		assert.Equal(t, "Metadata Test Store", vectorStore["name"])
 		assert.Contains(t, vectorStore, "metadata")
 	})
+
+	// Helper function to create a vector store as the anonymous user of disabled auth
+	createVectorStore := func(t *testing.T, payload CreateVectorStoreRequest) *httptest.ResponseRecorder {
+		req, err := createJSONRequest(payload)
+		require.NoError(t, err)
+
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		req = req.WithContext(context.WithValue(req.Context(), constants.LlamaStackClientKey, llamaStackClient))
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateVectorStoreHandler(rr, req, nil)
+		return rr
+	}
+
+	t.Run("should record the owner as private by default", func(t *testing.T) {
+		rr := createVectorStore(t, CreateVectorStoreRequest{
+			Name:     "Owned Store",
+			Metadata: map[string]string{"team": "engineering"},
+		})
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var response VectorStoreResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+
+		metadata := response.Data.(map[string]interface{})["metadata"].(map[string]interface{})
+		assert.Equal(t, anonymousUsername, metadata[constants.VectorStoreOwnerKey])
+		assert.Equal(t, testutil.TestNamespace, metadata[constants.VectorStoreNamespaceKey])
+		assert.Equal(t, constants.VectorStoreVisibilityPrivate, metadata[constants.VectorStoreVisibilityKey])
+		assert.Equal(t, "engineering", metadata["team"])
+	})
+
+	t.Run("should share vector store with groups", func(t *testing.T) {
+		rr := createVectorStore(t, CreateVectorStoreRequest{
+			Name:         "Team Store",
+			Visibility:   constants.VectorStoreVisibilityGroups,
+			SharedGroups: []string{"data-science", "ml-ops"},
+		})
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var response VectorStoreResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+
+		metadata := response.Data.(map[string]interface{})["metadata"].(map[string]interface{})
+		assert.Equal(t, constants.VectorStoreVisibilityGroups, metadata[constants.VectorStoreVisibilityKey])
+		assert.Equal(t, "data-science,ml-ops", metadata[constants.VectorStoreSharedGroupsKey])
+	})
+
+	t.Run("should reject invalid ownership", func(t *testing.T) {
+		for name, payload := range map[string]CreateVectorStoreRequest{
+			"unknown visibility":           {Name: "Store", Visibility: "public"},
+			"groups without shared groups": {Name: "Store", Visibility: constants.VectorStoreVisibilityGroups},
+			"shared groups without groups": {Name: "Store", SharedGroups: []string{"data-science"}},
+			"reserved metadata key":        {Name: "Store", Metadata: map[string]string{constants.VectorStoreOwnerKey: "someone-else"}},
+			"reserved embedding key":       {Name: "Store", Metadata: map[string]string{constants.VectorStoreEmbeddingModelKey: "other-model"}},
+		} {
+			rr := createVectorStore(t, payload)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, name)
+		}
+	})
+
+	t.Run("should record the default embedding model and provider", func(t *testing.T) {
+		rr := createVectorStore(t, CreateVectorStoreRequest{Name: "Default Store"})
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var response VectorStoreResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+
+		metadata := response.Data.(map[string]interface{})["metadata"].(map[string]interface{})
+		assert.Equal(t, constants.DefaultVectorStoreProvider, metadata["provider_id"])
+		assert.Equal(t, constants.DefaultEmbeddingModel.ModelID, metadata[constants.VectorStoreEmbeddingModelKey])
+		assert.Equal(t, "768", metadata[constants.VectorStoreEmbeddingDimensionKey])
+	})
+
+	t.Run("should create vector store with the chosen embedding model and provider", func(t *testing.T) {
+		rr := createVectorStore(t, CreateVectorStoreRequest{
+			Name:           "MiniLM Store",
+			EmbeddingModel: "ollama/all-minilm:l6-v2",
+			ProviderID:     "faiss",
+		})
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var response VectorStoreResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+
+		metadata := response.Data.(map[string]interface{})["metadata"].(map[string]interface{})
+		assert.Equal(t, "faiss", metadata["provider_id"])
+		assert.Equal(t, "ollama/all-minilm:l6-v2", metadata[constants.VectorStoreEmbeddingModelKey])
+		assert.Equal(t, "384", metadata[constants.VectorStoreEmbeddingDimensionKey])
+	})
+
+	t.Run("should reject embedding models and providers LlamaStack does not serve", func(t *testing.T) {
+		dimension := func(dimension int64) *int64 { return &dimension }
+		for name, payload := range map[string]CreateVectorStoreRequest{
+			"unknown embedding model": {Name: "Store", EmbeddingModel: "text-embedding-3-large"},
+			"llm as embedding model":  {Name: "Store", EmbeddingModel: "ollama/llama3.2:3b"},
+			"mismatching dimension":   {Name: "Store", EmbeddingModel: "ollama/all-minilm:l6-v2", EmbeddingDimension: dimension(768)},
+			"negative dimension":      {Name: "Store", EmbeddingDimension: dimension(-1)},
+			"unknown provider":        {Name: "Store", ProviderID: "pgvector"},
+		} {
+			rr := createVectorStore(t, payload)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, name)
+		}
+	})
 }
 
 func TestLlamaStackDeleteVectorStoreHandler(t *testing.T) {
@@ -510,7 +609,8 @@ func TestLlamaStackDeleteVectorStoreHandler(t *testing.T) {
 	llamaStackClientFactory := lsmocks.NewMockClientFactory()
 	app := App{
 		config: config.EnvConfig{
-			Port: 4000,
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
 		},
 		llamaStackClientFactory: llamaStackClientFactory,
 		repositories:            repositories.NewRepositories(),
@@ -563,6 +663,34 @@ func TestLlamaStackDeleteVectorStoreHandler(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	})
 
+	t.Run("vector store of another user is not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, constants.VectorStoresDeletePath+"?namespace="+testutil.TestNamespace+"&vector_store_id="+lsmocks.MockOtherUserVectorStoreID, nil)
+		// Simulate middleware: add namespace and LlamaStack client to context
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackDeleteVectorStoreHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("shared vector store of another user is forbidden", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, constants.VectorStoresDeletePath+"?namespace="+testutil.TestNamespace+"&vector_store_id="+lsmocks.MockSharedVectorStoreID, nil)
+		// Simulate middleware: add namespace and LlamaStack client to context
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackDeleteVectorStoreHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
 	t.Run("missing LlamaStack client in context", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodDelete, constants.VectorStoresDeletePath+"?namespace=default&vector_store_id=vs-test123", nil)
 		// Simulate AttachNamespace middleware but skip AttachLlamaStackClient
@@ -581,7 +709,8 @@ func TestLlamaStackListVectorStoreFilesHandler(t *testing.T) {
 	llamaStackClientFactory := lsmocks.NewMockClientFactory()
 	app := App{
 		config: config.EnvConfig{
-			Port: 4000,
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
 		},
 		llamaStackClientFactory: llamaStackClientFactory,
 		repositories:            repositories.NewRepositories(),
@@ -649,7 +778,7 @@ func TestLlamaStackListVectorStoreFilesHandler(t *testing.T) {
 		// Verify envelope structure
 		assert.Contains(t, response, "data")
 		data := response["data"].([]interface{})
-		assert.Len(t, data, 2) // Mock returns 2 files regardless of parameters
+		assert.Len(t, data, 2) // Mock returns 2 files regardless of the order and status filter
 	})
 
 	t.Run("missing vector_store_id parameter", func(t *testing.T) {
@@ -668,6 +797,34 @@ func TestLlamaStackListVectorStoreFilesHandler(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	})
 
+	t.Run("files of a shared vector store of another user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, constants.VectorStoreFilesListPath+"?namespace="+testutil.TestNamespace+"&vector_store_id="+lsmocks.MockSharedVectorStoreID, nil)
+		// Simulate middleware: add namespace and LlamaStack client to context
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackListVectorStoreFilesHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("files of a private vector store of another user are not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, constants.VectorStoreFilesListPath+"?namespace="+testutil.TestNamespace+"&vector_store_id="+lsmocks.MockOtherUserVectorStoreID, nil)
+		// Simulate middleware: add namespace and LlamaStack client to context
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackListVectorStoreFilesHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
 	t.Run("invalid limit parameter", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, constants.VectorStoreFilesListPath+"?namespace=default&vector_store_id=vs-test123&limit=invalid", nil)
 		// Simulate middleware: add RequestIdentity, namespace, and LlamaStack client to context
@@ -683,6 +840,111 @@ func TestLlamaStackListVectorStoreFilesHandler(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	})
+
+	t.Run("should return attributes and keep files whose attributes match the filters", func(t *testing.T) {
+		filters := url.QueryEscape(`{"type":"and","filters":[{"type":"eq","key":"department","value":"legal"},{"type":"lt","key":"year","value":2024}]}`)
+		req := httptest.NewRequest(http.MethodGet, constants.VectorStoreFilesListPath+"?namespace=default&vector_store_id=vs-test123&filters="+filters, nil)
+		// Simulate middleware: add namespace and LlamaStack client to context
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, "default")
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackListVectorStoreFilesHandler(rr, req, nil)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		var response struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, "file-mock789ghi012jkl", response.Data[0]["id"])
+		assert.Equal(t, map[string]interface{}{"department": "legal", "year": float64(2023)}, response.Data[0]["attributes"])
+	})
+
+	t.Run("invalid filters parameter", func(t *testing.T) {
+		for _, filters := range []string{"not-json", `{"type":"contains","key":"department","value":"legal"}`} {
+			req := httptest.NewRequest(http.MethodGet, constants.VectorStoreFilesListPath+"?namespace=default&vector_store_id=vs-test123&filters="+url.QueryEscape(filters), nil)
+			// Simulate middleware: add namespace and LlamaStack client to context
+			ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, "default")
+			llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+			ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			app.LlamaStackListVectorStoreFilesHandler(rr, req, nil)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code, filters)
+		}
+	})
+
+	t.Run("should page through LlamaStack until limit files match the filters", func(t *testing.T) {
+		type filesPage struct {
+			Data     []map[string]interface{} `json:"data"`
+			Metadata VectorStoresPage         `json:"metadata"`
+		}
+		listLegalFiles := func(t *testing.T, query string) filesPage {
+			filters := url.QueryEscape(`{"type":"eq","key":"department","value":"legal"}`)
+			req := httptest.NewRequest(http.MethodGet, constants.VectorStoreFilesListPath+"?namespace=default&vector_store_id="+lsmocks.MockManyFilesVectorStoreID+"&filters="+filters+query, nil)
+			// Simulate middleware: add namespace and LlamaStack client to context
+			ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, "default")
+			llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+			ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+			req = req.WithContext(ctx)
+
+			rr := httptest.NewRecorder()
+			app.LlamaStackListVectorStoreFilesHandler(rr, req, nil)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			var response filesPage
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+			return response
+		}
+		fileIDs := func(page filesPage) []string {
+			ids := make([]string, 0, len(page.Data))
+			for _, file := range page.Data {
+				ids = append(ids, file["id"].(string))
+			}
+			return ids
+		}
+
+		// The legal files are spread over the two pages LlamaStack serves
+		page := listLegalFiles(t, "&limit=2")
+		assert.Equal(t, []string{"file-mock-many049", "file-mock-many099"}, fileIDs(page))
+		assert.True(t, page.Metadata.HasMore)
+		assert.Equal(t, "file-mock-many099", page.Metadata.LastID)
+
+		page = listLegalFiles(t, "&limit=2&after="+page.Metadata.LastID)
+		assert.Equal(t, []string{"file-mock-many149"}, fileIDs(page))
+		assert.False(t, page.Metadata.HasMore)
+
+		page = listLegalFiles(t, "&limit=3")
+		assert.Len(t, page.Data, 3)
+		assert.False(t, page.Metadata.HasMore)
+	})
+
+	t.Run("should page files without filters", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, constants.VectorStoreFilesListPath+"?namespace=default&vector_store_id="+lsmocks.MockManyFilesVectorStoreID+"&limit=100", nil)
+		// Simulate middleware: add namespace and LlamaStack client to context
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, "default")
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackListVectorStoreFilesHandler(rr, req, nil)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		var response struct {
+			Data     []map[string]interface{} `json:"data"`
+			Metadata VectorStoresPage         `json:"metadata"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Len(t, response.Data, 100)
+		assert.True(t, response.Metadata.HasMore)
+		assert.Equal(t, "file-mock-many099", response.Metadata.LastID)
+	})
 }
 
 func TestLlamaStackDeleteVectorStoreFileHandler(t *testing.T) {
@@ -690,7 +952,8 @@ func TestLlamaStackDeleteVectorStoreFileHandler(t *testing.T) {
 	llamaStackClientFactory := lsmocks.NewMockClientFactory()
 	app := App{
 		config: config.EnvConfig{
-			Port: 4000,
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
 		},
 		llamaStackClientFactory: llamaStackClientFactory,
 		repositories:            repositories.NewRepositories(),
@@ -759,6 +1022,20 @@ func TestLlamaStackDeleteVectorStoreFileHandler(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, rr.Code)
 	})
 
+	t.Run("file of a shared vector store of another user is forbidden", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, constants.VectorStoreFilesDeletePath+"?namespace="+testutil.TestNamespace+"&vector_store_id="+lsmocks.MockSharedVectorStoreID+"&file_id=file-test456", nil)
+		// Simulate middleware: add namespace and LlamaStack client to context
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		req = req.WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackDeleteVectorStoreFileHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
 	t.Run("missing LlamaStack client in context", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodDelete, constants.VectorStoreFilesDeletePath+"?namespace=default&vector_store_id=vs-test123&file_id=file-test456", nil)
 		// Simulate AttachNamespace middleware but skip AttachLlamaStackClient
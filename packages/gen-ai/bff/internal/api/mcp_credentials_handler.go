@@ -0,0 +1,209 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	kubernetes "github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+)
+
+type MCPCredentialsEnvelope = Envelope[[]models.MCPCredential, None]
+type MCPCredentialEnvelope = Envelope[*models.MCPCredential, None]
+
+// SetMCPCredentialRequest represents the request body for storing an MCP server credential
+type SetMCPCredentialRequest struct {
+	Token string `json:"token"` // Bearer token for the MCP server, with or without the "Bearer " prefix
+}
+
+// mcpCredentialScope holds what every MCP credential operation needs to reach the user's Secrets
+type mcpCredentialScope struct {
+	namespace string
+	owner     string
+	identity  *integrations.RequestIdentity
+	k8sClient kubernetes.KubernetesClientInterface
+}
+
+// MCPCredentialsListHandler handles GET /gen-ai/api/v1/mcp/credentials
+func (app *App) MCPCredentialsListHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	scope, ok := app.resolveMCPCredentialScope(w, r)
+	if !ok {
+		return
+	}
+
+	credentials, err := app.repositories.MCPCredentials.ListCredentials(r.Context(), scope.k8sClient, scope.identity, scope.namespace, scope.owner)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	response := MCPCredentialsEnvelope{
+		Data: credentials,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// MCPCredentialSetHandler handles PUT /gen-ai/api/v1/mcp/credentials/:server_name
+func (app *App) MCPCredentialSetHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	scope, ok := app.resolveMCPCredentialScope(w, r)
+	if !ok {
+		return
+	}
+
+	var setRequest SetMCPCredentialRequest
+	if err := json.NewDecoder(r.Body).Decode(&setRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	token := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(setRequest.Token), "Bearer "))
+	if token == "" {
+		app.badRequestResponse(w, r, errors.New("token is required"))
+		return
+	}
+
+	// Credentials can only be stored for servers that are configured for the dashboard
+	serverName := ps.ByName("server_name")
+	if _, err := app.findMCPServerConfigByName(ctx, scope.k8sClient, scope.identity, serverName); err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	credential, err := app.repositories.MCPCredentials.SetCredential(ctx, scope.k8sClient, scope.identity, scope.namespace, scope.owner, serverName, token)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	response := MCPCredentialEnvelope{
+		Data: credential,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// MCPCredentialDeleteHandler handles DELETE /gen-ai/api/v1/mcp/credentials/:server_name
+func (app *App) MCPCredentialDeleteHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	scope, ok := app.resolveMCPCredentialScope(w, r)
+	if !ok {
+		return
+	}
+
+	serverName := ps.ByName("server_name")
+	if err := app.repositories.MCPCredentials.DeleteCredential(r.Context(), scope.k8sClient, scope.identity, scope.namespace, scope.owner, serverName); err != nil {
+		app.handleMCPCredentialError(w, r, err)
+		return
+	}
+
+	response := Envelope[map[string]interface{}, None]{
+		Data: map[string]interface{}{
+			"server_name": serverName,
+			"deleted":     true,
+		},
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// MCPCredentialTestHandler handles POST /gen-ai/api/v1/mcp/credentials/:server_name/test
+// It checks the connection to the MCP server using the stored credential.
+func (app *App) MCPCredentialTestHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	scope, ok := app.resolveMCPCredentialScope(w, r)
+	if !ok {
+		return
+	}
+
+	serverName := ps.ByName("server_name")
+	serverConfig, err := app.findMCPServerConfigByName(ctx, scope.k8sClient, scope.identity, serverName)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	token, err := app.repositories.MCPCredentials.GetToken(ctx, scope.k8sClient, scope.identity, scope.namespace, scope.owner, serverName)
+	if err != nil {
+		app.handleMCPCredentialError(w, r, err)
+		return
+	}
+
+	// Use a copy so the stored token never leaks into the identity shared by the request context
+	testIdentity := *scope.identity
+	testIdentity.MCPToken = token
+
+	connectionStatus, err := app.repositories.MCPClient.CheckMCPServerStatus(ctx, &testIdentity, serverConfig)
+	if err != nil {
+		app.handleMCPClientError(w, r, err)
+		return
+	}
+
+	// The stored token was rejected, so offer to authorize again
+	if connectionStatus.Status == mcp.StatusAuthRequired {
+		app.prepareMCPAuthorization(r, scope.namespace, serverConfig, connectionStatus.Authorization)
+	}
+
+	response := MCPStatusEnvelope{
+		Data: connectionStatus,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// resolveMCPCredentialScope resolves the namespace, user and Kubernetes client for MCP credential endpoints.
+// It writes an error response and returns false when any of them is unavailable.
+func (app *App) resolveMCPCredentialScope(w http.ResponseWriter, r *http.Request) (*mcpCredentialScope, bool) {
+	ctx := r.Context()
+
+	identity, k8sClient, err := app.setupMCPEndpoint(ctx)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return nil, false
+	}
+
+	namespace, ok := ctx.Value(constants.NamespaceQueryParameterKey).(string)
+	if !ok || namespace == "" {
+		app.badRequestResponse(w, r, errors.New("missing required query parameter: namespace"))
+		return nil, false
+	}
+
+	owner, err := app.getRequestUsername(ctx)
+	if err != nil {
+		app.unauthorizedResponse(w, r, err)
+		return nil, false
+	}
+
+	return &mcpCredentialScope{
+		namespace: namespace,
+		owner:     owner,
+		identity:  identity,
+		k8sClient: k8sClient,
+	}, true
+}
+
+// handleMCPCredentialError maps MCP credential repository errors to HTTP responses
+func (app *App) handleMCPCredentialError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, repositories.ErrMCPCredentialNotFound) {
+		app.notFoundResponse(w, r)
+		return
+	}
+	app.serverErrorResponse(w, r, err)
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes/k8smocks"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp/mcpmocks"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPCredentialsHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockMCPFactory := mcpmocks.NewMockedMCPClientFactory(
+		config.EnvConfig{MockK8sClient: true},
+		logger,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testEnv := k8smocks.TestEnvInput{
+		Users:  k8smocks.DefaultTestUsers,
+		Logger: logger,
+		Ctx:    ctx,
+		Cancel: cancel,
+	}
+
+	testEnvironment, ctrlClient, err := k8smocks.SetupEnvTest(testEnv)
+	require.NoError(t, err)
+
+	mockK8sFactory, err := k8smocks.NewMockedKubernetesClientFactory(ctrlClient, testEnvironment, config.EnvConfig{
+		AuthMethod: "user_token",
+	}, logger)
+	require.NoError(t, err)
+
+	app := &App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: "user_token",
+		},
+		logger:                  logger,
+		repositories:            repositories.NewRepositoriesWithMCP(mockMCPFactory, logger),
+		kubernetesClientFactory: mockK8sFactory,
+		mcpClientFactory:        mockMCPFactory,
+	}
+
+	const namespace = "dora-namespace"
+
+	// Helper function to call a credential handler with identity and namespace in context
+	serve := func(handler httprouter.Handle, method, serverName string, body interface{}) *httptest.ResponseRecorder {
+		var payload io.Reader
+		if body != nil {
+			jsonData, err := json.Marshal(body)
+			require.NoError(t, err)
+			payload = bytes.NewBuffer(jsonData)
+		}
+
+		req, err := http.NewRequest(method, "/gen-ai/api/v1/mcp/credentials?namespace="+namespace, payload)
+		require.NoError(t, err)
+
+		reqCtx := context.WithValue(req.Context(), constants.RequestIdentityKey, &integrations.RequestIdentity{
+			Token: "FAKE_BEARER_TOKEN",
+		})
+		reqCtx = context.WithValue(reqCtx, constants.NamespaceQueryParameterKey, namespace)
+		req = req.WithContext(reqCtx)
+
+		rr := httptest.NewRecorder()
+		handler(rr, req, httprouter.Params{{Key: "server_name", Value: serverName}})
+		return rr
+	}
+
+	listCredentials := func(t *testing.T) MCPCredentialsEnvelope {
+		rr := serve(app.MCPCredentialsListHandler, http.MethodGet, "", nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response MCPCredentialsEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("should store, list, test and revoke a credential without returning the token", func(t *testing.T) {
+		assert.Empty(t, listCredentials(t).Data)
+
+		rr := serve(app.MCPCredentialSetHandler, http.MethodPut, "brave", SetMCPCredentialRequest{Token: "Bearer brave-secret-token"})
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.NotContains(t, rr.Body.String(), "brave-secret-token")
+
+		var setResponse MCPCredentialEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &setResponse))
+		require.NotNil(t, setResponse.Data)
+		assert.Equal(t, "brave", setResponse.Data.ServerName)
+		assert.Greater(t, setResponse.Data.UpdatedAt, int64(0))
+
+		credentials := listCredentials(t)
+		require.Len(t, credentials.Data, 1)
+		assert.Equal(t, "brave", credentials.Data[0].ServerName)
+
+		// The token is stored in a Secret in the user's namespace
+		k8sClient, err := app.kubernetesClientFactory.GetClient(ctx)
+		require.NoError(t, err)
+		token, err := app.repositories.MCPCredentials.GetToken(ctx, k8sClient, &integrations.RequestIdentity{Token: "FAKE_BEARER_TOKEN"}, namespace, "mockUser", "brave")
+		require.NoError(t, err)
+		assert.Equal(t, "brave-secret-token", token)
+
+		rr = serve(app.MCPCredentialTestHandler, http.MethodPost, "brave", nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var testResponse MCPStatusEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &testResponse))
+		require.NotNil(t, testResponse.Data)
+		assert.Equal(t, "http://localhost:9090/sse", testResponse.Data.ServerURL)
+		assert.NotContains(t, rr.Body.String(), "brave-secret-token")
+
+		rr = serve(app.MCPCredentialDeleteHandler, http.MethodDelete, "brave", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Empty(t, listCredentials(t).Data)
+
+		rr = serve(app.MCPCredentialDeleteHandler, http.MethodDelete, "brave", nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should return 404 when testing a server without a stored credential", func(t *testing.T) {
+		rr := serve(app.MCPCredentialTestHandler, http.MethodPost, "kubernetes", nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should return 404 for servers that are not configured", func(t *testing.T) {
+		rr := serve(app.MCPCredentialSetHandler, http.MethodPut, "not-a-server", SetMCPCredentialRequest{Token: "token"})
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should return 400 when the token is empty", func(t *testing.T) {
+		rr := serve(app.MCPCredentialSetHandler, http.MethodPut, "brave", SetMCPCredentialRequest{Token: "  "})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -2,15 +2,18 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/opendatahub-io/gen-ai/internal/constants"
 	"github.com/opendatahub-io/gen-ai/internal/integrations"
 	kubernetes "github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes"
 	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
 	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
 )
 
 // parseMCPEndpointParams extracts and validates query parameters common to MCP endpoints
@@ -100,6 +103,74 @@ func (app *App) findMCPServerConfig(
 	return models.MCPServerConfig{}, fmt.Errorf("MCP server not found for URL: %s", decodedURL)
 }
 
+// findMCPServerConfigByName looks up MCP server configuration by its ConfigMap key
+func (app *App) findMCPServerConfigByName(
+	ctx context.Context,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	serverName string,
+) (models.MCPServerConfig, error) {
+	servers, err := app.repositories.MCPClient.GetMCPServersFromConfig(
+		k8sClient,
+		ctx,
+		identity,
+		app.dashboardNamespace,
+		constants.MCPServerName,
+	)
+	if err != nil {
+		return models.MCPServerConfig{}, fmt.Errorf("failed to get MCP server configurations: %w", err)
+	}
+
+	for _, server := range servers {
+		if server.Name == serverName {
+			return server.Config, nil
+		}
+	}
+
+	return models.MCPServerConfig{}, fmt.Errorf("MCP server not found: %s", serverName)
+}
+
+// applyStoredMCPCredential sets the user's stored credential for an MCP server on the identity
+// when the request did not provide an MCP token. Expiring OAuth tokens are refreshed first.
+// Lookup failures are logged and the request continues unauthenticated.
+func (app *App) applyStoredMCPCredential(
+	ctx context.Context,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace string,
+	serverName string,
+) {
+	if identity == nil || identity.MCPToken != "" || app.repositories.MCPCredentials == nil {
+		return
+	}
+
+	owner, err := app.getRequestUsername(ctx)
+	if err != nil {
+		app.logger.Warn("Failed to resolve user for MCP credential lookup", "server_name", serverName, "error", err)
+		return
+	}
+
+	credential, err := app.repositories.MCPCredentials.GetCredential(ctx, k8sClient, identity, namespace, owner, serverName)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrMCPCredentialNotFound) {
+			app.logger.Warn("Failed to load stored MCP credential", "server_name", serverName, "error", err)
+		}
+		return
+	}
+
+	token := credential.Token
+	if credential.ExpiresWithin(time.Now(), constants.MCPOAuthRefreshLeeway) {
+		// An expired token would be rejected anyway; without one the server reports that authorization is required
+		token, err = app.refreshMCPCredential(ctx, k8sClient, identity, namespace, owner, serverName, credential)
+		if err != nil {
+			app.logger.Warn("Failed to refresh MCP OAuth token", "server_name", serverName, "error", err)
+			return
+		}
+	}
+
+	identity.MCPToken = token
+}
+
 // handleMCPClientError maps MCP client errors to appropriate HTTP status codes
 func (app *App) handleMCPClientError(w http.ResponseWriter, r *http.Request, err error) {
 	if nonSSEErr, ok := err.(*mcp.NonSSEResponseError); ok {
@@ -135,6 +206,10 @@ func (app *App) getDefaultStatusCodeForMCPError(errorCode string) int {
 		return http.StatusServiceUnavailable
 	case mcp.ErrCodeInvalidResponse:
 		return http.StatusBadGateway
+	case mcp.ErrCodeInvalidArguments:
+		return http.StatusBadRequest
+	case mcp.ErrCodeToolNotFound, mcp.ErrCodeResourceNotFound, mcp.ErrCodePromptNotFound:
+		return http.StatusNotFound
 	default:
 		return http.StatusInternalServerError
 	}
@@ -146,12 +221,18 @@ func (app *App) mapMCPErrorToHTTPError(mcpErr *mcp.MCPError, statusCode int) *in
 	var message string
 
 	switch statusCode {
+	case http.StatusBadRequest:
+		code = "bad_request"
+		message = mcpErr.Message
 	case http.StatusUnauthorized:
 		code = "unauthorized"
 		message = mcpErr.Message
 	case http.StatusForbidden:
 		code = "forbidden"
 		message = mcpErr.Message
+	case http.StatusNotFound:
+		code = "not_found"
+		message = mcpErr.Message
 	case http.StatusServiceUnavailable:
 		code = "service_unavailable"
 		message = mcpErr.Message
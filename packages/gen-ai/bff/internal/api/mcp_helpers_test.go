@@ -488,6 +488,10 @@ func TestGetDefaultStatusCodeForMCPError(t *testing.T) {
 		{mcp.ErrCodeTimeout, http.StatusServiceUnavailable},
 		{mcp.ErrCodeServerUnavailable, http.StatusServiceUnavailable},
 		{mcp.ErrCodeInvalidResponse, http.StatusBadGateway},
+		{mcp.ErrCodeInvalidArguments, http.StatusBadRequest},
+		{mcp.ErrCodeToolNotFound, http.StatusNotFound},
+		{mcp.ErrCodeResourceNotFound, http.StatusNotFound},
+		{mcp.ErrCodePromptNotFound, http.StatusNotFound},
 		{"UNKNOWN_ERROR", http.StatusInternalServerError},
 		{"", http.StatusInternalServerError},
 	}
@@ -558,6 +562,28 @@ func TestMapMCPErrorToHTTPError(t *testing.T) {
 			expectedStatusCode: http.StatusBadGateway,
 			expectedMessage:    "Invalid response from MCP server: Invalid server response",
 		},
+		{
+			name: "invalid arguments error",
+			mcpError: &mcp.MCPError{
+				Code:    mcp.ErrCodeInvalidArguments,
+				Message: "missing required property \"query\"",
+			},
+			statusCode:         http.StatusBadRequest,
+			expectedCode:       "bad_request",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedMessage:    "missing required property \"query\"",
+		},
+		{
+			name: "tool not found error",
+			mcpError: &mcp.MCPError{
+				Code:    mcp.ErrCodeToolNotFound,
+				Message: "tool \"search\" not found",
+			},
+			statusCode:         http.StatusNotFound,
+			expectedCode:       "not_found",
+			expectedStatusCode: http.StatusNotFound,
+			expectedMessage:    "tool \"search\" not found",
+		},
 		{
 			name: "internal server error",
 			mcpError: &mcp.MCPError{
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	kubernetes "github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+)
+
+// mcpOAuthPendingAuthorization is kept in the memory store between the login redirect and the callback
+type mcpOAuthPendingAuthorization struct {
+	ServerName    string
+	Resource      string
+	TokenEndpoint string
+	Client        mcp.OAuthClientCredentials
+	CodeVerifier  string
+	RedirectURI   string
+}
+
+// MCPOAuthCallbackHandler handles GET /gen-ai/api/v1/mcp/oauth/callback?namespace=<>&state=<>&code=<>
+// The authorization server redirects the user here after login; the tokens are stored as the user's MCP credential
+// and the browser is sent back to the UI with the outcome.
+func (app *App) MCPOAuthCallbackHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	scope, ok := app.resolveMCPCredentialScope(w, r)
+	if !ok {
+		return
+	}
+
+	serverName, err := app.completeMCPAuthorization(r, scope)
+	http.Redirect(w, r, app.mcpOAuthOutcomeURL(scope.namespace, serverName, err), http.StatusSeeOther)
+}
+
+// completeMCPAuthorization exchanges the code of the callback for tokens and stores them as the user's credential.
+// It returns the MCP server the authorization was for, when known, and an error that can be shown to the user.
+func (app *App) completeMCPAuthorization(r *http.Request, scope *mcpCredentialScope) (string, error) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	state := query.Get("state")
+	if state == "" {
+		return "", errors.New("state is required")
+	}
+
+	// States are bound to the user and namespace that started the flow and can only be used once
+	cached, found := app.memoryStore.Get(scope.namespace, scope.owner, constants.CacheMCPOAuthStatesCategory, state)
+	pending, isPending := cached.(*mcpOAuthPendingAuthorization)
+	if !found || !isPending {
+		return "", errors.New("unknown or expired authorization state")
+	}
+	if err := app.memoryStore.Delete(scope.namespace, scope.owner, constants.CacheMCPOAuthStatesCategory, state); err != nil {
+		app.logger.Warn("Failed to remove MCP OAuth state", "error", err)
+	}
+
+	if authErr := query.Get("error"); authErr != "" {
+		message := "authorization failed: " + authErr
+		if description := query.Get("error_description"); description != "" {
+			message += ": " + description
+		}
+		return pending.ServerName, errors.New(message)
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return pending.ServerName, errors.New("code is required")
+	}
+
+	token, err := app.mcpClientFactory.GetOAuthClient().ExchangeCode(ctx, pending.TokenEndpoint, pending.Client, code, pending.CodeVerifier, pending.RedirectURI, pending.Resource)
+	if err != nil {
+		return pending.ServerName, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	_, err = app.repositories.MCPCredentials.SetOAuthCredential(ctx, scope.k8sClient, scope.identity, scope.namespace, scope.owner, pending.ServerName,
+		oauthStoredCredential(token, "", pending.TokenEndpoint, pending.Client, pending.Resource))
+	if err != nil {
+		// Like server error responses, the cause is logged rather than shown
+		app.logger.Error("Failed to store MCP OAuth credential", "server_name", pending.ServerName, "error", err)
+		return pending.ServerName, errors.New("the server encountered a problem and could not store the credential")
+	}
+	return pending.ServerName, nil
+}
+
+// mcpOAuthOutcomeURL returns the UI page of the namespace that the callback redirects to. The outcome is passed in
+// the query: mcp_oauth_status is connected or failed, with the reason in mcp_oauth_error, and mcp_oauth_server
+// names the MCP server when known.
+func (app *App) mcpOAuthOutcomeURL(namespace, serverName string, err error) string {
+	base := app.config.MCPOAuthUIURL
+	if base == "" {
+		base = constants.MCPOAuthDefaultUIPath
+	}
+
+	outcome := url.Values{}
+	if serverName != "" {
+		outcome.Set("mcp_oauth_server", serverName)
+	}
+	if err != nil {
+		outcome.Set("mcp_oauth_status", "failed")
+		outcome.Set("mcp_oauth_error", err.Error())
+	} else {
+		outcome.Set("mcp_oauth_status", "connected")
+	}
+	return strings.TrimSuffix(base, "/") + "/" + url.PathEscape(namespace) + "?" + outcome.Encode()
+}
+
+// prepareMCPAuthorization starts the OAuth flow for an MCP server that reported auth_required and sets the login URL.
+// Failures are logged and leave the login URL empty, so the status is still returned.
+func (app *App) prepareMCPAuthorization(r *http.Request, namespace string, serverConfig models.MCPServerConfig, authorization *models.MCPAuthorization) {
+	if authorization == nil || app.mcpClientFactory == nil || app.memoryStore == nil {
+		return
+	}
+	if err := app.startMCPAuthorization(r, namespace, serverConfig, authorization); err != nil {
+		app.logger.Warn("Failed to start MCP OAuth authorization", "server_name", serverConfig.Name, "error", err)
+	}
+}
+
+// startMCPAuthorization discovers the authorization server, registers the BFF when needed and records the pending authorization
+func (app *App) startMCPAuthorization(r *http.Request, namespace string, serverConfig models.MCPServerConfig, authorization *models.MCPAuthorization) error {
+	ctx := r.Context()
+
+	owner, err := app.getRequestUsername(ctx)
+	if err != nil {
+		return err
+	}
+
+	oauthClient := app.mcpClientFactory.GetOAuthClient()
+	discovery, err := oauthClient.Discover(ctx, serverConfig.URL, authorization.ResourceMetadataURL)
+	if err != nil {
+		return err
+	}
+
+	redirectURI, err := app.mcpOAuthRedirectURI(namespace)
+	if err != nil {
+		return err
+	}
+	client, err := app.mcpOAuthClientCredentials(ctx, oauthClient, discovery, namespace, owner, serverConfig, redirectURI)
+	if err != nil {
+		return err
+	}
+
+	codeVerifier, err := mcp.NewPKCEVerifier()
+	if err != nil {
+		return err
+	}
+	state, err := mcp.NewOAuthState()
+	if err != nil {
+		return err
+	}
+
+	requestedScope := authorization.Scope
+	if requestedScope == "" {
+		requestedScope = strings.Join(discovery.Scopes, " ")
+	}
+
+	loginURL, err := oauthClient.AuthorizationURL(discovery.AuthorizationServer, mcp.AuthorizationRequest{
+		ClientID:      client.ClientID,
+		RedirectURI:   redirectURI,
+		State:         state,
+		CodeChallenge: mcp.PKCEChallenge(codeVerifier),
+		Scope:         requestedScope,
+		Resource:      discovery.Resource,
+	})
+	if err != nil {
+		return err
+	}
+
+	pending := &mcpOAuthPendingAuthorization{
+		ServerName:    serverConfig.Name,
+		Resource:      discovery.Resource,
+		TokenEndpoint: discovery.AuthorizationServer.TokenEndpoint,
+		Client:        *client,
+		CodeVerifier:  codeVerifier,
+		RedirectURI:   redirectURI,
+	}
+	if err := app.memoryStore.Set(namespace, owner, constants.CacheMCPOAuthStatesCategory, state, pending, constants.MCPOAuthStateTTL); err != nil {
+		return fmt.Errorf("failed to store authorization state: %w", err)
+	}
+
+	authorization.LoginURL = loginURL
+	return nil
+}
+
+// mcpOAuthClientCredentials returns the OAuth client to use with the MCP server's authorization server:
+// the client ID from the ConfigMap, a previously registered client, or a newly registered one.
+func (app *App) mcpOAuthClientCredentials(
+	ctx context.Context,
+	oauthClient *mcp.OAuthClient,
+	discovery *mcp.OAuthDiscovery,
+	namespace, owner string,
+	serverConfig models.MCPServerConfig,
+	redirectURI string,
+) (*mcp.OAuthClientCredentials, error) {
+	if serverConfig.OAuthClientID != "" {
+		return &mcp.OAuthClientCredentials{ClientID: serverConfig.OAuthClientID}, nil
+	}
+
+	// Registrations are tied to the redirect URI, which includes the namespace
+	cacheKey := serverConfig.Name + "|" + redirectURI
+	if cached, found := app.memoryStore.Get(namespace, owner, constants.CacheMCPOAuthClientsCategory, cacheKey); found {
+		if client, ok := cached.(*mcp.OAuthClientCredentials); ok {
+			return client, nil
+		}
+	}
+
+	registrationEndpoint := discovery.AuthorizationServer.RegistrationEndpoint
+	if registrationEndpoint == "" {
+		return nil, fmt.Errorf("authorization server does not support dynamic client registration; set oauth_client_id for MCP server %s", serverConfig.Name)
+	}
+
+	client, err := oauthClient.RegisterClient(ctx, registrationEndpoint, redirectURI, constants.MCPOAuthClientName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := app.memoryStore.Set(namespace, owner, constants.CacheMCPOAuthClientsCategory, cacheKey, client, constants.MCPOAuthClientTTL); err != nil {
+		app.logger.Warn("Failed to cache MCP OAuth client registration", "server_name", serverConfig.Name, "error", err)
+	}
+	return client, nil
+}
+
+// mcpOAuthRedirectURI returns the callback URL for the namespace. It has to be configured, since the host of a
+// request can be set by the client and the authorization code must only be sent to the BFF.
+func (app *App) mcpOAuthRedirectURI(namespace string) (string, error) {
+	if app.config.MCPOAuthRedirectURL == "" {
+		return "", errors.New("MCP OAuth redirect URL is not configured")
+	}
+	return app.config.MCPOAuthRedirectURL + "?namespace=" + url.QueryEscape(namespace), nil
+}
+
+// refreshMCPCredential exchanges the stored refresh token for a new access token and stores the result
+func (app *App) refreshMCPCredential(
+	ctx context.Context,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace, owner, serverName string,
+	credential *repositories.MCPStoredCredential,
+) (string, error) {
+	if credential.RefreshToken == "" || credential.TokenEndpoint == "" || app.mcpClientFactory == nil {
+		return "", errors.New("access token expired and cannot be refreshed")
+	}
+
+	client := mcp.OAuthClientCredentials{ClientID: credential.ClientID, ClientSecret: credential.ClientSecret}
+	token, err := app.mcpClientFactory.GetOAuthClient().RefreshToken(ctx, credential.TokenEndpoint, client, credential.RefreshToken, credential.Resource)
+	if err != nil {
+		return "", err
+	}
+
+	refreshed := oauthStoredCredential(token, credential.RefreshToken, credential.TokenEndpoint, client, credential.Resource)
+	if _, err := app.repositories.MCPCredentials.SetOAuthCredential(ctx, k8sClient, identity, namespace, owner, serverName, refreshed); err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// oauthStoredCredential converts a token response to a stored credential.
+// Authorization servers that do not rotate refresh tokens omit them, so the previous one is kept.
+func oauthStoredCredential(token *mcp.OAuthToken, previousRefreshToken, tokenEndpoint string, client mcp.OAuthClientCredentials, resource string) repositories.MCPStoredCredential {
+	credential := repositories.MCPStoredCredential{
+		Token:         token.AccessToken,
+		RefreshToken:  token.RefreshToken,
+		TokenEndpoint: tokenEndpoint,
+		ClientID:      client.ClientID,
+		ClientSecret:  client.ClientSecret,
+		Resource:      resource,
+	}
+	if credential.RefreshToken == "" {
+		credential.RefreshToken = previousRefreshToken
+	}
+	if token.ExpiresIn > 0 {
+		credential.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second).Unix()
+	}
+	return credential
+}
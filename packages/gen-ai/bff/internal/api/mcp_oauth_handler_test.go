@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/opendatahub-io/gen-ai/internal/cache"
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes/k8smocks"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp/mcpmocks"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/opendatahub-io/gen-ai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPOAuthHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockMCPFactory := mcpmocks.NewMockedMCPClientFactory(
+		config.EnvConfig{MockK8sClient: true},
+		logger,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testEnv := k8smocks.TestEnvInput{
+		Users:  k8smocks.DefaultTestUsers,
+		Logger: logger,
+		Ctx:    ctx,
+		Cancel: cancel,
+	}
+
+	testEnvironment, ctrlClient, err := k8smocks.SetupEnvTest(testEnv)
+	require.NoError(t, err)
+
+	mockK8sFactory, err := k8smocks.NewMockedKubernetesClientFactory(ctrlClient, testEnvironment, config.EnvConfig{
+		AuthMethod: "user_token",
+	}, logger)
+	require.NoError(t, err)
+
+	const (
+		namespace   = "dora-namespace"
+		redirectURL = "https://bff.example.com/gen-ai/api/v1/mcp/oauth/callback"
+	)
+
+	app := &App{
+		config: config.EnvConfig{
+			Port:                4000,
+			AuthMethod:          "user_token",
+			MCPOAuthRedirectURL: redirectURL,
+		},
+		logger:                  logger,
+		repositories:            repositories.NewRepositoriesWithMCP(mockMCPFactory, logger),
+		kubernetesClientFactory: mockK8sFactory,
+		mcpClientFactory:        mockMCPFactory,
+		memoryStore:             cache.NewMemoryStore(),
+	}
+
+	oauthServer := testutil.NewOAuthStubServer()
+	defer oauthServer.Close()
+	// Shorter than the refresh leeway, so stored tokens are refreshed on first use
+	oauthServer.AccessTokenLifetime = 30
+
+	serverConfig := models.MCPServerConfig{Name: "oauth-server", URL: oauthServer.MCPURL()}
+
+	newRequest := func(target string) *http.Request {
+		req, err := http.NewRequest(http.MethodGet, target, nil)
+		require.NoError(t, err)
+
+		reqCtx := context.WithValue(req.Context(), constants.RequestIdentityKey, &integrations.RequestIdentity{
+			Token: "FAKE_BEARER_TOKEN",
+		})
+		reqCtx = context.WithValue(reqCtx, constants.NamespaceQueryParameterKey, namespace)
+		return req.WithContext(reqCtx)
+	}
+
+	// startLogin prepares the authorization like the status endpoint does and returns the callback query
+	startLogin := func(t *testing.T) string {
+		authorization := &models.MCPAuthorization{
+			ResourceMetadataURL: oauthServer.URL + testutil.OAuthStubResourceMetadataPath,
+			Scope:               testutil.OAuthStubScope,
+		}
+		app.prepareMCPAuthorization(newRequest("/gen-ai/api/v1/mcp/status?namespace="+namespace), namespace, serverConfig, authorization)
+		require.NotEmpty(t, authorization.LoginURL)
+
+		callback, err := oauthServer.Authorize(authorization.LoginURL)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(callback.String(), redirectURL+"?"))
+		assert.Equal(t, namespace, callback.Query().Get("namespace"))
+		return callback.RawQuery
+	}
+
+	// callCallback calls the callback and returns the query of the UI page it redirects to
+	callCallback := func(t *testing.T, query string) url.Values {
+		rr := httptest.NewRecorder()
+		app.MCPOAuthCallbackHandler(rr, newRequest(constants.MCPOAuthCallbackPath+"?"+query), nil)
+		require.Equal(t, http.StatusSeeOther, rr.Code, rr.Body.String())
+
+		location, err := url.Parse(rr.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(t, constants.MCPOAuthDefaultUIPath+"/"+namespace, location.Path)
+		return location.Query()
+	}
+
+	t.Run("should store OAuth tokens from the callback", func(t *testing.T) {
+		query := startLogin(t)
+
+		outcome := callCallback(t, query)
+		assert.Equal(t, "connected", outcome.Get("mcp_oauth_status"), outcome.Get("mcp_oauth_error"))
+		assert.Equal(t, "oauth-server", outcome.Get("mcp_oauth_server"))
+
+		identity, k8sClient, err := app.setupMCPEndpoint(newRequest("/").Context())
+		require.NoError(t, err)
+		owner, err := app.getRequestUsername(newRequest("/").Context())
+		require.NoError(t, err)
+		credential, err := app.repositories.MCPCredentials.GetCredential(ctx, k8sClient, identity, namespace, owner, serverConfig.Name)
+		require.NoError(t, err)
+		assert.NotEmpty(t, credential.Token)
+		assert.NotZero(t, credential.ExpiresAt)
+
+		t.Run("should reject a reused state", func(t *testing.T) {
+			outcome := callCallback(t, query)
+			assert.Equal(t, "failed", outcome.Get("mcp_oauth_status"))
+			assert.Contains(t, outcome.Get("mcp_oauth_error"), "unknown or expired authorization state")
+		})
+	})
+
+	t.Run("should refresh expiring tokens before use", func(t *testing.T) {
+		identity, k8sClient, err := app.setupMCPEndpoint(newRequest("/").Context())
+		require.NoError(t, err)
+		owner, err := app.getRequestUsername(newRequest("/").Context())
+		require.NoError(t, err)
+
+		before, err := app.repositories.MCPCredentials.GetCredential(ctx, k8sClient, identity, namespace, owner, serverConfig.Name)
+		require.NoError(t, err)
+
+		app.applyStoredMCPCredential(ctx, k8sClient, identity, namespace, serverConfig.Name)
+		require.NotEmpty(t, identity.MCPToken)
+		assert.NotEqual(t, before.Token, identity.MCPToken)
+
+		after, err := app.repositories.MCPCredentials.GetCredential(ctx, k8sClient, identity, namespace, owner, serverConfig.Name)
+		require.NoError(t, err)
+		assert.Equal(t, identity.MCPToken, after.Token)
+		assert.NotEqual(t, before.RefreshToken, after.RefreshToken)
+	})
+
+	t.Run("should reject an unknown state", func(t *testing.T) {
+		outcome := callCallback(t, "namespace="+namespace+"&state=unknown&code=code")
+		assert.Equal(t, "failed", outcome.Get("mcp_oauth_status"))
+		assert.Empty(t, outcome.Get("mcp_oauth_server"))
+	})
+
+	t.Run("should report authorization errors from the authorization server", func(t *testing.T) {
+		query, err := url.ParseQuery(startLogin(t))
+		require.NoError(t, err)
+		query.Del("code")
+		query.Set("error", "access_denied")
+
+		outcome := callCallback(t, query.Encode())
+		assert.Equal(t, "failed", outcome.Get("mcp_oauth_status"))
+		assert.Contains(t, outcome.Get("mcp_oauth_error"), "access_denied")
+		assert.Equal(t, "oauth-server", outcome.Get("mcp_oauth_server"))
+	})
+
+	t.Run("should require a state", func(t *testing.T) {
+		outcome := callCallback(t, "namespace="+namespace+"&code=code")
+		assert.Equal(t, "failed", outcome.Get("mcp_oauth_status"))
+		assert.Contains(t, outcome.Get("mcp_oauth_error"), "state is required")
+	})
+
+	t.Run("should not start the flow without a configured redirect URL", func(t *testing.T) {
+		unconfigured := *app
+		unconfigured.config.MCPOAuthRedirectURL = ""
+		authorization := &models.MCPAuthorization{
+			ResourceMetadataURL: oauthServer.URL + testutil.OAuthStubResourceMetadataPath,
+			Scope:               testutil.OAuthStubScope,
+		}
+		req := newRequest("/gen-ai/api/v1/mcp/status?namespace=" + namespace)
+		req.Host = "attacker.example.com"
+		unconfigured.prepareMCPAuthorization(req, namespace, serverConfig, authorization)
+		assert.Empty(t, authorization.LoginURL)
+	})
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+)
+
+type MCPPromptsEnvelope = Envelope[*models.PromptsStatus, None]
+type MCPPromptGetEnvelope = Envelope[*models.PromptResult, None]
+
+// MCPPromptGetRequest represents the request body for rendering an MCP prompt
+type MCPPromptGetRequest struct {
+	ServerURL string            `json:"server_url"` // URL of an MCP server from the MCP servers ConfigMap
+	Name      string            `json:"name"`       // Name of the prompt as returned by the prompts endpoint
+	Arguments map[string]string `json:"arguments"`  // Prompt arguments; all required arguments must be set
+}
+
+// MCPPromptsHandler handles GET /gen-ai/api/v1/mcp/prompts?namespace=<>&server_url=<>
+func (app *App) MCPPromptsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	identity, k8sClient, err := app.setupMCPEndpointWithTokenValidation(ctx, r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	namespace, _, decodedURL, err := app.parseMCPEndpointParams(r, true)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	serverConfig, err := app.findMCPServerConfig(ctx, k8sClient, identity, decodedURL, app.dashboardNamespace)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	app.applyStoredMCPCredential(ctx, k8sClient, identity, namespace, serverConfig.Name)
+
+	promptsStatus, err := app.repositories.MCPClient.ListMCPServerPrompts(ctx, identity, serverConfig)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if promptsStatus.Status == mcp.StatusAuthRequired {
+		app.prepareMCPAuthorization(r, namespace, serverConfig, promptsStatus.Authorization)
+	}
+
+	response := MCPPromptsEnvelope{
+		Data: promptsStatus,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// MCPPromptGetHandler handles POST /gen-ai/api/v1/mcp/prompts/get?namespace=<>
+func (app *App) MCPPromptGetHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	identity, k8sClient, err := app.setupMCPEndpointWithTokenValidation(ctx, r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	namespace, _, _, err := app.parseMCPEndpointParams(r, false)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	var promptRequest MCPPromptGetRequest
+	if err := json.NewDecoder(r.Body).Decode(&promptRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	promptRequest.ServerURL = strings.TrimSpace(promptRequest.ServerURL)
+	promptRequest.Name = strings.TrimSpace(promptRequest.Name)
+	if promptRequest.ServerURL == "" {
+		app.badRequestResponse(w, r, errors.New("server_url is required"))
+		return
+	}
+	if promptRequest.Name == "" {
+		app.badRequestResponse(w, r, errors.New("name is required"))
+		return
+	}
+
+	serverConfig, err := app.findMCPServerConfig(ctx, k8sClient, identity, promptRequest.ServerURL, app.dashboardNamespace)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	app.applyStoredMCPCredential(ctx, k8sClient, identity, namespace, serverConfig.Name)
+
+	result, err := app.repositories.MCPClient.GetMCPServerPrompt(ctx, identity, serverConfig, promptRequest.Name, promptRequest.Arguments)
+	if err != nil {
+		app.handleMCPClientError(w, r, err)
+		return
+	}
+
+	response := MCPPromptGetEnvelope{
+		Data: result,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
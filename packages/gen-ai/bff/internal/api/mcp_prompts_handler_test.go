@@ -0,0 +1,134 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes/k8smocks"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp/mcpmocks"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPPromptsHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockMCPFactory := mcpmocks.NewMockedMCPClientFactory(
+		config.EnvConfig{MockK8sClient: true},
+		logger,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testEnv := k8smocks.TestEnvInput{
+		Users:  k8smocks.DefaultTestUsers,
+		Logger: logger,
+		Ctx:    ctx,
+		Cancel: cancel,
+	}
+
+	testEnvironment, ctrlClient, err := k8smocks.SetupEnvTest(testEnv)
+	require.NoError(t, err)
+
+	mockK8sFactory, err := k8smocks.NewMockedKubernetesClientFactory(ctrlClient, testEnvironment, config.EnvConfig{
+		AuthMethod: "user_token",
+	}, logger)
+	require.NoError(t, err)
+
+	app := &App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: "user_token",
+		},
+		logger:                  logger,
+		repositories:            repositories.NewRepositoriesWithMCP(mockMCPFactory, logger),
+		kubernetesClientFactory: mockK8sFactory,
+		mcpClientFactory:        mockMCPFactory,
+	}
+
+	withIdentity := func(req *http.Request) *http.Request {
+		return req.WithContext(context.WithValue(req.Context(), constants.RequestIdentityKey, &integrations.RequestIdentity{
+			Token: "FAKE_BEARER_TOKEN",
+		}))
+	}
+
+	// Helper function to call the prompt get handler with identity in context
+	getPrompt := func(t *testing.T, body interface{}) *httptest.ResponseRecorder {
+		jsonData, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, "/gen-ai/api/v1/mcp/prompts/get?namespace=demo", bytes.NewBuffer(jsonData))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		app.MCPPromptGetHandler(rr, withIdentity(req), nil)
+		return rr
+	}
+
+	t.Run("should list prompts with their arguments", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/gen-ai/api/v1/mcp/prompts?namespace=demo&server_url=http://localhost:9091/mcp", nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		app.MCPPromptsHandler(rr, withIdentity(req), nil)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var response MCPPromptsEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.NotNil(t, response.Data)
+		assert.Equal(t, "success", response.Data.Status)
+		require.Len(t, response.Data.Prompts, 1)
+		assert.Equal(t, "troubleshoot_pod", response.Data.Prompts[0].Name)
+		require.Len(t, response.Data.Prompts[0].Arguments, 2)
+		assert.True(t, response.Data.Prompts[0].Arguments[0].Required)
+	})
+
+	t.Run("should render a prompt", func(t *testing.T) {
+		rr := getPrompt(t, MCPPromptGetRequest{
+			ServerURL: "http://localhost:9091/mcp",
+			Name:      "troubleshoot_pod",
+			Arguments: map[string]string{"pod": "api-server"},
+		})
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var response MCPPromptGetEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.NotNil(t, response.Data)
+		require.Len(t, response.Data.Messages, 1)
+		assert.Equal(t, "user", response.Data.Messages[0].Role)
+		assert.Contains(t, response.Data.Messages[0].Content.Text, "api-server")
+	})
+
+	t.Run("should return 400 when a required argument is missing", func(t *testing.T) {
+		rr := getPrompt(t, MCPPromptGetRequest{
+			ServerURL: "http://localhost:9091/mcp",
+			Name:      "troubleshoot_pod",
+		})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "missing required argument")
+	})
+
+	t.Run("should return 404 for an unknown prompt", func(t *testing.T) {
+		rr := getPrompt(t, MCPPromptGetRequest{
+			ServerURL: "http://localhost:9091/mcp",
+			Name:      "unknown_prompt",
+		})
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should return 400 when name is missing", func(t *testing.T) {
+		rr := getPrompt(t, MCPPromptGetRequest{ServerURL: "http://localhost:9091/mcp"})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
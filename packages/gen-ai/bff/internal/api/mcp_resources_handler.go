@@ -0,0 +1,102 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+)
+
+type MCPResourcesEnvelope = Envelope[*models.ResourcesStatus, None]
+type MCPResourceReadEnvelope = Envelope[*models.ResourceReadResult, None]
+
+// MCPResourcesHandler handles GET /gen-ai/api/v1/mcp/resources?namespace=<>&server_url=<>
+func (app *App) MCPResourcesHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	identity, k8sClient, err := app.setupMCPEndpointWithTokenValidation(ctx, r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	namespace, _, decodedURL, err := app.parseMCPEndpointParams(r, true)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	serverConfig, err := app.findMCPServerConfig(ctx, k8sClient, identity, decodedURL, app.dashboardNamespace)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	app.applyStoredMCPCredential(ctx, k8sClient, identity, namespace, serverConfig.Name)
+
+	resourcesStatus, err := app.repositories.MCPClient.ListMCPServerResources(ctx, identity, serverConfig)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if resourcesStatus.Status == mcp.StatusAuthRequired {
+		app.prepareMCPAuthorization(r, namespace, serverConfig, resourcesStatus.Authorization)
+	}
+
+	response := MCPResourcesEnvelope{
+		Data: resourcesStatus,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// MCPResourceReadHandler handles GET /gen-ai/api/v1/mcp/resources/read?namespace=<>&server_url=<>&uri=<>
+func (app *App) MCPResourceReadHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	identity, k8sClient, err := app.setupMCPEndpointWithTokenValidation(ctx, r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	namespace, _, decodedURL, err := app.parseMCPEndpointParams(r, true)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	uri := strings.TrimSpace(r.URL.Query().Get("uri"))
+	if uri == "" {
+		app.badRequestResponse(w, r, errors.New("uri parameter is required"))
+		return
+	}
+
+	serverConfig, err := app.findMCPServerConfig(ctx, k8sClient, identity, decodedURL, app.dashboardNamespace)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	app.applyStoredMCPCredential(ctx, k8sClient, identity, namespace, serverConfig.Name)
+
+	result, err := app.repositories.MCPClient.ReadMCPServerResource(ctx, identity, serverConfig, uri)
+	if err != nil {
+		app.handleMCPClientError(w, r, err)
+		return
+	}
+
+	response := MCPResourceReadEnvelope{
+		Data: result,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes/k8smocks"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp/mcpmocks"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPResourcesHandlers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockMCPFactory := mcpmocks.NewMockedMCPClientFactory(
+		config.EnvConfig{MockK8sClient: true},
+		logger,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testEnv := k8smocks.TestEnvInput{
+		Users:  k8smocks.DefaultTestUsers,
+		Logger: logger,
+		Ctx:    ctx,
+		Cancel: cancel,
+	}
+
+	testEnvironment, ctrlClient, err := k8smocks.SetupEnvTest(testEnv)
+	require.NoError(t, err)
+
+	mockK8sFactory, err := k8smocks.NewMockedKubernetesClientFactory(ctrlClient, testEnvironment, config.EnvConfig{
+		AuthMethod: "user_token",
+	}, logger)
+	require.NoError(t, err)
+
+	app := &App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: "user_token",
+		},
+		logger:                  logger,
+		repositories:            repositories.NewRepositoriesWithMCP(mockMCPFactory, logger),
+		kubernetesClientFactory: mockK8sFactory,
+		mcpClientFactory:        mockMCPFactory,
+	}
+
+	// Helper function to call a resources handler with identity in context
+	get := func(t *testing.T, handler httprouter.Handle, path string, query url.Values) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, path+"?"+query.Encode(), nil)
+		require.NoError(t, err)
+
+		reqCtx := context.WithValue(req.Context(), constants.RequestIdentityKey, &integrations.RequestIdentity{
+			Token: "FAKE_BEARER_TOKEN",
+		})
+		req = req.WithContext(reqCtx)
+
+		rr := httptest.NewRecorder()
+		handler(rr, req, nil)
+		return rr
+	}
+
+	t.Run("should list resources and resource templates", func(t *testing.T) {
+		rr := get(t, app.MCPResourcesHandler, "/gen-ai/api/v1/mcp/resources", url.Values{
+			"namespace":  {"demo"},
+			"server_url": {"http://localhost:9091/mcp"},
+		})
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var response MCPResourcesEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.NotNil(t, response.Data)
+		assert.Equal(t, "success", response.Data.Status)
+		assert.Equal(t, "kubernetes-mcp-server", response.Data.ServerInfo.Name)
+		require.NotNil(t, response.Data.ResourcesCount)
+		assert.Equal(t, 2, *response.Data.ResourcesCount)
+		require.Len(t, response.Data.ResourceTemplates, 1)
+		assert.Equal(t, "k8s://namespaces/{namespace}/pods", response.Data.ResourceTemplates[0].URITemplate)
+	})
+
+	t.Run("should return an empty list for servers without resources", func(t *testing.T) {
+		rr := get(t, app.MCPResourcesHandler, "/gen-ai/api/v1/mcp/resources", url.Values{
+			"namespace":  {"demo"},
+			"server_url": {"http://localhost:9090/sse"},
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response MCPResourcesEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, "success", response.Data.Status)
+		assert.NotNil(t, response.Data.Resources)
+		assert.Empty(t, response.Data.Resources)
+	})
+
+	t.Run("should report connection errors in the status", func(t *testing.T) {
+		rr := get(t, app.MCPResourcesHandler, "/gen-ai/api/v1/mcp/resources", url.Values{
+			"namespace":  {"demo"},
+			"server_url": {"https://mcp-unavailable:8080/sse"},
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response MCPResourcesEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, "error", response.Data.Status)
+		require.NotNil(t, response.Data.ErrorDetails)
+		assert.Equal(t, "connection_error", response.Data.ErrorDetails.Code)
+	})
+
+	t.Run("should read a resource", func(t *testing.T) {
+		rr := get(t, app.MCPResourceReadHandler, "/gen-ai/api/v1/mcp/resources/read", url.Values{
+			"namespace":  {"demo"},
+			"server_url": {"http://localhost:9091/mcp"},
+			"uri":        {"k8s://cluster/info"},
+		})
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var response MCPResourceReadEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.NotNil(t, response.Data)
+		assert.Equal(t, "k8s://cluster/info", response.Data.URI)
+		require.Len(t, response.Data.Contents, 1)
+		assert.Equal(t, "application/json", response.Data.Contents[0].MimeType)
+	})
+
+	t.Run("should return 404 for an unknown resource", func(t *testing.T) {
+		rr := get(t, app.MCPResourceReadHandler, "/gen-ai/api/v1/mcp/resources/read", url.Values{
+			"namespace":  {"demo"},
+			"server_url": {"http://localhost:9091/mcp"},
+			"uri":        {"k8s://missing"},
+		})
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should return 400 when uri is missing", func(t *testing.T) {
+		rr := get(t, app.MCPResourceReadHandler, "/gen-ai/api/v1/mcp/resources/read", url.Values{
+			"namespace":  {"demo"},
+			"server_url": {"http://localhost:9091/mcp"},
+		})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should return 404 for a server that is not configured", func(t *testing.T) {
+		rr := get(t, app.MCPResourcesHandler, "/gen-ai/api/v1/mcp/resources", url.Values{
+			"namespace":  {"demo"},
+			"server_url": {"https://nonexistent-server.com/mcp"},
+		})
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
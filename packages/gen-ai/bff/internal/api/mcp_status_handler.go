@@ -1,16 +1,64 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	kubernetes "github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
 	"github.com/opendatahub-io/gen-ai/internal/models"
 )
 
 type MCPStatusEnvelope = Envelope[*models.ConnectionStatus, None]
 
-// MCPStatusHandler handles GET /genai/v1/mcp/status?namespace=<>&server_url=<>
+// MCPServerStatus is the connection status of one server of the MCP ConfigMap
+type MCPServerStatus struct {
+	Name string `json:"name"` // ConfigMap key of the server
+	models.ConnectionStatus
+}
+
+// MCPStatusListData is the connection status of every server of the MCP ConfigMap.
+// Servers that fail or time out are reported with status "error" instead of failing the request.
+type MCPStatusListData struct {
+	Servers []MCPServerStatus `json:"servers"`
+	MCPStatusTotals
+}
+
+// MCPStatusTotals counts the servers of an aggregated status check by status
+type MCPStatusTotals struct {
+	TotalCount        int `json:"total_count"`
+	ConnectedCount    int `json:"connected_count"`
+	ErrorCount        int `json:"error_count"`
+	AuthRequiredCount int `json:"auth_required_count"`
+}
+
+type MCPStatusListEnvelope = Envelope[*MCPStatusListData, None]
+
+// SSE event names of the streamed aggregated status
+const (
+	mcpStatusEventStatus = "status"
+	mcpStatusEventDone   = "done"
+)
+
+// MCPStatusHandler handles GET /genai/v1/mcp/status?namespace=<>&server_url=<>.
+// With all=true it checks every server of the MCP ConfigMap instead; see MCPStatusAllHandler.
 func (app *App) MCPStatusHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	if r.URL.Query().Get("all") == "true" {
+		app.MCPStatusAllHandler(w, r, ps)
+		return
+	}
+
 	ctx := r.Context()
 
 	identity, k8sClient, err := app.setupMCPEndpointWithTokenValidation(ctx, r)
@@ -19,7 +67,7 @@ func (app *App) MCPStatusHandler(w http.ResponseWriter, r *http.Request, ps http
 		return
 	}
 
-	_, _, decodedURL, err := app.parseMCPEndpointParams(r, true)
+	namespace, _, decodedURL, err := app.parseMCPEndpointParams(r, true)
 	if err != nil {
 		app.badRequestResponse(w, r, err)
 		return
@@ -31,12 +79,18 @@ func (app *App) MCPStatusHandler(w http.ResponseWriter, r *http.Request, ps http
 		return
 	}
 
+	app.applyStoredMCPCredential(ctx, k8sClient, identity, namespace, serverConfig.Name)
+
 	connectionStatus, err := app.repositories.MCPClient.CheckMCPServerStatus(ctx, identity, serverConfig)
 	if err != nil {
 		app.handleMCPClientError(w, r, err)
 		return
 	}
 
+	if connectionStatus.Status == mcp.StatusAuthRequired {
+		app.prepareMCPAuthorization(r, namespace, serverConfig, connectionStatus.Authorization)
+	}
+
 	response := MCPStatusEnvelope{
 		Data: connectionStatus,
 	}
@@ -46,3 +100,241 @@ func (app *App) MCPStatusHandler(w http.ResponseWriter, r *http.Request, ps http
 		return
 	}
 }
+
+// MCPStatusAllHandler handles GET /genai/v1/mcp/status?namespace=<>&all=true.
+// It checks all servers of the MCP ConfigMap concurrently and returns their statuses in one envelope,
+// or streams them as server-sent events as they complete when the client accepts text/event-stream or sets stream=true.
+func (app *App) MCPStatusAllHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	identity, k8sClient, err := app.setupMCPEndpointWithTokenValidation(ctx, r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	namespace, _, _, err := app.parseMCPEndpointParams(r, false)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	result, err := app.repositories.MCPClient.GetMCPServersFromConfigWithMetadata(
+		k8sClient,
+		ctx,
+		identity,
+		app.dashboardNamespace,
+		constants.MCPServerName,
+	)
+	if err != nil {
+		app.handleConfigMapError(w, r, err, constants.MCPServerName, app.dashboardNamespace)
+		return
+	}
+
+	servers := make([]models.MCPServerConfig, 0, len(result.Servers))
+	for _, server := range result.Servers {
+		servers = append(servers, server.Config)
+	}
+
+	if wantsMCPStatusStream(r) {
+		app.streamMCPServerStatuses(w, r, k8sClient, identity, namespace, servers)
+		return
+	}
+
+	data := &MCPStatusListData{Servers: make([]MCPServerStatus, 0, len(servers))}
+	for status := range app.checkMCPServerStatuses(r, k8sClient, identity, namespace, servers) {
+		data.add(status)
+	}
+	sort.Slice(data.Servers, func(i, j int) bool { return data.Servers[i].Name < data.Servers[j].Name })
+
+	response := MCPStatusListEnvelope{
+		Data: data,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// streamMCPServerStatuses writes one status event per server as its check completes, followed by a done event with the totals
+func (app *App) streamMCPServerStatuses(
+	w http.ResponseWriter,
+	r *http.Request,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace string,
+	servers []models.MCPServerConfig,
+) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported by client", http.StatusNotImplemented)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache, no-transform")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var totals MCPStatusTotals
+	for status := range app.checkMCPServerStatuses(r, k8sClient, identity, namespace, servers) {
+		totals.count(status.Status)
+		if err := writeMCPStatusEvent(w, mcpStatusEventStatus, status); err != nil {
+			app.logger.Error("Failed to write MCP status event", "error", err, "server_name", status.Name)
+			return
+		}
+		flusher.Flush()
+	}
+
+	if err := writeMCPStatusEvent(w, mcpStatusEventDone, totals); err != nil {
+		app.logger.Error("Failed to write MCP status event", "error", err)
+		return
+	}
+	flusher.Flush()
+}
+
+// checkMCPServerStatuses checks the servers with a bounded number of concurrent checks and sends each status
+// on the returned channel as soon as it is known. The channel is closed once every server has been reported.
+func (app *App) checkMCPServerStatuses(
+	r *http.Request,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace string,
+	servers []models.MCPServerConfig,
+) <-chan MCPServerStatus {
+	results := make(chan MCPServerStatus, len(servers))
+	slots := make(chan struct{}, constants.MCPStatusMaxConcurrency)
+
+	var wg sync.WaitGroup
+	for _, serverConfig := range servers {
+		wg.Add(1)
+		go func(serverConfig models.MCPServerConfig) {
+			defer wg.Done()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-r.Context().Done():
+				results <- MCPServerStatus{Name: serverConfig.Name, ConnectionStatus: *mcpStatusCheckFailure(serverConfig, r.Context().Err())}
+				return
+			}
+
+			results <- app.checkMCPServerStatus(r, k8sClient, identity, namespace, serverConfig)
+		}(serverConfig)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// checkMCPServerStatus checks one server within the per-server timeout. A check that does not finish in time
+// is reported as a timeout error and its context is cancelled.
+func (app *App) checkMCPServerStatus(
+	r *http.Request,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace string,
+	serverConfig models.MCPServerConfig,
+) MCPServerStatus {
+	ctx, cancel := context.WithTimeout(r.Context(), constants.MCPStatusServerTimeout)
+	defer cancel()
+
+	type checkResult struct {
+		status *models.ConnectionStatus
+		err    error
+	}
+	done := make(chan checkResult, 1)
+
+	go func() {
+		// Stored credentials are per server, so each check gets its own copy of the identity
+		serverIdentity := *identity
+		app.applyStoredMCPCredential(ctx, k8sClient, &serverIdentity, namespace, serverConfig.Name)
+
+		status, err := app.repositories.MCPClient.CheckMCPServerStatus(ctx, &serverIdentity, serverConfig)
+		done <- checkResult{status: status, err: err}
+	}()
+
+	var status *models.ConnectionStatus
+	select {
+	case result := <-done:
+		status = result.status
+		if result.err != nil {
+			app.logger.Warn("MCP server status check failed", "server_name", serverConfig.Name, "error", result.err)
+			status = mcpStatusCheckFailure(serverConfig, result.err)
+		}
+	case <-ctx.Done():
+		app.logger.Warn("MCP server status check timed out", "server_name", serverConfig.Name, "timeout", constants.MCPStatusServerTimeout)
+		status = mcpStatusCheckFailure(serverConfig, ctx.Err())
+	}
+
+	if status.Status == mcp.StatusAuthRequired {
+		app.prepareMCPAuthorization(r, namespace, serverConfig, status.Authorization)
+	}
+
+	return MCPServerStatus{Name: serverConfig.Name, ConnectionStatus: *status}
+}
+
+// mcpStatusCheckFailure reports a server whose status check did not produce a status
+func mcpStatusCheckFailure(serverConfig models.MCPServerConfig, err error) *models.ConnectionStatus {
+	status := &models.ConnectionStatus{
+		ServerURL:   serverConfig.URL,
+		Status:      "error",
+		Message:     "MCP server status check failed",
+		LastChecked: time.Now().Unix(),
+		ErrorDetails: &models.ErrorDetails{
+			Code:       "connection_error",
+			StatusCode: http.StatusServiceUnavailable,
+			RawError:   err.Error(),
+		},
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		status.Message = "MCP server status check timed out"
+		status.ErrorDetails.Code = "timeout"
+		status.ErrorDetails.StatusCode = http.StatusGatewayTimeout
+	}
+	status.ServerInfo.Name = serverConfig.Name
+	status.ServerInfo.Version = "N/A"
+	return status
+}
+
+// add records the status of a server and updates the totals
+func (d *MCPStatusListData) add(status MCPServerStatus) {
+	d.Servers = append(d.Servers, status)
+	d.count(status.Status)
+}
+
+// count adds a server with the given status to the totals
+func (t *MCPStatusTotals) count(status string) {
+	t.TotalCount++
+	switch status {
+	case "connected":
+		t.ConnectedCount++
+	case mcp.StatusAuthRequired:
+		t.AuthRequiredCount++
+	default:
+		t.ErrorCount++
+	}
+}
+
+// wantsMCPStatusStream reports whether the client asked for the statuses as server-sent events
+func wantsMCPStatusStream(r *http.Request) bool {
+	return r.URL.Query().Get("stream") == "true" || strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+func writeMCPStatusEvent(w io.Writer, event string, data any) error {
+	eventData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal MCP status event: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, eventData); err != nil {
+		return fmt.Errorf("failed to write MCP status event: %w", err)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/opendatahub-io/gen-ai/internal/config"
@@ -336,4 +337,97 @@ func TestMCPStatusHandler(t *testing.T) {
 		assert.Equal(t, statusResponse.Data.ServerInfo.ProtocolVersion, toolsResponse.Data.ServerInfo.ProtocolVersion)
 		assert.Equal(t, statusResponse.Data.ServerURL, toolsResponse.Data.ServerURL)
 	})
+
+	t.Run("should return the status of all servers when all is true", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		req, err := http.NewRequest("GET", "/genai/v1/mcp/status?namespace=demo&all=true", nil)
+		require.NoError(t, err)
+		ctx := context.WithValue(req.Context(), constants.RequestIdentityKey, &integrations.RequestIdentity{
+			Token: "FAKE_BEARER_TOKEN",
+		})
+		req = req.WithContext(ctx)
+
+		app.MCPStatusHandler(rr, req, nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var response MCPStatusListEnvelope
+		err = json.Unmarshal(rr.Body.Bytes(), &response)
+		require.NoError(t, err)
+
+		require.NotNil(t, response.Data)
+		assert.Equal(t, 7, response.Data.TotalCount)
+		assert.Equal(t, 5, response.Data.ConnectedCount)
+		assert.Equal(t, 2, response.Data.ErrorCount)
+		assert.Equal(t, 0, response.Data.AuthRequiredCount)
+		require.Len(t, response.Data.Servers, 7)
+
+		statuses := make(map[string]MCPServerStatus)
+		for _, server := range response.Data.Servers {
+			statuses[server.Name] = server
+		}
+		assert.Equal(t, "connected", statuses["brave"].Status)
+		assert.Equal(t, "http://localhost:9090/sse", statuses["brave"].ServerURL)
+		assert.Equal(t, "error", statuses["unavailable-server"].Status)
+		require.NotNil(t, statuses["unavailable-server"].ErrorDetails)
+		assert.Equal(t, "connection_error", statuses["unavailable-server"].ErrorDetails.Code)
+		assert.Equal(t, "error", statuses["error-server"].Status)
+	})
+
+	t.Run("should stream the status of all servers as server-sent events", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		req, err := http.NewRequest("GET", "/genai/v1/mcp/status?namespace=demo&all=true", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "text/event-stream")
+		ctx := context.WithValue(req.Context(), constants.RequestIdentityKey, &integrations.RequestIdentity{
+			Token: "FAKE_BEARER_TOKEN",
+		})
+		req = req.WithContext(ctx)
+
+		app.MCPStatusHandler(rr, req, nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/event-stream; charset=utf-8", rr.Header().Get("Content-Type"))
+
+		var events []string
+		var totals MCPStatusTotals
+		for _, block := range strings.Split(strings.TrimSpace(rr.Body.String()), "\n\n") {
+			lines := strings.SplitN(block, "\n", 2)
+			require.Len(t, lines, 2)
+			event := strings.TrimPrefix(lines[0], "event: ")
+			data := strings.TrimPrefix(lines[1], "data: ")
+			events = append(events, event)
+
+			switch event {
+			case "status":
+				var status MCPServerStatus
+				require.NoError(t, json.Unmarshal([]byte(data), &status))
+				assert.NotEmpty(t, status.Name)
+				assert.NotEmpty(t, status.Status)
+			case "done":
+				require.NoError(t, json.Unmarshal([]byte(data), &totals))
+			}
+		}
+
+		require.Len(t, events, 8)
+		assert.Equal(t, "done", events[7])
+		assert.Equal(t, 7, totals.TotalCount)
+		assert.Equal(t, 5, totals.ConnectedCount)
+		assert.Equal(t, 2, totals.ErrorCount)
+	})
+
+	t.Run("should return 400 when namespace parameter is missing with all", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		req, err := http.NewRequest("GET", "/genai/v1/mcp/status?all=true", nil)
+		require.NoError(t, err)
+		ctx := context.WithValue(req.Context(), constants.RequestIdentityKey, &integrations.RequestIdentity{
+			Token: "FAKE_BEARER_TOKEN",
+		})
+		req = req.WithContext(ctx)
+
+		app.MCPStatusHandler(rr, req, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
 }
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+)
+
+type MCPToolCallEnvelope = Envelope[*models.ToolCallResult, None]
+
+// MCPToolCallRequest represents the request body for invoking an MCP tool directly
+type MCPToolCallRequest struct {
+	ServerURL string                 `json:"server_url"` // URL of an MCP server from the MCP servers ConfigMap
+	ToolName  string                 `json:"tool_name"`  // Name of the tool as returned by the tools endpoint
+	Arguments map[string]interface{} `json:"arguments"`  // Tool arguments, validated against the tool's input schema
+}
+
+// MCPToolCallHandler handles POST /gen-ai/api/v1/mcp/tools/call?namespace=<>
+// It calls a tool without a model response, so tools can be tried out from the playground.
+func (app *App) MCPToolCallHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	identity, k8sClient, err := app.setupMCPEndpointWithTokenValidation(ctx, r)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	namespace, _, _, err := app.parseMCPEndpointParams(r, false)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	var callRequest MCPToolCallRequest
+	if err := json.NewDecoder(r.Body).Decode(&callRequest); err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	callRequest.ServerURL = strings.TrimSpace(callRequest.ServerURL)
+	callRequest.ToolName = strings.TrimSpace(callRequest.ToolName)
+	if callRequest.ServerURL == "" {
+		app.badRequestResponse(w, r, errors.New("server_url is required"))
+		return
+	}
+	if callRequest.ToolName == "" {
+		app.badRequestResponse(w, r, errors.New("tool_name is required"))
+		return
+	}
+
+	serverConfig, err := app.findMCPServerConfig(ctx, k8sClient, identity, callRequest.ServerURL, app.dashboardNamespace)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	app.applyStoredMCPCredential(ctx, k8sClient, identity, namespace, serverConfig.Name)
+
+	result, err := app.repositories.MCPClient.CallMCPServerTool(ctx, identity, serverConfig, callRequest.ToolName, callRequest.Arguments)
+	if err != nil {
+		app.handleMCPClientError(w, r, err)
+		return
+	}
+
+	if result.Status == mcp.StatusAuthRequired {
+		app.prepareMCPAuthorization(r, namespace, serverConfig, result.Authorization)
+	}
+
+	response := MCPToolCallEnvelope{
+		Data: result,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
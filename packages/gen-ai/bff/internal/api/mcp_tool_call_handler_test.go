@@ -0,0 +1,149 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes/k8smocks"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp/mcpmocks"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMCPToolCallHandler(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockMCPFactory := mcpmocks.NewMockedMCPClientFactory(
+		config.EnvConfig{MockK8sClient: true},
+		logger,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testEnv := k8smocks.TestEnvInput{
+		Users:  k8smocks.DefaultTestUsers,
+		Logger: logger,
+		Ctx:    ctx,
+		Cancel: cancel,
+	}
+
+	testEnvironment, ctrlClient, err := k8smocks.SetupEnvTest(testEnv)
+	require.NoError(t, err)
+
+	mockK8sFactory, err := k8smocks.NewMockedKubernetesClientFactory(ctrlClient, testEnvironment, config.EnvConfig{
+		AuthMethod: "user_token",
+	}, logger)
+	require.NoError(t, err)
+
+	app := &App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: "user_token",
+		},
+		logger:                  logger,
+		repositories:            repositories.NewRepositoriesWithMCP(mockMCPFactory, logger),
+		kubernetesClientFactory: mockK8sFactory,
+		mcpClientFactory:        mockMCPFactory,
+	}
+
+	// Helper function to call the tool call handler with identity in context
+	callTool := func(t *testing.T, body interface{}) *httptest.ResponseRecorder {
+		jsonData, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPost, "/gen-ai/api/v1/mcp/tools/call?namespace=demo", bytes.NewBuffer(jsonData))
+		require.NoError(t, err)
+
+		reqCtx := context.WithValue(req.Context(), constants.RequestIdentityKey, &integrations.RequestIdentity{
+			Token: "FAKE_BEARER_TOKEN",
+		})
+		req = req.WithContext(reqCtx)
+
+		rr := httptest.NewRecorder()
+		app.MCPToolCallHandler(rr, req, nil)
+		return rr
+	}
+
+	t.Run("should call a tool and return its content and timing", func(t *testing.T) {
+		rr := callTool(t, MCPToolCallRequest{
+			ServerURL: "http://localhost:9090/sse",
+			ToolName:  "brave_web_search",
+			Arguments: map[string]interface{}{"query": "open data hub", "count": 3},
+		})
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var response MCPToolCallEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		require.NotNil(t, response.Data)
+		assert.Equal(t, "success", response.Data.Status)
+		assert.Equal(t, "brave_web_search", response.Data.ToolName)
+		assert.False(t, response.Data.IsError)
+		require.Len(t, response.Data.Content, 1)
+		assert.Equal(t, "text", response.Data.Content[0].Type)
+		assert.Contains(t, response.Data.Content[0].Text, "open data hub")
+		assert.Greater(t, response.Data.Timing.TotalMs, int64(0))
+	})
+
+	t.Run("should return 400 when arguments do not match the input schema", func(t *testing.T) {
+		rr := callTool(t, MCPToolCallRequest{
+			ServerURL: "http://localhost:9090/sse",
+			ToolName:  "brave_web_search",
+			Arguments: map[string]interface{}{"count": "three"},
+		})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Contains(t, rr.Body.String(), "missing required property")
+		assert.Contains(t, rr.Body.String(), "arguments.count must be of type number")
+	})
+
+	t.Run("should return 404 for an unknown tool", func(t *testing.T) {
+		rr := callTool(t, MCPToolCallRequest{
+			ServerURL: "http://localhost:9090/sse",
+			ToolName:  "unknown_tool",
+		})
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should return 404 for a server that is not configured", func(t *testing.T) {
+		rr := callTool(t, MCPToolCallRequest{
+			ServerURL: "https://nonexistent-server.com/mcp",
+			ToolName:  "brave_web_search",
+		})
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("should report connection errors in the result", func(t *testing.T) {
+		rr := callTool(t, MCPToolCallRequest{
+			ServerURL: "https://mcp-unavailable:8080/sse",
+			ToolName:  "brave_web_search",
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response MCPToolCallEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, "error", response.Data.Status)
+		require.NotNil(t, response.Data.ErrorDetails)
+		assert.Equal(t, "connection_error", response.Data.ErrorDetails.Code)
+		assert.Empty(t, response.Data.Content)
+	})
+
+	t.Run("should return 400 when tool_name is missing", func(t *testing.T) {
+		rr := callTool(t, MCPToolCallRequest{ServerURL: "http://localhost:9090/sse"})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should return 400 when server_url is missing", func(t *testing.T) {
+		rr := callTool(t, MCPToolCallRequest{ToolName: "brave_web_search"})
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
 	"github.com/opendatahub-io/gen-ai/internal/models"
 )
 
@@ -19,7 +20,7 @@ func (app *App) MCPToolsHandler(w http.ResponseWriter, r *http.Request, ps httpr
 		return
 	}
 
-	_, _, decodedURL, err := app.parseMCPEndpointParams(r, true)
+	namespace, _, decodedURL, err := app.parseMCPEndpointParams(r, true)
 	if err != nil {
 		app.badRequestResponse(w, r, err)
 		return
@@ -31,12 +32,18 @@ func (app *App) MCPToolsHandler(w http.ResponseWriter, r *http.Request, ps httpr
 		return
 	}
 
+	app.applyStoredMCPCredential(ctx, k8sClient, identity, namespace, serverConfig.Name)
+
 	toolsStatus, err := app.repositories.MCPClient.ListMCPServerToolsWithStatus(ctx, identity, serverConfig)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
+	if toolsStatus.Status == mcp.StatusAuthRequired {
+		app.prepareMCPAuthorization(r, namespace, serverConfig, toolsStatus.Authorization)
+	}
+
 	response := MCPToolsEnvelope{
 		Data: toolsStatus,
 	}
@@ -270,6 +270,17 @@ func (app *App) AttachLlamaStackClient(next func(http.ResponseWriter, *http.Requ
 	}
 }
 
+// MigrateVectorStores middleware records the users of the stores of the removed auto-provisioning as their owners
+// on the first vector store request of the namespace. It requires AttachNamespace and AttachLlamaStackClient.
+func (app *App) MigrateVectorStores(next func(http.ResponseWriter, *http.Request, httprouter.Params)) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if namespace, ok := r.Context().Value(constants.NamespaceQueryParameterKey).(string); ok && namespace != "" {
+			app.ensureVectorStoresMigrated(r.Context(), helper.GetContextLoggerFromReq(r), namespace)
+		}
+		next(w, r, ps)
+	}
+}
+
 // AttachMaaSClient middleware creates a MaaS client and attaches it to context.
 // This middleware can be used independently and doesn't require namespace.
 //
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+)
+
+type UsageReportEnvelope = Envelope[*models.UsageReport, None]
+
+// UsageHandler handles GET /gen-ai/api/v1/usage.
+// It reports the tokens and latency of the responses of the caller and of every user of the namespace, in total
+// and per model, over the last days (all retained days by default).
+func (app *App) UsageHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	namespace, username, ok := app.conversationScope(w, r)
+	if !ok {
+		return
+	}
+
+	opts := models.UsageReportOptions{
+		Days:  constants.UsageRetentionDays,
+		Model: r.URL.Query().Get("model"),
+	}
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days < 1 || days > constants.UsageRetentionDays {
+			app.badRequestResponse(w, r, fmt.Errorf("days must be between 1 and %d, got: %s", constants.UsageRetentionDays, daysStr))
+			return
+		}
+		opts.Days = days
+	}
+
+	report, err := app.repositories.Usage.GetUsageReport(ctx, namespace, username, opts)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	response := UsageReportEnvelope{
+		Data: report,
+	}
+
+	if err := app.WriteJSON(w, http.StatusOK, response, nil); err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/gen-ai/internal/cache"
+	"github.com/opendatahub-io/gen-ai/internal/config"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack/lsmocks"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/opendatahub-io/gen-ai/internal/repositories"
+	"github.com/opendatahub-io/gen-ai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageHandler(t *testing.T) {
+	app := App{
+		config: config.EnvConfig{
+			Port:       4000,
+			AuthMethod: config.AuthMethodDisabled,
+		},
+		llamaStackClientFactory: lsmocks.NewMockClientFactory(),
+		repositories:            repositories.NewRepositories(),
+	}
+
+	withContext := func(req *http.Request) *http.Request {
+		ctx := context.WithValue(req.Context(), constants.NamespaceQueryParameterKey, testutil.TestNamespace)
+		llamaStackClient := app.llamaStackClientFactory.CreateClient(testutil.TestLlamaStackURL, "token_mock", false, nil)
+		ctx = context.WithValue(ctx, constants.LlamaStackClientKey, llamaStackClient)
+		return req.WithContext(ctx)
+	}
+
+	createResponse := func(t *testing.T, model string) ResponseData {
+		payload, err := json.Marshal(CreateResponseRequest{Input: "How many tokens is this?", Model: model})
+		require.NoError(t, err)
+		req := withContext(httptest.NewRequest(http.MethodPost, constants.ResponsesPath+"?namespace="+testutil.TestNamespace, bytes.NewReader(payload)))
+
+		rr := httptest.NewRecorder()
+		app.LlamaStackCreateResponseHandler(rr, req, nil)
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		var response struct {
+			Data ResponseData `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	getUsage := func(t *testing.T, query string) *httptest.ResponseRecorder {
+		req := withContext(httptest.NewRequest(http.MethodGet, constants.UsagePath+"?namespace="+testutil.TestNamespace+query, nil))
+		rr := httptest.NewRecorder()
+		app.UsageHandler(rr, req, nil)
+		return rr
+	}
+
+	t.Run("should return the usage and latency of responses", func(t *testing.T) {
+		response := createResponse(t, "llama-3.1-8b")
+
+		require.NotNil(t, response.Usage)
+		assert.Equal(t, int64(5), response.Usage.InputTokens)
+		assert.Equal(t, response.Usage.InputTokens+response.Usage.OutputTokens, response.Usage.TotalTokens)
+		require.NotNil(t, response.Latency)
+		assert.Nil(t, response.Latency.TimeToFirstTokenMs)
+	})
+
+	t.Run("should report the usage per model", func(t *testing.T) {
+		createResponse(t, "llama-3.1-8b")
+		createResponse(t, "granite-3.3-8b")
+
+		rr := getUsage(t, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response UsageReportEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		report := response.Data
+		assert.Equal(t, testutil.TestNamespace, report.Namespace)
+		assert.Equal(t, time.Now().UTC().AddDate(0, 0, 1-constants.UsageRetentionDays).Format(time.DateOnly), report.Since)
+		assert.Equal(t, int64(3), report.User.Total.Requests)
+		assert.Equal(t, int64(0), report.User.Total.FailedRequests)
+		assert.Equal(t, int64(15), report.User.Total.InputTokens)
+
+		require.Len(t, report.User.Models, 2)
+		assert.Equal(t, "granite-3.3-8b", report.User.Models[0].Model)
+		assert.Equal(t, int64(1), report.User.Models[0].Requests)
+		assert.Equal(t, "llama-3.1-8b", report.User.Models[1].Model)
+		assert.Equal(t, int64(2), report.User.Models[1].Requests)
+		assert.Equal(t, report.User, report.AllUsers)
+	})
+
+	t.Run("should filter the report by model", func(t *testing.T) {
+		rr := getUsage(t, "&model=granite-3.3-8b&days=1")
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var response UsageReportEnvelope
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, time.Now().UTC().Format(time.DateOnly), response.Data.Since)
+		assert.Equal(t, int64(1), response.Data.User.Total.Requests)
+		assert.Len(t, response.Data.User.Models, 1)
+	})
+
+	t.Run("should reject invalid periods", func(t *testing.T) {
+		for _, days := range []string{"0", "31", "week"} {
+			rr := getUsage(t, "&days="+days)
+			assert.Equal(t, http.StatusBadRequest, rr.Code, days)
+		}
+	})
+}
+
+func TestMemoryUsageStore(t *testing.T) {
+	usage := repositories.NewUsageRepository(repositories.NewMemoryUsageStore(cache.NewMemoryStore()))
+	ctx := context.Background()
+	record := func(username string, createdAt time.Time) models.UsageRecord {
+		return models.UsageRecord{
+			Namespace:   testutil.TestNamespace,
+			Username:    username,
+			Model:       "llama-3.1-8b",
+			TotalTokens: 100,
+			LatencyMs:   500,
+			CreatedAt:   createdAt,
+		}
+	}
+
+	now := time.Now()
+	require.NoError(t, usage.RecordUsage(ctx, record("alice", now)))
+	require.NoError(t, usage.RecordUsage(ctx, record("alice", now.AddDate(0, 0, -3))))
+	require.NoError(t, usage.RecordUsage(ctx, record("bob", now)))
+	// Usage older than the retention is dropped
+	require.NoError(t, usage.RecordUsage(ctx, record("alice", now.AddDate(0, 0, -constants.UsageRetentionDays-1))))
+
+	t.Run("should report the caller and all users of the namespace", func(t *testing.T) {
+		report, err := usage.GetUsageReport(ctx, testutil.TestNamespace, "alice", models.UsageReportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), report.User.Total.Requests)
+		assert.Equal(t, int64(200), report.User.Total.TotalTokens)
+		assert.Equal(t, int64(500), report.User.Total.AverageLatencyMs)
+		assert.Equal(t, int64(3), report.AllUsers.Total.Requests)
+	})
+
+	t.Run("should only report the requested days", func(t *testing.T) {
+		report, err := usage.GetUsageReport(ctx, testutil.TestNamespace, "alice", models.UsageReportOptions{Days: 2})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), report.User.Total.Requests)
+		assert.Equal(t, int64(2), report.AllUsers.Total.Requests)
+	})
+
+	t.Run("should not report other namespaces", func(t *testing.T) {
+		report, err := usage.GetUsageReport(ctx, "other-namespace", "alice", models.UsageReportOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), report.AllUsers.Total.Requests)
+		assert.Empty(t, report.AllUsers.Models)
+	})
+}
@@ -1,6 +1,9 @@
 package config
 
-import "log/slog"
+import (
+	"log/slog"
+	"net/netip"
+)
 
 const (
 
@@ -32,6 +35,20 @@ type EnvConfig struct {
 	// Llama Stack Configuration
 	LlamaStackURL string
 
+	// Number of documents ingested into vector stores at the same time
+	IngestionWorkers int
+
+	// Private networks documents may be fetched from by URL. Loopback, link-local and other private addresses are refused.
+	DocumentSourceAllowedNetworks []netip.Prefix
+
+	// Private networks the endpoints of S3 data connections may be in, such as the network of in-cluster storage.
+	// Data connections are created by project editors, so their endpoints are checked like URLs.
+	DataConnectionAllowedNetworks []netip.Prefix
+
+	// Vector store quotas of the namespace, which has one LlamaStack deployment. Zero means unlimited.
+	VectorStoreQuotaStores int // Vector stores the namespace can have
+	VectorStoreQuotaFiles  int // Files the vector stores of the namespace can hold together
+
 	// MaaS (Model as a Service) Configuration
 	MaaSURL string
 
@@ -70,4 +87,15 @@ type EnvConfig struct {
 
 	// Path prefix for the BFF endpoints.
 	PathPrefix string
+
+	// ─── MCP ────────────────────────────────────────────────────
+	// Public URL of the MCP OAuth callback endpoint that is registered with authorization servers.
+	// OAuth login to MCP servers is disabled when empty, since the request host cannot be trusted.
+	MCPOAuthRedirectURL string
+	// UI page the MCP OAuth callback redirects to, followed by the namespace (the playground when empty)
+	MCPOAuthUIURL string
+
+	// In-cluster base URL at which LlamaStack reaches the BFF, used for the endpoints of bridged stdio and WebSocket
+	// MCP servers. Bridged servers are unavailable when empty, since the request host cannot be trusted.
+	MCPBridgeBaseURL string
 }
@@ -16,26 +16,50 @@ const (
 	// LlamaStack Distribution (LSD) endpoints
 	ModelsListPath                    = ApiPathPrefix + "/lsd/models"
 	VectorStoresListPath              = ApiPathPrefix + "/lsd/vectorstores"
+	VectorStorePath                   = ApiPathPrefix + "/lsd/vectorstores/:id"
 	VectorStoresDeletePath            = ApiPathPrefix + "/lsd/vectorstores/delete"
+	VectorStoreSearchPath             = ApiPathPrefix + "/lsd/vectorstores/:id/search"
 	ResponsesPath                     = ApiPathPrefix + "/lsd/responses"
+	ResponsesApprovalPath             = ApiPathPrefix + "/lsd/responses/approvals"
+	ResponsesComparePath              = ApiPathPrefix + "/lsd/responses/compare"
 	FilesListPath                     = ApiPathPrefix + "/lsd/files"
 	FilesUploadPath                   = ApiPathPrefix + "/lsd/files/upload"
 	FilesDeletePath                   = ApiPathPrefix + "/lsd/files/delete"
 	VectorStoreFilesListPath          = ApiPathPrefix + "/lsd/vectorstores/files"
 	VectorStoreFilesUploadPath        = ApiPathPrefix + "/lsd/vectorstores/files/upload"
 	VectorStoreFilesDeletePath        = ApiPathPrefix + "/lsd/vectorstores/files/delete"
+	VectorStoreFileContentPath        = ApiPathPrefix + "/lsd/vectorstores/files/content"
+	VectorStoreFileChunksPath         = ApiPathPrefix + "/lsd/vectorstores/files/chunks"
 	LlamaStackDistributionStatusPath  = ApiPathPrefix + "/lsd/status"
 	LlamaStackDistributionInstallPath = ApiPathPrefix + "/lsd/install"
 	LlamaStackDistributionDeletePath  = ApiPathPrefix + "/lsd/delete"
+	IngestionJobPath                  = ApiPathPrefix + "/lsd/ingestion-jobs/:id"
+	IngestionJobCancelPath            = ApiPathPrefix + "/lsd/ingestion-jobs/:id/cancel"
+	IngestionJobRetryPath             = ApiPathPrefix + "/lsd/ingestion-jobs/:id/retry"
 
 	// General endpoints
 	CodeExporterPath = ApiPathPrefix + "/code-exporter"
 	NamespacesPath   = ApiPathPrefix + "/namespaces"
 	UserPath         = ApiPathPrefix + "/user"
+	UsagePath        = ApiPathPrefix + "/usage"
+
+	// Conversation history endpoints
+	ConversationsPath        = ApiPathPrefix + "/conversations"
+	ConversationPath         = ApiPathPrefix + "/conversations/:id"
+	ConversationMessagesPath = ApiPathPrefix + "/conversations/:id/messages"
 
 	// MCP (Model Context Protocol) endpoint paths
-	MCPToolsPath  = ApiPathPrefix + "/mcp/tools"
-	MCPStatusPath = ApiPathPrefix + "/mcp/status"
+	MCPToolsPath          = ApiPathPrefix + "/mcp/tools"
+	MCPToolCallPath       = ApiPathPrefix + "/mcp/tools/call"
+	MCPStatusPath         = ApiPathPrefix + "/mcp/status"
+	MCPResourcesPath      = ApiPathPrefix + "/mcp/resources"
+	MCPResourceReadPath   = ApiPathPrefix + "/mcp/resources/read"
+	MCPPromptsPath        = ApiPathPrefix + "/mcp/prompts"
+	MCPPromptGetPath      = ApiPathPrefix + "/mcp/prompts/get"
+	MCPCredentialsPath    = ApiPathPrefix + "/mcp/credentials"
+	MCPCredentialPath     = ApiPathPrefix + "/mcp/credentials/:server_name"
+	MCPCredentialTestPath = ApiPathPrefix + "/mcp/credentials/:server_name/test"
+	MCPOAuthCallbackPath  = ApiPathPrefix + "/mcp/oauth/callback"
 
 	// AI Assets (AAA) endpoints
 	MCPServersListPath = ApiPathPrefix + "/aaa/mcps"
@@ -45,3 +69,10 @@ const (
 	MaaSModelsPath = ApiPathPrefix + "/maas/models"
 	MaaSTokensPath = ApiPathPrefix + "/maas/tokens"
 )
+
+// Limits of the conversation history of each user, which the default store keeps in process memory
+const (
+	ConversationMaxPerOwner     = 200     // Conversations a user can keep in a namespace
+	ConversationMaxMessages     = 500     // Messages of a conversation
+	ConversationMessageMaxBytes = 1 << 20 // Input, output text and response of a message together
+)
@@ -9,4 +9,29 @@ const (
 
 	// CacheAccessTokensCategory is the cache category for storing MaaS access tokens
 	CacheAccessTokensCategory = "access_tokens"
+
+	// CacheMCPOAuthStatesCategory is the cache category for MCP OAuth authorizations waiting for their callback
+	CacheMCPOAuthStatesCategory = "mcp_oauth_states"
+
+	// CacheMCPOAuthClientsCategory is the cache category for OAuth clients registered with MCP authorization servers
+	CacheMCPOAuthClientsCategory = "mcp_oauth_clients"
+
+	// CacheMCPNamespace groups cached MCP server results, which belong to a request identity rather than a Kubernetes namespace
+	CacheMCPNamespace = "mcp"
+
+	// CacheMCPStatusCategory is the cache category for MCP server connection statuses refreshed by the health prober
+	CacheMCPStatusCategory = "mcp_status"
+
+	// CacheMCPToolsCategory is the cache category for MCP server tool lists refreshed by the health prober
+	CacheMCPToolsCategory = "mcp_tools"
+
+	// CacheResponseUsageCategory is the cache category for the daily token usage and latency of responses per model
+	CacheResponseUsageCategory = "response_usage"
+
+	// CacheUsageAllUsers is kept in place of a username for the usage of every user of a namespace. Kubernetes
+	// reserves the system: prefix, so it cannot be the name of a user.
+	CacheUsageAllUsers = "system:all-users"
+
+	// UsageRetentionDays is how many days the usage of responses is kept and can be reported
+	UsageRetentionDays = 30
 )
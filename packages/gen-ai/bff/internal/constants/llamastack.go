@@ -1,5 +1,7 @@
 package constants
 
+import "time"
+
 // LlamaStack Distribution related constants
 const (
 	// LlamaStackConfigMapName is the default name of the LlamaStack configuration ConfigMap
@@ -8,3 +10,105 @@ const (
 	// LlamaStackRunYAMLKey is the key for the run.yaml configuration in the ConfigMap
 	LlamaStackRunYAMLKey = "run.yaml"
 )
+
+// Asynchronous document ingestion
+const (
+	// IngestionDefaultWorkers is how many documents are ingested at the same time when not configured
+	IngestionDefaultWorkers = 4
+	// IngestionQueueSize is how many ingestion jobs can wait for a worker before new uploads are refused
+	IngestionQueueSize = 100
+	// IngestionPollInterval is how often the processing status of an attached file is checked
+	IngestionPollInterval = 2 * time.Second
+	// IngestionJobTimeout bounds the upload and processing of a single document
+	IngestionJobTimeout = 30 * time.Minute
+	// IngestionJobRetention is how long finished jobs can still be looked up
+	IngestionJobRetention = time.Hour
+	// IngestionContentRetention is how long failed and cancelled jobs keep their uploaded documents to be retried
+	IngestionContentRetention = 10 * time.Minute
+	// IngestionPruneInterval is how often finished jobs and the documents of failed jobs are forgotten
+	IngestionPruneInterval = time.Minute
+	// IngestionMaxBufferedBytes is the largest total size of uploaded documents kept in memory until they are ingested
+	IngestionMaxBufferedBytes = 1 << 30
+	// IngestionMaxBufferedBytesPerUser is the largest size of uploaded documents one user can have waiting to be ingested
+	IngestionMaxBufferedBytesPerUser = 256 << 20
+)
+
+// Limits of a single upload, which can contain several files and zip or tar archives
+const (
+	// IngestionMaxUploadFiles is how many documents an upload can contain once archives are expanded
+	IngestionMaxUploadFiles = 100
+	// IngestionMaxFileBytes is the largest document accepted, including documents of archives
+	IngestionMaxFileBytes = 64 << 20
+	// IngestionMaxUploadBytes is the largest total size of the documents of an upload once archives are expanded
+	IngestionMaxUploadBytes = 256 << 20
+	// IngestionMaxCompressionRatio is the largest ratio of expanded to compressed size accepted for archives
+	IngestionMaxCompressionRatio = 100
+	// IngestionMaxRedirects is how many redirects are followed when a document is fetched from a URL
+	IngestionMaxRedirects = 5
+)
+
+// VectorStoreFileSourceURIAttribute is the vector store file attribute holding the remote location of a document
+// that was ingested from S3-compatible storage or a URL
+const VectorStoreFileSourceURIAttribute = "source_uri"
+
+// Limits of the attributes of a vector store file
+const (
+	// VectorStoreFileMaxAttributes is how many attributes a vector store file can have
+	VectorStoreFileMaxAttributes = 16
+	// VectorStoreFileMaxAttributeKeyLength is the longest attribute key accepted
+	VectorStoreFileMaxAttributeKeyLength = 64
+	// VectorStoreFileMaxAttributeValueLength is the longest string attribute value accepted
+	VectorStoreFileMaxAttributeValueLength = 512
+)
+
+// Paging of the chunks of a vector store file
+const (
+	// VectorStoreFileChunksDefaultLimit is how many chunks are returned when no limit is given
+	VectorStoreFileChunksDefaultLimit = 20
+	// VectorStoreFileChunksMaxLimit is the most chunks returned at once
+	VectorStoreFileChunksMaxLimit = 100
+)
+
+// Limits of the content parts of response input, such as images
+const (
+	// InputContentMaxParts is how many content parts a message can have
+	InputContentMaxParts = 20
+	// InputContentMaxBytes is the largest image or file accepted as a content part or vision upload
+	InputContentMaxBytes = 20 << 20
+	// ResponseRequestMaxContentParts is how many content parts a request can have across input_content and
+	// every message of chat_context
+	ResponseRequestMaxContentParts = 50
+	// ResponseRequestMaxBytes is the largest body of a responses request. Content parts are best sent as file IDs;
+	// the cap leaves room for a couple of images of InputContentMaxBytes sent inline as base64 data URLs.
+	ResponseRequestMaxBytes = 64 << 20
+	// VisionFilePurpose is the purpose of uploaded files that are used as images in responses
+	VisionFilePurpose = "vision"
+)
+
+// Limits of response comparisons
+const (
+	// CompareMinModels is how many models a comparison needs at least
+	CompareMinModels = 2
+	// CompareMaxModels is how many models a comparison can send the input to at once
+	CompareMaxModels = 4
+)
+
+// InputImageContentTypes are the image types models accept in response input
+var InputImageContentTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+
+// InputFileContentTypes are the types of files that can be inlined in response input
+var InputFileContentTypes = []string{"application/pdf", "text/plain"}
+
+// Data connections are Secrets managed by the dashboard that hold the credentials of S3-compatible storage
+const (
+	DataConnectionTypeAnnotation    = "opendatahub.io/connection-type"
+	DataConnectionTypeRefAnnotation = "opendatahub.io/connection-type-ref"
+	DataConnectionTypeS3            = "s3"
+	DataConnectionAccessKeyIDKey    = "AWS_ACCESS_KEY_ID"
+	DataConnectionSecretKeyKey      = "AWS_SECRET_ACCESS_KEY"
+	DataConnectionEndpointKey       = "AWS_S3_ENDPOINT"
+	DataConnectionRegionKey         = "AWS_DEFAULT_REGION"
+	DataConnectionBucketKey         = "AWS_S3_BUCKET"
+	// DataConnectionDefaultRegion is used to sign requests when the data connection has no region
+	DataConnectionDefaultRegion = "us-east-1"
+)
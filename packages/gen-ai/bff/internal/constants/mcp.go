@@ -1,5 +1,7 @@
 package constants
 
+import "time"
+
 // MCP Servers List
 const (
 	MCPServerName = "gen-ai-aa-mcp-servers"
@@ -9,9 +11,67 @@ const (
 const (
 	TransportTypeSSE            = "sse"
 	TransportTypeStreamableHTTP = "streamable-http"
+	TransportTypeStdio          = "stdio"
+	TransportTypeWebSocket      = "websocket"
 )
 
 // MCP Authentication Headers
 const (
-	MCPBearerHeader = "X-MCP-Bearer"
+	MCPBearerHeader      = "X-MCP-Bearer"
+	MCPBridgeTokenHeader = "X-MCP-Bridge-Token" // Authenticates LlamaStack to a bridged MCP server
+)
+
+// MCP bridge for servers that are not reachable over HTTP
+const (
+	// MCPBridgePath is where bridged MCP servers are served over streamable HTTP, as <path><namespace>/<server name>.
+	// It is outside the API prefix because LlamaStack calls it with a bridge token instead of a user token.
+	MCPBridgePath = "/mcp-bridge/"
+	// MCPStdioURLScheme identifies stdio servers, which have no URL of their own, as stdio://<server name>
+	MCPStdioURLScheme = "stdio://"
+)
+
+// MCP credential Secrets stored per user and MCP server
+const (
+	MCPCredentialSecretPrefix      = "mcp-credential-"
+	MCPCredentialLabelKey          = "gen-ai.opendatahub.io/mcp-credential"
+	MCPCredentialOwnerLabelKey     = "gen-ai.opendatahub.io/mcp-credential-owner"
+	MCPCredentialOwnerAnnotation   = "gen-ai.opendatahub.io/owner"
+	MCPCredentialServerAnnotation  = "gen-ai.opendatahub.io/mcp-server"
+	MCPCredentialUpdatedAnnotation = "gen-ai.opendatahub.io/updated-at"
+	MCPCredentialAuthAnnotation    = "gen-ai.opendatahub.io/auth-type"
+	MCPCredentialExpiresAnnotation = "gen-ai.opendatahub.io/expires-at"
+	MCPCredentialTokenKey          = "token"
+	MCPCredentialRefreshTokenKey   = "refresh_token"
+	MCPCredentialTokenEndpointKey  = "token_endpoint"
+	MCPCredentialClientIDKey       = "client_id"
+	MCPCredentialClientSecretKey   = "client_secret"
+	MCPCredentialResourceKey       = "resource"
+)
+
+// MCP credential types
+const (
+	MCPCredentialAuthTypeToken = "token" // Token provided by the user
+	MCPCredentialAuthTypeOAuth = "oauth" // Token obtained with the OAuth authorization code flow
+)
+
+// MCP OAuth authorization
+const (
+	// MCPOAuthStateTTL is how long a started authorization can be completed
+	MCPOAuthStateTTL = 10 * time.Minute
+	// MCPOAuthClientTTL is how long dynamically registered OAuth clients are reused
+	MCPOAuthClientTTL = 24 * time.Hour
+	// MCPOAuthRefreshLeeway refreshes access tokens this long before they expire
+	MCPOAuthRefreshLeeway = time.Minute
+	// MCPOAuthClientName is the client name registered with authorization servers
+	MCPOAuthClientName = "Open Data Hub Gen AI"
+	// MCPOAuthDefaultUIPath is the playground page the OAuth callback redirects to when no UI URL is configured
+	MCPOAuthDefaultUIPath = "/gen-ai-studio/playground"
+)
+
+// MCP aggregated status checks
+const (
+	// MCPStatusMaxConcurrency limits how many servers are checked at the same time
+	MCPStatusMaxConcurrency = 8
+	// MCPStatusServerTimeout bounds the check of a single server, so one slow server does not hold up the others
+	MCPStatusServerTimeout = 10 * time.Second
 )
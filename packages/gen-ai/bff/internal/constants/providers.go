@@ -1,6 +1,57 @@
 package constants
 
+import "time"
+
 // Vector Store Providers
 const (
 	DefaultVectorStoreProvider = "milvus"
 )
+
+// Vector store ownership metadata. These keys are reserved; users cannot set them through the create metadata.
+const (
+	VectorStoreOwnerKey        = "owner"
+	VectorStoreNamespaceKey    = "namespace"
+	VectorStoreVisibilityKey   = "visibility"
+	VectorStoreSharedGroupsKey = "shared_groups" // Comma-separated group names
+)
+
+// Metadata the removed per-user auto-provisioning recorded on the stores it created. Those stores are private to
+// the recorded user, also before they are migrated to the ownership keys.
+const (
+	VectorStoreCreatedByKey               = "created_by"
+	VectorStoreAutoProvisionedCreatedBy   = "auto-provisioning"
+	VectorStoreAutoProvisionedUsernameKey = "username"
+)
+
+// VectorStoreMigrationRetryInterval is how long a namespace waits before a failed migration of its auto-provisioned
+// vector stores is retried
+const VectorStoreMigrationRetryInterval = time.Minute
+
+// Vector store embedding metadata. The embedding model a store was created with is recorded next to its ownership,
+// so that clients know which model the chunks of the store are embedded with. These keys are reserved as well.
+const (
+	VectorStoreEmbeddingModelKey     = "embedding_model"
+	VectorStoreEmbeddingDimensionKey = "embedding_dimension"
+)
+
+// Vector store visibilities
+const (
+	VectorStoreVisibilityPrivate   = "private"   // Only the owner
+	VectorStoreVisibilityNamespace = "namespace" // Every user of the namespace
+	VectorStoreVisibilityGroups    = "groups"    // The owner and members of the shared groups
+)
+
+// Vector store listing
+const (
+	VectorStoreDefaultListLimit = 20
+	VectorStoreMaxListLimit     = 100
+)
+
+// FilesMaxListLimit is the most files the LlamaStack Files API lists at once
+const FilesMaxListLimit = 10000
+
+// VectorStoreMaxExpiresAfterDays is the longest expiration policy of a vector store, in days after its last activity
+const VectorStoreMaxExpiresAfterDays = 365
+
+// VectorStoreExpiresAfterAnchor is the only supported anchor of the expiration policy of a vector store
+const VectorStoreExpiresAfterAnchor = "last_active_at"
@@ -27,6 +27,12 @@ vector_store_name = "{{.VectorStore.Name}}"
 {{- if .Temperature }}
 temperature = {{.Temperature}}
 {{- end }}
+{{- range .GenerationSettings }}
+{{.Name}} = {{.Value}}
+{{- end }}
+{{- range .ExtraBodySettings }}
+{{.Name}} = {{.Value}}
+{{- end }}
 {{- if .Stream }}
 stream_enabled = True
 {{- end }}
@@ -41,7 +47,8 @@ files_to_upload = [
 ]
 {{- end }}
 
-import os
+{{ if .ResponseFormat }}import json
+{{ end }}import os
 
 from llama_stack_client import LlamaStackClient
 
@@ -99,13 +106,26 @@ for file_info in files_to_upload:
         )
 {{- end }}
 
+{{- if .ResponseFormat }}
+
+# Ask for JSON output matching the response format
+response_format = json.loads(r"""{{.ResponseFormat.JSON}}""")
+{{- end }}
+
 config = {
     "input": input_text,
     "model": model_name{{- if .Temperature }},
     "temperature": temperature{{- end }}{{- if .Instructions }},
     "instructions": system_instructions{{- end }}{{- if .Stream }},
     "stream": stream_enabled{{- end }}{{- if or .Tools .MCPServers }},
-    "tools": tools{{- end }}
+    "tools": tools{{- end }}{{- range .GenerationSettings }},
+    "{{.Name}}": {{.Name}}{{- end }}{{- if .ResponseFormat }},
+    "text": {"format": response_format}{{- end }}{{- if .ExtraBodySettings }},
+    "extra_body": {
+      {{- range .ExtraBodySettings }}
+        "{{.Name}}": {{.Name}},
+      {{- end }}
+    }{{- end }}
 }
 
 response = client.responses.create(**config)
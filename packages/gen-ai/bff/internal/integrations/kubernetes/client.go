@@ -25,6 +25,7 @@ type KubernetesClientInterface interface {
 
 	// Identity
 	GetUser(ctx context.Context, identity *integrations.RequestIdentity) (string, error)
+	GetUserGroups(ctx context.Context, identity *integrations.RequestIdentity) ([]string, error)
 
 	// LlamaStack Distribution
 	GetLlamaStackDistributions(ctx context.Context, identity *integrations.RequestIdentity, namespace string) (*lsdapi.LlamaStackDistributionList, error)
@@ -36,6 +37,12 @@ type KubernetesClientInterface interface {
 	// ConfigMap operations
 	GetConfigMap(ctx context.Context, identity *integrations.RequestIdentity, namespace string, name string) (*corev1.ConfigMap, error)
 
+	// Secret operations
+	GetSecret(ctx context.Context, identity *integrations.RequestIdentity, namespace string, name string) (*corev1.Secret, error)
+	ListSecrets(ctx context.Context, identity *integrations.RequestIdentity, namespace string, matchLabels map[string]string) ([]corev1.Secret, error)
+	ApplySecret(ctx context.Context, identity *integrations.RequestIdentity, secret *corev1.Secret) (*corev1.Secret, error)
+	DeleteSecret(ctx context.Context, identity *integrations.RequestIdentity, namespace string, name string) error
+
 	// Cluster information
 	GetClusterDomain(ctx context.Context) (string, error)
 }
@@ -254,6 +254,11 @@ func (m *TokenKubernetesClientMock) GetUser(ctx context.Context, identity *integ
 	return "mockUser", nil
 }
 
+// GetUserGroups returns mock user groups for testing
+func (m *TokenKubernetesClientMock) GetUserGroups(ctx context.Context, identity *integrations.RequestIdentity) ([]string, error) {
+	return []string{"system:authenticated", "mock-group"}, nil
+}
+
 // GetLlamaStackDistributions returns mock LSD list for testing
 func (m *TokenKubernetesClientMock) GetLlamaStackDistributions(ctx context.Context, identity *integrations.RequestIdentity, namespace string) (*lsdapi.LlamaStackDistributionList, error) {
 	// Special case: mock-test-namespace-1 should always return empty list for testing empty state
@@ -19,6 +19,7 @@ import (
 	authv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -352,8 +353,8 @@ func (kc *TokenKubernetesClient) BearerToken() (string, error) {
 	return kc.Token.Raw(), nil
 }
 
-// GetUser returns the username from a SelfSubjectReview request
-func (kc *TokenKubernetesClient) GetUser(ctx context.Context, identity *integrations.RequestIdentity) (string, error) {
+// selfSubjectReview returns the user info of the request identity from a SelfSubjectReview request
+func (kc *TokenKubernetesClient) selfSubjectReview(ctx context.Context, identity *integrations.RequestIdentity) (*authnv1.UserInfo, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
@@ -366,7 +367,7 @@ func (kc *TokenKubernetesClient) GetUser(ctx context.Context, identity *integrat
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		kc.Logger.Error("failed to create kubernetes clientset", "error", err)
-		return "", fmt.Errorf("failed to create kubernetes clientset: %w", err)
+		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
 	}
 
 	ssr := &authnv1.SelfSubjectReview{
@@ -379,10 +380,28 @@ func (kc *TokenKubernetesClient) GetUser(ctx context.Context, identity *integrat
 	resp, err := clientset.AuthenticationV1().SelfSubjectReviews().Create(ctx, ssr, metav1.CreateOptions{})
 	if err != nil {
 		kc.Logger.Error("failed to get user identity from token", "error", err)
-		return "", fmt.Errorf("failed to get user identity: %w", err)
+		return nil, fmt.Errorf("failed to get user identity: %w", err)
+	}
+	return &resp.Status.UserInfo, nil
+}
+
+// GetUserGroups returns the groups of the user from a SelfSubjectReview request
+func (kc *TokenKubernetesClient) GetUserGroups(ctx context.Context, identity *integrations.RequestIdentity) ([]string, error) {
+	userInfo, err := kc.selfSubjectReview(ctx, identity)
+	if err != nil {
+		return nil, err
 	}
+	return userInfo.Groups, nil
+}
 
-	username := resp.Status.UserInfo.Username
+// GetUser returns the username from a SelfSubjectReview request
+func (kc *TokenKubernetesClient) GetUser(ctx context.Context, identity *integrations.RequestIdentity) (string, error) {
+	userInfo, err := kc.selfSubjectReview(ctx, identity)
+	if err != nil {
+		return "", err
+	}
+
+	username := userInfo.Username
 	if username == "" {
 		kc.Logger.Error("user identity not found in token")
 		return "", fmt.Errorf("no username found in token")
@@ -418,6 +437,90 @@ func (kc *TokenKubernetesClient) GetConfigMap(ctx context.Context, identity *int
 	return configMap, nil
 }
 
+// GetSecret retrieves a Secret by name. Not-found errors are returned without logging
+// since callers commonly use them to detect missing secrets.
+func (kc *TokenKubernetesClient) GetSecret(ctx context.Context, identity *integrations.RequestIdentity, namespace string, name string) (*corev1.Secret, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	secret := &corev1.Secret{}
+	err := kc.Client.Get(ctx, client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}, secret)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			kc.Logger.Error("failed to get Secret", "error", err, "namespace", namespace, "name", name)
+		}
+		return nil, fmt.Errorf("failed to get Secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// ListSecrets lists the Secrets in a namespace that carry all of the given labels
+func (kc *TokenKubernetesClient) ListSecrets(ctx context.Context, identity *integrations.RequestIdentity, namespace string, matchLabels map[string]string) ([]corev1.Secret, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	secretList := &corev1.SecretList{}
+	err := kc.Client.List(ctx, secretList, client.InNamespace(namespace), client.MatchingLabels(matchLabels))
+	if err != nil {
+		kc.Logger.Error("failed to list Secrets", "error", err, "namespace", namespace)
+		return nil, fmt.Errorf("failed to list Secrets: %w", err)
+	}
+
+	return secretList.Items, nil
+}
+
+// ApplySecret creates the Secret, or replaces the labels, annotations and data of an existing Secret with the same name
+func (kc *TokenKubernetesClient) ApplySecret(ctx context.Context, identity *integrations.RequestIdentity, secret *corev1.Secret) (*corev1.Secret, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	existing := &corev1.Secret{}
+	err := kc.Client.Get(ctx, client.ObjectKey{Namespace: secret.Namespace, Name: secret.Name}, existing)
+	switch {
+	case apierrors.IsNotFound(err):
+		if err := kc.Client.Create(ctx, secret); err != nil {
+			kc.Logger.Error("failed to create Secret", "error", err, "namespace", secret.Namespace, "name", secret.Name)
+			return nil, fmt.Errorf("failed to create Secret: %w", err)
+		}
+		return secret, nil
+	case err != nil:
+		kc.Logger.Error("failed to get Secret", "error", err, "namespace", secret.Namespace, "name", secret.Name)
+		return nil, fmt.Errorf("failed to get Secret: %w", err)
+	}
+
+	existing.Labels = secret.Labels
+	existing.Annotations = secret.Annotations
+	existing.Type = secret.Type
+	existing.Data = secret.Data
+	existing.StringData = secret.StringData
+	if err := kc.Client.Update(ctx, existing); err != nil {
+		kc.Logger.Error("failed to update Secret", "error", err, "namespace", secret.Namespace, "name", secret.Name)
+		return nil, fmt.Errorf("failed to update Secret: %w", err)
+	}
+
+	return existing, nil
+}
+
+// DeleteSecret deletes a Secret by name
+func (kc *TokenKubernetesClient) DeleteSecret(ctx context.Context, identity *integrations.RequestIdentity, namespace string, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	err := kc.Client.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			kc.Logger.Error("failed to delete Secret", "error", err, "namespace", namespace, "name", name)
+		}
+		return fmt.Errorf("failed to delete Secret: %w", err)
+	}
+
+	return nil
+}
+
 func (kc *TokenKubernetesClient) GetAAModels(ctx context.Context, identity *integrations.RequestIdentity, namespace string) ([]models.AAModel, error) {
 	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
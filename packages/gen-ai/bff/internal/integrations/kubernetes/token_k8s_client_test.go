@@ -16,7 +16,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 // loadTestData loads test fixture files from the testdata directory
@@ -554,3 +558,81 @@ func TestGenerateLlamaStackConfigWithMaaSModels(t *testing.T) {
 		assert.Contains(t, err.Error(), "not found")
 	})
 }
+
+func TestSecretOperations(t *testing.T) {
+	newClient := func() *TokenKubernetesClient {
+		return &TokenKubernetesClient{
+			Client: fake.NewClientBuilder().Build(),
+			Logger: slog.Default(),
+		}
+	}
+	identity := &integrations.RequestIdentity{Token: "test-token"}
+	newSecret := func(name, token string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testutil.TestNamespace,
+				Labels:    map[string]string{"app": "test"},
+			},
+			Data: map[string][]byte{"token": []byte(token)},
+		}
+	}
+
+	t.Run("should create a secret and read it back", func(t *testing.T) {
+		client := newClient()
+		ctx := context.Background()
+
+		_, err := client.ApplySecret(ctx, identity, newSecret("creds", "first"))
+		require.NoError(t, err)
+
+		secret, err := client.GetSecret(ctx, identity, testutil.TestNamespace, "creds")
+		require.NoError(t, err)
+		assert.Equal(t, "first", string(secret.Data["token"]))
+	})
+
+	t.Run("should replace the data of an existing secret", func(t *testing.T) {
+		client := newClient()
+		ctx := context.Background()
+
+		_, err := client.ApplySecret(ctx, identity, newSecret("creds", "first"))
+		require.NoError(t, err)
+		_, err = client.ApplySecret(ctx, identity, newSecret("creds", "second"))
+		require.NoError(t, err)
+
+		secret, err := client.GetSecret(ctx, identity, testutil.TestNamespace, "creds")
+		require.NoError(t, err)
+		assert.Equal(t, "second", string(secret.Data["token"]))
+	})
+
+	t.Run("should list secrets by label", func(t *testing.T) {
+		client := newClient()
+		ctx := context.Background()
+
+		_, err := client.ApplySecret(ctx, identity, newSecret("labeled", "value"))
+		require.NoError(t, err)
+		unlabeled := newSecret("unlabeled", "value")
+		unlabeled.Labels = nil
+		_, err = client.ApplySecret(ctx, identity, unlabeled)
+		require.NoError(t, err)
+
+		secrets, err := client.ListSecrets(ctx, identity, testutil.TestNamespace, map[string]string{"app": "test"})
+		require.NoError(t, err)
+		require.Len(t, secrets, 1)
+		assert.Equal(t, "labeled", secrets[0].Name)
+	})
+
+	t.Run("should delete secrets and report missing ones as not found", func(t *testing.T) {
+		client := newClient()
+		ctx := context.Background()
+
+		_, err := client.ApplySecret(ctx, identity, newSecret("creds", "value"))
+		require.NoError(t, err)
+		require.NoError(t, client.DeleteSecret(ctx, identity, testutil.TestNamespace, "creds"))
+
+		_, err = client.GetSecret(ctx, identity, testutil.TestNamespace, "creds")
+		assert.True(t, apierrors.IsNotFound(err))
+
+		err = client.DeleteSecret(ctx, identity, testutil.TestNamespace, "creds")
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
@@ -0,0 +1,879 @@
+package llamastack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openai/openai-go/v2"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+)
+
+// Ingestion job statuses
+const (
+	IngestionJobQueued     = "queued"     // Waiting for a worker
+	IngestionJobUploading  = "uploading"  // Uploading the document to LlamaStack
+	IngestionJobProcessing = "processing" // Attached to the vector store, being chunked and embedded
+	IngestionJobCompleted  = "completed"
+	IngestionJobFailed     = "failed"
+	IngestionJobCancelled  = "cancelled"
+)
+
+var (
+	// ErrIngestionJobNotFound is returned when a job does not exist or does not belong to the caller
+	ErrIngestionJobNotFound = errors.New("ingestion job not found")
+	// ErrIngestionQueueFull is returned when too many jobs are waiting for a worker
+	ErrIngestionQueueFull = errors.New("too many documents are waiting to be ingested, try again later")
+	// ErrIngestionBufferFull is returned when the uploaded documents waiting to be ingested take too much memory,
+	// either in total or for the caller
+	ErrIngestionBufferFull = errors.New("too many uploaded documents are waiting to be ingested, try again later")
+	// ErrIngestionJobExpired is returned when retrying a job whose uploaded documents were already discarded
+	ErrIngestionJobExpired = errors.New("the uploaded documents of the ingestion job were discarded, upload them again")
+	// ErrIngestionJobFinished is returned when cancelling a job that already finished
+	ErrIngestionJobFinished = errors.New("ingestion job already finished")
+	// ErrIngestionJobNotRetryable is returned when retrying a job that did not fail and was not cancelled
+	ErrIngestionJobNotRetryable = errors.New("only failed or cancelled ingestion jobs can be retried")
+)
+
+// IngestionJob reports the progress of documents being uploaded and added to a vector store.
+// An upload with a single document reports it with FileID, one with several documents lists them in Files.
+type IngestionJob struct {
+	ID            string                 `json:"id"`
+	Namespace     string                 `json:"namespace"`
+	Owner         string                 `json:"owner"`
+	VectorStoreID string                 `json:"vector_store_id"`
+	Filename      string                 `json:"filename"`             // Name of the uploaded file or archive
+	SourceURI     string                 `json:"source_uri,omitempty"` // Remote location of a document that was not uploaded
+	Attributes    map[string]interface{} `json:"attributes,omitempty"` // Set on the vector store files of the documents
+	SizeBytes     int64                  `json:"size_bytes"`
+	Status        string                 `json:"status"`
+	FileID        string                 `json:"file_id,omitempty"`     // Set once a single document is uploaded
+	BatchID       string                 `json:"batch_id,omitempty"`    // File batch of the latest attempt for several documents
+	Files         []IngestionJobFile     `json:"files,omitempty"`       // Progress of each document when there are several
+	UsageBytes    int64                  `json:"usage_bytes,omitempty"` // Vector store usage of the processed files
+	ChunkCount    int                    `json:"chunk_count"`           // Set once processing completed
+	Error         string                 `json:"error,omitempty"`
+	Attempts      int                    `json:"attempts"`
+	CreatedAt     int64                  `json:"created_at"`            // Unix timestamp
+	StartedAt     int64                  `json:"started_at,omitempty"`  // Unix timestamp of the latest attempt
+	FinishedAt    int64                  `json:"finished_at,omitempty"` // Unix timestamp
+}
+
+// IngestionJobFile reports the progress of one of several documents of an ingestion job
+type IngestionJobFile struct {
+	Filename   string `json:"filename"`
+	SizeBytes  int64  `json:"size_bytes"`
+	Status     string `json:"status"`
+	FileID     string `json:"file_id,omitempty"`
+	UsageBytes int64  `json:"usage_bytes,omitempty"`
+	ChunkCount int    `json:"chunk_count"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Finished reports whether the job reached a final status
+func (j IngestionJob) Finished() bool {
+	return j.Status == IngestionJobCompleted || j.Status == IngestionJobFailed || j.Status == IngestionJobCancelled
+}
+
+// IngestionJobParams describes documents to ingest into a vector store. A single document is added to the
+// vector store on its own, several documents are added as one file batch.
+type IngestionJobParams struct {
+	Namespace        string
+	Owner            string
+	VectorStoreID    string
+	Filename         string // Name of the upload, the name of the document when empty
+	Purpose          string
+	ChunkingStrategy *ChunkingStrategy
+	Attributes       map[string]interface{} // Vector store file attributes of every document
+	Documents        []IngestionDocument
+}
+
+// IngestionDocument is a document to ingest, either with its content or from a remote source. The content is kept
+// until the job completes, or for a while after it failed or was cancelled so that it can be retried, while a
+// source is opened again for every attempt.
+type IngestionDocument struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+	Source      DocumentSource // Ingested instead of Content when set
+}
+
+// uploadParams returns the parameters to upload the document to the file storage
+func (d IngestionDocument) uploadParams(purpose string) UploadFileParams {
+	params := UploadFileParams{
+		Filename:    d.Filename,
+		ContentType: d.ContentType,
+		Purpose:     purpose,
+	}
+	if d.Source != nil {
+		params.Source = d.Source
+	} else {
+		params.Reader = bytes.NewReader(d.Content)
+	}
+	return params
+}
+
+// attributes returns the vector store file attributes of the document: the attributes of the job, and where the
+// document came from when it was not uploaded
+func (d IngestionDocument) attributes(jobAttributes map[string]interface{}) map[string]interface{} {
+	if d.Source == nil {
+		return jobAttributes
+	}
+	attributes := make(map[string]interface{}, len(jobAttributes)+1)
+	for key, value := range jobAttributes {
+		attributes[key] = value
+	}
+	attributes[constants.VectorStoreFileSourceURIAttribute] = d.Source.URI()
+	return attributes
+}
+
+// IngestionManagerConfig configures the worker pool of an IngestionManager
+type IngestionManagerConfig struct {
+	Workers      int
+	QueueSize    int
+	PollInterval time.Duration
+	Timeout      time.Duration // Bounds a single attempt
+	Retention    time.Duration // How long finished jobs are kept
+
+	// Uploaded documents are kept in memory until they are ingested. Zero values do not limit them.
+	MaxBufferedBytes        int64         // Uploaded documents kept in memory for all users
+	MaxBufferedBytesPerUser int64         // Uploaded documents kept in memory for one user of a namespace
+	ContentRetention        time.Duration // How long failed and cancelled jobs keep their uploaded documents for retries
+}
+
+// DefaultIngestionManagerConfig returns the default ingestion settings with the given number of workers
+func DefaultIngestionManagerConfig(workers int) IngestionManagerConfig {
+	if workers <= 0 {
+		workers = constants.IngestionDefaultWorkers
+	}
+	return IngestionManagerConfig{
+		Workers:      workers,
+		QueueSize:    constants.IngestionQueueSize,
+		PollInterval: constants.IngestionPollInterval,
+		Timeout:      constants.IngestionJobTimeout,
+		Retention:    constants.IngestionJobRetention,
+
+		MaxBufferedBytes:        constants.IngestionMaxBufferedBytes,
+		MaxBufferedBytesPerUser: constants.IngestionMaxBufferedBytesPerUser,
+		ContentRetention:        constants.IngestionContentRetention,
+	}
+}
+
+// ingestionJob is the state of a job tracked by the manager. All fields are guarded by the manager mutex,
+// except params which is only used by the worker running the job, or by the manager while the job is not running.
+type ingestionJob struct {
+	IngestionJob
+	params        IngestionJobParams
+	client        LlamaStackClientInterface // Client of the user who submitted or last retried the job
+	attached      bool                      // Whether the files were added to the vector store
+	running       bool                      // Whether a worker is working on the job
+	bufferedBytes int64                     // Size of the uploaded documents kept in memory
+	discarded     bool                      // Whether the uploaded documents were discarded before the job completed
+	cancel        context.CancelFunc
+	watchers      map[chan struct{}]struct{}
+}
+
+// IngestionManager uploads documents and adds them to vector stores in the background with a bounded pool of
+// workers, and keeps track of their progress. Jobs are kept in memory and scoped by namespace and owner.
+type IngestionManager struct {
+	config IngestionManagerConfig
+	logger *slog.Logger
+	queue  chan *ingestionJob
+
+	mu             sync.Mutex
+	jobs           map[string]*ingestionJob
+	buffered       int64            // Size of the uploaded documents kept in memory by all jobs
+	bufferedByUser map[string]int64 // Size of the uploaded documents kept in memory by the jobs of a user
+
+	// ctx is the parent of the job contexts and is cancelled when the manager is closed
+	ctx       context.Context
+	cancelAll context.CancelFunc
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewIngestionManager creates an ingestion manager. Its workers are started with the first job.
+func NewIngestionManager(config IngestionManagerConfig, logger *slog.Logger) *IngestionManager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	ctx, cancelAll := context.WithCancel(context.Background())
+	return &IngestionManager{
+		config:         config,
+		logger:         logger,
+		queue:          make(chan *ingestionJob, config.QueueSize),
+		jobs:           make(map[string]*ingestionJob),
+		bufferedByUser: make(map[string]int64),
+		ctx:            ctx,
+		cancelAll:      cancelAll,
+	}
+}
+
+// Submit queues documents for ingestion with the given client and returns the queued job
+func (m *IngestionManager) Submit(client LlamaStackClientInterface, params IngestionJobParams) (*IngestionJob, error) {
+	if params.VectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if len(params.Documents) == 0 {
+		return nil, fmt.Errorf("at least one document is required")
+	}
+	for _, document := range params.Documents {
+		if document.Filename == "" {
+			return nil, fmt.Errorf("filename is required")
+		}
+		if err := ValidateFileAttributes(document.attributes(params.Attributes)); err != nil {
+			return nil, err
+		}
+	}
+	if params.Filename == "" {
+		params.Filename = params.Documents[0].Filename
+	}
+	m.startOnce.Do(m.startWorkers)
+
+	job := &ingestionJob{
+		IngestionJob: IngestionJob{
+			ID:            uuid.NewString(),
+			Namespace:     params.Namespace,
+			Owner:         params.Owner,
+			VectorStoreID: params.VectorStoreID,
+			Filename:      params.Filename,
+			Attributes:    params.Attributes,
+			Status:        IngestionJobQueued,
+			CreatedAt:     time.Now().Unix(),
+		},
+		params:   params,
+		client:   client,
+		watchers: make(map[chan struct{}]struct{}),
+	}
+	if len(params.Documents) == 1 && params.Documents[0].Source != nil {
+		job.SourceURI = params.Documents[0].Source.URI()
+	}
+	for _, document := range params.Documents {
+		job.SizeBytes += int64(len(document.Content))
+		job.bufferedBytes += int64(len(document.Content))
+		if len(params.Documents) > 1 {
+			job.Files = append(job.Files, IngestionJobFile{
+				Filename:  document.Filename,
+				SizeBytes: int64(len(document.Content)),
+				Status:    IngestionJobQueued,
+			})
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneLocked(time.Now())
+
+	user := ingestionUser(job.Namespace, job.Owner)
+	if (m.config.MaxBufferedBytes > 0 && m.buffered+job.bufferedBytes > m.config.MaxBufferedBytes) ||
+		(m.config.MaxBufferedBytesPerUser > 0 && m.bufferedByUser[user]+job.bufferedBytes > m.config.MaxBufferedBytesPerUser) {
+		return nil, ErrIngestionBufferFull
+	}
+
+	select {
+	case m.queue <- job:
+	default:
+		return nil, ErrIngestionQueueFull
+	}
+	m.jobs[job.ID] = job
+	if job.bufferedBytes > 0 {
+		m.buffered += job.bufferedBytes
+		m.bufferedByUser[user] += job.bufferedBytes
+	}
+	return job.snapshot(), nil
+}
+
+// Get returns the current state of a job of the given owner
+func (m *IngestionManager) Get(namespace, owner, id string) (*IngestionJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.lookupLocked(namespace, owner, id)
+	if err != nil {
+		return nil, err
+	}
+	return job.snapshot(), nil
+}
+
+// PendingDocuments returns how many documents of the unfinished jobs of the namespace were not added to their
+// vector store yet. Documents that were added are counted by the vector store itself.
+func (m *IngestionManager) PendingDocuments(namespace string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pending := 0
+	for _, job := range m.jobs {
+		if job.Namespace != namespace || job.Finished() || job.attached {
+			continue
+		}
+		pending += max(len(job.Files), 1)
+	}
+	return pending
+}
+
+// Watch returns a channel that receives a value whenever the job changes, and a function to stop watching.
+// Changes are coalesced, so receivers should read the latest state with Get.
+func (m *IngestionManager) Watch(namespace, owner, id string) (<-chan struct{}, func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.lookupLocked(namespace, owner, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes := make(chan struct{}, 1)
+	job.watchers[changes] = struct{}{}
+
+	unwatch := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		delete(job.watchers, changes)
+	}
+	return changes, unwatch, nil
+}
+
+// Cancel stops a job that has not finished. Documents that were already uploaded are removed again.
+func (m *IngestionManager) Cancel(namespace, owner, id string) (*IngestionJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.lookupLocked(namespace, owner, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Finished() {
+		return nil, ErrIngestionJobFinished
+	}
+
+	// A running job is cleaned up by its worker once the cancellation reaches it
+	if job.running {
+		job.cancel()
+	} else if fileIDs := job.uploadedFileIDs(); len(fileIDs) > 0 {
+		go m.removeFiles(job.client, job.VectorStoreID, "", fileIDs, job.attached)
+		job.forgetUploadsLocked()
+	}
+	job.Status = IngestionJobCancelled
+	for i := range job.Files {
+		if job.Files[i].Status != IngestionJobCompleted {
+			job.Files[i].Status = IngestionJobCancelled
+		}
+	}
+	job.FinishedAt = time.Now().Unix()
+	m.notifyLocked(job)
+
+	return job.snapshot(), nil
+}
+
+// Retry queues a failed or cancelled job again with the given client. Documents that were already uploaded
+// are not uploaded again, and documents of a batch that were processed are not processed again.
+func (m *IngestionManager) Retry(client LlamaStackClientInterface, namespace, owner, id string) (*IngestionJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, err := m.lookupLocked(namespace, owner, id)
+	if err != nil {
+		return nil, err
+	}
+	if (job.Status != IngestionJobFailed && job.Status != IngestionJobCancelled) || job.running {
+		return nil, ErrIngestionJobNotRetryable
+	}
+	if job.discarded && job.needsUploadLocked() {
+		return nil, ErrIngestionJobExpired
+	}
+
+	select {
+	case m.queue <- job:
+	default:
+		return nil, ErrIngestionQueueFull
+	}
+	job.client = client
+	job.Status = IngestionJobQueued
+	job.Error = ""
+	job.ChunkCount = 0
+	job.UsageBytes = 0
+	job.FinishedAt = 0
+	for i := range job.Files {
+		if job.Files[i].Status != IngestionJobCompleted {
+			job.Files[i].Status = IngestionJobQueued
+			job.Files[i].Error = ""
+		}
+	}
+	m.notifyLocked(job)
+
+	return job.snapshot(), nil
+}
+
+// Close stops the workers. Running jobs are interrupted and reported as failed.
+func (m *IngestionManager) Close() error {
+	m.stopOnce.Do(func() {
+		m.cancelAll()
+		m.wg.Wait()
+	})
+	return nil
+}
+
+func (m *IngestionManager) startWorkers() {
+	// Finished jobs and the documents of failed jobs are also forgotten when no new jobs are submitted
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(constants.IngestionPruneInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+			case now := <-ticker.C:
+				m.mu.Lock()
+				m.pruneLocked(now)
+				m.mu.Unlock()
+			}
+		}
+	}()
+
+	for i := 0; i < m.config.Workers; i++ {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			for {
+				select {
+				case <-m.ctx.Done():
+					return
+				case job := <-m.queue:
+					m.run(job)
+				}
+			}
+		}()
+	}
+}
+
+// run performs one attempt of a job. Jobs that were cancelled while queued are skipped.
+func (m *IngestionManager) run(job *ingestionJob) {
+	m.mu.Lock()
+	if job.Status != IngestionJobQueued || job.running || m.ctx.Err() != nil {
+		m.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithTimeout(m.ctx, m.config.Timeout)
+	defer cancel()
+
+	job.running = true
+	job.cancel = cancel
+	job.Attempts++
+	job.StartedAt = time.Now().Unix()
+	job.Status = IngestionJobUploading
+	batch := len(job.Files) > 0
+	m.notifyLocked(job)
+	m.mu.Unlock()
+
+	var err error
+	if batch {
+		err = m.ingestBatch(ctx, job)
+	} else {
+		err = m.ingest(ctx, job)
+	}
+	m.finish(job, err)
+}
+
+// ingest uploads a single document when needed, adds it to the vector store and waits until it is processed
+func (m *IngestionManager) ingest(ctx context.Context, job *ingestionJob) error {
+	m.mu.Lock()
+	client, fileID, attached := job.client, job.FileID, job.attached
+	if fileID != "" {
+		job.setStatusLocked(IngestionJobProcessing)
+		m.notifyLocked(job)
+	}
+	m.mu.Unlock()
+	params := job.params
+
+	if fileID == "" {
+		result, err := client.UploadFile(ctx, params.Documents[0].uploadParams(params.Purpose))
+		if err != nil {
+			return err
+		}
+		fileID = result.FileID
+		m.update(job, func(j *ingestionJob) {
+			if j.SourceURI != "" {
+				// The size of a remote document is only known once it was streamed
+				j.SizeBytes = result.Bytes
+			}
+			j.FileID = fileID
+			j.setStatusLocked(IngestionJobProcessing)
+		})
+	} else if attached {
+		// A previous attempt failed during processing, so the file is added to the vector store again
+		if err := client.DeleteVectorStoreFile(ctx, params.VectorStoreID, fileID); err != nil {
+			m.logger.Debug("Failed to remove vector store file before retrying", "error", err, "file_id", fileID)
+		}
+	}
+
+	vectorStoreFile, err := client.AddFileToVectorStore(ctx, params.VectorStoreID, fileID, params.ChunkingStrategy, params.Documents[0].attributes(params.Attributes))
+	if err != nil {
+		return err
+	}
+	m.update(job, func(j *ingestionJob) {
+		j.attached = true
+	})
+
+	for vectorStoreFile.Status == openai.VectorStoreFileStatusInProgress {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.config.PollInterval):
+		}
+
+		vectorStoreFile, err = client.GetVectorStoreFile(ctx, params.VectorStoreID, fileID)
+		if err != nil {
+			return err
+		}
+		usageBytes := vectorStoreFile.UsageBytes
+		m.update(job, func(j *ingestionJob) {
+			j.UsageBytes = usageBytes
+		})
+	}
+
+	if vectorStoreFile.Status != openai.VectorStoreFileStatusCompleted {
+		return vectorStoreFileError(vectorStoreFile)
+	}
+
+	// The chunk count is informative, so failing to read it does not fail the job
+	chunks, err := client.ListVectorStoreFileContent(ctx, params.VectorStoreID, fileID)
+	if err != nil {
+		m.logger.Debug("Failed to count vector store file chunks", "error", err, "file_id", fileID)
+	}
+	usageBytes := vectorStoreFile.UsageBytes
+	m.update(job, func(j *ingestionJob) {
+		j.ChunkCount = len(chunks)
+		j.UsageBytes = usageBytes
+	})
+	return nil
+}
+
+// ingestBatch uploads the documents that were not uploaded yet, adds the documents that were not processed yet to
+// the vector store as one file batch and waits until the batch is processed. Documents that fail are reported
+// with their error, and the attempt fails when any document failed.
+func (m *IngestionManager) ingestBatch(ctx context.Context, job *ingestionJob) error {
+	m.mu.Lock()
+	client, attached := job.client, job.attached
+	files := append([]IngestionJobFile(nil), job.Files...)
+	m.mu.Unlock()
+	params := job.params
+
+	var pending []int
+	for i, file := range files {
+		if file.Status == IngestionJobCompleted {
+			continue
+		}
+		if file.FileID != "" {
+			// A previous attempt failed during processing, so the file is added to the vector store again
+			if attached {
+				if err := client.DeleteVectorStoreFile(ctx, params.VectorStoreID, file.FileID); err != nil {
+					m.logger.Debug("Failed to remove vector store file before retrying", "error", err, "file_id", file.FileID)
+				}
+			}
+			pending = append(pending, i)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		m.updateFile(job, i, func(f *IngestionJobFile) { f.Status = IngestionJobUploading })
+		result, err := client.UploadFile(ctx, params.Documents[i].uploadParams(params.Purpose))
+		if err != nil {
+			m.updateFile(job, i, func(f *IngestionJobFile) { f.Status, f.Error = IngestionJobFailed, err.Error() })
+			continue
+		}
+		files[i].FileID = result.FileID
+		m.updateFile(job, i, func(f *IngestionJobFile) { f.Status, f.FileID = IngestionJobProcessing, result.FileID })
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return errors.New("none of the files could be uploaded")
+	}
+
+	fileIDs := make([]string, 0, len(pending))
+	for _, i := range pending {
+		fileIDs = append(fileIDs, files[i].FileID)
+	}
+	m.update(job, func(j *ingestionJob) {
+		j.setStatusLocked(IngestionJobProcessing)
+		for _, i := range pending {
+			j.Files[i].Status = IngestionJobProcessing
+		}
+	})
+
+	batch, err := client.CreateVectorStoreFileBatch(ctx, params.VectorStoreID, fileIDs, params.ChunkingStrategy, params.Attributes)
+	if err != nil {
+		return err
+	}
+	m.update(job, func(j *ingestionJob) {
+		j.BatchID = batch.ID
+		j.attached = true
+	})
+
+	for batch.Status == openai.VectorStoreFileBatchStatusInProgress {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.config.PollInterval):
+		}
+
+		batch, err = client.GetVectorStoreFileBatch(ctx, params.VectorStoreID, batch.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Report the outcome of each file of the batch
+	for _, i := range pending {
+		fileID := files[i].FileID
+		vectorStoreFile, err := client.GetVectorStoreFile(ctx, params.VectorStoreID, fileID)
+		if err != nil {
+			m.updateFile(job, i, func(f *IngestionJobFile) { f.Status, f.Error = IngestionJobFailed, err.Error() })
+			continue
+		}
+		if vectorStoreFile.Status != openai.VectorStoreFileStatusCompleted {
+			fileErr := vectorStoreFileError(vectorStoreFile)
+			m.updateFile(job, i, func(f *IngestionJobFile) { f.Status, f.Error = IngestionJobFailed, fileErr.Error() })
+			continue
+		}
+
+		// The chunk count is informative, so failing to read it does not fail the file
+		chunks, err := client.ListVectorStoreFileContent(ctx, params.VectorStoreID, fileID)
+		if err != nil {
+			m.logger.Debug("Failed to count vector store file chunks", "error", err, "file_id", fileID)
+		}
+		usageBytes := vectorStoreFile.UsageBytes
+		m.updateFile(job, i, func(f *IngestionJobFile) {
+			f.Status = IngestionJobCompleted
+			f.ChunkCount = len(chunks)
+			f.UsageBytes = usageBytes
+		})
+	}
+
+	failed := 0
+	m.update(job, func(j *ingestionJob) {
+		j.ChunkCount, j.UsageBytes = 0, 0
+		for _, file := range j.Files {
+			j.ChunkCount += file.ChunkCount
+			j.UsageBytes += file.UsageBytes
+			if file.Status == IngestionJobFailed {
+				failed++
+			}
+		}
+	})
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, len(files))
+	}
+	return nil
+}
+
+// vectorStoreFileError describes why a vector store file was not processed
+func vectorStoreFileError(vectorStoreFile *openai.VectorStoreFile) error {
+	if vectorStoreFile.Status == openai.VectorStoreFileStatusFailed {
+		if vectorStoreFile.LastError.Message != "" {
+			return fmt.Errorf("vector store failed to process file: %s", vectorStoreFile.LastError.Message)
+		}
+		return errors.New("vector store failed to process file")
+	}
+	return fmt.Errorf("vector store file processing ended with status %s", vectorStoreFile.Status)
+}
+
+// finish records the outcome of an attempt. Cancelled jobs have their uploaded documents removed.
+func (m *IngestionManager) finish(job *ingestionJob, err error) {
+	m.mu.Lock()
+	job.cancel = nil
+
+	if job.Status == IngestionJobCancelled {
+		client, batchID, fileIDs, attached := job.client, job.BatchID, job.uploadedFileIDs(), job.attached
+		m.mu.Unlock()
+
+		if len(fileIDs) > 0 {
+			m.removeFiles(client, job.params.VectorStoreID, batchID, fileIDs, attached)
+		}
+		m.update(job, func(j *ingestionJob) {
+			j.forgetUploadsLocked()
+			j.running = false
+		})
+		return
+	}
+	defer m.mu.Unlock()
+
+	switch {
+	case err == nil:
+		job.Status = IngestionJobCompleted
+		job.params.Documents = nil
+		m.releaseLocked(job)
+	case errors.Is(err, context.DeadlineExceeded):
+		job.Status = IngestionJobFailed
+		job.Error = fmt.Sprintf("ingestion did not finish within %s", m.config.Timeout)
+	case errors.Is(err, context.Canceled):
+		job.Status = IngestionJobFailed
+		job.Error = "ingestion was interrupted"
+	default:
+		job.Status = IngestionJobFailed
+		job.Error = err.Error()
+	}
+	if err != nil {
+		m.logger.Warn("Document ingestion failed", "error", err, "job_id", job.ID, "vector_store_id", job.VectorStoreID)
+	}
+	job.FinishedAt = time.Now().Unix()
+	job.running = false
+	m.notifyLocked(job)
+}
+
+// removeFiles removes the documents of a cancelled job from the vector store and the file storage.
+// The processing of a file batch that is still running is cancelled first.
+func (m *IngestionManager) removeFiles(client LlamaStackClientInterface, vectorStoreID, batchID string, fileIDs []string, attached bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if attached && batchID != "" {
+		if err := client.CancelVectorStoreFileBatch(ctx, vectorStoreID, batchID); err != nil {
+			m.logger.Debug("Failed to cancel file batch of cancelled ingestion job", "error", err, "batch_id", batchID)
+		}
+	}
+	for _, fileID := range fileIDs {
+		if attached {
+			if err := client.DeleteVectorStoreFile(ctx, vectorStoreID, fileID); err != nil {
+				m.logger.Warn("Failed to remove file of cancelled ingestion job from vector store", "error", err, "file_id", fileID)
+			}
+		}
+		if err := client.DeleteFile(ctx, fileID); err != nil {
+			m.logger.Warn("Failed to delete file of cancelled ingestion job", "error", err, "file_id", fileID)
+		}
+	}
+}
+
+// update changes a job and notifies its watchers
+func (m *IngestionManager) update(job *ingestionJob, change func(*ingestionJob)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	change(job)
+	m.notifyLocked(job)
+}
+
+// updateFile changes a document of a job with several documents and notifies the watchers of the job
+func (m *IngestionManager) updateFile(job *ingestionJob, index int, change func(*IngestionJobFile)) {
+	m.update(job, func(j *ingestionJob) {
+		change(&j.Files[index])
+	})
+}
+
+// snapshot returns a copy of the job state that is not changed by the workers
+func (j *ingestionJob) snapshot() *IngestionJob {
+	snapshot := j.IngestionJob
+	snapshot.Files = append([]IngestionJobFile(nil), j.Files...)
+	return &snapshot
+}
+
+// setStatusLocked changes the status of a running job unless it was cancelled meanwhile
+func (j *ingestionJob) setStatusLocked(status string) {
+	if j.Status != IngestionJobCancelled {
+		j.Status = status
+	}
+}
+
+// uploadedFileIDs returns the IDs of the uploaded documents of the job
+func (j *ingestionJob) uploadedFileIDs() []string {
+	if j.FileID != "" {
+		return []string{j.FileID}
+	}
+	var fileIDs []string
+	for _, file := range j.Files {
+		if file.FileID != "" {
+			fileIDs = append(fileIDs, file.FileID)
+		}
+	}
+	return fileIDs
+}
+
+// forgetUploadsLocked clears the uploads of a job whose documents were removed, so a retry uploads them again
+func (j *ingestionJob) forgetUploadsLocked() {
+	j.FileID = ""
+	j.BatchID = ""
+	j.attached = false
+	for i := range j.Files {
+		j.Files[i].FileID = ""
+		j.Files[i].Status = IngestionJobCancelled
+		j.Files[i].ChunkCount = 0
+		j.Files[i].UsageBytes = 0
+	}
+}
+
+func (m *IngestionManager) notifyLocked(job *ingestionJob) {
+	for changes := range job.watchers {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (m *IngestionManager) lookupLocked(namespace, owner, id string) (*ingestionJob, error) {
+	job, ok := m.jobs[id]
+	if !ok || job.Namespace != namespace || job.Owner != owner {
+		return nil, ErrIngestionJobNotFound
+	}
+	return job, nil
+}
+
+// pruneLocked forgets jobs that finished longer ago than the retention, and discards the uploaded documents of
+// jobs that failed or were cancelled longer ago than the content retention
+func (m *IngestionManager) pruneLocked(now time.Time) {
+	cutoff := now.Add(-m.config.Retention).Unix()
+	contentCutoff := now.Add(-m.config.ContentRetention).Unix()
+	for id, job := range m.jobs {
+		if !job.Finished() || job.running {
+			continue
+		}
+		if job.FinishedAt < cutoff {
+			m.releaseLocked(job)
+			delete(m.jobs, id)
+		} else if m.config.ContentRetention > 0 && job.bufferedBytes > 0 && job.FinishedAt < contentCutoff {
+			m.releaseLocked(job)
+			job.discarded = true
+			for i := range job.params.Documents {
+				job.params.Documents[i].Content = nil
+			}
+		}
+	}
+}
+
+// releaseLocked stops accounting for the uploaded documents of a job that are no longer kept
+func (m *IngestionManager) releaseLocked(job *ingestionJob) {
+	if job.bufferedBytes == 0 {
+		return
+	}
+	user := ingestionUser(job.Namespace, job.Owner)
+	m.buffered -= job.bufferedBytes
+	if m.bufferedByUser[user] -= job.bufferedBytes; m.bufferedByUser[user] <= 0 {
+		delete(m.bufferedByUser, user)
+	}
+	job.bufferedBytes = 0
+}
+
+// needsUploadLocked reports whether a retry of the job would upload a document that is not read from a source
+func (j *ingestionJob) needsUploadLocked() bool {
+	if len(j.Files) == 0 {
+		return j.FileID == "" && j.params.Documents[0].Source == nil
+	}
+	for i, file := range j.Files {
+		if file.Status != IngestionJobCompleted && file.FileID == "" && j.params.Documents[i].Source == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ingestionUser identifies a user of a namespace for the memory limits
+func ingestionUser(namespace, owner string) string {
+	return namespace + "/" + owner
+}
@@ -0,0 +1,24 @@
+package llamastack
+
+import (
+	"context"
+	"io"
+)
+
+// DocumentSource provides a document that is ingested from remote storage, such as an object in S3-compatible
+// storage or a web page. It is opened for every upload attempt, so its content is streamed instead of being kept
+// in memory.
+type DocumentSource interface {
+	// URI identifies the document, it is recorded in the attributes of the vector store file
+	URI() string
+	// Filename is the name the document is uploaded with
+	Filename() string
+	// Open starts reading the document
+	Open(ctx context.Context) (*SourceDocument, error)
+}
+
+// SourceDocument is an opened document of a DocumentSource
+type SourceDocument struct {
+	Body        io.ReadCloser
+	ContentType string // Empty when the source does not know it
+}
@@ -0,0 +1,543 @@
+package llamastack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go/v2"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIngestionClient serves the file operations of an ingestion job. Processing of an attached file
+// ends with the status held by processing, or waits until the request context ends when block is set.
+// Files of a batch whose name is listed in failing fail to process, and uploads fail with uploadErr when set.
+type fakeIngestionClient struct {
+	LlamaStackClientInterface
+
+	mu           sync.Mutex
+	uploadErr    error
+	processing   openai.VectorStoreFileStatus
+	lastError    string
+	block        bool
+	failing      map[string]bool
+	uploads      int
+	uploaded     []byte
+	fileNames    map[string]string
+	attachments  int
+	attributes   map[string]interface{}
+	batches      [][]string
+	deletedFiles []string
+}
+
+func (c *fakeIngestionClient) UploadFile(ctx context.Context, params UploadFileParams) (*FileUploadResult, error) {
+	c.mu.Lock()
+	uploadErr := c.uploadErr
+	c.mu.Unlock()
+	if uploadErr != nil {
+		return nil, uploadErr
+	}
+
+	reader := params.Reader
+	if params.Source != nil {
+		document, err := params.Source.Open(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer document.Body.Close()
+		reader = document.Body
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.uploads++
+	c.uploaded = content
+	if c.fileNames == nil {
+		// Single documents keep a fixed ID, the documents of a batch are told apart by their name
+		return &FileUploadResult{FileID: "file-ingested", Bytes: int64(len(content))}, nil
+	}
+	fileID := fmt.Sprintf("file-%d", c.uploads)
+	c.fileNames[fileID] = params.Filename
+	return &FileUploadResult{FileID: fileID}, nil
+}
+
+func (c *fakeIngestionClient) CreateVectorStoreFileBatch(ctx context.Context, vectorStoreID string, fileIDs []string, chunkingStrategy *ChunkingStrategy, attributes map[string]interface{}) (*openai.VectorStoreFileBatch, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.batches = append(c.batches, append([]string(nil), fileIDs...))
+	c.attributes = attributes
+	return &openai.VectorStoreFileBatch{ID: fmt.Sprintf("vsfb-%d", len(c.batches)), VectorStoreID: vectorStoreID, Status: openai.VectorStoreFileBatchStatusInProgress}, nil
+}
+
+func (c *fakeIngestionClient) GetVectorStoreFileBatch(ctx context.Context, vectorStoreID, batchID string) (*openai.VectorStoreFileBatch, error) {
+	c.mu.Lock()
+	block := c.block
+	c.mu.Unlock()
+
+	if block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &openai.VectorStoreFileBatch{ID: batchID, VectorStoreID: vectorStoreID, Status: openai.VectorStoreFileBatchStatusCompleted}, nil
+}
+
+func (c *fakeIngestionClient) CancelVectorStoreFileBatch(ctx context.Context, vectorStoreID, batchID string) error {
+	return nil
+}
+
+func (c *fakeIngestionClient) AddFileToVectorStore(ctx context.Context, vectorStoreID, fileID string, chunkingStrategy *ChunkingStrategy, attributes map[string]interface{}) (*openai.VectorStoreFile, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attachments++
+	c.attributes = attributes
+	return &openai.VectorStoreFile{ID: fileID, VectorStoreID: vectorStoreID, Status: openai.VectorStoreFileStatusInProgress}, nil
+}
+
+func (c *fakeIngestionClient) GetVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) (*openai.VectorStoreFile, error) {
+	c.mu.Lock()
+	block, status, lastError := c.block, c.processing, c.lastError
+	if c.failing[c.fileNames[fileID]] {
+		status, lastError = openai.VectorStoreFileStatusFailed, "unsupported file format"
+	}
+	c.mu.Unlock()
+
+	if block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &openai.VectorStoreFile{
+		ID:            fileID,
+		VectorStoreID: vectorStoreID,
+		Status:        status,
+		UsageBytes:    1024,
+		LastError:     openai.VectorStoreFileLastError{Message: lastError},
+	}, nil
+}
+
+func (c *fakeIngestionClient) ListVectorStoreFileContent(ctx context.Context, vectorStoreID, fileID string) ([]openai.VectorStoreFileContentResponse, error) {
+	return []openai.VectorStoreFileContentResponse{{Type: "text", Text: "first"}, {Type: "text", Text: "second"}}, nil
+}
+
+func (c *fakeIngestionClient) DeleteVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) error {
+	return nil
+}
+
+func (c *fakeIngestionClient) DeleteFile(ctx context.Context, fileID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deletedFiles = append(c.deletedFiles, fileID)
+	return nil
+}
+
+func (c *fakeIngestionClient) set(change func(*fakeIngestionClient)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	change(c)
+}
+
+func (c *fakeIngestionClient) counts() (uploads, attachments int, deletedFiles []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.uploads, c.attachments, append([]string(nil), c.deletedFiles...)
+}
+
+func (c *fakeIngestionClient) batchedFiles() [][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]string(nil), c.batches...)
+}
+
+// fakeDocumentSource serves a document from memory and counts how often it was opened
+type fakeDocumentSource struct {
+	content string
+	opened  int
+}
+
+func (s *fakeDocumentSource) URI() string      { return "s3://docs/guides/install.md" }
+func (s *fakeDocumentSource) Filename() string { return "install.md" }
+
+func (s *fakeDocumentSource) Open(ctx context.Context) (*SourceDocument, error) {
+	s.opened++
+	return &SourceDocument{Body: io.NopCloser(strings.NewReader(s.content)), ContentType: "text/markdown"}, nil
+}
+
+func newTestIngestionManager(t *testing.T) *IngestionManager {
+	manager := NewIngestionManager(IngestionManagerConfig{
+		Workers:      2,
+		QueueSize:    4,
+		PollInterval: time.Millisecond,
+		Timeout:      time.Minute,
+		Retention:    time.Hour,
+	}, nil)
+	t.Cleanup(func() { _ = manager.Close() })
+	return manager
+}
+
+func testIngestionJobParams() IngestionJobParams {
+	return IngestionJobParams{
+		Namespace:     "test-namespace",
+		Owner:         "mockUser",
+		VectorStoreID: "vs_test123",
+		Documents:     []IngestionDocument{{Filename: "guide.pdf", Content: []byte("document content")}},
+	}
+}
+
+func testIngestionBatchParams() IngestionJobParams {
+	params := testIngestionJobParams()
+	params.Filename = "docs.zip"
+	params.Documents = []IngestionDocument{
+		{Filename: "intro.md", Content: []byte("intro")},
+		{Filename: "setup.md", Content: []byte("setup guide")},
+		{Filename: "broken.bin", Content: []byte("binary")},
+	}
+	return params
+}
+
+// waitForIngestionJob waits until the job reaches the given status
+func waitForIngestionJob(t *testing.T, manager *IngestionManager, id, status string) *IngestionJob {
+	var job *IngestionJob
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = manager.Get("test-namespace", "mockUser", id)
+		require.NoError(t, err)
+		return job.Status == status
+	}, 5*time.Second, time.Millisecond, "job did not reach status %s", status)
+	return job
+}
+
+func TestIngestionManager(t *testing.T) {
+	t.Run("should upload, attach and wait until the file is processed", func(t *testing.T) {
+		manager := newTestIngestionManager(t)
+		client := &fakeIngestionClient{processing: openai.VectorStoreFileStatusCompleted}
+
+		job, err := manager.Submit(client, testIngestionJobParams())
+		require.NoError(t, err)
+		assert.NotEmpty(t, job.ID)
+		assert.Equal(t, IngestionJobQueued, job.Status)
+		assert.Equal(t, int64(len("document content")), job.SizeBytes)
+		assert.Equal(t, "guide.pdf", job.Filename)
+		assert.Empty(t, job.Files)
+
+		job = waitForIngestionJob(t, manager, job.ID, IngestionJobCompleted)
+		assert.Equal(t, "file-ingested", job.FileID)
+		assert.Equal(t, 2, job.ChunkCount)
+		assert.Equal(t, int64(1024), job.UsageBytes)
+		assert.Equal(t, 1, job.Attempts)
+		assert.Empty(t, job.Error)
+		assert.NotZero(t, job.FinishedAt)
+		assert.Equal(t, []byte("document content"), client.uploaded)
+	})
+
+	t.Run("should report processing failures and retry without uploading again", func(t *testing.T) {
+		manager := newTestIngestionManager(t)
+		client := &fakeIngestionClient{processing: openai.VectorStoreFileStatusFailed, lastError: "unsupported file format"}
+
+		job, err := manager.Submit(client, testIngestionJobParams())
+		require.NoError(t, err)
+
+		job = waitForIngestionJob(t, manager, job.ID, IngestionJobFailed)
+		assert.Contains(t, job.Error, "unsupported file format")
+
+		client.set(func(c *fakeIngestionClient) { c.processing = openai.VectorStoreFileStatusCompleted })
+		retried, err := manager.Retry(client, "test-namespace", "mockUser", job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, IngestionJobQueued, retried.Status)
+		assert.Empty(t, retried.Error)
+
+		job = waitForIngestionJob(t, manager, job.ID, IngestionJobCompleted)
+		assert.Equal(t, 2, job.Attempts)
+		uploads, attachments, _ := client.counts()
+		assert.Equal(t, 1, uploads)
+		assert.Equal(t, 2, attachments)
+	})
+
+	t.Run("should cancel a running job and remove its document", func(t *testing.T) {
+		manager := newTestIngestionManager(t)
+		client := &fakeIngestionClient{block: true}
+
+		job, err := manager.Submit(client, testIngestionJobParams())
+		require.NoError(t, err)
+		waitForIngestionJob(t, manager, job.ID, IngestionJobProcessing)
+
+		cancelled, err := manager.Cancel("test-namespace", "mockUser", job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, IngestionJobCancelled, cancelled.Status)
+
+		require.Eventually(t, func() bool {
+			_, _, deletedFiles := client.counts()
+			return len(deletedFiles) == 1
+		}, 5*time.Second, time.Millisecond)
+		_, _, deletedFiles := client.counts()
+		assert.Equal(t, []string{"file-ingested"}, deletedFiles)
+
+		_, err = manager.Cancel("test-namespace", "mockUser", job.ID)
+		assert.ErrorIs(t, err, ErrIngestionJobFinished)
+	})
+
+	t.Run("should not retry jobs that did not fail", func(t *testing.T) {
+		manager := newTestIngestionManager(t)
+		client := &fakeIngestionClient{processing: openai.VectorStoreFileStatusCompleted}
+
+		job, err := manager.Submit(client, testIngestionJobParams())
+		require.NoError(t, err)
+		waitForIngestionJob(t, manager, job.ID, IngestionJobCompleted)
+
+		_, err = manager.Retry(client, "test-namespace", "mockUser", job.ID)
+		assert.ErrorIs(t, err, ErrIngestionJobNotRetryable)
+	})
+
+	t.Run("should only show jobs to their owner", func(t *testing.T) {
+		manager := newTestIngestionManager(t)
+		client := &fakeIngestionClient{processing: openai.VectorStoreFileStatusCompleted}
+
+		job, err := manager.Submit(client, testIngestionJobParams())
+		require.NoError(t, err)
+
+		_, err = manager.Get("test-namespace", "otherUser", job.ID)
+		assert.ErrorIs(t, err, ErrIngestionJobNotFound)
+		_, err = manager.Get("other-namespace", "mockUser", job.ID)
+		assert.ErrorIs(t, err, ErrIngestionJobNotFound)
+		_, err = manager.Cancel("test-namespace", "otherUser", job.ID)
+		assert.ErrorIs(t, err, ErrIngestionJobNotFound)
+	})
+
+	t.Run("should refuse jobs when the queue is full", func(t *testing.T) {
+		manager := NewIngestionManager(IngestionManagerConfig{Workers: 1, QueueSize: 1, PollInterval: time.Millisecond, Timeout: time.Minute, Retention: time.Hour}, nil)
+		t.Cleanup(func() { _ = manager.Close() })
+		client := &fakeIngestionClient{block: true}
+
+		first, err := manager.Submit(client, testIngestionJobParams())
+		require.NoError(t, err)
+		waitForIngestionJob(t, manager, first.ID, IngestionJobProcessing)
+
+		_, err = manager.Submit(client, testIngestionJobParams())
+		require.NoError(t, err)
+		_, err = manager.Submit(client, testIngestionJobParams())
+		assert.True(t, errors.Is(err, ErrIngestionQueueFull))
+	})
+
+	t.Run("should count the documents that are not in a vector store yet as pending", func(t *testing.T) {
+		manager := NewIngestionManager(IngestionManagerConfig{Workers: 1, QueueSize: 2, PollInterval: time.Millisecond, Timeout: time.Minute, Retention: time.Hour}, nil)
+		t.Cleanup(func() { _ = manager.Close() })
+		client := &fakeIngestionClient{block: true}
+
+		// The attached document is counted by its vector store
+		first, err := manager.Submit(client, testIngestionJobParams())
+		require.NoError(t, err)
+		waitForIngestionJob(t, manager, first.ID, IngestionJobProcessing)
+		assert.Equal(t, 0, manager.PendingDocuments("test-namespace"))
+
+		_, err = manager.Submit(client, testIngestionBatchParams())
+		require.NoError(t, err)
+		assert.Equal(t, 3, manager.PendingDocuments("test-namespace"))
+		assert.Equal(t, 0, manager.PendingDocuments("other-namespace"))
+	})
+
+	t.Run("should refuse uploads beyond the memory limits until documents are ingested", func(t *testing.T) {
+		manager := NewIngestionManager(IngestionManagerConfig{
+			Workers: 1, QueueSize: 10, PollInterval: time.Millisecond, Timeout: time.Minute, Retention: time.Hour,
+			MaxBufferedBytes: 40, MaxBufferedBytesPerUser: 20,
+		}, nil)
+		t.Cleanup(func() { _ = manager.Close() })
+
+		// A completed job releases its documents
+		completed, err := manager.Submit(&fakeIngestionClient{processing: openai.VectorStoreFileStatusCompleted}, testIngestionJobParams())
+		require.NoError(t, err)
+		waitForIngestionJob(t, manager, completed.ID, IngestionJobCompleted)
+
+		// 16 bytes each, so a second document of the same user exceeds the per-user limit
+		client := &fakeIngestionClient{block: true}
+		_, err = manager.Submit(client, testIngestionJobParams())
+		require.NoError(t, err)
+		_, err = manager.Submit(client, testIngestionJobParams())
+		assert.ErrorIs(t, err, ErrIngestionBufferFull)
+
+		other := testIngestionJobParams()
+		other.Owner = "otherUser"
+		_, err = manager.Submit(client, other)
+		require.NoError(t, err)
+		other.Owner = "thirdUser"
+		_, err = manager.Submit(client, other)
+		assert.ErrorIs(t, err, ErrIngestionBufferFull)
+
+		// Documents read from a source are not kept in memory
+		other.Documents = []IngestionDocument{{Filename: "install.md", Source: &fakeDocumentSource{content: "# Install"}}}
+		_, err = manager.Submit(client, other)
+		require.NoError(t, err)
+	})
+
+	t.Run("should discard the documents of failed jobs after the content retention", func(t *testing.T) {
+		manager := NewIngestionManager(IngestionManagerConfig{
+			Workers: 1, QueueSize: 10, PollInterval: time.Millisecond, Timeout: time.Minute, Retention: time.Hour,
+			MaxBufferedBytesPerUser: 20, ContentRetention: time.Minute,
+		}, nil)
+		t.Cleanup(func() { _ = manager.Close() })
+		client := &fakeIngestionClient{uploadErr: errors.New("storage unavailable")}
+
+		job, err := manager.Submit(client, testIngestionJobParams())
+		require.NoError(t, err)
+		waitForIngestionJob(t, manager, job.ID, IngestionJobFailed)
+
+		// The failed job keeps its documents for a retry until the content retention passed
+		_, err = manager.Submit(client, testIngestionJobParams())
+		assert.ErrorIs(t, err, ErrIngestionBufferFull)
+
+		manager.mu.Lock()
+		manager.pruneLocked(time.Now().Add(2 * time.Minute))
+		manager.mu.Unlock()
+
+		_, err = manager.Retry(client, "test-namespace", "mockUser", job.ID)
+		assert.ErrorIs(t, err, ErrIngestionJobExpired)
+		_, err = manager.Submit(client, testIngestionJobParams())
+		assert.NoError(t, err)
+	})
+
+	t.Run("should stream documents from a source and record where they came from", func(t *testing.T) {
+		manager := newTestIngestionManager(t)
+		client := &fakeIngestionClient{processing: openai.VectorStoreFileStatusCompleted}
+		source := &fakeDocumentSource{content: "# Install"}
+
+		params := testIngestionJobParams()
+		params.Documents = []IngestionDocument{{Filename: source.Filename(), Source: source}}
+		params.Attributes = map[string]interface{}{"department": "engineering"}
+		job, err := manager.Submit(client, params)
+		require.NoError(t, err)
+		assert.Equal(t, "install.md", job.Filename)
+		assert.Equal(t, "s3://docs/guides/install.md", job.SourceURI)
+
+		job = waitForIngestionJob(t, manager, job.ID, IngestionJobCompleted)
+		assert.Equal(t, int64(len("# Install")), job.SizeBytes)
+		assert.Equal(t, []byte("# Install"), client.uploaded)
+		assert.Equal(t, map[string]interface{}{"department": "engineering", "source_uri": "s3://docs/guides/install.md"}, client.attributes)
+		assert.Equal(t, 1, source.opened)
+	})
+
+	t.Run("should add several documents as one file batch and report each file", func(t *testing.T) {
+		manager := newTestIngestionManager(t)
+		client := &fakeIngestionClient{
+			processing: openai.VectorStoreFileStatusCompleted,
+			fileNames:  map[string]string{},
+			failing:    map[string]bool{"broken.bin": true},
+		}
+
+		job, err := manager.Submit(client, testIngestionBatchParams())
+		require.NoError(t, err)
+		assert.Equal(t, "docs.zip", job.Filename)
+		assert.Equal(t, int64(len("intro")+len("setup guide")+len("binary")), job.SizeBytes)
+		require.Len(t, job.Files, 3)
+		assert.Equal(t, IngestionJobQueued, job.Files[0].Status)
+
+		job = waitForIngestionJob(t, manager, job.ID, IngestionJobFailed)
+		assert.Equal(t, "1 of 3 files failed", job.Error)
+		assert.Equal(t, "vsfb-1", job.BatchID)
+		assert.Equal(t, IngestionJobCompleted, job.Files[0].Status)
+		assert.Equal(t, IngestionJobCompleted, job.Files[1].Status)
+		assert.Equal(t, IngestionJobFailed, job.Files[2].Status)
+		assert.Contains(t, job.Files[2].Error, "unsupported file format")
+		assert.Equal(t, 4, job.ChunkCount)
+		assert.Equal(t, int64(2048), job.UsageBytes)
+		assert.Equal(t, [][]string{{"file-1", "file-2", "file-3"}}, client.batchedFiles())
+
+		// Only the failed file is processed again, without uploading it again
+		client.set(func(c *fakeIngestionClient) { c.failing = nil })
+		retried, err := manager.Retry(client, "test-namespace", "mockUser", job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, IngestionJobQueued, retried.Files[2].Status)
+		assert.Empty(t, retried.Files[2].Error)
+		assert.Equal(t, IngestionJobCompleted, retried.Files[0].Status)
+
+		job = waitForIngestionJob(t, manager, job.ID, IngestionJobCompleted)
+		assert.Equal(t, 6, job.ChunkCount)
+		assert.Equal(t, [][]string{{"file-1", "file-2", "file-3"}, {"file-3"}}, client.batchedFiles())
+		uploads, _, _ := client.counts()
+		assert.Equal(t, 3, uploads)
+	})
+
+	t.Run("should set the attributes on every document of a batch", func(t *testing.T) {
+		manager := newTestIngestionManager(t)
+		client := &fakeIngestionClient{processing: openai.VectorStoreFileStatusCompleted, fileNames: map[string]string{}}
+
+		params := testIngestionBatchParams()
+		params.Documents = params.Documents[:2]
+		params.Attributes = map[string]interface{}{"doc_type": "guide", "year": float64(2024), "public": true}
+		job, err := manager.Submit(client, params)
+		require.NoError(t, err)
+		assert.Equal(t, params.Attributes, job.Attributes)
+
+		waitForIngestionJob(t, manager, job.ID, IngestionJobCompleted)
+		assert.Equal(t, params.Attributes, client.attributes)
+	})
+
+	t.Run("should refuse invalid attributes", func(t *testing.T) {
+		manager := newTestIngestionManager(t)
+		client := &fakeIngestionClient{}
+
+		params := testIngestionJobParams()
+		params.Attributes = map[string]interface{}{"tags": []interface{}{"a", "b"}}
+		_, err := manager.Submit(client, params)
+		assert.ErrorContains(t, err, `attribute "tags" must be a string, number or boolean`)
+
+		params.Attributes = map[string]interface{}{}
+		for i := 0; i <= constants.VectorStoreFileMaxAttributes; i++ {
+			params.Attributes[fmt.Sprintf("key%d", i)] = "value"
+		}
+		_, err = manager.Submit(client, params)
+		assert.ErrorContains(t, err, "at most 16 attributes")
+	})
+
+	t.Run("should cancel a running file batch and remove all its documents", func(t *testing.T) {
+		manager := newTestIngestionManager(t)
+		client := &fakeIngestionClient{block: true, fileNames: map[string]string{}}
+
+		job, err := manager.Submit(client, testIngestionBatchParams())
+		require.NoError(t, err)
+		waitForIngestionJob(t, manager, job.ID, IngestionJobProcessing)
+
+		_, err = manager.Cancel("test-namespace", "mockUser", job.ID)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			job, err = manager.Get("test-namespace", "mockUser", job.ID)
+			require.NoError(t, err)
+			return job.Files[0].FileID == ""
+		}, 5*time.Second, time.Millisecond)
+		_, _, deletedFiles := client.counts()
+		assert.ElementsMatch(t, []string{"file-1", "file-2", "file-3"}, deletedFiles)
+		assert.Equal(t, IngestionJobCancelled, job.Status)
+		assert.Empty(t, job.BatchID)
+		for _, file := range job.Files {
+			assert.Equal(t, IngestionJobCancelled, file.Status)
+			assert.Empty(t, file.FileID)
+		}
+	})
+
+	t.Run("should notify watchers about progress", func(t *testing.T) {
+		manager := newTestIngestionManager(t)
+		client := &fakeIngestionClient{block: true}
+
+		job, err := manager.Submit(client, testIngestionJobParams())
+		require.NoError(t, err)
+		changes, unwatch, err := manager.Watch("test-namespace", "mockUser", job.ID)
+		require.NoError(t, err)
+		defer unwatch()
+
+		_, err = manager.Cancel("test-namespace", "mockUser", job.ID)
+		require.NoError(t, err)
+
+		select {
+		case <-changes:
+		case <-time.After(5 * time.Second):
+			t.Fatal("watcher was not notified")
+		}
+	})
+}
@@ -0,0 +1,194 @@
+package llamastack
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/responses"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+)
+
+// Types of input content parts
+const (
+	InputContentText  = "input_text"
+	InputContentImage = "input_image"
+	InputContentFile  = "input_file"
+)
+
+// InputContentPart is a part of a multimodal user message: text, an image or a file.
+// Images are given by URL, base64 data URL or the ID of a file uploaded with purpose vision,
+// files by ID, URL or base64 data URL.
+type InputContentPart struct {
+	// Type is input_text, input_image or input_file
+	Type string `json:"type"`
+	// Text is the text of input_text parts
+	Text string `json:"text,omitempty"`
+	// ImageURL is the http(s) or data URL of input_image parts
+	ImageURL string `json:"image_url,omitempty"`
+	// Detail is the detail level of input_image parts: auto (default), low or high
+	Detail string `json:"detail,omitempty"`
+	// FileID is the ID of an uploaded file, for input_image and input_file parts
+	FileID string `json:"file_id,omitempty"`
+	// FileURL is the URL of input_file parts
+	FileURL string `json:"file_url,omitempty"`
+	// FileData is the base64 data URL of input_file parts
+	FileData string `json:"file_data,omitempty"`
+	// Filename is the name of the file of input_file parts
+	Filename string `json:"filename,omitempty"`
+}
+
+// Validate checks that the part has exactly one source of the kind its type supports, and that inlined
+// images and files are of a supported type and within constants.InputContentMaxBytes.
+func (p *InputContentPart) Validate() error {
+	switch p.Type {
+	case InputContentText:
+		if p.Text == "" {
+			return fmt.Errorf("text is required for %s parts", InputContentText)
+		}
+		if p.ImageURL != "" || p.FileID != "" || p.FileURL != "" || p.FileData != "" {
+			return fmt.Errorf("%s parts only have text", InputContentText)
+		}
+		return nil
+	case InputContentImage:
+		if p.Text != "" || p.FileURL != "" || p.FileData != "" || p.Filename != "" {
+			return fmt.Errorf("%s parts only have an image_url or file_id and a detail", InputContentImage)
+		}
+		if (p.ImageURL == "") == (p.FileID == "") {
+			return fmt.Errorf("%s parts need either an image_url or a file_id", InputContentImage)
+		}
+		if p.Detail != "" && !slices.Contains([]string{"auto", "low", "high"}, p.Detail) {
+			return fmt.Errorf("detail must be auto, low or high, got: %q", p.Detail)
+		}
+		if p.ImageURL != "" {
+			return validateContentURL(p.ImageURL, constants.InputImageContentTypes)
+		}
+		return nil
+	case InputContentFile:
+		if p.Text != "" || p.ImageURL != "" || p.Detail != "" {
+			return fmt.Errorf("%s parts only have a file_id, file_url or file_data and a filename", InputContentFile)
+		}
+		sources := 0
+		for _, source := range []string{p.FileID, p.FileURL, p.FileData} {
+			if source != "" {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("%s parts need one of file_id, file_url or file_data", InputContentFile)
+		}
+		if p.FileURL != "" {
+			return validateContentURL(p.FileURL, nil)
+		}
+		if p.FileData != "" {
+			if p.Filename == "" {
+				return fmt.Errorf("filename is required with file_data")
+			}
+			if !strings.HasPrefix(p.FileData, "data:") {
+				return fmt.Errorf("file_data must be a base64 data URL")
+			}
+			return validateContentURL(p.FileData, constants.InputFileContentTypes)
+		}
+		return nil
+	default:
+		return fmt.Errorf("type must be %s, %s or %s, got: %q", InputContentText, InputContentImage, InputContentFile, p.Type)
+	}
+}
+
+// validateContentURL checks that a URL is an http(s) URL, or a base64 data URL of one of the given content
+// types that decodes to at most constants.InputContentMaxBytes. Data URLs are refused when contentTypes is nil.
+func validateContentURL(value string, contentTypes []string) error {
+	if strings.HasPrefix(value, "data:") && contentTypes == nil {
+		return fmt.Errorf("URL must be an http or https URL, inline data is sent as file_data")
+	}
+	if !strings.HasPrefix(value, "data:") {
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("URL must be an http or https URL or a base64 data URL")
+		}
+		return nil
+	}
+
+	mediaType, data, found := strings.Cut(strings.TrimPrefix(value, "data:"), ",")
+	contentType, isBase64 := strings.CutSuffix(mediaType, ";base64")
+	if !found || !isBase64 {
+		return fmt.Errorf("data URL must be base64 encoded")
+	}
+	if !slices.Contains(contentTypes, contentType) {
+		return fmt.Errorf("content type must be one of %v, got: %q", contentTypes, contentType)
+	}
+	if base64.StdEncoding.DecodedLen(len(data)) > constants.InputContentMaxBytes+2 {
+		return fmt.Errorf("content must be at most %d MB", constants.InputContentMaxBytes>>20)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return fmt.Errorf("invalid base64 data: %w", err)
+	}
+	if len(decoded) > constants.InputContentMaxBytes {
+		return fmt.Errorf("content must be at most %d MB", constants.InputContentMaxBytes>>20)
+	}
+	return nil
+}
+
+// ValidateInputContent checks the parts of a message, and that there are at most constants.InputContentMaxParts.
+func ValidateInputContent(parts []InputContentPart) error {
+	if len(parts) > constants.InputContentMaxParts {
+		return fmt.Errorf("a message can have at most %d content parts, got: %d", constants.InputContentMaxParts, len(parts))
+	}
+	for i := range parts {
+		if err := parts[i].Validate(); err != nil {
+			return fmt.Errorf("content part %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// toParam converts the part to the OpenAI input content parameter.
+func (p *InputContentPart) toParam() responses.ResponseInputContentUnionParam {
+	switch p.Type {
+	case InputContentImage:
+		detail := responses.ResponseInputImageDetailAuto
+		if p.Detail != "" {
+			detail = responses.ResponseInputImageDetail(p.Detail)
+		}
+		content := responses.ResponseInputContentParamOfInputImage(detail)
+		if p.ImageURL != "" {
+			content.OfInputImage.ImageURL = openai.String(p.ImageURL)
+		} else {
+			content.OfInputImage.FileID = openai.String(p.FileID)
+		}
+		return content
+	case InputContentFile:
+		file := &responses.ResponseInputFileParam{}
+		if p.FileID != "" {
+			file.FileID = openai.String(p.FileID)
+		}
+		if p.FileURL != "" {
+			file.FileURL = openai.String(p.FileURL)
+		}
+		if p.FileData != "" {
+			file.FileData = openai.String(p.FileData)
+		}
+		if p.Filename != "" {
+			file.Filename = openai.String(p.Filename)
+		}
+		return responses.ResponseInputContentUnionParam{OfInputFile: file}
+	default:
+		return responses.ResponseInputContentParamOfInputText(p.Text)
+	}
+}
+
+// messageContent returns the content list of a message with the given text followed by the parts
+func messageContent(text string, parts []InputContentPart) responses.ResponseInputMessageContentListParam {
+	content := make(responses.ResponseInputMessageContentListParam, 0, len(parts)+1)
+	if text != "" {
+		content = append(content, responses.ResponseInputContentParamOfInputText(text))
+	}
+	for i := range parts {
+		content = append(content, parts[i].toParam())
+	}
+	return content
+}
@@ -0,0 +1,129 @@
+package llamastack
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateInputContent(t *testing.T) {
+	pngData := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\nmock"))
+
+	t.Run("should accept text, images and files", func(t *testing.T) {
+		assert.NoError(t, ValidateInputContent([]InputContentPart{
+			{Type: InputContentText, Text: "Compare these"},
+			{Type: InputContentImage, ImageURL: "https://example.com/cat.png", Detail: "low"},
+			{Type: InputContentImage, ImageURL: pngData},
+			{Type: InputContentImage, FileID: "file-abc123"},
+			{Type: InputContentFile, FileID: "file-def456"},
+			{Type: InputContentFile, FileURL: "https://example.com/report.pdf"},
+			{Type: InputContentFile, FileData: "data:application/pdf;base64,JVBERi0=", Filename: "report.pdf"},
+		}))
+	})
+
+	t.Run("should reject invalid parts", func(t *testing.T) {
+		tooLarge := "data:image/png;base64," + base64.StdEncoding.EncodeToString(make([]byte, constants.InputContentMaxBytes+1))
+		for name, tc := range map[string]struct {
+			part InputContentPart
+			err  string
+		}{
+			"unknown type":            {InputContentPart{Type: "input_audio"}, "type must be input_text, input_image or input_file"},
+			"empty text":              {InputContentPart{Type: InputContentText}, "text is required"},
+			"image without source":    {InputContentPart{Type: InputContentImage}, "either an image_url or a file_id"},
+			"image with two sources":  {InputContentPart{Type: InputContentImage, ImageURL: pngData, FileID: "file-abc123"}, "either an image_url or a file_id"},
+			"image on other scheme":   {InputContentPart{Type: InputContentImage, ImageURL: "file:///etc/passwd"}, "http or https URL"},
+			"image of other type":     {InputContentPart{Type: InputContentImage, ImageURL: "data:image/svg+xml;base64,PHN2Zz4="}, "content type must be one of"},
+			"image not base64":        {InputContentPart{Type: InputContentImage, ImageURL: "data:image/png,raw"}, "must be base64 encoded"},
+			"image too large":         {InputContentPart{Type: InputContentImage, ImageURL: tooLarge}, "at most 20 MB"},
+			"image with bad detail":   {InputContentPart{Type: InputContentImage, FileID: "file-abc123", Detail: "max"}, "detail must be auto, low or high"},
+			"file data without name":  {InputContentPart{Type: InputContentFile, FileData: "data:application/pdf;base64,JVBERi0="}, "filename is required"},
+			"file data of other type": {InputContentPart{Type: InputContentFile, FileData: "data:application/zip;base64,UEs=", Filename: "a.zip"}, "content type must be one of"},
+			"file URL with data":      {InputContentPart{Type: InputContentFile, FileURL: "data:application/pdf;base64,JVBERi0="}, "inline data is sent as file_data"},
+			"file with two sources":   {InputContentPart{Type: InputContentFile, FileID: "file-abc123", FileURL: "https://example.com/a.pdf"}, "one of file_id, file_url or file_data"},
+		} {
+			err := ValidateInputContent([]InputContentPart{tc.part})
+			assert.ErrorContains(t, err, tc.err, name)
+		}
+	})
+
+	t.Run("should limit the number of parts", func(t *testing.T) {
+		parts := make([]InputContentPart, constants.InputContentMaxParts+1)
+		for i := range parts {
+			parts[i] = InputContentPart{Type: InputContentText, Text: "part"}
+		}
+		assert.ErrorContains(t, ValidateInputContent(parts), "at most 20 content parts")
+	})
+}
+
+func TestPrepareResponseParams_InputContent(t *testing.T) {
+	client := &LlamaStackClient{}
+
+	inputJSON := func(t *testing.T, params CreateResponseParams) []interface{} {
+		apiParams, err := client.prepareResponseParams(params)
+		require.NoError(t, err)
+		data, err := json.Marshal(apiParams)
+		require.NoError(t, err)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &body))
+		input, ok := body["input"].([]interface{})
+		require.True(t, ok, "input should be a list of items")
+		return input
+	}
+
+	t.Run("should send the input text followed by the images as one user message", func(t *testing.T) {
+		input := inputJSON(t, CreateResponseParams{
+			Input: "What is in this image?",
+			Model: "llava",
+			InputContent: []InputContentPart{
+				{Type: InputContentImage, FileID: "file-abc123", Detail: "high"},
+			},
+		})
+
+		require.Len(t, input, 1)
+		message := input[0].(map[string]interface{})
+		assert.Equal(t, "user", message["role"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"type": "input_text", "text": "What is in this image?"},
+			map[string]interface{}{"type": "input_image", "file_id": "file-abc123", "detail": "high"},
+		}, message["content"])
+	})
+
+	t.Run("should send the content parts of chat context messages", func(t *testing.T) {
+		input := inputJSON(t, CreateResponseParams{
+			Input: "And now?",
+			Model: "llava",
+			ChatContext: []ChatContextMessage{
+				{Role: "user", Content: "Describe it", ContentParts: []InputContentPart{{Type: InputContentImage, ImageURL: "https://example.com/cat.png"}}},
+				{Role: "assistant", Content: "A cat."},
+			},
+		})
+
+		require.Len(t, input, 3)
+		content := input[0].(map[string]interface{})["content"].([]interface{})
+		require.Len(t, content, 2)
+		assert.Equal(t, "https://example.com/cat.png", content[1].(map[string]interface{})["image_url"])
+		assert.Equal(t, "A cat.", input[1].(map[string]interface{})["content"])
+	})
+
+	t.Run("should reject content parts on assistant messages and invalid input content", func(t *testing.T) {
+		_, err := client.prepareResponseParams(CreateResponseParams{
+			Input: "Hello",
+			Model: "llava",
+			ChatContext: []ChatContextMessage{
+				{Role: "assistant", Content: "A cat.", ContentParts: []InputContentPart{{Type: InputContentImage, FileID: "file-abc123"}}},
+			},
+		})
+		assert.ErrorContains(t, err, "only user messages can have content parts")
+
+		_, err = client.prepareResponseParams(CreateResponseParams{
+			Model:        "llava",
+			InputContent: []InputContentPart{{Type: InputContentImage}},
+		})
+		assert.True(t, strings.HasPrefix(err.Error(), "invalid input_content"), err.Error())
+	})
+}
@@ -1,6 +1,7 @@
 package llamastack
 
 import (
+	"cmp"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -9,19 +10,23 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/openai/openai-go/v2"
 	"github.com/openai/openai-go/v2/option"
+	"github.com/openai/openai-go/v2/packages/param"
 	"github.com/openai/openai-go/v2/packages/ssestream"
 	"github.com/openai/openai-go/v2/responses"
+	"github.com/openai/openai-go/v2/shared"
 	"github.com/opendatahub-io/gen-ai/internal/constants"
 )
 
 // LlamaStackClient wraps the OpenAI client for Llama Stack communication.
 type LlamaStackClient struct {
-	client *openai.Client
+	client  *openai.Client
+	baseURL string
 }
 
 // NewLlamaStackClient creates a new client configured for Llama Stack.
@@ -45,7 +50,8 @@ func NewLlamaStackClient(baseURL string, authToken string, insecureSkipVerify bo
 	)
 
 	return &LlamaStackClient{
-		client: &client,
+		client:  &client,
+		baseURL: baseURL,
 	}
 }
 
@@ -58,6 +64,60 @@ func (c *LlamaStackClient) ListModels(ctx context.Context) ([]openai.Model, erro
 	return modelsPage.Data, nil
 }
 
+// ModelType returns the LlamaStack type of a model (llm or embedding). Depending on its version, LlamaStack
+// reports it at the top level of the model or in its custom metadata.
+func ModelType(model openai.Model) string {
+	var modelType string
+	if modelField(model, "model_type", &modelType) && modelType != "" {
+		return modelType
+	}
+	var customMetadata struct {
+		ModelType string `json:"model_type"`
+	}
+	modelField(model, "custom_metadata", &customMetadata)
+	return customMetadata.ModelType
+}
+
+// ModelEmbeddingDimension returns the dimension of the vectors of an embedding model, or 0 when LlamaStack
+// does not report it
+func ModelEmbeddingDimension(model openai.Model) int64 {
+	var metadata struct {
+		EmbeddingDimension int64 `json:"embedding_dimension"`
+	}
+	for _, name := range []string{"metadata", "custom_metadata"} {
+		if modelField(model, name, &metadata) && metadata.EmbeddingDimension > 0 {
+			return metadata.EmbeddingDimension
+		}
+	}
+	return 0
+}
+
+// modelField decodes a field LlamaStack adds to the OpenAI model object, and reports whether it was present
+func modelField(model openai.Model, name string, v any) bool {
+	field, ok := model.JSON.ExtraFields[name]
+	if !ok {
+		return false
+	}
+	return json.Unmarshal([]byte(field.Raw()), v) == nil
+}
+
+// ListProviders retrieves the providers LlamaStack serves an API with, such as vector_io.
+func (c *LlamaStackClient) ListProviders(ctx context.Context, api string) ([]Provider, error) {
+	var providers ProviderList
+	// Providers are not part of the OpenAI-compatible API, which is served under a different path
+	if err := c.client.Get(ctx, c.baseURL+"/v1/providers", nil, &providers); err != nil {
+		return nil, fmt.Errorf("failed to list providers: %w", err)
+	}
+
+	matching := make([]Provider, 0, len(providers.Data))
+	for _, provider := range providers.Data {
+		if provider.API == api {
+			matching = append(matching, provider)
+		}
+	}
+	return matching, nil
+}
+
 // ListVectorStoresParams contains parameters for listing vector stores.
 type ListVectorStoresParams struct {
 	// Limit specifies the number of objects to return (range: 1-100, default: 20).
@@ -84,6 +144,8 @@ type ListVectorStoreFilesParams struct {
 	Order string
 	// Filter specifies the filter on file status ("in_progress", "completed", "failed", "cancelled").
 	Filter string
+	// After is the ID of the file the page starts after.
+	After string
 }
 
 // ListVectorStores retrieves vector stores with optional filtering parameters.
@@ -162,19 +224,8 @@ func (c *LlamaStackClient) CreateVectorStore(ctx context.Context, params CreateV
 	}
 
 	// Validate metadata if provided
-	if len(params.Metadata) > 0 {
-		if len(params.Metadata) > 16 {
-			return nil, fmt.Errorf("metadata can have max 16 key-value pairs, got: %d", len(params.Metadata))
-		}
-
-		for k, v := range params.Metadata {
-			if len(k) > 64 {
-				return nil, fmt.Errorf("metadata key '%s' exceeds 64 chars", k)
-			}
-			if len(v) > 512 {
-				return nil, fmt.Errorf("metadata value for '%s' exceeds 512 chars", k)
-			}
-		}
+	if err := validateVectorStoreMetadata(params.Metadata); err != nil {
+		return nil, err
 	}
 
 	// Use default embedding model and dimension if not specified
@@ -217,6 +268,83 @@ func (c *LlamaStackClient) CreateVectorStore(ctx context.Context, params CreateV
 	return vectorStore, nil
 }
 
+// validateVectorStoreMetadata checks the metadata limits of vector stores (max 16 pairs, keys ≤64 chars, values ≤512 chars).
+func validateVectorStoreMetadata(metadata map[string]string) error {
+	if len(metadata) > 16 {
+		return fmt.Errorf("metadata can have max 16 key-value pairs, got: %d", len(metadata))
+	}
+
+	for k, v := range metadata {
+		if len(k) > 64 {
+			return fmt.Errorf("metadata key '%s' exceeds 64 chars", k)
+		}
+		if len(v) > 512 {
+			return fmt.Errorf("metadata value for '%s' exceeds 512 chars", k)
+		}
+	}
+	return nil
+}
+
+// GetVectorStore retrieves a vector store by ID.
+func (c *LlamaStackClient) GetVectorStore(ctx context.Context, vectorStoreID string) (*openai.VectorStore, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+
+	vectorStore, err := c.client.VectorStores.Get(ctx, vectorStoreID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vector store: %w", err)
+	}
+
+	return vectorStore, nil
+}
+
+// UpdateVectorStoreParams contains parameters for updating vector stores.
+type UpdateVectorStoreParams struct {
+	// Name is the optional new name for the vector store.
+	Name *string
+	// Metadata replaces the metadata of the vector store when not nil (same limits as on creation).
+	Metadata map[string]string
+	// ExpiresAfterDays sets the expiration policy when not nil: the store expires this many days (1-365) after it
+	// was last active. Zero removes the expiration policy.
+	ExpiresAfterDays *int64
+}
+
+// UpdateVectorStore updates the name and metadata of a vector store.
+func (c *LlamaStackClient) UpdateVectorStore(ctx context.Context, vectorStoreID string, params UpdateVectorStoreParams) (*openai.VectorStore, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if err := validateVectorStoreMetadata(params.Metadata); err != nil {
+		return nil, err
+	}
+
+	apiParams := openai.VectorStoreUpdateParams{}
+	if params.Name != nil {
+		apiParams.Name = openai.String(*params.Name)
+	}
+	if params.Metadata != nil {
+		apiParams.Metadata = params.Metadata
+	}
+	if days := params.ExpiresAfterDays; days != nil {
+		switch {
+		case *days == 0:
+			apiParams.ExpiresAfter = param.NullStruct[openai.VectorStoreUpdateParamsExpiresAfter]()
+		case *days < 0 || *days > constants.VectorStoreMaxExpiresAfterDays:
+			return nil, fmt.Errorf("expires_after days must be between 1 and %d, got: %d", constants.VectorStoreMaxExpiresAfterDays, *days)
+		default:
+			apiParams.ExpiresAfter = openai.VectorStoreUpdateParamsExpiresAfter{Days: *days}
+		}
+	}
+
+	vectorStore, err := c.client.VectorStores.Update(ctx, vectorStoreID, apiParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update vector store: %w", err)
+	}
+
+	return vectorStore, nil
+}
+
 // ChunkingStrategy represents chunking configuration for file processing.
 type ChunkingStrategy struct {
 	// Type specifies the chunking strategy type ("auto" or "static").
@@ -235,13 +363,16 @@ type StaticChunkingConfig struct {
 
 // UploadFileParams contains parameters for file upload operations.
 type UploadFileParams struct {
-	// FilePath is the path to the file to upload (use this OR Reader+Filename).
+	// FilePath is the path to the file to upload (use this OR Reader+Filename OR Source).
 	FilePath string
-	// Reader provides file content for direct streaming (use this OR FilePath).
+	// Reader provides file content for direct streaming (use this OR FilePath OR Source).
 	Reader io.Reader
-	// Filename is required when using Reader (ignored when using FilePath).
+	// Source provides file content from remote storage, which is opened and streamed (use this OR FilePath OR Reader).
+	Source DocumentSource
+	// Filename is required when using Reader, defaults to the source file name when using Source
+	// (ignored when using FilePath).
 	Filename string
-	// ContentType is optional when using Reader (auto-detected if empty).
+	// ContentType is optional when using Reader or Source (auto-detected if empty).
 	ContentType string
 	// Purpose specifies the intended use case (optional, defaults to "assistants").
 	// Valid values: "assistants", "batch", "fine-tune", "vision", "user_data", "evals".
@@ -250,11 +381,14 @@ type UploadFileParams struct {
 	VectorStoreID string
 	// ChunkingStrategy specifies how to chunk the file when adding to vector store (optional).
 	ChunkingStrategy *ChunkingStrategy
+	// Attributes are stored with the vector store file for filtering (optional, see ValidateFileAttributes).
+	Attributes map[string]interface{}
 }
 
 // FileUploadResult contains the result of a file upload operation.
 type FileUploadResult struct {
 	FileID          string                  `json:"file_id"`
+	Bytes           int64                   `json:"bytes"`
 	VectorStoreFile *openai.VectorStoreFile `json:"vector_store_file,omitempty"`
 }
 
@@ -262,8 +396,23 @@ type FileUploadResult struct {
 func (c *LlamaStackClient) UploadFile(ctx context.Context, params UploadFileParams) (*FileUploadResult, error) {
 	var apiParams openai.FileNewParams
 
-	// Handle both streaming and file path approaches
-	if params.Reader != nil {
+	// Handle streaming, remote source and file path approaches
+	if params.Source != nil {
+		document, err := params.Source.Open(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", params.Source.URI(), err)
+		}
+		defer document.Body.Close()
+
+		filename, contentType := params.Filename, params.ContentType
+		if filename == "" {
+			filename = params.Source.Filename()
+		}
+		if contentType == "" {
+			contentType = document.ContentType
+		}
+		apiParams.File = openai.File(document.Body, filename, contentType)
+	} else if params.Reader != nil {
 		// Direct streaming approach
 		if params.Filename == "" {
 			return nil, fmt.Errorf("filename is required when using Reader")
@@ -306,40 +455,196 @@ func (c *LlamaStackClient) UploadFile(ctx context.Context, params UploadFilePara
 
 	result := &FileUploadResult{
 		FileID: uploadedFile.ID,
+		Bytes:  uploadedFile.Bytes,
 	}
 
 	// If vector store ID is provided, add file to vector store
 	if params.VectorStoreID != "" {
-		vectorStoreFileParams := openai.VectorStoreFileNewParams{
-			FileID: uploadedFile.ID,
+		vectorStoreFile, err := c.AddFileToVectorStore(ctx, params.VectorStoreID, uploadedFile.ID, params.ChunkingStrategy, params.Attributes)
+		if err != nil {
+			return nil, err
 		}
 
-		// Add chunking strategy if provided
-		if params.ChunkingStrategy != nil {
-			if params.ChunkingStrategy.Type == "auto" {
-				autoStrategy := openai.NewAutoFileChunkingStrategyParam()
-				vectorStoreFileParams.ChunkingStrategy = openai.FileChunkingStrategyParamUnion{
-					OfAuto: &autoStrategy,
-				}
-			} else if params.ChunkingStrategy.Type == "static" && params.ChunkingStrategy.Static != nil {
-				vectorStoreFileParams.ChunkingStrategy = openai.FileChunkingStrategyParamOfStatic(
-					openai.StaticFileChunkingStrategyParam{
-						MaxChunkSizeTokens: int64(params.ChunkingStrategy.Static.MaxChunkSizeTokens),
-						ChunkOverlapTokens: int64(params.ChunkingStrategy.Static.ChunkOverlapTokens),
-					},
-				)
+		result.VectorStoreFile = vectorStoreFile
+	}
+
+	return result, nil
+}
+
+// AddFileToVectorStore attaches an uploaded file to a vector store, which chunks and embeds it in the background.
+// The attributes are stored with the vector store file, returned with its search results and can be used to filter
+// searches.
+func (c *LlamaStackClient) AddFileToVectorStore(ctx context.Context, vectorStoreID, fileID string, chunkingStrategy *ChunkingStrategy, attributes map[string]interface{}) (*openai.VectorStoreFile, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if fileID == "" {
+		return nil, fmt.Errorf("fileID is required")
+	}
+
+	attributeParams, err := fileAttributeParams(attributes, func(s *string, f *float64, b *bool) openai.VectorStoreFileNewParamsAttributeUnion {
+		return openai.VectorStoreFileNewParamsAttributeUnion{OfString: optional(s), OfFloat: optional(f), OfBool: optional(b)}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vectorStoreFileParams := openai.VectorStoreFileNewParams{
+		FileID:           fileID,
+		ChunkingStrategy: chunkingStrategy.toParam(),
+		Attributes:       attributeParams,
+	}
+
+	vectorStoreFile, err := c.client.VectorStores.Files.New(ctx, vectorStoreID, vectorStoreFileParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add file to vector store: %w", err)
+	}
+
+	return vectorStoreFile, nil
+}
+
+// ValidateFileAttributes checks vector store file attributes against the limits of the vector store API: at most
+// 16 attributes with keys of up to 64 characters, and values that are strings of up to 512 characters, numbers or
+// booleans.
+func ValidateFileAttributes(attributes map[string]interface{}) error {
+	if len(attributes) > constants.VectorStoreFileMaxAttributes {
+		return fmt.Errorf("at most %d attributes can be set, got: %d", constants.VectorStoreFileMaxAttributes, len(attributes))
+	}
+	for key, value := range attributes {
+		if key == "" || len(key) > constants.VectorStoreFileMaxAttributeKeyLength {
+			return fmt.Errorf("attribute keys must have between 1 and %d characters, got: %q", constants.VectorStoreFileMaxAttributeKeyLength, key)
+		}
+		switch value := value.(type) {
+		case string:
+			if len(value) > constants.VectorStoreFileMaxAttributeValueLength {
+				return fmt.Errorf("attribute %q must have at most %d characters", key, constants.VectorStoreFileMaxAttributeValueLength)
 			}
+		case float64, int, bool:
+		default:
+			return fmt.Errorf("attribute %q must be a string, number or boolean", key)
 		}
+	}
+	return nil
+}
 
-		vectorStoreFile, err := c.client.VectorStores.Files.New(ctx, params.VectorStoreID, vectorStoreFileParams)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add file to vector store: %w", err)
+// fileAttributeParams converts file attributes to their API form, using newUnion to build the attribute union of
+// the endpoint from the value that is set
+func fileAttributeParams[T any](attributes map[string]interface{}, newUnion func(s *string, f *float64, b *bool) T) (map[string]T, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	if err := ValidateFileAttributes(attributes); err != nil {
+		return nil, err
+	}
+	params := make(map[string]T, len(attributes))
+	for key, value := range attributes {
+		switch value := value.(type) {
+		case string:
+			params[key] = newUnion(&value, nil, nil)
+		case float64:
+			params[key] = newUnion(nil, &value, nil)
+		case int:
+			number := float64(value)
+			params[key] = newUnion(nil, &number, nil)
+		case bool:
+			params[key] = newUnion(nil, nil, &value)
 		}
+	}
+	return params, nil
+}
 
-		result.VectorStoreFile = vectorStoreFile
+// optional converts a pointer to an optional API parameter, which is omitted for nil
+func optional[T comparable](value *T) param.Opt[T] {
+	if value == nil {
+		return param.Opt[T]{}
 	}
+	return param.NewOpt(*value)
+}
 
-	return result, nil
+// toParam converts the chunking strategy to its API form. Without a strategy LlamaStack uses its default.
+func (s *ChunkingStrategy) toParam() openai.FileChunkingStrategyParamUnion {
+	if s == nil {
+		return openai.FileChunkingStrategyParamUnion{}
+	}
+	if s.Type == "auto" {
+		autoStrategy := openai.NewAutoFileChunkingStrategyParam()
+		return openai.FileChunkingStrategyParamUnion{
+			OfAuto: &autoStrategy,
+		}
+	}
+	if s.Type == "static" && s.Static != nil {
+		return openai.FileChunkingStrategyParamOfStatic(
+			openai.StaticFileChunkingStrategyParam{
+				MaxChunkSizeTokens: int64(s.Static.MaxChunkSizeTokens),
+				ChunkOverlapTokens: int64(s.Static.ChunkOverlapTokens),
+			},
+		)
+	}
+	return openai.FileChunkingStrategyParamUnion{}
+}
+
+// CreateVectorStoreFileBatch attaches uploaded files to a vector store at once. The files are chunked and
+// embedded in the background, and the attributes are stored with each of them.
+func (c *LlamaStackClient) CreateVectorStoreFileBatch(ctx context.Context, vectorStoreID string, fileIDs []string, chunkingStrategy *ChunkingStrategy, attributes map[string]interface{}) (*openai.VectorStoreFileBatch, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if len(fileIDs) == 0 {
+		return nil, fmt.Errorf("at least one file ID is required")
+	}
+
+	attributeParams, err := fileAttributeParams(attributes, func(s *string, f *float64, b *bool) openai.VectorStoreFileBatchNewParamsAttributeUnion {
+		return openai.VectorStoreFileBatchNewParamsAttributeUnion{OfString: optional(s), OfFloat: optional(f), OfBool: optional(b)}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	batchParams := openai.VectorStoreFileBatchNewParams{
+		FileIDs:          fileIDs,
+		ChunkingStrategy: chunkingStrategy.toParam(),
+		Attributes:       attributeParams,
+	}
+
+	batch, err := c.client.VectorStores.FileBatches.New(ctx, vectorStoreID, batchParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add files to vector store: %w", err)
+	}
+
+	return batch, nil
+}
+
+// GetVectorStoreFileBatch retrieves a file batch of a vector store, including its processing status.
+func (c *LlamaStackClient) GetVectorStoreFileBatch(ctx context.Context, vectorStoreID, batchID string) (*openai.VectorStoreFileBatch, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if batchID == "" {
+		return nil, fmt.Errorf("batchID is required")
+	}
+
+	batch, err := c.client.VectorStores.FileBatches.Get(ctx, vectorStoreID, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vector store file batch: %w", err)
+	}
+
+	return batch, nil
+}
+
+// CancelVectorStoreFileBatch stops the processing of the files of a batch that are not processed yet.
+func (c *LlamaStackClient) CancelVectorStoreFileBatch(ctx context.Context, vectorStoreID, batchID string) error {
+	if vectorStoreID == "" {
+		return fmt.Errorf("vectorStoreID is required")
+	}
+	if batchID == "" {
+		return fmt.Errorf("batchID is required")
+	}
+
+	if _, err := c.client.VectorStores.FileBatches.Cancel(ctx, vectorStoreID, batchID); err != nil {
+		return fmt.Errorf("failed to cancel vector store file batch: %w", err)
+	}
+
+	return nil
 }
 
 // ChatContextMessage represents a message in chat context history.
@@ -348,6 +653,8 @@ type ChatContextMessage struct {
 	Role string `json:"role"`
 	// Content contains the message text.
 	Content string `json:"content"`
+	// ContentParts adds images and files to user messages, after the text of Content.
+	ContentParts []InputContentPart `json:"content_parts,omitempty"`
 }
 
 // MCPServerParam represents MCP server configuration for LlamaStack
@@ -358,47 +665,365 @@ type MCPServerParam struct {
 	ServerURL string
 	// Headers contains custom headers for MCP server authentication
 	Headers map[string]string
+	// AllowedTools restricts the tools the model may call on this server (empty means all tools)
+	AllowedTools []string
+	// RequireApproval controls which tool calls must be approved before they run (nil means never)
+	RequireApproval *MCPApprovalPolicy
+}
+
+// MCP approval settings that apply to every tool of a server
+const (
+	MCPApprovalAlways = "always"
+	MCPApprovalNever  = "never"
+)
+
+// MCPApprovalPolicy controls which MCP tool calls require explicit user approval before they run.
+// Either Setting applies to the whole server, or the tool lists select individual tools.
+// As in LlamaStack, tools listed in neither list require approval when tool lists are used.
+type MCPApprovalPolicy struct {
+	// Setting is "always" or "never" and applies to every tool of the server
+	Setting string
+	// AlwaysTools lists tools that always require approval
+	AlwaysTools []string
+	// NeverTools lists tools that never require approval
+	NeverTools []string
+}
+
+// Validate checks that the policy is either a valid setting or a consistent set of tool lists.
+func (p *MCPApprovalPolicy) Validate() error {
+	hasToolLists := len(p.AlwaysTools) > 0 || len(p.NeverTools) > 0
+	if p.Setting != "" {
+		if p.Setting != MCPApprovalAlways && p.Setting != MCPApprovalNever {
+			return fmt.Errorf("require_approval must be %q or %q, got: %q", MCPApprovalAlways, MCPApprovalNever, p.Setting)
+		}
+		if hasToolLists {
+			return fmt.Errorf("require_approval cannot combine a setting with tool lists")
+		}
+		return nil
+	}
+	if !hasToolLists {
+		return fmt.Errorf("require_approval must specify a setting or at least one tool")
+	}
+
+	always := make(map[string]bool, len(p.AlwaysTools))
+	for _, tool := range p.AlwaysTools {
+		always[tool] = true
+	}
+	for _, tool := range p.NeverTools {
+		if always[tool] {
+			return fmt.Errorf("tool %q cannot be in both always and never approval lists", tool)
+		}
+	}
+	return nil
+}
+
+// toParam converts the policy to the OpenAI require_approval parameter.
+func (p *MCPApprovalPolicy) toParam() responses.ToolMcpRequireApprovalUnionParam {
+	if p.Setting != "" {
+		return responses.ToolMcpRequireApprovalUnionParam{
+			OfMcpToolApprovalSetting: openai.String(p.Setting),
+		}
+	}
+
+	filter := &responses.ToolMcpRequireApprovalMcpToolApprovalFilterParam{}
+	if len(p.AlwaysTools) > 0 {
+		filter.Always = responses.ToolMcpRequireApprovalMcpToolApprovalFilterAlwaysParam{ToolNames: p.AlwaysTools}
+	}
+	if len(p.NeverTools) > 0 {
+		filter.Never = responses.ToolMcpRequireApprovalMcpToolApprovalFilterNeverParam{ToolNames: p.NeverTools}
+	}
+	return responses.ToolMcpRequireApprovalUnionParam{
+		OfMcpToolApprovalFilter: filter,
+	}
+}
+
+// MCPApprovalResponseParam approves or denies a pending MCP tool call from a previous response.
+type MCPApprovalResponseParam struct {
+	// ApprovalRequestID is the ID of the mcp_approval_request output item
+	ApprovalRequestID string
+	// Approve is true to run the tool call and false to deny it
+	Approve bool
+	// Reason optionally explains the decision
+	Reason string
+}
+
+// Response formats the model can be asked to output
+const (
+	ResponseFormatText       = "text"
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+// responseFormatNamePattern is the pattern of JSON schema names the OpenAI API accepts
+var responseFormatNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// ResponseFormat asks the model for JSON output: any JSON object, or JSON matching a named schema.
+type ResponseFormat struct {
+	// Type is text, json_object or json_schema
+	Type string
+	// Name identifies the schema of json_schema formats
+	Name string
+	// Description tells the model what the schema is for (optional)
+	Description string
+	// Schema is the JSON schema of json_schema formats
+	Schema map[string]interface{}
+	// Strict asks the model to follow the schema exactly, which only a subset of JSON schema supports
+	Strict bool
+}
+
+// Validate checks that the format is complete for its type.
+func (f *ResponseFormat) Validate() error {
+	switch f.Type {
+	case ResponseFormatText, ResponseFormatJSONObject:
+		if f.Name != "" || f.Schema != nil || f.Strict {
+			return fmt.Errorf("name, schema and strict are only supported for %s formats", ResponseFormatJSONSchema)
+		}
+		return nil
+	case ResponseFormatJSONSchema:
+		if !responseFormatNamePattern.MatchString(f.Name) {
+			return fmt.Errorf("name must be 1-64 letters, digits, underscores or dashes, got: %q", f.Name)
+		}
+		if len(f.Schema) == 0 {
+			return fmt.Errorf("schema is required for %s formats", ResponseFormatJSONSchema)
+		}
+		return nil
+	default:
+		return fmt.Errorf("type must be %s, %s or %s, got: %q", ResponseFormatText, ResponseFormatJSONObject, ResponseFormatJSONSchema, f.Type)
+	}
+}
+
+// toParam converts the format to the OpenAI text format parameter.
+func (f *ResponseFormat) toParam() responses.ResponseFormatTextConfigUnionParam {
+	switch f.Type {
+	case ResponseFormatJSONObject:
+		return responses.ResponseFormatTextConfigUnionParam{OfJSONObject: &shared.ResponseFormatJSONObjectParam{}}
+	case ResponseFormatJSONSchema:
+		jsonSchema := &responses.ResponseFormatTextJSONSchemaConfigParam{
+			Name:   f.Name,
+			Schema: f.Schema,
+			Strict: openai.Bool(f.Strict),
+		}
+		if f.Description != "" {
+			jsonSchema.Description = openai.String(f.Description)
+		}
+		return responses.ResponseFormatTextConfigUnionParam{OfJSONSchema: jsonSchema}
+	default:
+		return responses.ResponseFormatTextConfigUnionParam{OfText: &shared.ResponseFormatTextParam{}}
+	}
 }
 
 // CreateResponseParams contains parameters for creating AI responses.
 type CreateResponseParams struct {
-	// Input is the text input for response generation (required).
+	// Input is the text input for response generation (required unless InputContent is set).
 	Input string
+	// InputContent adds images and files to the input, after the text of Input.
+	InputContent []InputContentPart
 	// Model specifies the model ID to use for generation (required).
 	Model string
 	// VectorStoreIDs contains vector store IDs for file search functionality.
 	VectorStoreIDs []string
+	// FileSearch tunes the file search over the vector stores (optional).
+	FileSearch *FileSearchOptions
 	// ChatContext contains the full conversation history for multi-turn conversations.
 	ChatContext []ChatContextMessage
 	// Temperature controls response creativity/randomness (range: 0.0-2.0).
 	Temperature *float64
 	// TopP controls nucleus sampling for response variety (range: 0.0-1.0).
 	TopP *float64
+	// MaxOutputTokens limits the tokens generated for the response, including tool calls (at least 1).
+	MaxOutputTokens *int64
+	// Stop lists up to 4 sequences at which generation stops.
+	Stop []string
+	// Seed makes sampling repeatable on model servers that support it.
+	Seed *int64
+	// FrequencyPenalty penalizes tokens by how often they were generated (range: -2.0-2.0).
+	FrequencyPenalty *float64
+	// PresencePenalty penalizes tokens that were generated at all (range: -2.0-2.0).
+	PresencePenalty *float64
+	// ParallelToolCalls allows the model to call several tools at once (server default when nil).
+	ParallelToolCalls *bool
+	// ToolChoice controls whether and which tools the model calls (auto when nil).
+	ToolChoice *ToolChoice
+	// Truncation is auto to drop conversation items that exceed the context window, or disabled to fail.
+	Truncation string
 	// Instructions provides system-level guidance for AI behavior.
 	Instructions string
 	// Tools contains MCP server configurations for tool-enabled responses.
 	Tools []MCPServerParam
 	// PreviousResponseID links this response to a previous response for conversation continuity.
 	PreviousResponseID string
+	// MCPApprovalResponses answers pending MCP tool approval requests of the previous response.
+	// When set, Input is optional.
+	MCPApprovalResponses []MCPApprovalResponseParam
+	// Include requests additional output, such as the results of file search calls (IncludeFileSearchResults).
+	Include []string
+	// ResponseFormat asks the model for JSON output (optional, text by default).
+	ResponseFormat *ResponseFormat
 	// ProviderData contains custom provider headers (e.g., vllm_api_token)
 	ProviderData map[string]interface{}
 }
 
+// maxStopSequences is the number of stop sequences the OpenAI API accepts
+const maxStopSequences = 4
+
+// Truncation strategies of responses
+const (
+	TruncationAuto     = string(responses.ResponseNewParamsTruncationAuto)
+	TruncationDisabled = string(responses.ResponseNewParamsTruncationDisabled)
+)
+
+// ValidateGeneration checks the sampling and generation settings of the params against the ranges of the
+// OpenAI API, and that a tool the model must call is one of the tools of the response.
+func (params *CreateResponseParams) ValidateGeneration() error {
+	if params.Temperature != nil && (*params.Temperature < 0 || *params.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2, got: %.2f", *params.Temperature)
+	}
+	if params.TopP != nil && (*params.TopP < 0 || *params.TopP > 1) {
+		return fmt.Errorf("top_p must be between 0 and 1, got: %.2f", *params.TopP)
+	}
+	if params.MaxOutputTokens != nil && *params.MaxOutputTokens < 1 {
+		return fmt.Errorf("max_output_tokens must be at least 1, got: %d", *params.MaxOutputTokens)
+	}
+	if len(params.Stop) > maxStopSequences {
+		return fmt.Errorf("stop must have at most %d sequences, got: %d", maxStopSequences, len(params.Stop))
+	}
+	for _, stop := range params.Stop {
+		if stop == "" {
+			return fmt.Errorf("stop sequences cannot be empty")
+		}
+	}
+	if params.FrequencyPenalty != nil && (*params.FrequencyPenalty < -2 || *params.FrequencyPenalty > 2) {
+		return fmt.Errorf("frequency_penalty must be between -2 and 2, got: %.2f", *params.FrequencyPenalty)
+	}
+	if params.PresencePenalty != nil && (*params.PresencePenalty < -2 || *params.PresencePenalty > 2) {
+		return fmt.Errorf("presence_penalty must be between -2 and 2, got: %.2f", *params.PresencePenalty)
+	}
+	if params.Truncation != "" && params.Truncation != TruncationAuto && params.Truncation != TruncationDisabled {
+		return fmt.Errorf("truncation must be %s or %s, got: %q", TruncationAuto, TruncationDisabled, params.Truncation)
+	}
+
+	if params.ToolChoice != nil {
+		if err := params.ToolChoice.Validate(); err != nil {
+			return fmt.Errorf("invalid tool_choice: %w", err)
+		}
+		switch params.ToolChoice.Type {
+		case ToolChoiceTypeFileSearch:
+			if len(params.VectorStoreIDs) == 0 {
+				return fmt.Errorf("tool_choice %s requires vector stores", ToolChoiceTypeFileSearch)
+			}
+		case ToolChoiceTypeMCP:
+			found := false
+			for _, tool := range params.Tools {
+				if tool.ServerLabel == params.ToolChoice.ServerLabel {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("tool_choice server_label %q is not one of the MCP servers", params.ToolChoice.ServerLabel)
+			}
+		}
+	}
+	return nil
+}
+
+// Tool choice modes
+const (
+	ToolChoiceAuto     = string(responses.ToolChoiceOptionsAuto)
+	ToolChoiceNone     = string(responses.ToolChoiceOptionsNone)
+	ToolChoiceRequired = string(responses.ToolChoiceOptionsRequired)
+)
+
+// Types of tools the model can be made to call
+const (
+	ToolChoiceTypeFileSearch = "file_search"
+	ToolChoiceTypeMCP        = "mcp"
+)
+
+// ToolChoice controls which tools the model calls. Either Mode applies to all tools, or Type names the
+// tool the model must call.
+type ToolChoice struct {
+	// Mode is auto (the model decides), none (no tools) or required (at least one tool)
+	Mode string
+	// Type is file_search or mcp
+	Type string
+	// ServerLabel is the MCP server of the tool (mcp only)
+	ServerLabel string
+	// Name is the MCP tool to call, any tool of the server when empty (mcp only)
+	Name string
+}
+
+// Validate checks that the choice is either a mode or a tool.
+func (c *ToolChoice) Validate() error {
+	if c.Mode != "" {
+		if c.Mode != ToolChoiceAuto && c.Mode != ToolChoiceNone && c.Mode != ToolChoiceRequired {
+			return fmt.Errorf("mode must be %s, %s or %s, got: %q", ToolChoiceAuto, ToolChoiceNone, ToolChoiceRequired, c.Mode)
+		}
+		if c.Type != "" || c.ServerLabel != "" || c.Name != "" {
+			return fmt.Errorf("a mode cannot be combined with a tool")
+		}
+		return nil
+	}
+
+	switch c.Type {
+	case ToolChoiceTypeFileSearch:
+		if c.ServerLabel != "" || c.Name != "" {
+			return fmt.Errorf("server_label and name are only supported for %s tools", ToolChoiceTypeMCP)
+		}
+		return nil
+	case ToolChoiceTypeMCP:
+		if c.ServerLabel == "" {
+			return fmt.Errorf("server_label is required for %s tools", ToolChoiceTypeMCP)
+		}
+		return nil
+	default:
+		return fmt.Errorf("type must be %s or %s, got: %q", ToolChoiceTypeFileSearch, ToolChoiceTypeMCP, c.Type)
+	}
+}
+
+// toParam converts the choice to the OpenAI tool_choice parameter.
+func (c *ToolChoice) toParam() responses.ResponseNewParamsToolChoiceUnion {
+	switch {
+	case c.Mode != "":
+		return responses.ResponseNewParamsToolChoiceUnion{OfToolChoiceMode: openai.Opt(responses.ToolChoiceOptions(c.Mode))}
+	case c.Type == ToolChoiceTypeMCP:
+		mcpTool := &responses.ToolChoiceMcpParam{ServerLabel: c.ServerLabel}
+		if c.Name != "" {
+			mcpTool.Name = openai.String(c.Name)
+		}
+		return responses.ResponseNewParamsToolChoiceUnion{OfMcpTool: mcpTool}
+	default:
+		return responses.ResponseNewParamsToolChoiceUnion{OfHostedTool: &responses.ToolChoiceTypesParam{Type: responses.ToolChoiceTypesType(c.Type)}}
+	}
+}
+
+// IncludeFileSearchResults includes the retrieved chunks in the file search calls of a response
+const IncludeFileSearchResults = string(responses.ResponseIncludableFileSearchCallResults)
+
 // prepareResponseParams validates input parameters and prepares the API parameters for response creation.
 func (c *LlamaStackClient) prepareResponseParams(params CreateResponseParams) (*responses.ResponseNewParams, error) {
-	if params.Input == "" {
+	if params.Input == "" && len(params.InputContent) == 0 && len(params.MCPApprovalResponses) == 0 {
 		return nil, fmt.Errorf("input is required")
 	}
+	if err := ValidateInputContent(params.InputContent); err != nil {
+		return nil, fmt.Errorf("invalid input_content: %w", err)
+	}
 	if params.Model == "" {
 		return nil, fmt.Errorf("model is required")
 	}
+	if len(params.MCPApprovalResponses) > 0 && params.PreviousResponseID == "" {
+		return nil, fmt.Errorf("previous_response_id is required when responding to MCP approval requests")
+	}
 
 	apiParams := &responses.ResponseNewParams{
 		Model: responses.ResponsesModel(params.Model),
 		Store: openai.Bool(true),
 	}
+	for _, include := range params.Include {
+		apiParams.Include = append(apiParams.Include, responses.ResponseIncludable(include))
+	}
 
-	if len(params.ChatContext) > 0 {
+	if len(params.ChatContext) > 0 || len(params.MCPApprovalResponses) > 0 || len(params.InputContent) > 0 {
 		inputItems := make(responses.ResponseInputParam, 0)
 
 		// Add chat context messages first
@@ -416,17 +1041,47 @@ func (c *LlamaStackClient) prepareResponseParams(params CreateResponseParams) (*
 				role = responses.EasyInputMessageRoleUser // fallback to user
 			}
 
+			if len(msg.ContentParts) > 0 {
+				if role != responses.EasyInputMessageRoleUser {
+					return nil, fmt.Errorf("only user messages can have content parts")
+				}
+				if err := ValidateInputContent(msg.ContentParts); err != nil {
+					return nil, fmt.Errorf("invalid chat context content: %w", err)
+				}
+				inputItems = append(inputItems, responses.ResponseInputItemParamOfMessage(messageContent(msg.Content, msg.ContentParts), role))
+				continue
+			}
+
 			inputItems = append(inputItems, responses.ResponseInputItemParamOfMessage(
 				msg.Content,
 				role,
 			))
 		}
 
+		// Add approval decisions for pending MCP tool calls
+		for _, approval := range params.MCPApprovalResponses {
+			if approval.ApprovalRequestID == "" {
+				return nil, fmt.Errorf("approval_request_id is required for MCP approval responses")
+			}
+			approvalItem := responses.ResponseInputItemParamOfMcpApprovalResponse(approval.ApprovalRequestID, approval.Approve)
+			if approval.Reason != "" {
+				approvalItem.OfMcpApprovalResponse.Reason = openai.String(approval.Reason)
+			}
+			inputItems = append(inputItems, approvalItem)
+		}
+
 		// Add the new user input
-		inputItems = append(inputItems, responses.ResponseInputItemParamOfMessage(
-			params.Input,
-			responses.EasyInputMessageRoleUser,
-		))
+		if len(params.InputContent) > 0 {
+			inputItems = append(inputItems, responses.ResponseInputItemParamOfMessage(
+				messageContent(params.Input, params.InputContent),
+				responses.EasyInputMessageRoleUser,
+			))
+		} else if params.Input != "" {
+			inputItems = append(inputItems, responses.ResponseInputItemParamOfMessage(
+				params.Input,
+				responses.EasyInputMessageRoleUser,
+			))
+		}
 
 		apiParams.Input = responses.ResponseNewParamsInputUnion{
 			OfInputItemList: inputItems,
@@ -447,19 +1102,53 @@ func (c *LlamaStackClient) prepareResponseParams(params CreateResponseParams) (*
 		}
 	}
 
+	if err := params.ValidateGeneration(); err != nil {
+		return nil, err
+	}
 	if params.Temperature != nil {
-		if *params.Temperature < 0 || *params.Temperature > 2 {
-			return nil, fmt.Errorf("temperature must be between 0 and 2, got: %.2f", *params.Temperature)
-		}
 		apiParams.Temperature = openai.Float(*params.Temperature)
 	}
-
 	if params.TopP != nil {
-		if *params.TopP < 0 || *params.TopP > 1 {
-			return nil, fmt.Errorf("top_p must be between 0 and 1, got: %.2f", *params.TopP)
-		}
 		apiParams.TopP = openai.Float(*params.TopP)
 	}
+	if params.MaxOutputTokens != nil {
+		apiParams.MaxOutputTokens = openai.Int(*params.MaxOutputTokens)
+	}
+	if params.ParallelToolCalls != nil {
+		apiParams.ParallelToolCalls = openai.Bool(*params.ParallelToolCalls)
+	}
+	if params.ToolChoice != nil {
+		apiParams.ToolChoice = params.ToolChoice.toParam()
+	}
+	if params.Truncation != "" {
+		apiParams.Truncation = responses.ResponseNewParamsTruncation(params.Truncation)
+	}
+
+	// The Responses API has no stop, seed or penalty parameters, so they are sent as extra fields named
+	// like their chat completions parameters
+	extraFields := map[string]any{}
+	if len(params.Stop) > 0 {
+		extraFields["stop"] = params.Stop
+	}
+	if params.Seed != nil {
+		extraFields["seed"] = *params.Seed
+	}
+	if params.FrequencyPenalty != nil {
+		extraFields["frequency_penalty"] = *params.FrequencyPenalty
+	}
+	if params.PresencePenalty != nil {
+		extraFields["presence_penalty"] = *params.PresencePenalty
+	}
+	if len(extraFields) > 0 {
+		apiParams.SetExtraFields(extraFields)
+	}
+
+	if params.ResponseFormat != nil {
+		if err := params.ResponseFormat.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid response_format: %w", err)
+		}
+		apiParams.Text = responses.ResponseTextConfigParam{Format: params.ResponseFormat.toParam()}
+	}
 
 	// Handle tools (both file search and MCP tools)
 	var tools []responses.ToolUnionParam
@@ -467,6 +1156,12 @@ func (c *LlamaStackClient) prepareResponseParams(params CreateResponseParams) (*
 	// Add file search tools if vector store IDs are provided
 	if len(params.VectorStoreIDs) > 0 {
 		fileSearchTool := responses.ToolParamOfFileSearch(params.VectorStoreIDs)
+		if params.FileSearch != nil {
+			if err := params.FileSearch.Validate(); err != nil {
+				return nil, err
+			}
+			params.FileSearch.apply(fileSearchTool.OfFileSearch)
+		}
 		tools = append(tools, fileSearchTool)
 	}
 
@@ -482,14 +1177,28 @@ func (c *LlamaStackClient) prepareResponseParams(params CreateResponseParams) (*
 			}
 
 			// Create MCP tool parameter for OpenAI client
-			mcpServerToolParam := responses.ToolUnionParam{
-				OfMcp: &responses.ToolMcpParam{
-					ServerLabel: mcpServer.ServerLabel,
-					ServerURL:   openai.String(mcpServer.ServerURL),
-					Headers:     mcpServer.Headers,
-				},
+			mcpToolParam := &responses.ToolMcpParam{
+				ServerLabel: mcpServer.ServerLabel,
+				ServerURL:   openai.String(mcpServer.ServerURL),
+				Headers:     mcpServer.Headers,
+			}
+
+			// Restrict the tools the model may call on this server
+			if len(mcpServer.AllowedTools) > 0 {
+				mcpToolParam.AllowedTools = responses.ToolMcpAllowedToolsUnionParam{
+					OfMcpAllowedTools: mcpServer.AllowedTools,
+				}
+			}
+
+			// Require human approval before matching tool calls are executed
+			if mcpServer.RequireApproval != nil {
+				if err := mcpServer.RequireApproval.Validate(); err != nil {
+					return nil, fmt.Errorf("invalid require_approval for MCP server %s: %w", mcpServer.ServerLabel, err)
+				}
+				mcpToolParam.RequireApproval = mcpServer.RequireApproval.toParam()
 			}
-			tools = append(tools, mcpServerToolParam)
+
+			tools = append(tools, responses.ToolUnionParam{OfMcp: mcpToolParam})
 		}
 	}
 
@@ -640,6 +1349,26 @@ func (c *LlamaStackClient) GetFile(ctx context.Context, fileID string) (*openai.
 	return file, nil
 }
 
+// FileContent is the content of an uploaded file. The caller must close Body.
+type FileContent struct {
+	Body        io.ReadCloser
+	ContentType string // As reported by LlamaStack, which often only reports application/octet-stream
+}
+
+// GetFileContent retrieves the original content of an uploaded file.
+func (c *LlamaStackClient) GetFileContent(ctx context.Context, fileID string) (*FileContent, error) {
+	if fileID == "" {
+		return nil, fmt.Errorf("fileID is required")
+	}
+
+	response, err := c.client.Files.Content(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file content: %w", err)
+	}
+
+	return &FileContent{Body: response.Body, ContentType: response.Header.Get("Content-Type")}, nil
+}
+
 // DeleteFile deletes a file by ID.
 func (c *LlamaStackClient) DeleteFile(ctx context.Context, fileID string) error {
 	if fileID == "" {
@@ -691,6 +1420,10 @@ func (c *LlamaStackClient) ListVectorStoreFiles(ctx context.Context, vectorStore
 		apiParams.Filter = openai.VectorStoreFileListParamsFilter(params.Filter)
 	}
 
+	if params.After != "" {
+		apiParams.After = openai.String(params.After)
+	}
+
 	filesPage, err := c.client.VectorStores.Files.List(ctx, vectorStoreID, apiParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list vector store files: %w", err)
@@ -699,6 +1432,40 @@ func (c *LlamaStackClient) ListVectorStoreFiles(ctx context.Context, vectorStore
 	return filesPage.Data, nil
 }
 
+// GetVectorStoreFile retrieves a file of a vector store, including its processing status.
+func (c *LlamaStackClient) GetVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) (*openai.VectorStoreFile, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if fileID == "" {
+		return nil, fmt.Errorf("fileID is required")
+	}
+
+	vectorStoreFile, err := c.client.VectorStores.Files.Get(ctx, vectorStoreID, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vector store file: %w", err)
+	}
+
+	return vectorStoreFile, nil
+}
+
+// ListVectorStoreFileContent retrieves the chunks a vector store file was split into.
+func (c *LlamaStackClient) ListVectorStoreFileContent(ctx context.Context, vectorStoreID, fileID string) ([]openai.VectorStoreFileContentResponse, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if fileID == "" {
+		return nil, fmt.Errorf("fileID is required")
+	}
+
+	contentPage, err := c.client.VectorStores.Files.Content(ctx, vectorStoreID, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vector store file content: %w", err)
+	}
+
+	return contentPage.Data, nil
+}
+
 // DeleteVectorStoreFile removes a file from a vector store.
 func (c *LlamaStackClient) DeleteVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) error {
 	if vectorStoreID == "" {
@@ -715,3 +1482,265 @@ func (c *LlamaStackClient) DeleteVectorStoreFile(ctx context.Context, vectorStor
 
 	return nil
 }
+
+// SearchVectorStoreParams contains parameters for searching a vector store.
+type SearchVectorStoreParams struct {
+	// Query is the text to search for (required).
+	Query string
+	// MaxNumResults specifies the number of chunks to return (range: 1-50, default: 10).
+	MaxNumResults *int64
+	// ScoreThreshold drops chunks scoring below it (range: 0-1).
+	ScoreThreshold *float64
+	// Ranker selects the re-ranking of the chunks ("none" disables it).
+	Ranker string
+	// RewriteQuery rewrites the natural language query for vector search.
+	RewriteQuery *bool
+	// Filters restricts the search to files whose attributes match.
+	Filters *VectorStoreSearchFilter
+}
+
+// VectorStoreSearchFilter is a comparison of a file attribute with a value (eq, ne, gt, gte, lt, lte),
+// or a compound of comparisons (and, or).
+type VectorStoreSearchFilter struct {
+	Type    string                    `json:"type"`
+	Key     string                    `json:"key,omitempty"`
+	Value   interface{}               `json:"value,omitempty"`
+	Filters []VectorStoreSearchFilter `json:"filters,omitempty"`
+}
+
+// Validate checks that the filter can be sent to LlamaStack
+func (f VectorStoreSearchFilter) Validate() error {
+	_, _, err := f.toParams()
+	return err
+}
+
+// toParams converts the filter to its API form, which is either a comparison or a compound filter
+func (f VectorStoreSearchFilter) toParams() (*openai.ComparisonFilterParam, *openai.CompoundFilterParam, error) {
+	switch f.Type {
+	case "and", "or":
+		if len(f.Filters) == 0 {
+			return nil, nil, fmt.Errorf("%s filter requires filters", f.Type)
+		}
+		compound := &openai.CompoundFilterParam{Type: openai.CompoundFilterType(f.Type)}
+		for _, filter := range f.Filters {
+			comparison, err := filter.comparisonParam()
+			if err != nil {
+				return nil, nil, err
+			}
+			compound.Filters = append(compound.Filters, comparison)
+		}
+		return nil, compound, nil
+	default:
+		comparison, err := f.comparisonParam()
+		if err != nil {
+			return nil, nil, err
+		}
+		return &comparison, nil, nil
+	}
+}
+
+// comparisonParam converts a comparison filter to its API form. Compound filters cannot be nested.
+func (f VectorStoreSearchFilter) comparisonParam() (openai.ComparisonFilterParam, error) {
+	switch f.Type {
+	case "eq", "ne", "gt", "gte", "lt", "lte":
+	default:
+		return openai.ComparisonFilterParam{}, fmt.Errorf("filter type must be one of eq, ne, gt, gte, lt, lte, and, or, got: %q", f.Type)
+	}
+	if f.Key == "" {
+		return openai.ComparisonFilterParam{}, fmt.Errorf("%s filter requires a key", f.Type)
+	}
+
+	comparison := openai.ComparisonFilterParam{
+		Key:  f.Key,
+		Type: openai.ComparisonFilterType(f.Type),
+	}
+	switch value := f.Value.(type) {
+	case string:
+		comparison.Value.OfString = openai.String(value)
+	case float64:
+		comparison.Value.OfFloat = openai.Float(value)
+	case int:
+		comparison.Value.OfFloat = openai.Float(float64(value))
+	case bool:
+		comparison.Value.OfBool = openai.Bool(value)
+	default:
+		return openai.ComparisonFilterParam{}, fmt.Errorf("filter value of %q must be a string, number or boolean", f.Key)
+	}
+	return comparison, nil
+}
+
+// Matches reports whether file attributes satisfy the filter, to filter files outside of a search. Comparisons
+// with attributes that are not set, or that hold a value of another type, do not match.
+func (f VectorStoreSearchFilter) Matches(attributes map[string]interface{}) bool {
+	switch f.Type {
+	case "and":
+		for _, filter := range f.Filters {
+			if !filter.Matches(attributes) {
+				return false
+			}
+		}
+		return true
+	case "or":
+		for _, filter := range f.Filters {
+			if filter.Matches(attributes) {
+				return true
+			}
+		}
+		return false
+	}
+
+	value, ok := attributes[f.Key]
+	if !ok {
+		return false
+	}
+	order, ok := compareAttribute(value, f.Value)
+	if !ok {
+		return false
+	}
+	switch f.Type {
+	case "eq":
+		return order == 0
+	case "ne":
+		return order != 0
+	case "gt":
+		return order > 0
+	case "gte":
+		return order >= 0
+	case "lt":
+		return order < 0
+	case "lte":
+		return order <= 0
+	default:
+		return false
+	}
+}
+
+// compareAttribute orders an attribute value and a filter value of the same type. Strings are compared
+// lexically, so that ISO 8601 dates compare chronologically, and false is ordered before true.
+func compareAttribute(value, other interface{}) (int, bool) {
+	switch value := value.(type) {
+	case string:
+		other, ok := other.(string)
+		return strings.Compare(value, other), ok
+	case bool:
+		other, ok := other.(bool)
+		if !ok || value == other {
+			return 0, ok
+		}
+		if value {
+			return 1, true
+		}
+		return -1, true
+	default:
+		number, ok := attributeNumber(value)
+		if !ok {
+			return 0, false
+		}
+		otherNumber, ok := attributeNumber(other)
+		return cmp.Compare(number, otherNumber), ok
+	}
+}
+
+func attributeNumber(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case int:
+		return float64(value), true
+	default:
+		return 0, false
+	}
+}
+
+// Validate checks the search parameters against the limits of the vector store search API
+func (p SearchVectorStoreParams) Validate() error {
+	if strings.TrimSpace(p.Query) == "" {
+		return fmt.Errorf("query is required")
+	}
+	return validateRetrieval(p.MaxNumResults, p.ScoreThreshold, p.Filters)
+}
+
+// validateRetrieval checks the result count, score threshold and filters shared by vector store searches and
+// the file search tool
+func validateRetrieval(maxNumResults *int64, scoreThreshold *float64, filters *VectorStoreSearchFilter) error {
+	if maxNumResults != nil && (*maxNumResults < 1 || *maxNumResults > 50) {
+		return fmt.Errorf("max_num_results must be between 1 and 50, got: %d", *maxNumResults)
+	}
+	if scoreThreshold != nil && (*scoreThreshold < 0 || *scoreThreshold > 1) {
+		return fmt.Errorf("score_threshold must be between 0 and 1, got: %g", *scoreThreshold)
+	}
+	if filters != nil {
+		if err := filters.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FileSearchOptions tunes the file search tool of a response, like the parameters of a vector store search.
+type FileSearchOptions struct {
+	// MaxNumResults specifies the number of chunks to retrieve (range: 1-50).
+	MaxNumResults *int64
+	// ScoreThreshold drops chunks scoring below it (range: 0-1).
+	ScoreThreshold *float64
+	// Ranker selects the re-ranking of the chunks ("none" disables it).
+	Ranker string
+	// Filters restricts the search to files whose attributes match.
+	Filters *VectorStoreSearchFilter
+}
+
+// Validate checks the options against the limits of the file search tool
+func (o FileSearchOptions) Validate() error {
+	return validateRetrieval(o.MaxNumResults, o.ScoreThreshold, o.Filters)
+}
+
+// apply sets the validated options on a file search tool
+func (o FileSearchOptions) apply(tool *responses.FileSearchToolParam) {
+	if o.MaxNumResults != nil {
+		tool.MaxNumResults = openai.Int(*o.MaxNumResults)
+	}
+	if o.ScoreThreshold != nil {
+		tool.RankingOptions.ScoreThreshold = openai.Float(*o.ScoreThreshold)
+	}
+	tool.RankingOptions.Ranker = o.Ranker
+	if o.Filters != nil {
+		comparison, compound, _ := o.Filters.toParams()
+		tool.Filters = responses.FileSearchToolFiltersUnionParam{OfComparisonFilter: comparison, OfCompoundFilter: compound}
+	}
+}
+
+// SearchVectorStore retrieves the chunks of a vector store most relevant to a query, with their scores.
+func (c *LlamaStackClient) SearchVectorStore(ctx context.Context, vectorStoreID string, params SearchVectorStoreParams) ([]openai.VectorStoreSearchResponse, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	apiParams := openai.VectorStoreSearchParams{
+		Query: openai.VectorStoreSearchParamsQueryUnion{OfString: openai.String(params.Query)},
+	}
+	if params.MaxNumResults != nil {
+		apiParams.MaxNumResults = openai.Int(*params.MaxNumResults)
+	}
+	if params.ScoreThreshold != nil {
+		apiParams.RankingOptions.ScoreThreshold = openai.Float(*params.ScoreThreshold)
+	}
+	apiParams.RankingOptions.Ranker = params.Ranker
+	if params.RewriteQuery != nil {
+		apiParams.RewriteQuery = openai.Bool(*params.RewriteQuery)
+	}
+	if params.Filters != nil {
+		// Already validated
+		comparison, compound, _ := params.Filters.toParams()
+		apiParams.Filters = openai.VectorStoreSearchParamsFiltersUnion{OfComparisonFilter: comparison, OfCompoundFilter: compound}
+	}
+
+	resultsPage, err := c.client.VectorStores.Search(ctx, vectorStoreID, apiParams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search vector store: %w", err)
+	}
+
+	return resultsPage.Data, nil
+}
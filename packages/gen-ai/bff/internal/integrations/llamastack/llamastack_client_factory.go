@@ -12,15 +12,26 @@ import (
 // LlamaStackClientInterface defines the interface for LlamaStack client operations
 type LlamaStackClientInterface interface {
 	ListModels(ctx context.Context) ([]openai.Model, error)
+	ListProviders(ctx context.Context, api string) ([]Provider, error)
 	ListVectorStores(ctx context.Context, params ListVectorStoresParams) ([]openai.VectorStore, error)
 	CreateVectorStore(ctx context.Context, params CreateVectorStoreParams) (*openai.VectorStore, error)
+	GetVectorStore(ctx context.Context, vectorStoreID string) (*openai.VectorStore, error)
+	UpdateVectorStore(ctx context.Context, vectorStoreID string, params UpdateVectorStoreParams) (*openai.VectorStore, error)
 	DeleteVectorStore(ctx context.Context, vectorStoreID string) error
 	UploadFile(ctx context.Context, params UploadFileParams) (*FileUploadResult, error)
 	ListFiles(ctx context.Context, params ListFilesParams) ([]openai.FileObject, error)
 	GetFile(ctx context.Context, fileID string) (*openai.FileObject, error)
+	GetFileContent(ctx context.Context, fileID string) (*FileContent, error)
 	DeleteFile(ctx context.Context, fileID string) error
+	AddFileToVectorStore(ctx context.Context, vectorStoreID, fileID string, chunkingStrategy *ChunkingStrategy, attributes map[string]interface{}) (*openai.VectorStoreFile, error)
+	CreateVectorStoreFileBatch(ctx context.Context, vectorStoreID string, fileIDs []string, chunkingStrategy *ChunkingStrategy, attributes map[string]interface{}) (*openai.VectorStoreFileBatch, error)
+	GetVectorStoreFileBatch(ctx context.Context, vectorStoreID, batchID string) (*openai.VectorStoreFileBatch, error)
+	CancelVectorStoreFileBatch(ctx context.Context, vectorStoreID, batchID string) error
 	ListVectorStoreFiles(ctx context.Context, vectorStoreID string, params ListVectorStoreFilesParams) ([]openai.VectorStoreFile, error)
+	GetVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) (*openai.VectorStoreFile, error)
+	ListVectorStoreFileContent(ctx context.Context, vectorStoreID, fileID string) ([]openai.VectorStoreFileContentResponse, error)
 	DeleteVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) error
+	SearchVectorStore(ctx context.Context, vectorStoreID string, params SearchVectorStoreParams) ([]openai.VectorStoreSearchResponse, error)
 	CreateResponse(ctx context.Context, params CreateResponseParams) (*responses.Response, error)
 	CreateResponseStream(ctx context.Context, params CreateResponseParams) (*ssestream.Stream[responses.ResponseStreamEventUnion], error)
 	GetResponse(ctx context.Context, responseID string) (*responses.Response, error)
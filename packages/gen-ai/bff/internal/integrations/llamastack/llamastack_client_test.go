@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/responses"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -292,3 +294,419 @@ func TestBuildRequestOptions_ExactJSONOutput(t *testing.T) {
 		assert.Equal(t, providerData["url"], parsed["url"])
 	})
 }
+
+func TestPrepareResponseParams_MCPApproval(t *testing.T) {
+	client := &LlamaStackClient{}
+
+	// marshalTools returns the JSON of the first tool so the wire format sent to LlamaStack can be checked
+	marshalTools := func(t *testing.T, params CreateResponseParams) map[string]interface{} {
+		apiParams, err := client.prepareResponseParams(params)
+		require.NoError(t, err)
+		require.Len(t, apiParams.Tools, 1)
+
+		data, err := json.Marshal(apiParams.Tools[0])
+		require.NoError(t, err)
+
+		var tool map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &tool))
+		return tool
+	}
+
+	t.Run("should pass allowed tools and a server-wide approval setting", func(t *testing.T) {
+		tool := marshalTools(t, CreateResponseParams{
+			Input: "Hello",
+			Model: "llama",
+			Tools: []MCPServerParam{{
+				ServerLabel:     "github",
+				ServerURL:       "https://mcp.example.com/mcp",
+				AllowedTools:    []string{"get_latest_release"},
+				RequireApproval: &MCPApprovalPolicy{Setting: MCPApprovalAlways},
+			}},
+		})
+
+		assert.Equal(t, "always", tool["require_approval"])
+		assert.Equal(t, []interface{}{"get_latest_release"}, tool["allowed_tools"])
+	})
+
+	t.Run("should pass per-tool approval lists", func(t *testing.T) {
+		tool := marshalTools(t, CreateResponseParams{
+			Input: "Hello",
+			Model: "llama",
+			Tools: []MCPServerParam{{
+				ServerLabel: "github",
+				ServerURL:   "https://mcp.example.com/mcp",
+				RequireApproval: &MCPApprovalPolicy{
+					AlwaysTools: []string{"create_issue"},
+					NeverTools:  []string{"get_latest_release"},
+				},
+			}},
+		})
+
+		requireApproval, ok := tool["require_approval"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, map[string]interface{}{"tool_names": []interface{}{"create_issue"}}, requireApproval["always"])
+		assert.Equal(t, map[string]interface{}{"tool_names": []interface{}{"get_latest_release"}}, requireApproval["never"])
+		assert.NotContains(t, tool, "allowed_tools")
+	})
+
+	t.Run("should omit approval settings when no policy is configured", func(t *testing.T) {
+		tool := marshalTools(t, CreateResponseParams{
+			Input: "Hello",
+			Model: "llama",
+			Tools: []MCPServerParam{{ServerLabel: "github", ServerURL: "https://mcp.example.com/mcp"}},
+		})
+
+		assert.NotContains(t, tool, "require_approval")
+	})
+
+	t.Run("should reject invalid approval policies", func(t *testing.T) {
+		policies := []*MCPApprovalPolicy{
+			{Setting: "sometimes"},
+			{},
+			{Setting: MCPApprovalNever, AlwaysTools: []string{"create_issue"}},
+			{AlwaysTools: []string{"create_issue"}, NeverTools: []string{"create_issue"}},
+		}
+
+		for _, policy := range policies {
+			_, err := client.prepareResponseParams(CreateResponseParams{
+				Input: "Hello",
+				Model: "llama",
+				Tools: []MCPServerParam{{ServerLabel: "github", ServerURL: "https://mcp.example.com/mcp", RequireApproval: policy}},
+			})
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("should send approval responses as input items without requiring input", func(t *testing.T) {
+		apiParams, err := client.prepareResponseParams(CreateResponseParams{
+			Model:              "llama",
+			PreviousResponseID: "resp_123",
+			MCPApprovalResponses: []MCPApprovalResponseParam{
+				{ApprovalRequestID: "mcpr_1", Approve: true},
+				{ApprovalRequestID: "mcpr_2", Approve: false, Reason: "Not allowed"},
+			},
+		})
+		require.NoError(t, err)
+
+		data, err := json.Marshal(apiParams.Input)
+		require.NoError(t, err)
+
+		var items []map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &items))
+		require.Len(t, items, 2)
+		assert.Equal(t, "mcp_approval_response", items[0]["type"])
+		assert.Equal(t, "mcpr_1", items[0]["approval_request_id"])
+		assert.Equal(t, true, items[0]["approve"])
+		assert.Equal(t, false, items[1]["approve"])
+		assert.Equal(t, "Not allowed", items[1]["reason"])
+	})
+
+	t.Run("should require a previous response for approval responses", func(t *testing.T) {
+		_, err := client.prepareResponseParams(CreateResponseParams{
+			Model:                "llama",
+			MCPApprovalResponses: []MCPApprovalResponseParam{{ApprovalRequestID: "mcpr_1", Approve: true}},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestVectorStoreSearchFilter_ToParams(t *testing.T) {
+	// marshalFilter returns the JSON of the filter as sent to LlamaStack
+	marshalFilter := func(t *testing.T, filter VectorStoreSearchFilter) map[string]interface{} {
+		comparison, compound, err := filter.toParams()
+		require.NoError(t, err)
+
+		data, err := json.Marshal(openai.VectorStoreSearchParamsFiltersUnion{OfComparisonFilter: comparison, OfCompoundFilter: compound})
+		require.NoError(t, err)
+
+		var result map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &result))
+		return result
+	}
+
+	t.Run("should pass a comparison filter", func(t *testing.T) {
+		filter := marshalFilter(t, VectorStoreSearchFilter{Type: "eq", Key: "category", Value: "guide"})
+
+		assert.Equal(t, map[string]interface{}{"type": "eq", "key": "category", "value": "guide"}, filter)
+	})
+
+	t.Run("should pass a compound filter", func(t *testing.T) {
+		filter := marshalFilter(t, VectorStoreSearchFilter{
+			Type: "or",
+			Filters: []VectorStoreSearchFilter{
+				{Type: "gte", Key: "version", Value: float64(2)},
+				{Type: "eq", Key: "published", Value: true},
+			},
+		})
+
+		assert.Equal(t, "or", filter["type"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"type": "gte", "key": "version", "value": float64(2)},
+			map[string]interface{}{"type": "eq", "key": "published", "value": true},
+		}, filter["filters"])
+	})
+
+	t.Run("should reject invalid filters", func(t *testing.T) {
+		for name, filter := range map[string]VectorStoreSearchFilter{
+			"unknown type":     {Type: "contains", Key: "category", Value: "guide"},
+			"missing key":      {Type: "eq", Value: "guide"},
+			"missing value":    {Type: "eq", Key: "category"},
+			"object value":     {Type: "eq", Key: "category", Value: map[string]interface{}{}},
+			"empty compound":   {Type: "and"},
+			"nested compound":  {Type: "and", Filters: []VectorStoreSearchFilter{{Type: "or", Filters: []VectorStoreSearchFilter{{Type: "eq", Key: "a", Value: "b"}}}}},
+			"invalid children": {Type: "and", Filters: []VectorStoreSearchFilter{{Type: "eq", Key: "category"}}},
+		} {
+			assert.Error(t, filter.Validate(), name)
+		}
+	})
+}
+
+func TestVectorStoreSearchFilter_Matches(t *testing.T) {
+	attributes := map[string]interface{}{
+		"department": "finance",
+		"date":       "2024-03-01",
+		"year":       float64(2024),
+		"public":     true,
+	}
+
+	for name, tc := range map[string]struct {
+		filter  VectorStoreSearchFilter
+		matches bool
+	}{
+		"equal string":           {VectorStoreSearchFilter{Type: "eq", Key: "department", Value: "finance"}, true},
+		"other string":           {VectorStoreSearchFilter{Type: "ne", Key: "department", Value: "finance"}, false},
+		"later date":             {VectorStoreSearchFilter{Type: "gte", Key: "date", Value: "2024-01-01"}, true},
+		"earlier number":         {VectorStoreSearchFilter{Type: "lt", Key: "year", Value: 2024}, false},
+		"number up to":           {VectorStoreSearchFilter{Type: "lte", Key: "year", Value: float64(2024)}, true},
+		"boolean":                {VectorStoreSearchFilter{Type: "eq", Key: "public", Value: true}, true},
+		"missing attribute":      {VectorStoreSearchFilter{Type: "ne", Key: "region", Value: "emea"}, false},
+		"value of another type":  {VectorStoreSearchFilter{Type: "eq", Key: "year", Value: "2024"}, false},
+		"all comparisons match":  {VectorStoreSearchFilter{Type: "and", Filters: []VectorStoreSearchFilter{{Type: "eq", Key: "department", Value: "finance"}, {Type: "gt", Key: "year", Value: 2023}}}, true},
+		"one comparison fails":   {VectorStoreSearchFilter{Type: "and", Filters: []VectorStoreSearchFilter{{Type: "eq", Key: "department", Value: "finance"}, {Type: "gt", Key: "year", Value: 2024}}}, false},
+		"any comparison matches": {VectorStoreSearchFilter{Type: "or", Filters: []VectorStoreSearchFilter{{Type: "eq", Key: "department", Value: "legal"}, {Type: "eq", Key: "public", Value: true}}}, true},
+		"no comparison matches":  {VectorStoreSearchFilter{Type: "or", Filters: []VectorStoreSearchFilter{{Type: "eq", Key: "department", Value: "legal"}, {Type: "eq", Key: "public", Value: false}}}, false},
+	} {
+		assert.Equal(t, tc.matches, tc.filter.Matches(attributes), name)
+	}
+}
+
+func TestPrepareResponseParams_FileSearch(t *testing.T) {
+	client := &LlamaStackClient{}
+	maxNumResults := int64(5)
+	scoreThreshold := 0.4
+
+	t.Run("should pass filters, max results and ranking options to the file search tool", func(t *testing.T) {
+		apiParams, err := client.prepareResponseParams(CreateResponseParams{
+			Input:          "What is the travel policy?",
+			Model:          "llama",
+			VectorStoreIDs: []string{"vs_policies"},
+			FileSearch: &FileSearchOptions{
+				MaxNumResults:  &maxNumResults,
+				ScoreThreshold: &scoreThreshold,
+				Ranker:         "auto",
+				Filters: &VectorStoreSearchFilter{Type: "and", Filters: []VectorStoreSearchFilter{
+					{Type: "eq", Key: "department", Value: "finance"},
+					{Type: "gte", Key: "year", Value: float64(2024)},
+				}},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, apiParams.Tools, 1)
+
+		data, err := json.Marshal(apiParams.Tools[0])
+		require.NoError(t, err)
+		var tool map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &tool))
+
+		assert.Equal(t, "file_search", tool["type"])
+		assert.Equal(t, []interface{}{"vs_policies"}, tool["vector_store_ids"])
+		assert.Equal(t, float64(5), tool["max_num_results"])
+		assert.Equal(t, map[string]interface{}{"ranker": "auto", "score_threshold": 0.4}, tool["ranking_options"])
+		assert.Equal(t, map[string]interface{}{
+			"type": "and",
+			"filters": []interface{}{
+				map[string]interface{}{"type": "eq", "key": "department", "value": "finance"},
+				map[string]interface{}{"type": "gte", "key": "year", "value": float64(2024)},
+			},
+		}, tool["filters"])
+	})
+
+	t.Run("should request the file search results when included", func(t *testing.T) {
+		apiParams, err := client.prepareResponseParams(CreateResponseParams{
+			Input:          "What is the travel policy?",
+			Model:          "llama",
+			VectorStoreIDs: []string{"vs_policies"},
+			Include:        []string{IncludeFileSearchResults},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []responses.ResponseIncludable{"file_search_call.results"}, apiParams.Include)
+	})
+
+	t.Run("should reject invalid options", func(t *testing.T) {
+		tooMany := int64(51)
+		_, err := client.prepareResponseParams(CreateResponseParams{
+			Input:          "Hello",
+			Model:          "llama",
+			VectorStoreIDs: []string{"vs_policies"},
+			FileSearch:     &FileSearchOptions{MaxNumResults: &tooMany},
+		})
+		assert.ErrorContains(t, err, "max_num_results must be between 1 and 50")
+	})
+}
+
+func TestPrepareResponseParams_ResponseFormat(t *testing.T) {
+	client := &LlamaStackClient{}
+
+	t.Run("should pass the JSON schema as the text format", func(t *testing.T) {
+		apiParams, err := client.prepareResponseParams(CreateResponseParams{
+			Input: "Extract the invoice",
+			Model: "llama",
+			ResponseFormat: &ResponseFormat{
+				Type:   ResponseFormatJSONSchema,
+				Name:   "invoice",
+				Schema: map[string]interface{}{"type": "object", "required": []interface{}{"total"}},
+				Strict: true,
+			},
+		})
+		require.NoError(t, err)
+
+		data, err := json.Marshal(apiParams.Text)
+		require.NoError(t, err)
+		var text map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &text))
+
+		assert.Equal(t, map[string]interface{}{
+			"type":   "json_schema",
+			"name":   "invoice",
+			"schema": map[string]interface{}{"type": "object", "required": []interface{}{"total"}},
+			"strict": true,
+		}, text["format"])
+	})
+
+	t.Run("should pass the JSON object format", func(t *testing.T) {
+		apiParams, err := client.prepareResponseParams(CreateResponseParams{
+			Input:          "Extract the invoice",
+			Model:          "llama",
+			ResponseFormat: &ResponseFormat{Type: ResponseFormatJSONObject},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, apiParams.Text.Format.OfJSONObject)
+	})
+
+	t.Run("should reject invalid formats", func(t *testing.T) {
+		for name, format := range map[string]ResponseFormat{
+			"unknown type":        {Type: "xml"},
+			"schema without name": {Type: ResponseFormatJSONSchema, Schema: map[string]interface{}{"type": "object"}},
+			"invalid name":        {Type: ResponseFormatJSONSchema, Name: "my invoice", Schema: map[string]interface{}{"type": "object"}},
+			"name without schema": {Type: ResponseFormatJSONSchema, Name: "invoice"},
+			"schema on JSON mode": {Type: ResponseFormatJSONObject, Schema: map[string]interface{}{"type": "object"}},
+			"strict on text":      {Type: ResponseFormatText, Strict: true},
+		} {
+			_, err := client.prepareResponseParams(CreateResponseParams{
+				Input:          "Extract the invoice",
+				Model:          "llama",
+				ResponseFormat: &format,
+			})
+			assert.ErrorContains(t, err, "invalid response_format", name)
+		}
+	})
+}
+
+func TestPrepareResponseParams_Generation(t *testing.T) {
+	client := &LlamaStackClient{}
+	maxOutputTokens := int64(512)
+	seed := int64(7)
+	frequencyPenalty := -0.5
+	parallelToolCalls := false
+
+	t.Run("should pass the generation settings, with stop, seed and penalties as extra fields", func(t *testing.T) {
+		apiParams, err := client.prepareResponseParams(CreateResponseParams{
+			Input:             "Hello",
+			Model:             "llama",
+			Tools:             []MCPServerParam{{ServerLabel: "github", ServerURL: "http://localhost:3000/sse"}},
+			MaxOutputTokens:   &maxOutputTokens,
+			Stop:              []string{"END"},
+			Seed:              &seed,
+			FrequencyPenalty:  &frequencyPenalty,
+			ParallelToolCalls: &parallelToolCalls,
+			ToolChoice:        &ToolChoice{Type: ToolChoiceTypeMCP, ServerLabel: "github", Name: "get_latest_release"},
+			Truncation:        TruncationDisabled,
+		})
+		require.NoError(t, err)
+
+		data, err := json.Marshal(apiParams)
+		require.NoError(t, err)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &body))
+
+		assert.Equal(t, float64(512), body["max_output_tokens"])
+		assert.Equal(t, []interface{}{"END"}, body["stop"])
+		assert.Equal(t, float64(7), body["seed"])
+		assert.Equal(t, -0.5, body["frequency_penalty"])
+		assert.NotContains(t, body, "presence_penalty")
+		assert.Equal(t, false, body["parallel_tool_calls"])
+		assert.Equal(t, map[string]interface{}{"type": "mcp", "server_label": "github", "name": "get_latest_release"}, body["tool_choice"])
+		assert.Equal(t, "disabled", body["truncation"])
+	})
+
+	t.Run("should pass a tool choice mode", func(t *testing.T) {
+		apiParams, err := client.prepareResponseParams(CreateResponseParams{
+			Input:      "Hello",
+			Model:      "llama",
+			ToolChoice: &ToolChoice{Mode: ToolChoiceNone},
+		})
+		require.NoError(t, err)
+
+		data, err := json.Marshal(apiParams.ToolChoice)
+		require.NoError(t, err)
+		assert.JSONEq(t, `"none"`, string(data))
+	})
+
+	t.Run("should reject settings out of range", func(t *testing.T) {
+		negative := int64(-1)
+		penalty := -2.1
+		for name, tc := range map[string]struct {
+			params CreateResponseParams
+			err    string
+		}{
+			"negative max tokens":    {CreateResponseParams{MaxOutputTokens: &negative}, "max_output_tokens must be at least 1"},
+			"empty stop sequence":    {CreateResponseParams{Stop: []string{""}}, "stop sequences cannot be empty"},
+			"presence penalty":       {CreateResponseParams{PresencePenalty: &penalty}, "presence_penalty must be between -2 and 2"},
+			"truncation":             {CreateResponseParams{Truncation: "none"}, "truncation must be auto or disabled"},
+			"mode and tool":          {CreateResponseParams{ToolChoice: &ToolChoice{Mode: ToolChoiceAuto, Type: ToolChoiceTypeFileSearch}}, "a mode cannot be combined with a tool"},
+			"MCP without server":     {CreateResponseParams{ToolChoice: &ToolChoice{Type: ToolChoiceTypeMCP}}, "server_label is required"},
+			"file search without vs": {CreateResponseParams{ToolChoice: &ToolChoice{Type: ToolChoiceTypeFileSearch}}, "requires vector stores"},
+		} {
+			tc.params.Input = "Hello"
+			tc.params.Model = "llama"
+			_, err := client.prepareResponseParams(tc.params)
+			assert.ErrorContains(t, err, tc.err, name)
+		}
+	})
+}
+
+func TestModelTypeAndEmbeddingDimension(t *testing.T) {
+	decode := func(t *testing.T, raw string) openai.Model {
+		var model openai.Model
+		require.NoError(t, json.Unmarshal([]byte(raw), &model))
+		return model
+	}
+
+	t.Run("should read the type and dimension LlamaStack adds to the model", func(t *testing.T) {
+		model := decode(t, `{"id": "all-minilm", "object": "model", "model_type": "embedding", "metadata": {"embedding_dimension": 384}}`)
+		assert.Equal(t, EmbeddingModelType, ModelType(model))
+		assert.Equal(t, int64(384), ModelEmbeddingDimension(model))
+	})
+
+	t.Run("should read the type and dimension from the custom metadata", func(t *testing.T) {
+		model := decode(t, `{"id": "granite-embedding-125m", "object": "model", "custom_metadata": {"model_type": "embedding", "embedding_dimension": 768}}`)
+		assert.Equal(t, EmbeddingModelType, ModelType(model))
+		assert.Equal(t, int64(768), ModelEmbeddingDimension(model))
+	})
+
+	t.Run("should report nothing for plain OpenAI models", func(t *testing.T) {
+		model := decode(t, `{"id": "llama3.2:3b", "object": "model", "owned_by": "llama_stack"}`)
+		assert.Empty(t, ModelType(model))
+		assert.Zero(t, ModelEmbeddingDimension(model))
+	})
+}
@@ -7,6 +7,9 @@ const (
 	EmbeddingModelType = "embedding"
 )
 
+// VectorIOAPI is the LlamaStack API of the providers that hold vector stores
+const VectorIOAPI = "vector_io"
+
 type APIResponse struct {
 	Data     interface{}       `json:"data"`
 	Metadata *ResponseMetadata `json:"metadata,omitempty"`
@@ -32,6 +35,17 @@ type ModelList struct {
 	Data []Model `json:"data"`
 }
 
+// Provider is a provider LlamaStack serves one of its APIs with
+type Provider struct {
+	API          string `json:"api"`
+	ProviderID   string `json:"provider_id"`
+	ProviderType string `json:"provider_type"`
+}
+
+type ProviderList struct {
+	Data []Provider `json:"data"`
+}
+
 type VectorDB struct {
 	EmbeddingDimension int64  `json:"embedding_dimension"`
 	EmbeddingModel     string `json:"embedding_model"`
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -20,6 +23,40 @@ type MockResponse struct {
 	ID     string
 	Model  string
 	Status string
+	Output []responses.ResponseOutputItemUnion
+}
+
+// Mock identifiers for the MCP tool approval flow
+const (
+	MockApprovalResponseID  = "resp_mock_approval123"
+	MockApprovalRequestID   = "mcpr_mock123"
+	MockApprovalServerLabel = "github"
+	MockMCPToolName         = "get_latest_release"
+	mockMCPToolArguments    = `{"owner":"llamastack","repo":"llama-stack"}`
+)
+
+// mockToolAllowed reports whether the server's allowed_tools filter lets the mock model call its tool
+func mockToolAllowed(server llamastack.MCPServerParam) bool {
+	if len(server.AllowedTools) == 0 {
+		return true
+	}
+	for _, tool := range server.AllowedTools {
+		if tool == MockMCPToolName {
+			return true
+		}
+	}
+	return false
+}
+
+// mockApprovalRequestItem returns the pending approval request the mock model raises for its tool call
+func mockApprovalRequestItem(serverLabel string) responses.ResponseOutputItemUnion {
+	return responses.ResponseOutputItemUnion{
+		ID:          MockApprovalRequestID,
+		Type:        "mcp_approval_request",
+		ServerLabel: serverLabel,
+		Name:        MockMCPToolName,
+		Arguments:   mockMCPToolArguments,
+	}
 }
 
 // MockLlamaStackClient provides a mock implementation of the LlamaStackClient for testing
@@ -57,98 +94,239 @@ func (m *MockLlamaStackClient) ListModels(ctx context.Context) ([]openai.Model,
 		}
 	}
 
-	return []openai.Model{
-		{
-			ID:      "ollama/llama3.2:3b",
-			Object:  "model",
-			Created: 1755721063,
-			OwnedBy: "llama_stack",
-		},
-		{
-			ID:      "ollama/all-minilm:l6-v2",
-			Object:  "model",
-			Created: 1755721063,
-			OwnedBy: "llama_stack",
-		},
-		{
-			ID:      "mistral-7b-instruct",
-			Object:  "model",
-			Created: 1755721063,
-			OwnedBy: "llama_stack",
-		},
-		{
-			ID:      "llama-3.1-8b-instruct",
-			Object:  "model",
-			Created: 1755721063,
-			OwnedBy: "llama_stack",
-		},
-	}, nil
+	// Models are decoded from JSON so that they carry the type and metadata LlamaStack adds to them
+	mockModels := []map[string]interface{}{
+		{"id": "ollama/llama3.2:3b", "model_type": llamastack.LLMModelType},
+		{"id": "ollama/all-minilm:l6-v2", "model_type": llamastack.EmbeddingModelType, "metadata": map[string]interface{}{"embedding_dimension": 384}},
+		{"id": "mistral-7b-instruct", "model_type": llamastack.LLMModelType},
+		{"id": "llama-3.1-8b-instruct", "model_type": llamastack.LLMModelType},
+		{"id": constants.DefaultEmbeddingModel.ModelID, "model_type": llamastack.EmbeddingModelType, "metadata": map[string]interface{}{"embedding_dimension": constants.DefaultEmbeddingModel.EmbeddingDimension}},
+	}
+
+	models := make([]openai.Model, len(mockModels))
+	for i, model := range mockModels {
+		model["object"] = "model"
+		model["created"] = 1755721063
+		model["owned_by"] = "llama_stack"
+		raw, err := json.Marshal(model)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &models[i]); err != nil {
+			return nil, err
+		}
+	}
+	return models, nil
 }
 
-// ListVectorStores returns mock vector store data with optional parameters
-func (m *MockLlamaStackClient) ListVectorStores(ctx context.Context, params llamastack.ListVectorStoresParams) ([]openai.VectorStore, error) {
-	return []openai.VectorStore{
-		{
-			ID:         "vs_mock123",
-			Object:     "vector_store",
-			CreatedAt:  1755721097,
-			Name:       "Mock Vector Store",
-			UsageBytes: 0,
-			FileCounts: openai.VectorStoreFileCounts{
-				InProgress: 0,
-				Completed:  1,
-				Failed:     0,
-				Cancelled:  0,
-				Total:      1,
-			},
-			Status:       "completed",
-			LastActiveAt: 1755721097,
-			Metadata: map[string]string{
-				"provider_id":           "milvus",
-				"provider_vector_db_id": "vs_mock123",
-			},
-			ExpiresAfter: openai.VectorStoreExpiresAfter{
-				Anchor: "last_active_at",
-				Days:   0,
-			},
-			ExpiresAt: 0,
-		},
-	}, nil
+// MockVectorIOProviderIDs are the vector_io providers of the mock LlamaStack
+var MockVectorIOProviderIDs = []string{constants.DefaultVectorStoreProvider, "faiss"}
+
+// ListProviders returns the mock providers of an API
+func (m *MockLlamaStackClient) ListProviders(ctx context.Context, api string) ([]llamastack.Provider, error) {
+	if api != llamastack.VectorIOAPI {
+		return []llamastack.Provider{}, nil
+	}
+
+	providers := make([]llamastack.Provider, 0, len(MockVectorIOProviderIDs))
+	for _, providerID := range MockVectorIOProviderIDs {
+		providers = append(providers, llamastack.Provider{
+			API:          llamastack.VectorIOAPI,
+			ProviderID:   providerID,
+			ProviderType: "inline::" + providerID,
+		})
+	}
+	return providers, nil
 }
 
-// CreateVectorStore returns a mock created vector store with optional parameters
-func (m *MockLlamaStackClient) CreateVectorStore(ctx context.Context, params llamastack.CreateVectorStoreParams) (*openai.VectorStore, error) {
-	name := params.Name
-	if name == "" {
-		name = "Mock Vector Store"
+// Mock vector stores. The mock user of the Kubernetes mocks owns MockPrivateVectorStoreID, another user owns
+// MockOtherUserVectorStoreID and shares MockSharedVectorStoreID with the namespace. MockLegacyVectorStoreID was
+// auto-provisioned for the mock user, so it is private to them, and MockVectorStoreID predates ownership metadata, so it is read-only.
+// Stores that are not listed belong to the user requests are made for when authentication is disabled, and are
+// shared with the namespace.
+const (
+	MockVectorStoreID          = "vs_mock123"
+	MockPrivateVectorStoreID   = "vs_mock_private123"
+	MockOtherUserVectorStoreID = "vs_mock_private456"
+	MockSharedVectorStoreID    = "vs_mock_shared321"
+	MockLegacyVectorStoreID    = "vs_mock_legacy789"
+	MockVectorStoreOwner       = "mockUser"
+	MockVectorStoreOtherOwner  = "otherUser"
+	MockUnauthenticatedOwner   = "anonymous"
+	MockVectorStoreNamespace   = "test-namespace"
+	// MockLegacyVectorStoreName is the hashed username the auto-provisioning used as name for the mock user
+	MockLegacyVectorStoreName = "2111b9c9eeae15df80c30f7300493670"
+)
+
+// MockEmbeddingVectorStoreID is a vector store created with the all-minilm embedding model.
+// It can be retrieved but is not listed, so that the listing stays the same.
+const MockEmbeddingVectorStoreID = "vs_mock_minilm654"
+
+// MockManyFilesVectorStoreID is a vector store with MockManyFilesCount files, more than one page of a listing holds.
+// Every 50th file is from the legal department, the others from engineering.
+const (
+	MockManyFilesVectorStoreID = "vs_mock_many987"
+	MockManyFilesCount         = 150
+)
+
+// MockMissingFileID is a file that is not in any mock vector store
+const MockMissingFileID = "file-mock-missing"
+
+// mockNotFoundError returns the error the OpenAI client reports when LlamaStack answers 404 Not Found
+func mockNotFoundError(path string) error {
+	return &openai.Error{
+		StatusCode: http.StatusNotFound,
+		Request:    &http.Request{Method: http.MethodGet, URL: &url.URL{Path: path}},
+		Response:   &http.Response{StatusCode: http.StatusNotFound},
+	}
+}
+
+// MockFileBatchID is the ID of the file batches created by the mock client
+const MockFileBatchID = "vsfb_mock123"
+
+// mockVectorStore builds a mock vector store with the provider metadata LlamaStack adds
+func mockVectorStore(id, name string, createdAt int64, metadata map[string]string) openai.VectorStore {
+	storeMetadata := map[string]string{
+		"provider_id":           "milvus",
+		"provider_vector_db_id": id,
+	}
+	for key, value := range metadata {
+		storeMetadata[key] = value
 	}
 
-	mockID := "vs_mock_new123"
-	return &openai.VectorStore{
-		ID:         mockID,
+	return openai.VectorStore{
+		ID:         id,
 		Object:     "vector_store",
-		CreatedAt:  1755721097,
+		CreatedAt:  createdAt,
 		Name:       name,
 		UsageBytes: 0,
 		FileCounts: openai.VectorStoreFileCounts{
 			InProgress: 0,
-			Completed:  0,
+			Completed:  1,
 			Failed:     0,
 			Cancelled:  0,
-			Total:      0,
+			Total:      1,
 		},
 		Status:       "completed",
-		LastActiveAt: 1755721097,
-		Metadata: map[string]string{
-			"provider_id":           "milvus",
-			"provider_vector_db_id": mockID,
-		},
+		LastActiveAt: createdAt,
+		Metadata:     storeMetadata,
 		ExpiresAfter: openai.VectorStoreExpiresAfter{
 			Anchor: "last_active_at",
 			Days:   0,
 		},
 		ExpiresAt: 0,
-	}, nil
+	}
+}
+
+// mockVectorStores returns the mock vector stores, newest first
+func mockVectorStores() []openai.VectorStore {
+	return []openai.VectorStore{
+		mockVectorStore(MockPrivateVectorStoreID, "Mock Private Vector Store", 1755721400, map[string]string{
+			constants.VectorStoreOwnerKey:      MockVectorStoreOwner,
+			constants.VectorStoreNamespaceKey:  MockVectorStoreNamespace,
+			constants.VectorStoreVisibilityKey: constants.VectorStoreVisibilityPrivate,
+		}),
+		mockVectorStore(MockSharedVectorStoreID, "Shared Vector Store", 1755721350, map[string]string{
+			constants.VectorStoreOwnerKey:      MockVectorStoreOtherOwner,
+			constants.VectorStoreNamespaceKey:  MockVectorStoreNamespace,
+			constants.VectorStoreVisibilityKey: constants.VectorStoreVisibilityNamespace,
+		}),
+		mockVectorStore(MockOtherUserVectorStoreID, "Other User Vector Store", 1755721300, map[string]string{
+			constants.VectorStoreOwnerKey:      MockVectorStoreOtherOwner,
+			constants.VectorStoreNamespaceKey:  MockVectorStoreNamespace,
+			constants.VectorStoreVisibilityKey: constants.VectorStoreVisibilityPrivate,
+		}),
+		mockVectorStore(MockLegacyVectorStoreID, MockLegacyVectorStoreName, 1755721200, map[string]string{
+			constants.VectorStoreCreatedByKey:               constants.VectorStoreAutoProvisionedCreatedBy,
+			constants.VectorStoreAutoProvisionedUsernameKey: MockVectorStoreOwner,
+		}),
+		mockVectorStore(MockVectorStoreID, "Mock Vector Store", 1755721097, nil),
+	}
+}
+
+// ListVectorStores returns mock vector store data with optional parameters
+func (m *MockLlamaStackClient) ListVectorStores(ctx context.Context, params llamastack.ListVectorStoresParams) ([]openai.VectorStore, error) {
+	vectorStores := mockVectorStores()
+	if params.Order == "asc" {
+		slices.Reverse(vectorStores)
+	}
+	return vectorStores, nil
+}
+
+// CreateVectorStore returns a mock created vector store with optional parameters
+func (m *MockLlamaStackClient) CreateVectorStore(ctx context.Context, params llamastack.CreateVectorStoreParams) (*openai.VectorStore, error) {
+	name := params.Name
+	if name == "" {
+		name = "Mock Vector Store"
+	}
+
+	vectorStore := mockVectorStore("vs_mock_new123", name, 1755721097, params.Metadata)
+	vectorStore.FileCounts = openai.VectorStoreFileCounts{}
+	if params.ProviderID != "" {
+		vectorStore.Metadata["provider_id"] = params.ProviderID
+	}
+	return &vectorStore, nil
+}
+
+// GetVectorStore returns the mock vector store with the given ID, or a mock vector store of the unauthenticated
+// user for unknown IDs
+func (m *MockLlamaStackClient) GetVectorStore(ctx context.Context, vectorStoreID string) (*openai.VectorStore, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+
+	for _, vectorStore := range mockVectorStores() {
+		if vectorStore.ID == vectorStoreID {
+			return &vectorStore, nil
+		}
+	}
+
+	if vectorStoreID == MockEmbeddingVectorStoreID {
+		vectorStore := mockVectorStore(vectorStoreID, "MiniLM Vector Store", 1755721097, mockUnauthenticatedOwnership(map[string]string{
+			constants.VectorStoreEmbeddingModelKey:     "ollama/all-minilm:l6-v2",
+			constants.VectorStoreEmbeddingDimensionKey: "384",
+		}))
+		return &vectorStore, nil
+	}
+
+	vectorStore := mockVectorStore(vectorStoreID, "Mock Vector Store", 1755721097, mockUnauthenticatedOwnership(nil))
+	return &vectorStore, nil
+}
+
+// mockUnauthenticatedOwnership adds the ownership of the unauthenticated user, shared with the namespace, to metadata
+func mockUnauthenticatedOwnership(metadata map[string]string) map[string]string {
+	owned := map[string]string{
+		constants.VectorStoreOwnerKey:      MockUnauthenticatedOwner,
+		constants.VectorStoreNamespaceKey:  MockVectorStoreNamespace,
+		constants.VectorStoreVisibilityKey: constants.VectorStoreVisibilityNamespace,
+	}
+	for key, value := range metadata {
+		owned[key] = value
+	}
+	return owned
+}
+
+// UpdateVectorStore returns the mock vector store with the update applied
+func (m *MockLlamaStackClient) UpdateVectorStore(ctx context.Context, vectorStoreID string, params llamastack.UpdateVectorStoreParams) (*openai.VectorStore, error) {
+	vectorStore, err := m.GetVectorStore(ctx, vectorStoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Name != nil {
+		vectorStore.Name = *params.Name
+	}
+	if params.Metadata != nil {
+		vectorStore.Metadata = params.Metadata
+	}
+	if params.ExpiresAfterDays != nil {
+		vectorStore.ExpiresAfter.Days = *params.ExpiresAfterDays
+		vectorStore.ExpiresAt = 0
+		if *params.ExpiresAfterDays > 0 {
+			vectorStore.ExpiresAt = vectorStore.LastActiveAt + *params.ExpiresAfterDays*24*60*60
+		}
+	}
+	return vectorStore, nil
 }
 
 // UploadFile uploads a file with optional parameters and optionally adds to vector store
@@ -158,6 +336,24 @@ func (m *MockLlamaStackClient) UploadFile(ctx context.Context, params llamastack
 		FileID: mockFileID,
 	}
 
+	// Consume the content like a real upload, so remote sources are fetched
+	reader := params.Reader
+	if params.Source != nil {
+		document, err := params.Source.Open(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", params.Source.URI(), err)
+		}
+		defer document.Body.Close()
+		reader = document.Body
+	}
+	if reader != nil {
+		n, err := io.Copy(io.Discard, reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload file: %w", err)
+		}
+		result.Bytes = n
+	}
+
 	// If vector store ID is provided, simulate adding to vector store
 	if params.VectorStoreID != "" {
 		result.VectorStoreFile = &openai.VectorStoreFile{
@@ -200,29 +396,59 @@ func (m *MockLlamaStackClient) CreateResponse(ctx context.Context, params llamas
 
 	// If MCP tools are provided, simulate MCP tool interactions
 	if len(params.Tools) > 0 {
-		// Add mock MCP list tools output
-		outputItems = append(outputItems, responses.ResponseOutputItemUnion{
-			ID:          "mcp_list_mock123",
-			Type:        "mcp_list_tools",
-			Role:        "assistant",
-			ServerLabel: params.Tools[0].ServerLabel,
-		})
+		server := params.Tools[0]
 
 		// Add mock MCP tool call with realistic GitHub API output
 		mcpOutput := `{"tag_name":"v1.95.0","name":"Mock Release","body":"This is a mock GitHub release with realistic data structure","published_at":"2025-09-17T15:00:00Z","author":{"login":"mock-user","id":12345}}`
-
-		outputItems = append(outputItems, responses.ResponseOutputItemUnion{
+		mcpCall := responses.ResponseOutputItemUnion{
 			ID:          "call_mock456",
 			Type:        "mcp_call",
 			Role:        "assistant",
-			ServerLabel: params.Tools[0].ServerLabel,
-			Name:        "get_latest_release",
-			Arguments:   `{"owner":"llamastack","repo":"llama-stack"}`,
+			ServerLabel: server.ServerLabel,
+			Name:        MockMCPToolName,
+			Arguments:   mockMCPToolArguments,
 			Output:      mcpOutput,
-		})
+		}
+
+		switch {
+		case len(params.MCPApprovalResponses) > 0:
+			// Continue a response that was waiting for tool approval
+			if params.MCPApprovalResponses[0].Approve {
+				outputItems = append(outputItems, mcpCall)
+				responseText = "Continuing from previous response " + params.PreviousResponseID + ". Based on the approved GitHub MCP tool call, the latest release is v1.95.0."
+			} else {
+				responseText = "Continuing from previous response " + params.PreviousResponseID + ". The " + MockMCPToolName + " tool call was denied, so I can't look up the latest release."
+			}
+		case !mockToolAllowed(server):
+			// The tool is filtered out by allowed_tools, so the model answers without it
+		default:
+			// Add mock MCP list tools output
+			outputItems = append(outputItems, responses.ResponseOutputItemUnion{
+				ID:          "mcp_list_mock123",
+				Type:        "mcp_list_tools",
+				Role:        "assistant",
+				ServerLabel: server.ServerLabel,
+			})
+
+			// Stop and wait for the user when the tool call requires approval
+			if mockRequiresApproval(server.RequireApproval, MockMCPToolName) {
+				outputItems = append(outputItems, mockApprovalRequestItem(server.ServerLabel))
+				return &responses.Response{
+					ID:        MockApprovalResponseID,
+					Object:    "response",
+					CreatedAt: 1234567890.0,
+					Model:     params.Model,
+					Status:    "completed",
+					Metadata:  map[string]string{},
+					Output:    outputItems,
+				}, nil
+			}
+
+			outputItems = append(outputItems, mcpCall)
 
-		// Update response text to reflect MCP tool usage
-		responseText = "Based on the GitHub MCP tool results, the latest release is v1.95.0. " + responseText
+			// Update response text to reflect MCP tool usage
+			responseText = "Based on the GitHub MCP tool results, the latest release is v1.95.0. " + responseText
+		}
 	}
 
 	// If vector stores are provided, simulate file search call
@@ -240,9 +466,11 @@ func (m *MockLlamaStackClient) CreateResponse(ctx context.Context, params llamas
 		lastItem := &outputItems[len(outputItems)-1]
 		results := []map[string]interface{}{
 			{
-				"score":    0.8542,
-				"text":     "This is mock retrieved content that relates to your query: " + params.Input + ". This content comes from the vector store and provides context for the AI response.",
-				"filename": "mock_document.txt",
+				"score":      0.8542,
+				"text":       "This is mock retrieved content that relates to your query: " + params.Input + ". This content comes from the vector store and provides context for the AI response.",
+				"file_id":    "file-mock123abc456def",
+				"filename":   "mock_document.txt",
+				"attributes": map[string]interface{}{"department": "engineering", "page": 3},
 			},
 		}
 
@@ -265,8 +493,11 @@ func (m *MockLlamaStackClient) CreateResponse(ctx context.Context, params llamas
 		responseText = "Based on retrieved documents, this is a mock response to your query: " + params.Input
 	}
 
+	responseText += mockInputContentText(params.InputContent)
+	responseText = mockStructuredText(params.ResponseFormat, responseText)
+
 	// Add message content
-	outputItems = append(outputItems, responses.ResponseOutputItemUnion{
+	messageItem := responses.ResponseOutputItemUnion{
 		ID:     "msg_mock123",
 		Type:   "message",
 		Role:   "assistant",
@@ -277,7 +508,25 @@ func (m *MockLlamaStackClient) CreateResponse(ctx context.Context, params llamas
 				Text: responseText,
 			},
 		},
-	})
+	}
+	if len(params.VectorStoreIDs) > 0 {
+		// Cite the retrieved document by file ID only, like LlamaStack does
+		messageJSON, err := json.Marshal(map[string]interface{}{
+			"id":     messageItem.ID,
+			"type":   messageItem.Type,
+			"role":   messageItem.Role,
+			"status": messageItem.Status,
+			"content": []map[string]interface{}{{
+				"type":        "output_text",
+				"text":        responseText,
+				"annotations": []map[string]interface{}{mockFileCitation(len(responseText), "")},
+			}},
+		})
+		if err == nil {
+			_ = json.Unmarshal(messageJSON, &messageItem)
+		}
+	}
+	outputItems = append(outputItems, messageItem)
 
 	// Create mock response with proper Output structure
 	mockResponse := &responses.Response{
@@ -288,11 +537,63 @@ func (m *MockLlamaStackClient) CreateResponse(ctx context.Context, params llamas
 		Status:    "completed",
 		Metadata:  map[string]string{},
 		Output:    outputItems,
+		Usage:     mockUsage(params.Input, responseText),
 	}
 
 	return mockResponse, nil
 }
 
+// mockUsage counts the words of the input and output as their tokens
+func mockUsage(input, output string) responses.ResponseUsage {
+	inputTokens := int64(len(strings.Fields(input)))
+	outputTokens := int64(len(strings.Fields(output)))
+	return responses.ResponseUsage{
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		TotalTokens:  inputTokens + outputTokens,
+	}
+}
+
+// mockInputContentText describes the images and files of the input, as a vision model would
+func mockInputContentText(parts []llamastack.InputContentPart) string {
+	images, files := 0, 0
+	for _, part := range parts {
+		switch part.Type {
+		case llamastack.InputContentImage:
+			images++
+		case llamastack.InputContentFile:
+			files++
+		}
+	}
+	if images == 0 && files == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (looked at %d images and %d files)", images, files)
+}
+
+// mockStructuredText returns the mock response text as the JSON a response format asks for: an object with the
+// text as its answer property
+func mockStructuredText(format *llamastack.ResponseFormat, text string) string {
+	if format == nil || format.Type == llamastack.ResponseFormatText {
+		return text
+	}
+	data, err := json.Marshal(map[string]string{"answer": text})
+	if err != nil {
+		return text
+	}
+	return string(data)
+}
+
+// mockFileCitation returns a file citation of the mock document at a position of the output text
+func mockFileCitation(index int, filename string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "file_citation",
+		"file_id":  "file-mock123abc456def",
+		"filename": filename,
+		"index":    index,
+	}
+}
+
 // MockStreamError indicates mock streaming mode and provides response data
 type MockStreamError struct {
 	Message      string
@@ -327,21 +628,47 @@ func (m *MockLlamaStackClient) HandleMockStreaming(w http.ResponseWriter, flushe
 	// Mock identifiers
 	responseID := "resp_mock_stream123"
 	itemID := "msg_mock_stream123"
+	sequenceNum := 0
 
-	// Helper function to send SSE event
-	sendEvent := func(eventData interface{}) {
+	// Helper function to send SSE event using the same event names as the real handler
+	sendEvent := func(eventName string, eventData map[string]interface{}) {
+		eventData["sequence_number"] = sequenceNum
+		sequenceNum++
 		if data, err := json.Marshal(eventData); err == nil {
-			fmt.Fprintf(w, "data: %s\n\n", data)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventName, data)
 		}
 	}
 
+	// sendItemEvents sends the added/done events for an output item around its progress events
+	sendItemEvents := func(outputIndex int, item map[string]interface{}, progressEventName string, progressTypes []string, delay time.Duration) {
+		sendEvent("output_item", map[string]interface{}{
+			"type":         "response.output_item.added",
+			"output_index": outputIndex,
+			"item":         item,
+		})
+		for _, progressType := range progressTypes {
+			sendEvent(progressEventName, map[string]interface{}{
+				"type":         progressType,
+				"item_id":      item["id"],
+				"output_index": outputIndex,
+			})
+			time.Sleep(delay)
+			flusher.Flush()
+		}
+		sendEvent("output_item", map[string]interface{}{
+			"type":         "response.output_item.done",
+			"output_index": outputIndex,
+			"item":         item,
+		})
+		flusher.Flush()
+	}
+
 	// 1. Response created event
-	sendEvent(map[string]interface{}{
-		"type":            "response.created",
-		"sequence_number": 0,
-		"item_id":         "",
-		"output_index":    0,
-		"delta":           "",
+	sendEvent("response", map[string]interface{}{
+		"type":         "response.created",
+		"item_id":      "",
+		"output_index": 0,
+		"delta":        "",
 		"response": map[string]interface{}{
 			"id":         responseID,
 			"model":      params.Model,
@@ -354,64 +681,166 @@ func (m *MockLlamaStackClient) HandleMockStreaming(w http.ResponseWriter, flushe
 	time.Sleep(200 * time.Millisecond)
 	flusher.Flush()
 
-	// 2. If MCP tools provided, simulate MCP tool processing
-	sequenceNum := 1
-	if len(params.Tools) > 0 {
-		// Simulate MCP list tools event
-		sendEvent(map[string]interface{}{
-			"type":            "mcp_list_tools",
-			"sequence_number": sequenceNum,
-			"item_id":         "mcp_list_mock123",
-			"output_index":    0,
-			"delta":           "",
-			"server_label":    params.Tools[0].ServerLabel,
-		})
-		sequenceNum++
-		time.Sleep(300 * time.Millisecond)
-		flusher.Flush()
+	var outputItems []map[string]interface{}
 
-		// Simulate MCP tool call event
-		mcpOutput := `{"tag_name":"v1.95.0","name":"Mock Release","body":"This is a mock GitHub release","published_at":"2025-09-17T15:00:00Z","author":{"login":"mock-user","id":12345}}`
-		sendEvent(map[string]interface{}{
-			"type":            "mcp_call",
-			"sequence_number": sequenceNum,
-			"item_id":         "call_mock456",
-			"output_index":    0,
-			"delta":           "",
-			"server_label":    params.Tools[0].ServerLabel,
-			"name":            "get_latest_release",
-			"arguments":       `{"owner":"llamastack","repo":"llama-stack"}`,
-			"output":          mcpOutput,
-		})
-		sequenceNum++
-		time.Sleep(500 * time.Millisecond)
-		flusher.Flush()
+	// 2. If MCP tools provided, simulate MCP tool discovery and a tool call
+	if len(params.Tools) > 0 {
+		server := params.Tools[0]
+		continuingApproval := len(params.MCPApprovalResponses) > 0
+		callTool := mockToolAllowed(server)
+
+		if continuingApproval {
+			// Run the tool only if the pending call was approved
+			callTool = params.MCPApprovalResponses[0].Approve
+			if !callTool {
+				responseText = "Continuing from previous response " + params.PreviousResponseID + ". The " + MockMCPToolName + " tool call was denied, so I can't look up the latest release."
+			}
+		} else if callTool {
+			listToolsItem := map[string]interface{}{
+				"id":           "mcp_list_mock123",
+				"type":         "mcp_list_tools",
+				"server_label": server.ServerLabel,
+				"tools": []map[string]interface{}{
+					{"name": MockMCPToolName, "description": "Get the latest release of a repository"},
+				},
+			}
+			sendItemEvents(len(outputItems), listToolsItem, "mcp_list_tools",
+				[]string{"response.mcp_list_tools.in_progress", "response.mcp_list_tools.completed"}, 150*time.Millisecond)
+			outputItems = append(outputItems, listToolsItem)
+
+			// Stop and wait for the user when the tool call requires approval
+			if mockRequiresApproval(server.RequireApproval, MockMCPToolName) {
+				approvalItem := map[string]interface{}{
+					"id":           MockApprovalRequestID,
+					"type":         "mcp_approval_request",
+					"server_label": server.ServerLabel,
+					"name":         MockMCPToolName,
+					"arguments":    mockMCPToolArguments,
+				}
+				sendEvent("output_item", map[string]interface{}{
+					"type":         "response.output_item.added",
+					"output_index": len(outputItems),
+					"item":         approvalItem,
+				})
+				sendEvent("mcp_approval_request", map[string]interface{}{
+					"type":         "response.output_item.done",
+					"output_index": len(outputItems),
+					"item":         approvalItem,
+				})
+				outputItems = append(outputItems, approvalItem)
+
+				sendEvent("response", map[string]interface{}{
+					"type":         "response.completed",
+					"item_id":      "",
+					"output_index": 0,
+					"delta":        "",
+					"response": map[string]interface{}{
+						"id":                MockApprovalResponseID,
+						"model":             params.Model,
+						"status":            "completed",
+						"created_at":        1234567890.0,
+						"output":            outputItems,
+						"requires_approval": true,
+					},
+				})
+				flusher.Flush()
+				return
+			}
+		}
 
-		// Update response text to reflect MCP tool usage
-		responseText = "Based on the GitHub MCP tool results, the latest release is v1.95.0. " + responseText
+		if callTool {
+			mcpOutput := `{"tag_name":"v1.95.0","name":"Mock Release","body":"This is a mock GitHub release","published_at":"2025-09-17T15:00:00Z","author":{"login":"mock-user","id":12345}}`
+			mcpCallItem := map[string]interface{}{
+				"id":           "call_mock456",
+				"type":         "mcp_call",
+				"server_label": server.ServerLabel,
+				"name":         MockMCPToolName,
+				"arguments":    mockMCPToolArguments,
+				"output":       mcpOutput,
+			}
+			sendEvent("output_item", map[string]interface{}{
+				"type":         "response.output_item.added",
+				"output_index": len(outputItems),
+				"item":         mcpCallItem,
+			})
+			sendEvent("mcp_call", map[string]interface{}{
+				"type":         "response.mcp_call.in_progress",
+				"item_id":      mcpCallItem["id"],
+				"output_index": len(outputItems),
+			})
+			sendEvent("mcp_call", map[string]interface{}{
+				"type":         "response.mcp_call_arguments.done",
+				"item_id":      mcpCallItem["id"],
+				"output_index": len(outputItems),
+				"arguments":    mockMCPToolArguments,
+			})
+			time.Sleep(500 * time.Millisecond)
+			sendEvent("mcp_call", map[string]interface{}{
+				"type":         "response.mcp_call.completed",
+				"item_id":      mcpCallItem["id"],
+				"output_index": len(outputItems),
+			})
+			sendEvent("output_item", map[string]interface{}{
+				"type":         "response.output_item.done",
+				"output_index": len(outputItems),
+				"item":         mcpCallItem,
+			})
+			flusher.Flush()
+			outputItems = append(outputItems, mcpCallItem)
+
+			// Update response text to reflect MCP tool usage
+			if continuingApproval {
+				responseText = "Continuing from previous response " + params.PreviousResponseID + ". Based on the approved GitHub MCP tool call, the latest release is v1.95.0."
+			} else {
+				responseText = "Based on the GitHub MCP tool results, the latest release is v1.95.0. " + responseText
+			}
+		}
 	}
 
 	// 3. If vector stores provided, simulate RAG processing
 	if len(params.VectorStoreIDs) > 0 {
-		// RAG processing happens in background (skip some sequence numbers)
-		sequenceNum += 3 // Skip some numbers for RAG background processing
-		time.Sleep(500 * time.Millisecond)
+		fileSearchItem := map[string]interface{}{
+			"id":      "call_mock123",
+			"type":    "file_search_call",
+			"status":  "completed",
+			"queries": []string{params.Input},
+			"results": []map[string]interface{}{
+				{
+					"file_id":    "file-mock123abc456def",
+					"filename":   "mock_document.txt",
+					"score":      0.8542,
+					"text":       "This is mock retrieved content that relates to your query: " + params.Input + ". This content comes from the vector store and provides context for the AI response.",
+					"attributes": map[string]interface{}{"department": "engineering", "page": 3},
+				},
+			},
+		}
+		sendItemEvents(len(outputItems), fileSearchItem, "file_search",
+			[]string{"response.file_search_call.in_progress", "response.file_search_call.searching", "response.file_search_call.completed"}, 200*time.Millisecond)
+		outputItems = append(outputItems, fileSearchItem)
 	}
 
+	responseText += mockInputContentText(params.InputContent)
+	responseText = mockStructuredText(params.ResponseFormat, responseText)
+
 	// 4. Content part added event
-	sendEvent(map[string]interface{}{
-		"type":            "response.content_part.added",
-		"sequence_number": sequenceNum,
-		"item_id":         itemID,
-		"output_index":    0,
-		"delta":           "",
+	messageIndex := len(outputItems)
+	sendEvent("content_part", map[string]interface{}{
+		"type":          "response.content_part.added",
+		"item_id":       itemID,
+		"output_index":  messageIndex,
+		"content_index": 0,
+		"delta":         "",
+		"part": map[string]interface{}{
+			"type": "output_text",
+			"text": "",
+		},
 	})
 
 	// Small delay before starting text generation
 	time.Sleep(150 * time.Millisecond)
 	flusher.Flush()
 
-	// 4. Split text into words and send as delta events (like real streaming)
+	// 5. Split text into words and send as delta events (like real streaming)
 	words := strings.Fields(responseText)
 	for i, word := range words {
 		// Add space before each word except the first
@@ -420,12 +849,12 @@ func (m *MockLlamaStackClient) HandleMockStreaming(w http.ResponseWriter, flushe
 			chunk = " " + word
 		}
 
-		sendEvent(map[string]interface{}{
-			"type":            "response.output_text.delta",
-			"sequence_number": sequenceNum + 1 + i,
-			"item_id":         itemID,
-			"output_index":    0,
-			"delta":           chunk,
+		sendEvent("text", map[string]interface{}{
+			"type":          "response.output_text.delta",
+			"item_id":       itemID,
+			"output_index":  messageIndex,
+			"content_index": 0,
+			"delta":         chunk,
 		})
 
 		// Add realistic delay between chunks to simulate real streaming
@@ -433,67 +862,49 @@ func (m *MockLlamaStackClient) HandleMockStreaming(w http.ResponseWriter, flushe
 		flusher.Flush()
 	}
 
-	// 5. Content part done event
-	sendEvent(map[string]interface{}{
-		"type":            "response.content_part.done",
-		"sequence_number": sequenceNum + 1 + len(words),
-		"item_id":         itemID,
-		"output_index":    0,
-		"delta":           "",
+	sendEvent("text", map[string]interface{}{
+		"type":          "response.output_text.done",
+		"item_id":       itemID,
+		"output_index":  messageIndex,
+		"content_index": 0,
+		"text":          responseText,
 	})
 
-	// Brief delay before completion
-	time.Sleep(100 * time.Millisecond)
-	if flusher, ok := w.(http.Flusher); ok {
-		flusher.Flush()
-	}
-
-	// 6. Response completed event
-	var outputItems []map[string]interface{}
-
-	// Add MCP tool outputs to completed response if MCP tools were used
-	if len(params.Tools) > 0 {
-		// Add MCP list tools output
-		outputItems = append(outputItems, map[string]interface{}{
-			"id":           "mcp_list_mock123",
-			"type":         "mcp_list_tools",
-			"role":         "assistant",
-			"server_label": params.Tools[0].ServerLabel,
-			"output":       "",
-		})
-
-		// Add MCP tool call output
-		mcpOutput := `{"tag_name":"v1.95.0","name":"Mock Release","body":"This is a mock GitHub release","published_at":"2025-09-17T15:00:00Z","author":{"login":"mock-user","id":12345}}`
-		outputItems = append(outputItems, map[string]interface{}{
-			"id":           "call_mock456",
-			"type":         "mcp_call",
-			"role":         "assistant",
-			"server_label": params.Tools[0].ServerLabel,
-			"name":         "get_latest_release",
-			"arguments":    `{"owner":"llamastack","repo":"llama-stack"}`,
-			"output":       mcpOutput,
-		})
-	}
-
-	// Add tool call to completed response if vector stores were used
+	// Cite the retrieved document at the end of the text
+	annotations := []map[string]interface{}{}
 	if len(params.VectorStoreIDs) > 0 {
-		outputItems = append(outputItems, map[string]interface{}{
-			"id":      "call_mock123",
-			"type":    "file_search_call",
-			"role":    "assistant",
-			"status":  "completed",
-			"queries": []string{params.Input},
-			"results": []map[string]interface{}{
-				{
-					"filename": "mock_document.txt",
-					"score":    0.8542,
-					"text":     "This is mock retrieved content that relates to your query: " + params.Input + ". This content comes from the vector store and provides context for the AI response.",
-				},
-			},
+		annotation := mockFileCitation(len(responseText), "mock_document.txt")
+		annotation["text"] = "This is mock retrieved content that relates to your query: " + params.Input + ". This content comes from the vector store and provides context for the AI response."
+		sendEvent("annotation", map[string]interface{}{
+			"type":             "response.output_text.annotation.added",
+			"item_id":          itemID,
+			"output_index":     messageIndex,
+			"content_index":    0,
+			"annotation_index": 0,
+			"annotation":       annotation,
 		})
+		annotations = append(annotations, annotation)
 	}
 
-	// Add message content
+	// 6. Content part done event
+	sendEvent("content_part", map[string]interface{}{
+		"type":          "response.content_part.done",
+		"item_id":       itemID,
+		"output_index":  messageIndex,
+		"content_index": 0,
+		"delta":         "",
+		"part": map[string]interface{}{
+			"type":        "output_text",
+			"text":        responseText,
+			"annotations": annotations,
+		},
+	})
+
+	// Brief delay before completion
+	time.Sleep(100 * time.Millisecond)
+	flusher.Flush()
+
+	// 7. Response completed event
 	outputItems = append(outputItems, map[string]interface{}{
 		"id":     itemID,
 		"type":   "message",
@@ -501,26 +912,28 @@ func (m *MockLlamaStackClient) HandleMockStreaming(w http.ResponseWriter, flushe
 		"status": "completed",
 		"content": []map[string]interface{}{
 			{
-				"type": "output_text",
-				"text": responseText,
+				"type":        "output_text",
+				"text":        responseText,
+				"annotations": annotations,
 			},
 		},
 	})
 
-	sendEvent(map[string]interface{}{
-		"type":            "response.completed",
-		"sequence_number": 0,
-		"item_id":         "",
-		"output_index":    0,
-		"delta":           "",
+	sendEvent("response", map[string]interface{}{
+		"type":         "response.completed",
+		"item_id":      "",
+		"output_index": 0,
+		"delta":        "",
 		"response": map[string]interface{}{
 			"id":         responseID,
 			"model":      params.Model,
 			"status":     "completed",
 			"created_at": 1234567890.0,
 			"output":     outputItems,
+			"usage":      mockUsage(params.Input, responseText),
 		},
 	})
+	flusher.Flush()
 }
 
 // CreateResponseStream returns an error that indicates mock streaming mode
@@ -634,6 +1047,18 @@ func (m *MockLlamaStackClient) GetFile(ctx context.Context, fileID string) (*ope
 	}, nil
 }
 
+// GetFileContent returns mock text content of a file, reported as application/octet-stream like LlamaStack does
+func (m *MockLlamaStackClient) GetFileContent(ctx context.Context, fileID string) (*llamastack.FileContent, error) {
+	if fileID == "" {
+		return nil, fmt.Errorf("fileID is required")
+	}
+
+	return &llamastack.FileContent{
+		Body:        io.NopCloser(strings.NewReader("Mock content of " + fileID + ".\nIt spans several lines of extracted text.\n")),
+		ContentType: "application/octet-stream",
+	}, nil
+}
+
 // DeleteFile returns success for mock deletion
 func (m *MockLlamaStackClient) DeleteFile(ctx context.Context, fileID string) error {
 	if fileID == "" {
@@ -661,6 +1086,18 @@ func (m *MockLlamaStackClient) GetResponse(ctx context.Context, responseID strin
 			Model:     responses.ResponsesModel(mockResp.Model),
 			Status:    responses.ResponseStatus(mockResp.Status),
 			CreatedAt: 1234567890,
+			Output:    mockResp.Output,
+		}, nil
+	}
+
+	// Mock response waiting for an MCP tool approval
+	if responseID == MockApprovalResponseID {
+		return &responses.Response{
+			ID:        responseID,
+			Model:     "llama-3.1-8b",
+			Status:    "completed",
+			CreatedAt: 1234567890,
+			Output:    []responses.ResponseOutputItemUnion{mockApprovalRequestItem(MockApprovalServerLabel)},
 		}, nil
 	}
 
@@ -679,7 +1116,7 @@ func (m *MockLlamaStackClient) ListVectorStoreFiles(ctx context.Context, vectorS
 		return nil, fmt.Errorf("vectorStoreID is required")
 	}
 
-	return []openai.VectorStoreFile{
+	files := []openai.VectorStoreFile{
 		{
 			ID:            "file-mock123abc456def",
 			Object:        "vector_store.file",
@@ -691,7 +1128,7 @@ func (m *MockLlamaStackClient) ListVectorStoreFiles(ctx context.Context, vectorS
 				Code:    "",
 				Message: "",
 			},
-			Attributes: map[string]openai.VectorStoreFileAttributeUnion{},
+			Attributes: mockFileAttributes(map[string]interface{}{"department": "engineering", "year": 2024}),
 			ChunkingStrategy: openai.FileChunkingStrategyUnion{
 				Type: "auto",
 				Static: openai.StaticFileChunkingStrategy{
@@ -711,7 +1148,7 @@ func (m *MockLlamaStackClient) ListVectorStoreFiles(ctx context.Context, vectorS
 				Code:    "",
 				Message: "",
 			},
-			Attributes: map[string]openai.VectorStoreFileAttributeUnion{},
+			Attributes: mockFileAttributes(map[string]interface{}{"department": "legal", "year": 2023}),
 			ChunkingStrategy: openai.FileChunkingStrategyUnion{
 				Type: "auto",
 				Static: openai.StaticFileChunkingStrategy{
@@ -720,9 +1157,195 @@ func (m *MockLlamaStackClient) ListVectorStoreFiles(ctx context.Context, vectorS
 				},
 			},
 		},
+	}
+	if vectorStoreID == MockManyFilesVectorStoreID {
+		files = make([]openai.VectorStoreFile, MockManyFilesCount)
+		for i := range files {
+			department := "engineering"
+			if i%50 == 49 {
+				department = "legal"
+			}
+			files[i] = openai.VectorStoreFile{
+				ID:            fmt.Sprintf("file-mock-many%03d", i),
+				Object:        "vector_store.file",
+				CreatedAt:     1755721386 + int64(i),
+				VectorStoreID: vectorStoreID,
+				Status:        "completed",
+				Attributes:    mockFileAttributes(map[string]interface{}{"department": department}),
+			}
+		}
+	}
+
+	// Pages follow the after cursor and hold at most limit files, 20 by default, like LlamaStack
+	if params.After != "" {
+		index := slices.IndexFunc(files, func(file openai.VectorStoreFile) bool { return file.ID == params.After })
+		files = files[index+1:]
+	}
+	limit := 20
+	if params.Limit != nil {
+		limit = int(*params.Limit)
+	}
+	if len(files) > limit {
+		files = files[:limit]
+	}
+	return files, nil
+}
+
+// mockRequiresApproval reports whether LlamaStack would stop for approval of a call to the tool under the policy.
+// As in LlamaStack, tools listed in neither tool list require approval.
+func mockRequiresApproval(policy *llamastack.MCPApprovalPolicy, toolName string) bool {
+	if policy == nil {
+		return false
+	}
+	switch policy.Setting {
+	case llamastack.MCPApprovalAlways:
+		return true
+	case llamastack.MCPApprovalNever:
+		return false
+	}
+	return !slices.Contains(policy.NeverTools, toolName)
+}
+
+// mockFileAttributes converts attribute values to the vector store file attributes of a response
+func mockFileAttributes(attributes map[string]interface{}) map[string]openai.VectorStoreFileAttributeUnion {
+	fileAttributes := make(map[string]openai.VectorStoreFileAttributeUnion, len(attributes))
+	for key, value := range attributes {
+		switch value := value.(type) {
+		case string:
+			fileAttributes[key] = openai.VectorStoreFileAttributeUnion{OfString: value}
+		case float64:
+			fileAttributes[key] = openai.VectorStoreFileAttributeUnion{OfFloat: value}
+		case int:
+			fileAttributes[key] = openai.VectorStoreFileAttributeUnion{OfFloat: float64(value)}
+		case bool:
+			fileAttributes[key] = openai.VectorStoreFileAttributeUnion{OfBool: value}
+		}
+	}
+	return fileAttributes
+}
+
+// AddFileToVectorStore returns a mock vector store file that is still being processed
+func (m *MockLlamaStackClient) AddFileToVectorStore(ctx context.Context, vectorStoreID, fileID string, chunkingStrategy *llamastack.ChunkingStrategy, attributes map[string]interface{}) (*openai.VectorStoreFile, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if fileID == "" {
+		return nil, fmt.Errorf("fileID is required")
+	}
+
+	return &openai.VectorStoreFile{
+		ID:            fileID,
+		Object:        "vector_store.file",
+		CreatedAt:     1755721386,
+		VectorStoreID: vectorStoreID,
+		Status:        "in_progress",
+		Attributes:    mockFileAttributes(attributes),
 	}, nil
 }
 
+// CreateVectorStoreFileBatch returns a mock file batch that is still being processed
+func (m *MockLlamaStackClient) CreateVectorStoreFileBatch(ctx context.Context, vectorStoreID string, fileIDs []string, chunkingStrategy *llamastack.ChunkingStrategy, attributes map[string]interface{}) (*openai.VectorStoreFileBatch, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if len(fileIDs) == 0 {
+		return nil, fmt.Errorf("at least one file ID is required")
+	}
+
+	return &openai.VectorStoreFileBatch{
+		ID:            MockFileBatchID,
+		Object:        "vector_store.files_batch",
+		CreatedAt:     1755721386,
+		VectorStoreID: vectorStoreID,
+		Status:        "in_progress",
+		FileCounts:    openai.VectorStoreFileBatchFileCounts{InProgress: int64(len(fileIDs)), Total: int64(len(fileIDs))},
+	}, nil
+}
+
+// GetVectorStoreFileBatch returns a mock file batch that finished processing
+func (m *MockLlamaStackClient) GetVectorStoreFileBatch(ctx context.Context, vectorStoreID, batchID string) (*openai.VectorStoreFileBatch, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if batchID == "" {
+		return nil, fmt.Errorf("batchID is required")
+	}
+
+	return &openai.VectorStoreFileBatch{
+		ID:            batchID,
+		Object:        "vector_store.files_batch",
+		CreatedAt:     1755721386,
+		VectorStoreID: vectorStoreID,
+		Status:        "completed",
+	}, nil
+}
+
+// CancelVectorStoreFileBatch returns success for mock cancellation
+func (m *MockLlamaStackClient) CancelVectorStoreFileBatch(ctx context.Context, vectorStoreID, batchID string) error {
+	if vectorStoreID == "" {
+		return fmt.Errorf("vectorStoreID is required")
+	}
+	if batchID == "" {
+		return fmt.Errorf("batchID is required")
+	}
+	return nil
+}
+
+// GetVectorStoreFile returns a mock vector store file that finished processing, or not found for MockMissingFileID
+func (m *MockLlamaStackClient) GetVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) (*openai.VectorStoreFile, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if fileID == "" {
+		return nil, fmt.Errorf("fileID is required")
+	}
+	if fileID == MockMissingFileID {
+		return nil, mockNotFoundError("/v1/vector_stores/" + vectorStoreID + "/files/" + fileID)
+	}
+
+	return &openai.VectorStoreFile{
+		ID:            fileID,
+		Object:        "vector_store.file",
+		UsageBytes:    2048,
+		CreatedAt:     1755721386,
+		VectorStoreID: vectorStoreID,
+		Status:        "completed",
+		Attributes:    map[string]openai.VectorStoreFileAttributeUnion{},
+	}, nil
+}
+
+// ListVectorStoreFileContent returns mock chunks of a vector store file
+func (m *MockLlamaStackClient) ListVectorStoreFileContent(ctx context.Context, vectorStoreID, fileID string) ([]openai.VectorStoreFileContentResponse, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if fileID == "" {
+		return nil, fmt.Errorf("fileID is required")
+	}
+
+	// Chunks are decoded from JSON so that they carry the chunk metadata LlamaStack reports beyond the OpenAI fields
+	chunks := make([]openai.VectorStoreFileContentResponse, 3)
+	for i := range chunks {
+		text := fmt.Sprintf("Mock chunk %d of %s", i+1, fileID)
+		raw, err := json.Marshal(map[string]interface{}{
+			"type": "text",
+			"text": text,
+			"chunk_metadata": map[string]interface{}{
+				"chunk_id":            fmt.Sprintf("%s-chunk-%d", fileID, i+1),
+				"document_id":         fileID,
+				"content_token_count": len(strings.Fields(text)),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &chunks[i]); err != nil {
+			return nil, err
+		}
+	}
+	return chunks, nil
+}
+
 // DeleteVectorStoreFile returns success for mock deletion
 func (m *MockLlamaStackClient) DeleteVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) error {
 	if vectorStoreID == "" {
@@ -734,3 +1357,46 @@ func (m *MockLlamaStackClient) DeleteVectorStoreFile(ctx context.Context, vector
 	// Mock deletion always succeeds
 	return nil
 }
+
+// SearchVectorStore returns mock chunks with descending scores, honoring max_num_results and score_threshold
+func (m *MockLlamaStackClient) SearchVectorStore(ctx context.Context, vectorStoreID string, params llamastack.SearchVectorStoreParams) ([]openai.VectorStoreSearchResponse, error) {
+	if vectorStoreID == "" {
+		return nil, fmt.Errorf("vectorStoreID is required")
+	}
+	if strings.TrimSpace(params.Query) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+
+	chunks := []struct {
+		fileID   string
+		filename string
+		score    float64
+		category string
+	}{
+		{"file-mock123abc456def", "mock-document.pdf", 0.92, "guide"},
+		{"file-mock789ghi012jkl", "mock-faq.txt", 0.71, "faq"},
+		{"file-mock123abc456def", "mock-document.pdf", 0.38, "guide"},
+	}
+
+	results := []openai.VectorStoreSearchResponse{}
+	for i, chunk := range chunks {
+		if params.ScoreThreshold != nil && chunk.score < *params.ScoreThreshold {
+			continue
+		}
+		if params.MaxNumResults != nil && int64(len(results)) >= *params.MaxNumResults {
+			break
+		}
+		results = append(results, openai.VectorStoreSearchResponse{
+			FileID:   chunk.fileID,
+			Filename: chunk.filename,
+			Score:    chunk.score,
+			Attributes: map[string]openai.VectorStoreSearchResponseAttributeUnion{
+				"category": {OfString: chunk.category},
+			},
+			Content: []openai.VectorStoreSearchResponseContent{
+				{Type: "text", Text: fmt.Sprintf("Mock chunk %d relevant to: %s", i+1, params.Query)},
+			},
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,853 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// States of a bridged server
+const (
+	BridgeStateStarting   = "starting"
+	BridgeStateRunning    = "running"
+	BridgeStateRestarting = "restarting"
+	BridgeStateFailed     = "failed"
+	BridgeStateStopped    = "stopped"
+)
+
+const (
+	// bridgeOutputLines is how many lines of process output are kept for error reports
+	bridgeOutputLines = 100
+	// bridgeOutputLineLength is how many bytes of a line of process output are kept; longer lines are cut
+	bridgeOutputLineLength = 4096
+	// bridgeStableAfter is how long a server must run before an exit no longer counts towards the restart limit
+	bridgeStableAfter = time.Minute
+)
+
+var errBridgeStopped = errors.New("MCP bridge stopped")
+
+// bridgeKey identifies a bridged server; every namespace gets its own instance of a server
+type bridgeKey struct {
+	Namespace string
+	Server    string
+}
+
+// Bridge makes MCP servers that LlamaStack cannot reach over HTTP usable from the dashboard. Stdio servers are
+// run as child processes and websocket servers are connected to, and each of them is served over streamable HTTP
+// under MCPBridgePath. Every namespace gets its own instance, with its own process, working directory and token.
+// Crashed servers are restarted with backoff, and servers that stay unused are stopped.
+type Bridge struct {
+	logger           *slog.Logger
+	config           *MCPClientConfig
+	transportFactory TransportFactory
+
+	mu      sync.Mutex
+	servers map[bridgeKey]*bridgedServer
+	closed  bool
+
+	started  bool
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewBridge creates a bridge; websocket servers are connected to with the transport options
+func NewBridge(logger *slog.Logger, config *MCPClientConfig, transportOpts *TransportOptions) *Bridge {
+	if config == nil {
+		config = DefaultMCPClientConfig()
+	}
+	return &Bridge{
+		logger:           logger,
+		config:           config,
+		transportFactory: NewMCPTransportFactory(transportOpts),
+		servers:          make(map[bridgeKey]*bridgedServer),
+		stop:             make(chan struct{}),
+		done:             make(chan struct{}),
+	}
+}
+
+// Start stops idle bridged servers in the background until Close is called
+func (b *Bridge) Start() {
+	b.mu.Lock()
+	b.started = true
+	b.mu.Unlock()
+
+	go func() {
+		defer close(b.done)
+
+		ticker := time.NewTicker(b.config.HealthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-b.stop:
+				return
+			case now := <-ticker.C:
+				if stopped := b.stopIdle(now); stopped > 0 {
+					b.logger.Debug("Stopped idle bridged MCP servers", "count", stopped)
+				}
+			}
+		}
+	}()
+}
+
+// Close stops all bridged servers
+func (b *Bridge) Close() {
+	b.stopOnce.Do(func() {
+		close(b.stop)
+
+		b.mu.Lock()
+		started := b.started
+		servers := b.servers
+		b.servers = make(map[bridgeKey]*bridgedServer)
+		b.closed = true
+		b.mu.Unlock()
+
+		if started {
+			<-b.done
+		}
+		for _, server := range servers {
+			server.stop()
+		}
+	})
+}
+
+// ClientTransport returns a transport that connects the BFF to the namespace's instance of the server,
+// starting it when needed
+func (b *Bridge) ClientTransport(ctx context.Context, namespace string, serverConfig models.MCPServerConfig) (mcp.Transport, error) {
+	server, err := b.ensure(ctx, namespace, serverConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &bridgeClientTransport{server: server}, nil
+}
+
+// Endpoint starts the namespace's instance of the server when needed and returns the path it is served at,
+// relative to the BFF root, and the token requests to it must carry in the X-MCP-Bridge-Token header
+func (b *Bridge) Endpoint(ctx context.Context, namespace string, serverConfig models.MCPServerConfig) (string, string, error) {
+	server, err := b.ensure(ctx, namespace, serverConfig)
+	if err != nil {
+		return "", "", err
+	}
+	return constants.MCPBridgePath + namespace + "/" + url.PathEscape(serverConfig.Name), server.token, nil
+}
+
+// ServeHTTP serves the bridged servers over streamable HTTP at <MCPBridgePath><namespace>/<server name>.
+// Unknown servers and wrong tokens are both answered with 404, so the response does not reveal which servers run.
+func (b *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	namespace, name, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, constants.MCPBridgePath), "/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	b.mu.Lock()
+	server := b.servers[bridgeKey{Namespace: namespace, Server: name}]
+	b.mu.Unlock()
+
+	if server == nil || subtle.ConstantTimeCompare([]byte(r.Header.Get(constants.MCPBridgeTokenHeader)), []byte(server.token)) != 1 {
+		http.NotFound(w, r)
+		return
+	}
+
+	handler := server.httpHandler()
+	if handler == nil {
+		http.Error(w, "MCP server is not running", http.StatusServiceUnavailable)
+		return
+	}
+	server.touch()
+	handler.ServeHTTP(w, r)
+}
+
+// State reports the state of the namespace's instance of a server, and the error that stopped it if it failed
+func (b *Bridge) State(namespace, serverName string) (string, error) {
+	b.mu.Lock()
+	server := b.servers[bridgeKey{Namespace: namespace, Server: serverName}]
+	b.mu.Unlock()
+
+	if server == nil {
+		return BridgeStateStopped, nil
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return server.state, server.startErr
+}
+
+// ensure returns the running instance of the server for the namespace, starting it or replacing a failed
+// or reconfigured instance first. It waits at most BridgeStartTimeout for the server to start.
+func (b *Bridge) ensure(ctx context.Context, namespace string, serverConfig models.MCPServerConfig) (*bridgedServer, error) {
+	if err := validateBridgedServer(namespace, serverConfig); err != nil {
+		return nil, err
+	}
+	key := bridgeKey{Namespace: namespace, Server: serverConfig.Name}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil, errBridgeStopped
+	}
+	server, ok := b.servers[key]
+	var replaced *bridgedServer
+	if ok && (server.failed() || !sameBridgedConfig(server.serverConfig, serverConfig)) {
+		replaced = server
+		ok = false
+	}
+	if !ok {
+		var err error
+		server, err = b.newBridgedServer(key, serverConfig)
+		if err != nil {
+			b.mu.Unlock()
+			return nil, err
+		}
+		b.servers[key] = server
+		go server.run()
+	}
+	server.touch()
+	b.mu.Unlock()
+
+	if replaced != nil {
+		replaced.stop()
+	}
+
+	// Restarts with backoff can take longer than the request allows, so the wait has a bound of its own
+	timer := time.NewTimer(b.config.BridgeStartTimeout)
+	defer timer.Stop()
+	select {
+	case <-server.ready:
+	case <-timer.C:
+		return nil, fmt.Errorf("MCP server %q did not start within %s", serverConfig.Name, b.config.BridgeStartTimeout)
+	case <-ctx.Done():
+		return nil, fmt.Errorf("MCP server %q did not start in time: %w", serverConfig.Name, ctx.Err())
+	}
+	if err := server.err(); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// stopIdle stops the servers no request used within the idle timeout and returns how many were stopped
+func (b *Bridge) stopIdle(now time.Time) int {
+	b.mu.Lock()
+	var idle []*bridgedServer
+	for key, server := range b.servers {
+		if now.Sub(server.lastUsedAt()) >= b.config.BridgeIdleTimeout {
+			idle = append(idle, server)
+			delete(b.servers, key)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, server := range idle {
+		server.stop()
+	}
+	return len(idle)
+}
+
+func (b *Bridge) newBridgedServer(key bridgeKey, serverConfig models.MCPServerConfig) (*bridgedServer, error) {
+	token, err := newBridgeToken()
+	if err != nil {
+		return nil, err
+	}
+	server := &bridgedServer{
+		bridge:       b,
+		key:          key,
+		serverConfig: serverConfig,
+		token:        token,
+		ready:        make(chan struct{}),
+		stopped:      make(chan struct{}),
+		state:        BridgeStateStarting,
+	}
+	server.output = newProcessOutput(b.logger.With("namespace", key.Namespace, "server_name", key.Server), bridgeOutputLines)
+	return server, nil
+}
+
+// validateBridgedServer checks that the server can be bridged; names end up in paths, so they must be plain names
+func validateBridgedServer(namespace string, serverConfig models.MCPServerConfig) error {
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, ", "))
+	}
+	name := serverConfig.Name
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid MCP server name %q", name)
+	}
+
+	switch TransportType(serverConfig.Transport) {
+	case TransportTypeStdio:
+		if serverConfig.Command == "" {
+			return fmt.Errorf("MCP server %q uses the stdio transport but has no command", name)
+		}
+	case TransportTypeWebSocket:
+		if serverConfig.URL == "" {
+			return fmt.Errorf("MCP server %q uses the websocket transport but has no URL", name)
+		}
+	default:
+		return fmt.Errorf("MCP server %q uses the %s transport, which is not bridged", name, serverConfig.Transport)
+	}
+	return nil
+}
+
+// sameBridgedConfig reports whether a running instance still matches the server configuration
+func sameBridgedConfig(a, b models.MCPServerConfig) bool {
+	return a.Transport == b.Transport && a.URL == b.URL && a.Command == b.Command &&
+		slices.Equal(a.Args, b.Args) && reflect.DeepEqual(a.Env, b.Env)
+}
+
+func newBridgeToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate MCP bridge token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// bridgedServer is the instance of a server for one namespace. It keeps one upstream session to the process or
+// websocket server and serves its tools, resources and prompts from a local MCP server that survives restarts.
+type bridgedServer struct {
+	bridge       *Bridge
+	key          bridgeKey
+	serverConfig models.MCPServerConfig
+	token        string
+	output       *processOutput
+
+	ready     chan struct{} // Closed once the server first runs or gives up
+	readyOnce sync.Once
+	stopped   chan struct{}
+	stopOnce  sync.Once
+
+	mu        sync.Mutex
+	server    *mcp.Server
+	handler   http.Handler
+	session   *mcp.ClientSession // Nil while the upstream is (re)starting
+	state     string
+	startErr  error
+	lastUsed  time.Time
+	tools     []string
+	resources []string
+	templates []string
+	prompts   []string
+}
+
+// run connects to the upstream and reconnects when it exits, until the server is stopped or keeps failing
+func (s *bridgedServer) run() {
+	b := s.bridge
+	failures := 0
+	for {
+		started := time.Now()
+		session, err := s.connect()
+		if err == nil {
+			if err = s.attach(session); err != nil {
+				_ = session.Close()
+			}
+		}
+		if err == nil {
+			err = session.Wait()
+			s.detach()
+		}
+		if s.isStopped() {
+			return
+		}
+
+		if time.Since(started) >= bridgeStableAfter {
+			failures = 0
+		}
+		failures++
+		exitErr := s.describeExit(err)
+		b.logger.Warn("Bridged MCP server exited", "namespace", s.key.Namespace, "server_name", s.key.Server,
+			"error", exitErr, "failures", failures)
+
+		if failures > b.config.BridgeMaxRestarts || isPermanentStartError(err) {
+			s.fail(exitErr)
+			return
+		}
+
+		s.setState(BridgeStateRestarting)
+		delay := min(b.config.RetryBackoff<<(failures-1), b.config.RetryMaxDelay)
+		select {
+		case <-s.stopped:
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// connect starts the process or dials the websocket server and initializes the upstream session
+func (s *bridgedServer) connect() (*mcp.ClientSession, error) {
+	b := s.bridge
+
+	var transport mcp.Transport
+	switch TransportType(s.serverConfig.Transport) {
+	case TransportTypeStdio:
+		cmd, err := s.command()
+		if err != nil {
+			return nil, err
+		}
+		transport = &mcp.CommandTransport{Command: cmd}
+	case TransportTypeWebSocket:
+		var err error
+		transport, err = b.transportFactory.CreateWebSocketTransport(s.serverConfig.URL, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// List changes are synced outside the notification handlers, which must not call back into the session
+	client := mcp.NewClient(b.config.ToMCPImplementation(), &mcp.ClientOptions{
+		ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) {
+			go s.resync(s.syncTools)
+		},
+		ResourceListChangedHandler: func(context.Context, *mcp.ResourceListChangedRequest) {
+			go s.resync(s.syncResources)
+		},
+		PromptListChangedHandler: func(context.Context, *mcp.PromptListChangedRequest) {
+			go s.resync(s.syncPrompts)
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.config.BridgeStartTimeout)
+	defer cancel()
+	return client.Connect(ctx, transport, nil)
+}
+
+// command builds the command of a stdio server. The BFF environment holds credentials, so the process only
+// gets a search path, a home directory of its own and its configured variables.
+func (s *bridgedServer) command() (*exec.Cmd, error) {
+	dir := filepath.Join(s.bridge.config.BridgeWorkDir, s.key.Namespace, s.key.Server)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create working directory for MCP server %q: %w", s.key.Server, err)
+	}
+
+	cmd := exec.Command(s.serverConfig.Command, s.serverConfig.Args...)
+	cmd.Dir = dir
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH"), "HOME=" + dir}
+	names := make([]string, 0, len(s.serverConfig.Env))
+	for name := range s.serverConfig.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd.Env = append(cmd.Env, name+"="+s.serverConfig.Env[name])
+	}
+	cmd.Stderr = s.output
+	return cmd, nil
+}
+
+// attach makes a new upstream session current and mirrors its features. The local server is created on the first
+// connection, with the identity and capabilities of the upstream server.
+func (s *bridgedServer) attach(session *mcp.ClientSession) error {
+	initResult := session.InitializeResult()
+
+	s.mu.Lock()
+	if s.isStopped() {
+		s.mu.Unlock()
+		return errBridgeStopped
+	}
+	if s.server == nil {
+		impl := &mcp.Implementation{Name: s.key.Server, Version: "N/A"}
+		options := &mcp.ServerOptions{}
+		if initResult != nil {
+			if initResult.ServerInfo != nil {
+				impl = initResult.ServerInfo
+			}
+			options.Instructions = initResult.Instructions
+			if initResult.Capabilities != nil {
+				options.HasTools = initResult.Capabilities.Tools != nil
+				options.HasResources = initResult.Capabilities.Resources != nil
+				options.HasPrompts = initResult.Capabilities.Prompts != nil
+			}
+		}
+		s.server = mcp.NewServer(impl, options)
+		s.handler = mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return s.server }, nil)
+	}
+	s.session = session
+	s.state = BridgeStateRunning
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.bridge.config.BridgeStartTimeout)
+	defer cancel()
+
+	var caps *mcp.ServerCapabilities
+	if initResult != nil {
+		caps = initResult.Capabilities
+	}
+	if caps != nil && caps.Tools != nil {
+		if err := s.syncTools(ctx, session); err != nil {
+			return err
+		}
+	}
+	if caps != nil && caps.Resources != nil {
+		if err := s.syncResources(ctx, session); err != nil {
+			return err
+		}
+	}
+	if caps != nil && caps.Prompts != nil {
+		if err := s.syncPrompts(ctx, session); err != nil {
+			return err
+		}
+	}
+
+	s.readyOnce.Do(func() { close(s.ready) })
+	return nil
+}
+
+// detach forgets the upstream session after it ended
+func (s *bridgedServer) detach() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.session = nil
+}
+
+// resync mirrors a changed feature list of the current upstream session
+func (s *bridgedServer) resync(sync func(context.Context, *mcp.ClientSession) error) {
+	session, err := s.upstream()
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.bridge.config.BridgeStartTimeout)
+	defer cancel()
+	if err := sync(ctx, session); err != nil {
+		s.bridge.logger.Warn("Failed to sync bridged MCP server", "namespace", s.key.Namespace, "server_name", s.key.Server, "error", err)
+	}
+}
+
+func (s *bridgedServer) syncTools(ctx context.Context, session *mcp.ClientSession) error {
+	var names []string
+	for tool, err := range session.Tools(ctx, nil) {
+		if err != nil {
+			return fmt.Errorf("failed to list tools: %w", err)
+		}
+		// The local server only accepts object schemas; arguments are passed through unvalidated either way
+		if tool.InputSchema == nil {
+			tool.InputSchema = &jsonschema.Schema{Type: "object"}
+		}
+		if tool.InputSchema.Type != "object" {
+			s.bridge.logger.Warn("Skipping bridged MCP tool without an object input schema", "server_name", s.key.Server, "tool", tool.Name)
+			continue
+		}
+		if tool.OutputSchema != nil && tool.OutputSchema.Type != "object" {
+			tool.OutputSchema = nil
+		}
+		if s.register("tool", tool.Name, func() { s.server.AddTool(tool, s.callTool) }) {
+			names = append(names, tool.Name)
+		}
+	}
+
+	s.mu.Lock()
+	removed := missing(s.tools, names)
+	s.tools = names
+	s.mu.Unlock()
+	s.server.RemoveTools(removed...)
+	return nil
+}
+
+func (s *bridgedServer) syncResources(ctx context.Context, session *mcp.ClientSession) error {
+	var uris, templates []string
+	for resource, err := range session.Resources(ctx, nil) {
+		if err != nil {
+			return fmt.Errorf("failed to list resources: %w", err)
+		}
+		if s.register("resource", resource.URI, func() { s.server.AddResource(resource, s.readResource) }) {
+			uris = append(uris, resource.URI)
+		}
+	}
+	for template, err := range session.ResourceTemplates(ctx, nil) {
+		if err != nil {
+			return fmt.Errorf("failed to list resource templates: %w", err)
+		}
+		if s.register("resource template", template.URITemplate, func() { s.server.AddResourceTemplate(template, s.readResource) }) {
+			templates = append(templates, template.URITemplate)
+		}
+	}
+
+	s.mu.Lock()
+	removedResources := missing(s.resources, uris)
+	removedTemplates := missing(s.templates, templates)
+	s.resources, s.templates = uris, templates
+	s.mu.Unlock()
+	s.server.RemoveResources(removedResources...)
+	s.server.RemoveResourceTemplates(removedTemplates...)
+	return nil
+}
+
+func (s *bridgedServer) syncPrompts(ctx context.Context, session *mcp.ClientSession) error {
+	var names []string
+	for prompt, err := range session.Prompts(ctx, nil) {
+		if err != nil {
+			return fmt.Errorf("failed to list prompts: %w", err)
+		}
+		if s.register("prompt", prompt.Name, func() { s.server.AddPrompt(prompt, s.getPrompt) }) {
+			names = append(names, prompt.Name)
+		}
+	}
+
+	s.mu.Lock()
+	removed := missing(s.prompts, names)
+	s.prompts = names
+	s.mu.Unlock()
+	s.server.RemovePrompts(removed...)
+	return nil
+}
+
+// register adds a feature to the local server. The SDK panics on invalid features, which an upstream
+// server must not be able to trigger, so those are skipped.
+func (s *bridgedServer) register(kind, name string, add func()) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.bridge.logger.Warn("Skipping invalid bridged MCP feature", "server_name", s.key.Server, "kind", kind, "name", name, "error", r)
+			ok = false
+		}
+	}()
+	add()
+	return true
+}
+
+func (s *bridgedServer) callTool(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	session, err := s.upstream()
+	if err != nil {
+		return nil, err
+	}
+	params := &mcp.CallToolParams{Meta: req.Params.Meta, Name: req.Params.Name}
+	// Arguments arrive as raw JSON; absent arguments must stay absent rather than become null
+	if raw, ok := req.Params.Arguments.(json.RawMessage); !ok || len(raw) > 0 {
+		params.Arguments = req.Params.Arguments
+	}
+	return session.CallTool(ctx, params)
+}
+
+func (s *bridgedServer) readResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	session, err := s.upstream()
+	if err != nil {
+		return nil, err
+	}
+	return session.ReadResource(ctx, req.Params)
+}
+
+func (s *bridgedServer) getPrompt(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	session, err := s.upstream()
+	if err != nil {
+		return nil, err
+	}
+	return session.GetPrompt(ctx, req.Params)
+}
+
+// upstream returns the current upstream session and marks the server as used
+func (s *bridgedServer) upstream() (*mcp.ClientSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastUsed = time.Now()
+	if s.session == nil {
+		return nil, fmt.Errorf("MCP server %q is %s", s.key.Server, s.state)
+	}
+	return s.session, nil
+}
+
+func (s *bridgedServer) httpHandler() http.Handler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.handler
+}
+
+func (s *bridgedServer) touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastUsed = time.Now()
+}
+
+func (s *bridgedServer) lastUsedAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastUsed
+}
+
+func (s *bridgedServer) setState(state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+}
+
+// fail records that the server gave up restarting
+func (s *bridgedServer) fail(err error) {
+	s.mu.Lock()
+	s.state = BridgeStateFailed
+	s.startErr = err
+	s.mu.Unlock()
+	s.readyOnce.Do(func() { close(s.ready) })
+}
+
+func (s *bridgedServer) failed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state == BridgeStateFailed
+}
+
+// err returns why the server is not usable, if it is not
+func (s *bridgedServer) err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch s.state {
+	case BridgeStateFailed:
+		return s.startErr
+	case BridgeStateStopped:
+		return errBridgeStopped
+	}
+	return nil
+}
+
+func (s *bridgedServer) isStopped() bool {
+	select {
+	case <-s.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop closes the upstream session, which ends a stdio process, and the sessions of the local server
+func (s *bridgedServer) stop() {
+	s.stopOnce.Do(func() {
+		s.mu.Lock()
+		close(s.stopped)
+		session, server := s.session, s.server
+		s.session = nil
+		s.state = BridgeStateStopped
+		s.mu.Unlock()
+
+		if session != nil {
+			_ = session.Close()
+		}
+		if server != nil {
+			for serverSession := range server.Sessions() {
+				_ = serverSession.Close()
+			}
+		}
+		s.readyOnce.Do(func() { close(s.ready) })
+	})
+}
+
+// describeExit explains why the upstream ended, with the last lines the process wrote
+func (s *bridgedServer) describeExit(err error) error {
+	if err == nil {
+		err = errors.New("connection closed")
+	}
+	if tail := s.output.tail(10); tail != "" {
+		return fmt.Errorf("MCP server %q exited: %w; output: %s", s.key.Server, err, tail)
+	}
+	return fmt.Errorf("MCP server %q exited: %w", s.key.Server, err)
+}
+
+// isPermanentStartError reports whether starting the process again cannot help, for example when the command does not exist
+func isPermanentStartError(err error) bool {
+	return errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission)
+}
+
+// missing returns the names of before that are not in after
+func missing(before, after []string) []string {
+	var result []string
+	for _, name := range before {
+		if !slices.Contains(after, name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// bridgeClientTransport connects the BFF to a bridged server in memory
+type bridgeClientTransport struct {
+	server *bridgedServer
+}
+
+func (t *bridgeClientTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	t.server.mu.Lock()
+	server := t.server.server
+	t.server.mu.Unlock()
+	if server == nil {
+		return nil, fmt.Errorf("MCP server %q is not running", t.server.key.Server)
+	}
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		return nil, fmt.Errorf("failed to connect to bridged MCP server: %w", err)
+	}
+	return clientTransport.Connect(ctx)
+}
+
+// processOutput keeps the last lines a process wrote and logs them. Lines are cut at bridgeOutputLineLength
+// bytes, so a process that never writes a newline cannot make it grow without bound.
+type processOutput struct {
+	logger   *slog.Logger
+	maxLines int
+
+	mu      sync.Mutex
+	lines   []string
+	partial []byte
+}
+
+func newProcessOutput(logger *slog.Logger, maxLines int) *processOutput {
+	return &processOutput{logger: logger, maxLines: maxLines}
+}
+
+func (o *processOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.partial = append(o.partial, p...)
+	for {
+		i := slices.Index(o.partial, '\n')
+		if i < 0 {
+			break
+		}
+		o.addLineLocked(strings.TrimRight(string(o.partial[:i]), "\r"))
+		o.partial = o.partial[i+1:]
+	}
+	if len(o.partial) > bridgeOutputLineLength {
+		o.addLineLocked(string(o.partial[:bridgeOutputLineLength]))
+		o.partial = nil
+	}
+	return len(p), nil
+}
+
+// addLineLocked logs a line and keeps it, cut to bridgeOutputLineLength bytes; the caller holds o.mu
+func (o *processOutput) addLineLocked(line string) {
+	if len(line) > bridgeOutputLineLength {
+		line = line[:bridgeOutputLineLength]
+	}
+	o.logger.Debug("Bridged MCP server output", "line", line)
+	o.lines = append(o.lines, line)
+	if len(o.lines) > o.maxLines {
+		o.lines = o.lines[len(o.lines)-o.maxLines:]
+	}
+}
+
+// tail returns up to n of the last lines, joined with " | "
+func (o *processOutput) tail(n int) string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	lines := o.lines[max(0, len(o.lines)-n):]
+	if len(o.partial) > 0 {
+		lines = append(slices.Clone(lines), string(o.partial))
+	}
+	return strings.Join(lines, " | ")
+}
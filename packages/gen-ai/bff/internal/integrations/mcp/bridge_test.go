@@ -0,0 +1,347 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// bridgeTestServerEnv makes the test binary run as a stdio MCP server instead of running the tests
+const bridgeTestServerEnv = "GENAI_BRIDGE_TEST_SERVER"
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(bridgeTestServerEnv) {
+	case "":
+		os.Exit(m.Run())
+	case "fail":
+		fmt.Fprintln(os.Stderr, "bridge test server failed to start")
+		os.Exit(1)
+	case "hang":
+		time.Sleep(time.Hour)
+		os.Exit(0)
+	default:
+		runBridgeTestServer()
+		os.Exit(0)
+	}
+}
+
+// runBridgeTestServer serves a whoami tool that reports what the process sees, and a crash tool that exits
+func runBridgeTestServer() {
+	fmt.Fprintln(os.Stderr, "bridge test server starting")
+
+	server := newBridgeTestServer("stdio-server")
+	mcp.AddTool(server, &mcp.Tool{Name: "crash"}, func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, struct{}, error) {
+		fmt.Fprintln(os.Stderr, "bridge test server crashing")
+		os.Exit(3)
+		return nil, struct{}{}, nil
+	})
+	_ = server.Run(context.Background(), mcp.NewStdioTransport())
+}
+
+func newBridgeTestServer(name string) *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: name, Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "whoami"}, func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, struct{}, error) {
+		dir, _ := os.Getwd()
+		text := strings.Join([]string{os.Getenv("BRIDGE_TEST_NAME"), dir, fmt.Sprint(os.Getpid()), os.Getenv("GENAI_BRIDGE_TEST_SECRET")}, "|")
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, struct{}{}, nil
+	})
+	return server
+}
+
+func newTestBridge(t *testing.T) *Bridge {
+	config := DefaultMCPClientConfig()
+	config.BridgeWorkDir = t.TempDir()
+	config.BridgeStartTimeout = 10 * time.Second
+	config.BridgeMaxRestarts = 2
+	config.RetryBackoff = 10 * time.Millisecond
+	config.RetryMaxDelay = 50 * time.Millisecond
+
+	bridge := NewBridge(slog.New(slog.NewTextHandler(io.Discard, nil)), config, nil)
+	t.Cleanup(bridge.Close)
+	return bridge
+}
+
+func stdioServerConfig(t *testing.T, name, mode string) models.MCPServerConfig {
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	return models.MCPServerConfig{
+		Name:      name,
+		URL:       constants.MCPStdioURLScheme + name,
+		Transport: string(TransportTypeStdio),
+		Command:   executable,
+		Args:      []string{"-test.run=^$"},
+		Env:       map[string]string{bridgeTestServerEnv: mode, "BRIDGE_TEST_NAME": name},
+	}
+}
+
+// whoami calls the whoami tool and returns the name, working directory, pid and leaked secret it reports
+func whoami(ctx context.Context, session *mcp.ClientSession) ([]string, error) {
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "whoami"})
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError || len(result.Content) == 0 {
+		return nil, fmt.Errorf("whoami failed")
+	}
+	return strings.Split(result.Content[0].(*mcp.TextContent).Text, "|"), nil
+}
+
+func connectBridged(t *testing.T, bridge *Bridge, namespace string, serverConfig models.MCPServerConfig) *mcp.ClientSession {
+	ctx := context.Background()
+	transport, err := bridge.ClientTransport(ctx, namespace, serverConfig)
+	require.NoError(t, err)
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil).Connect(ctx, transport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+// tokenTransport adds the bridge token to every request
+type tokenTransport struct {
+	token string
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(constants.MCPBridgeTokenHeader, t.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBridgeStdio(t *testing.T) {
+	t.Setenv("GENAI_BRIDGE_TEST_SECRET", "do-not-leak")
+	ctx := context.Background()
+	bridge := newTestBridge(t)
+	serverConfig := stdioServerConfig(t, "stdio-echo", "serve")
+
+	t.Run("should run the server with only its configured environment", func(t *testing.T) {
+		session := connectBridged(t, bridge, "team-a", serverConfig)
+
+		assert.Equal(t, "stdio-server", session.InitializeResult().ServerInfo.Name)
+		fields, err := whoami(ctx, session)
+		require.NoError(t, err)
+		assert.Equal(t, "stdio-echo", fields[0])
+		assert.Empty(t, fields[3])
+
+		state, err := bridge.State("team-a", "stdio-echo")
+		require.NoError(t, err)
+		assert.Equal(t, BridgeStateRunning, state)
+	})
+
+	t.Run("should run one process per namespace", func(t *testing.T) {
+		fieldsA, err := whoami(ctx, connectBridged(t, bridge, "team-a", serverConfig))
+		require.NoError(t, err)
+		fieldsB, err := whoami(ctx, connectBridged(t, bridge, "team-b", serverConfig))
+		require.NoError(t, err)
+
+		assert.NotEqual(t, fieldsA[1], fieldsB[1])
+		assert.NotEqual(t, fieldsA[2], fieldsB[2])
+
+		_, tokenA, err := bridge.Endpoint(ctx, "team-a", serverConfig)
+		require.NoError(t, err)
+		_, tokenB, err := bridge.Endpoint(ctx, "team-b", serverConfig)
+		require.NoError(t, err)
+		assert.NotEqual(t, tokenA, tokenB)
+	})
+
+	t.Run("should let clients use the server of the request namespace", func(t *testing.T) {
+		client := newPooledTestClient(nil)
+		defer client.sessions.Close()
+		client.bridge = bridge
+		identity := &integrations.RequestIdentity{Token: "user-token"}
+
+		result, err := client.CallTool(ctx, identity, serverConfig, "whoami", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "error", result.Status)
+
+		namespaceCtx := context.WithValue(ctx, constants.NamespaceQueryParameterKey, "team-a")
+		result, err = client.CallTool(namespaceCtx, identity, serverConfig, "whoami", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "success", result.Status)
+
+		tools, err := client.ListToolsWithStatus(namespaceCtx, identity, serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "success", tools.Status)
+		assert.Len(t, tools.Tools, 2)
+	})
+
+	t.Run("should restart the server after it crashes", func(t *testing.T) {
+		session := connectBridged(t, bridge, "team-c", serverConfig)
+		before, err := whoami(ctx, session)
+		require.NoError(t, err)
+
+		_, _ = session.CallTool(ctx, &mcp.CallToolParams{Name: "crash"})
+
+		require.Eventually(t, func() bool {
+			after, err := whoami(ctx, session)
+			return err == nil && after[2] != before[2]
+		}, 10*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("should serve the server over streamable HTTP with the bridge token", func(t *testing.T) {
+		httpServer := httptest.NewServer(bridge)
+		defer httpServer.Close()
+
+		path, token, err := bridge.Endpoint(ctx, "team-a", serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "/mcp-bridge/team-a/stdio-echo", path)
+
+		transport := &mcp.StreamableClientTransport{
+			Endpoint:   httpServer.URL + path,
+			HTTPClient: &http.Client{Transport: &tokenTransport{token: token}},
+		}
+		session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil).Connect(ctx, transport, nil)
+		require.NoError(t, err)
+		defer func() { _ = session.Close() }()
+
+		fields, err := whoami(ctx, session)
+		require.NoError(t, err)
+		assert.Equal(t, "stdio-echo", fields[0])
+
+		for _, wrongToken := range []string{"", "wrong"} {
+			req, err := http.NewRequest(http.MethodPost, httpServer.URL+path, strings.NewReader("{}"))
+			require.NoError(t, err)
+			req.Header.Set(constants.MCPBridgeTokenHeader, wrongToken)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		}
+	})
+
+	t.Run("should report the output of a server that keeps failing", func(t *testing.T) {
+		_, _, err := bridge.Endpoint(ctx, "team-a", stdioServerConfig(t, "stdio-fail", "fail"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bridge test server failed to start")
+
+		state, _ := bridge.State("team-a", "stdio-fail")
+		assert.Equal(t, BridgeStateFailed, state)
+	})
+
+	t.Run("should stop waiting for a server that does not start within the start timeout", func(t *testing.T) {
+		slowBridge := newTestBridge(t)
+		slowBridge.config.BridgeStartTimeout = 200 * time.Millisecond
+
+		started := time.Now()
+		_, err := slowBridge.ClientTransport(ctx, "team-a", stdioServerConfig(t, "stdio-hang", "hang"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did not start within")
+		assert.Less(t, time.Since(started), 5*time.Second)
+	})
+
+	t.Run("should not retry a command that does not exist", func(t *testing.T) {
+		missing := stdioServerConfig(t, "stdio-missing", "serve")
+		missing.Command = "/nonexistent/mcp-server"
+
+		start := time.Now()
+		_, _, err := bridge.Endpoint(ctx, "team-a", missing)
+		require.Error(t, err)
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("should reject names that are not plain names", func(t *testing.T) {
+		_, _, err := bridge.Endpoint(ctx, "../team-a", serverConfig)
+		assert.Error(t, err)
+
+		badName := serverConfig
+		badName.Name = "../stdio-echo"
+		_, _, err = bridge.Endpoint(ctx, "team-a", badName)
+		assert.Error(t, err)
+	})
+
+	t.Run("should stop idle servers", func(t *testing.T) {
+		connectBridged(t, bridge, "team-d", serverConfig)
+
+		assert.Positive(t, bridge.stopIdle(time.Now().Add(time.Hour)))
+		state, _ := bridge.State("team-d", "stdio-echo")
+		assert.Equal(t, BridgeStateStopped, state)
+	})
+}
+
+// webSocketServerTransport serves one accepted WebSocket connection
+type webSocketServerTransport struct {
+	conn *websocket.Conn
+}
+
+func (t *webSocketServerTransport) Connect(context.Context) (mcp.Connection, error) {
+	return NewWebSocketConnection(t.conn), nil
+}
+
+func TestWebSocketTransport(t *testing.T) {
+	server := newBridgeTestServer("websocket-server")
+	httpServer := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		session, err := server.Connect(context.Background(), &webSocketServerTransport{conn: conn}, nil)
+		if err != nil {
+			return
+		}
+		_ = session.Wait()
+	}))
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	serverConfig := models.MCPServerConfig{
+		Name:      "websocket-echo",
+		URL:       "ws" + strings.TrimPrefix(httpServer.URL, "http"),
+		Transport: string(TransportTypeWebSocket),
+	}
+
+	t.Run("should call tools over WebSocket", func(t *testing.T) {
+		client := NewSimpleMCPClient(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		result, err := client.CallTool(ctx, &integrations.RequestIdentity{Token: "user-token"}, serverConfig, "whoami", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "success", result.Status)
+	})
+
+	t.Run("should bridge WebSocket servers", func(t *testing.T) {
+		session := connectBridged(t, newTestBridge(t), "team-a", serverConfig)
+
+		assert.Equal(t, "websocket-server", session.InitializeResult().ServerInfo.Name)
+		_, err := whoami(ctx, session)
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject endpoints that are not WebSocket URLs", func(t *testing.T) {
+		transport := &WebSocketClientTransport{Endpoint: httpServer.URL}
+		_, err := transport.Connect(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestProcessOutput(t *testing.T) {
+	t.Run("should keep the last lines", func(t *testing.T) {
+		output := newProcessOutput(slog.New(slog.NewTextHandler(io.Discard, nil)), 2)
+		_, _ = output.Write([]byte("one\ntwo\r\nthree\nfour"))
+		assert.Equal(t, "two | three | four", output.tail(3))
+	})
+
+	t.Run("should cut lines that never end", func(t *testing.T) {
+		output := newProcessOutput(slog.New(slog.NewTextHandler(io.Discard, nil)), 2)
+		chunk := []byte(strings.Repeat("x", 1024))
+		for range 100 {
+			_, _ = output.Write(chunk)
+		}
+
+		output.mu.Lock()
+		defer output.mu.Unlock()
+		assert.LessOrEqual(t, len(output.partial), bridgeOutputLineLength)
+		require.NotEmpty(t, output.lines)
+		for _, line := range output.lines {
+			assert.Len(t, line, bridgeOutputLineLength)
+		}
+	})
+}
@@ -11,4 +11,9 @@ import (
 type MCPClientInterface interface {
 	CheckConnectionStatus(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig) (*models.ConnectionStatus, error)
 	ListToolsWithStatus(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig) (*models.ToolsStatus, error)
+	CallTool(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig, toolName string, arguments map[string]interface{}) (*models.ToolCallResult, error)
+	ListResources(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig) (*models.ResourcesStatus, error)
+	ReadResource(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig, uri string) (*models.ResourceReadResult, error)
+	ListPrompts(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig) (*models.PromptsStatus, error)
+	GetPrompt(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig, promptName string, arguments map[string]string) (*models.PromptResult, error)
 }
@@ -1,6 +1,8 @@
 package mcp
 
 import (
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -8,15 +10,21 @@ import (
 
 // MCPClientConfig contains configuration for the MCP client
 type MCPClientConfig struct {
-	EnableProtocolHealthCheck bool          `json:"enable_protocol_health_check"`
+	EnableProtocolHealthCheck bool          `json:"enable_protocol_health_check"` // Probe pooled sessions with MCP pings instead of HTTP requests
 	HealthCheckTimeout        time.Duration `json:"health_check_timeout"`
+	HealthCheckInterval       time.Duration `json:"health_check_interval"` // How often pooled sessions are probed and cached results refreshed
+	SessionIdleTimeout        time.Duration `json:"session_idle_timeout"`  // Pooled sessions unused for this long are closed
 	TransportTimeout          time.Duration `json:"transport_timeout"`
 	InsecureSkipVerify        bool          `json:"insecure_skip_verify"`
 	MaxIdleConns              int           `json:"max_idle_conns"`
 	IdleConnTimeout           time.Duration `json:"idle_conn_timeout"`
-	MaxRetries                int           `json:"max_retries"`
-	RetryBackoff              time.Duration `json:"retry_backoff"`
-	RetryMaxDelay             time.Duration `json:"retry_max_delay"`
+	MaxRetries                int           `json:"max_retries"`          // Retries of a failed connection when the server is unreachable
+	RetryBackoff              time.Duration `json:"retry_backoff"`        // Delay before the first retry, doubled for every further retry
+	RetryMaxDelay             time.Duration `json:"retry_max_delay"`      // Upper bound of the retry delay
+	BridgeStartTimeout        time.Duration `json:"bridge_start_timeout"` // How long a bridged server may take to start and initialize
+	BridgeIdleTimeout         time.Duration `json:"bridge_idle_timeout"`  // Bridged servers unused for this long are stopped
+	BridgeMaxRestarts         int           `json:"bridge_max_restarts"`  // Consecutive restarts of a crashing bridged server before giving up
+	BridgeWorkDir             string        `json:"bridge_work_dir"`      // Parent of the per-namespace working directories of stdio servers
 	ClientName                string        `json:"client_name"`
 	ClientVersion             string        `json:"client_version"`
 }
@@ -26,13 +34,19 @@ func DefaultMCPClientConfig() *MCPClientConfig {
 	return &MCPClientConfig{
 		EnableProtocolHealthCheck: false, // Start with HTTP-only health checks
 		HealthCheckTimeout:        10 * time.Second,
+		HealthCheckInterval:       30 * time.Second,
+		SessionIdleTimeout:        5 * time.Minute,
 		TransportTimeout:          60 * time.Second,
 		InsecureSkipVerify:        false,
 		MaxIdleConns:              5,
 		IdleConnTimeout:           30 * time.Second,
-		MaxRetries:                3,
-		RetryBackoff:              1 * time.Second,
+		MaxRetries:                2,
+		RetryBackoff:              500 * time.Millisecond,
 		RetryMaxDelay:             10 * time.Second,
+		BridgeStartTimeout:        30 * time.Second,
+		BridgeIdleTimeout:         15 * time.Minute,
+		BridgeMaxRestarts:         5,
+		BridgeWorkDir:             filepath.Join(os.TempDir(), "mcp-bridge"),
 		ClientName:                "llama-stack-bff-client",
 		ClientVersion:             "v1.0.0",
 	}
@@ -44,6 +58,14 @@ func (c *MCPClientConfig) Validate() error {
 		c.HealthCheckTimeout = 10 * time.Second
 	}
 
+	if c.HealthCheckInterval <= 0 {
+		c.HealthCheckInterval = 30 * time.Second
+	}
+
+	if c.SessionIdleTimeout <= 0 {
+		c.SessionIdleTimeout = 5 * time.Minute
+	}
+
 	if c.TransportTimeout <= 0 {
 		c.TransportTimeout = 60 * time.Second
 	}
@@ -57,17 +79,33 @@ func (c *MCPClientConfig) Validate() error {
 	}
 
 	if c.MaxRetries < 0 {
-		c.MaxRetries = 3
+		c.MaxRetries = 2
 	}
 
 	if c.RetryBackoff <= 0 {
-		c.RetryBackoff = 1 * time.Second
+		c.RetryBackoff = 500 * time.Millisecond
 	}
 
 	if c.RetryMaxDelay <= 0 {
 		c.RetryMaxDelay = 10 * time.Second
 	}
 
+	if c.BridgeStartTimeout <= 0 {
+		c.BridgeStartTimeout = 30 * time.Second
+	}
+
+	if c.BridgeIdleTimeout <= 0 {
+		c.BridgeIdleTimeout = 15 * time.Minute
+	}
+
+	if c.BridgeMaxRestarts < 0 {
+		c.BridgeMaxRestarts = 5
+	}
+
+	if c.BridgeWorkDir == "" {
+		c.BridgeWorkDir = filepath.Join(os.TempDir(), "mcp-bridge")
+	}
+
 	if c.ClientName == "" {
 		c.ClientName = "llama-stack-bff-client"
 	}
@@ -28,6 +28,9 @@ const (
 	ErrCodeUnauthorized      = "UNAUTHORIZED"
 	ErrCodeInvalidConfig     = "INVALID_CONFIG"
 	ErrCodeToolNotFound      = "TOOL_NOT_FOUND"
+	ErrCodeInvalidArguments  = "INVALID_ARGUMENTS"
+	ErrCodeResourceNotFound  = "RESOURCE_NOT_FOUND"
+	ErrCodePromptNotFound    = "PROMPT_NOT_FOUND"
 	ErrCodeInternalError     = "INTERNAL_ERROR"
 )
 
@@ -65,6 +68,26 @@ func NewInvalidResponseError(serverURL, message string) *MCPError {
 	return NewMCPErrorWithServer(ErrCodeInvalidResponse, message, serverURL, 502)
 }
 
+// NewToolNotFoundError creates an error for a tool the server does not provide
+func NewToolNotFoundError(serverURL, toolName string) *MCPError {
+	return NewMCPErrorWithServer(ErrCodeToolNotFound, fmt.Sprintf("tool %q not found", toolName), serverURL, 404)
+}
+
+// NewResourceNotFoundError creates an error for a resource URI the server cannot read
+func NewResourceNotFoundError(serverURL, uri string) *MCPError {
+	return NewMCPErrorWithServer(ErrCodeResourceNotFound, fmt.Sprintf("resource %q not found", uri), serverURL, 404)
+}
+
+// NewPromptNotFoundError creates an error for a prompt the server does not provide
+func NewPromptNotFoundError(serverURL, promptName string) *MCPError {
+	return NewMCPErrorWithServer(ErrCodePromptNotFound, fmt.Sprintf("prompt %q not found", promptName), serverURL, 404)
+}
+
+// NewInvalidArgumentsError creates an error for tool arguments that do not match the tool's input schema
+func NewInvalidArgumentsError(serverURL, message string) *MCPError {
+	return NewMCPErrorWithServer(ErrCodeInvalidArguments, message, serverURL, 400)
+}
+
 // NewServerUnavailableError creates a server unavailable error
 func NewServerUnavailableError(serverURL string) *MCPError {
 	return NewMCPErrorWithServer(ErrCodeServerUnavailable, "MCP server is unavailable", serverURL, 503)
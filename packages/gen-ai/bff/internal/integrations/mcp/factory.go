@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/opendatahub-io/gen-ai/internal/cache"
 	"github.com/opendatahub-io/gen-ai/internal/config"
 	"github.com/opendatahub-io/gen-ai/internal/constants"
 	"github.com/opendatahub-io/gen-ai/internal/integrations"
@@ -19,9 +20,12 @@ type MCPClientFactory interface {
 	GetClient(ctx context.Context) (MCPClientInterface, error)
 	ExtractRequestIdentity(httpHeader http.Header) (*integrations.RequestIdentity, error)
 	ValidateRequestIdentity(identity *integrations.RequestIdentity) error
+	GetOAuthClient() *OAuthClient
+	GetBridge() *Bridge
 }
 
-// SimpleClientFactory creates stateless MCP clients
+// SimpleClientFactory creates MCP clients. Once pooling is enabled, the clients share a session pool
+// and a cache of results that a background health prober keeps fresh.
 type SimpleClientFactory struct {
 	Logger             *slog.Logger
 	Header             string
@@ -29,12 +33,21 @@ type SimpleClientFactory struct {
 	MCPConfig          *MCPClientConfig
 	InsecureSkipVerify bool
 	RootCAs            *x509.CertPool
+
+	sessions    *SessionPool
+	resultCache cache.MemoryStore
+	prober      *HealthProber
+	bridge      *Bridge
 }
 
-// NewMCPClientFactory creates a new MCP client factory
-func NewMCPClientFactory(cfg config.EnvConfig, logger *slog.Logger, insecureSkipVerify bool, rootCAs *x509.CertPool) (MCPClientFactory, error) {
+// NewMCPClientFactory creates a new MCP client factory with pooled sessions whose results are cached in the memory store,
+// and a bridge for stdio and WebSocket servers
+func NewMCPClientFactory(cfg config.EnvConfig, logger *slog.Logger, insecureSkipVerify bool, rootCAs *x509.CertPool, memoryStore cache.MemoryStore) (MCPClientFactory, error) {
 	mcpConfig := createMCPConfigFromEnv()
-	return NewSimpleClientFactoryWithTLS(logger, cfg, mcpConfig, insecureSkipVerify, rootCAs), nil
+	factory := NewSimpleClientFactoryWithTLS(logger, cfg, mcpConfig, insecureSkipVerify, rootCAs)
+	factory.EnableBridge()
+	factory.EnablePooling(memoryStore)
+	return factory, nil
 }
 
 // createMCPConfigFromEnv creates MCP configuration from environment variables
@@ -140,16 +153,87 @@ func (f *SimpleClientFactory) ValidateRequestIdentity(identity *integrations.Req
 	return nil
 }
 
+// EnablePooling makes the clients of the factory share pooled sessions and cache their connection statuses
+// and tool lists in the memory store, and starts the health prober that refreshes them. Call Close to stop it.
+func (f *SimpleClientFactory) EnablePooling(memoryStore cache.MemoryStore) {
+	if f.sessions != nil {
+		return
+	}
+
+	client := f.newClient()
+	f.sessions = NewSessionPool(f.Logger, client.config.SessionIdleTimeout)
+	f.resultCache = memoryStore
+	client.sessions = f.sessions
+	client.resultCache = f.resultCache
+
+	f.prober = NewHealthProber(client)
+	f.prober.Start()
+}
+
+// EnableBridge makes the clients of the factory able to use stdio servers, and lets LlamaStack reach stdio and
+// WebSocket servers through the bridge. Call Close to stop the bridged servers.
+func (f *SimpleClientFactory) EnableBridge() {
+	if f.bridge != nil {
+		return
+	}
+
+	client := f.newClient()
+	f.bridge = NewBridge(f.Logger, client.config, f.transportOptions(client.config))
+	f.bridge.Start()
+}
+
+// GetBridge returns the bridge of the factory, or nil when it is not enabled
+func (f *SimpleClientFactory) GetBridge() *Bridge {
+	return f.bridge
+}
+
 // GetClient creates a new MCP client instance
 func (f *SimpleClientFactory) GetClient(ctx context.Context) (MCPClientInterface, error) {
+	client := f.newClient()
+	client.sessions = f.sessions
+	client.resultCache = f.resultCache
+	return client, nil
+}
+
+// newClient creates a client with the factory configuration, TLS settings and bridge
+func (f *SimpleClientFactory) newClient() *SimpleMCPClient {
+	var client *SimpleMCPClient
 	if f.MCPConfig != nil {
 		// Update transport options with TLS settings
 		f.MCPConfig.InsecureSkipVerify = f.InsecureSkipVerify
-		transportOpts := f.MCPConfig.ToTransportOptions()
-		transportOpts.RootCAs = f.RootCAs
 		// Create config with updated transport options
 		configWithTLS := *f.MCPConfig
-		return NewSimpleMCPClientWithConfigAndTLS(f.Logger, &configWithTLS, transportOpts), nil
+		client = NewSimpleMCPClientWithConfigAndTLS(f.Logger, &configWithTLS, f.transportOptions(&configWithTLS))
+	} else {
+		client = NewSimpleMCPClient(f.Logger)
+	}
+	client.bridge = f.bridge
+	return client
+}
+
+// transportOptions returns the transport options of the configuration with the factory TLS settings
+func (f *SimpleClientFactory) transportOptions(mcpConfig *MCPClientConfig) *TransportOptions {
+	transportOpts := mcpConfig.ToTransportOptions()
+	transportOpts.InsecureSkipVerify = f.InsecureSkipVerify
+	transportOpts.RootCAs = f.RootCAs
+	return transportOpts
+}
+
+// Close stops the health prober, closes the pooled sessions and stops the bridged servers
+func (f *SimpleClientFactory) Close() error {
+	if f.prober != nil {
+		f.prober.Stop()
+	}
+	if f.sessions != nil {
+		f.sessions.Close()
+	}
+	if f.bridge != nil {
+		f.bridge.Close()
 	}
-	return NewSimpleMCPClient(f.Logger), nil
+	return nil
+}
+
+// GetOAuthClient creates an OAuth client for MCP authorization servers using the factory TLS settings
+func (f *SimpleClientFactory) GetOAuthClient() *OAuthClient {
+	return NewOAuthClient(NewOAuthHTTPClient(f.InsecureSkipVerify, f.RootCAs))
 }
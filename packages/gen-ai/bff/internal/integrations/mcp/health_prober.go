@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+)
+
+// HealthProber periodically checks the servers of pooled sessions and refreshes their cached connection
+// statuses and tool lists, so status and tools requests are answered without contacting the servers.
+// It also closes pooled sessions that stayed idle.
+type HealthProber struct {
+	client   *SimpleMCPClient
+	interval time.Duration
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewHealthProber creates a prober for the session pool and cache of the client
+func NewHealthProber(client *SimpleMCPClient) *HealthProber {
+	return &HealthProber{
+		client:   client,
+		interval: client.config.HealthCheckInterval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the prober in the background until Stop is called
+func (p *HealthProber) Start() {
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.probeAll(context.Background())
+			}
+		}
+	}()
+}
+
+// Stop stops the prober and waits for a running probe to finish
+func (p *HealthProber) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		<-p.done
+	})
+}
+
+// probeAll closes idle sessions and probes the remaining ones
+func (p *HealthProber) probeAll(ctx context.Context) {
+	c := p.client
+	if closed := c.sessions.evictIdle(time.Now()); closed > 0 {
+		c.logger.Debug("Closed idle MCP sessions", "count", closed)
+	}
+
+	var wg sync.WaitGroup
+	for _, entry := range c.sessions.snapshot() {
+		wg.Add(1)
+		go func(entry *pooledSession) {
+			defer wg.Done()
+			p.probe(ctx, entry)
+		}(entry)
+	}
+	wg.Wait()
+}
+
+// probe checks the server of a pooled session and refreshes its cached status and tools.
+// Sessions of servers that fail the check are evicted, and the failure is cached so it is reported right away.
+func (p *HealthProber) probe(ctx context.Context, entry *pooledSession) {
+	c := p.client
+	ctx, cancel := context.WithTimeout(ctx, c.config.HealthCheckTimeout)
+	defer cancel()
+
+	var status *models.ConnectionStatus
+	var err error
+	// Stdio and WebSocket servers do not answer plain HTTP requests
+	if c.config.EnableProtocolHealthCheck || RequiresBridge(entry.serverConfig.Transport) {
+		status, err = c.connectionStatusForSession(ctx, entry.serverConfig, entry.session, entry.initResult)
+	} else {
+		status, err = c.httpHealthCheck(ctx, entry.serverConfig, entry.initResult)
+	}
+	if err != nil {
+		c.logger.Warn("MCP server failed health check", "error", err, "server_url", entry.serverConfig.URL)
+		c.sessions.evict(entry)
+		p.cacheFailure(entry, status)
+		return
+	}
+
+	tools, err := c.toolsStatusForSession(ctx, entry.serverConfig, entry.session, entry.initResult)
+	if err != nil {
+		if isSessionError(err) {
+			c.sessions.evict(entry)
+			p.cacheFailure(entry, c.connectionFailureStatus(entry.serverConfig, entry.initResult, err, "MCP server health check failed"))
+		}
+		return
+	}
+
+	c.sessions.markVerified(entry)
+	c.cacheResult(constants.CacheMCPStatusCategory, entry.key.Namespace, &entry.identity, entry.serverConfig, status)
+	c.cacheResult(constants.CacheMCPToolsCategory, entry.key.Namespace, &entry.identity, entry.serverConfig, tools)
+}
+
+// cacheFailure caches the failed status of a server and drops its cached tools. Authorization failures are not
+// cached, because the authorization URL is prepared per request.
+func (p *HealthProber) cacheFailure(entry *pooledSession, status *models.ConnectionStatus) {
+	c := p.client
+	if c.resultCache == nil {
+		return
+	}
+	cacheKey := resultCacheKey(entry.key.Namespace, entry.serverConfig)
+	_ = c.resultCache.Delete(constants.CacheMCPNamespace, identityKey(&entry.identity), constants.CacheMCPToolsCategory, cacheKey)
+	if status.Status == StatusAuthRequired {
+		_ = c.resultCache.Delete(constants.CacheMCPNamespace, identityKey(&entry.identity), constants.CacheMCPStatusCategory, cacheKey)
+		return
+	}
+	c.cacheResult(constants.CacheMCPStatusCategory, entry.key.Namespace, &entry.identity, entry.serverConfig, status)
+}
+
+// httpHealthCheck checks that the server answers HTTP requests. Any response counts, since authorization and
+// protocol problems surface when the tools are refreshed over the session.
+func (c *SimpleMCPClient) httpHealthCheck(ctx context.Context, serverConfig models.MCPServerConfig, initResult *mcp.InitializeResult) (*models.ConnectionStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, serverConfig.URL, nil)
+	if err != nil {
+		return c.connectionFailureStatus(serverConfig, initResult, err, "MCP server health check failed"), err
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return c.connectionFailureStatus(serverConfig, initResult, err, "MCP server health check failed"), err
+	}
+	_ = resp.Body.Close()
+
+	return c.connectedStatus(serverConfig, initResult, time.Since(start).Milliseconds()), nil
+}
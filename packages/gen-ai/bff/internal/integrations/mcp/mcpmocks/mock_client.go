@@ -2,11 +2,14 @@ package mcpmocks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
 	"github.com/opendatahub-io/gen-ai/internal/models"
 )
 
@@ -274,13 +277,15 @@ func (m *MockMCPClient) CheckConnectionStatus(ctx context.Context, identity *int
 			Message:     "Connection successful",
 			LastChecked: timestamp,
 			ServerInfo: struct {
-				Name            string `json:"name"`
-				Version         string `json:"version"`
-				ProtocolVersion string `json:"protocol_version"`
+				Name            string                        `json:"name"`
+				Version         string                        `json:"version"`
+				ProtocolVersion string                        `json:"protocol_version"`
+				Capabilities    *models.MCPServerCapabilities `json:"capabilities,omitempty"`
 			}{
 				Name:            "brave-search-mcp-server",
 				Version:         "1.2.3",
 				ProtocolVersion: "2024-11-05",
+				Capabilities:    &models.MCPServerCapabilities{Tools: true},
 			},
 			PingResponseTimeMs: &pingMs,
 		}, nil
@@ -292,13 +297,15 @@ func (m *MockMCPClient) CheckConnectionStatus(ctx context.Context, identity *int
 			Message:     "Connection successful",
 			LastChecked: timestamp,
 			ServerInfo: struct {
-				Name            string `json:"name"`
-				Version         string `json:"version"`
-				ProtocolVersion string `json:"protocol_version"`
+				Name            string                        `json:"name"`
+				Version         string                        `json:"version"`
+				ProtocolVersion string                        `json:"protocol_version"`
+				Capabilities    *models.MCPServerCapabilities `json:"capabilities,omitempty"`
 			}{
 				Name:            "kubernetes-mcp-server",
 				Version:         "2.0.1",
 				ProtocolVersion: "2024-11-05",
+				Capabilities:    &models.MCPServerCapabilities{Tools: true, Resources: true, Prompts: true, Logging: true},
 			},
 			PingResponseTimeMs: &pingMs,
 		}, nil
@@ -309,9 +316,10 @@ func (m *MockMCPClient) CheckConnectionStatus(ctx context.Context, identity *int
 			Message:     "Server is not reachable",
 			LastChecked: timestamp,
 			ServerInfo: struct {
-				Name            string `json:"name"`
-				Version         string `json:"version"`
-				ProtocolVersion string `json:"protocol_version"`
+				Name            string                        `json:"name"`
+				Version         string                        `json:"version"`
+				ProtocolVersion string                        `json:"protocol_version"`
+				Capabilities    *models.MCPServerCapabilities `json:"capabilities,omitempty"`
 			}{
 				Name:            serverConfig.Name,
 				Version:         "N/A",
@@ -330,9 +338,10 @@ func (m *MockMCPClient) CheckConnectionStatus(ctx context.Context, identity *int
 			Message:     "Authentication failed",
 			LastChecked: timestamp,
 			ServerInfo: struct {
-				Name            string `json:"name"`
-				Version         string `json:"version"`
-				ProtocolVersion string `json:"protocol_version"`
+				Name            string                        `json:"name"`
+				Version         string                        `json:"version"`
+				ProtocolVersion string                        `json:"protocol_version"`
+				Capabilities    *models.MCPServerCapabilities `json:"capabilities,omitempty"`
 			}{
 				Name:            serverConfig.Name,
 				Version:         "N/A",
@@ -352,13 +361,15 @@ func (m *MockMCPClient) CheckConnectionStatus(ctx context.Context, identity *int
 			Message:     "Connection successful",
 			LastChecked: timestamp,
 			ServerInfo: struct {
-				Name            string `json:"name"`
-				Version         string `json:"version"`
-				ProtocolVersion string `json:"protocol_version"`
+				Name            string                        `json:"name"`
+				Version         string                        `json:"version"`
+				ProtocolVersion string                        `json:"protocol_version"`
+				Capabilities    *models.MCPServerCapabilities `json:"capabilities,omitempty"`
 			}{
 				Name:            "default-transport-server",
 				Version:         "1.0.0",
 				ProtocolVersion: "2024-11-05",
+				Capabilities:    &models.MCPServerCapabilities{Tools: true},
 			},
 			PingResponseTimeMs: &pingMs,
 		}, nil
@@ -370,13 +381,15 @@ func (m *MockMCPClient) CheckConnectionStatus(ctx context.Context, identity *int
 			Message:     "Connection successful",
 			LastChecked: timestamp,
 			ServerInfo: struct {
-				Name            string `json:"name"`
-				Version         string `json:"version"`
-				ProtocolVersion string `json:"protocol_version"`
+				Name            string                        `json:"name"`
+				Version         string                        `json:"version"`
+				ProtocolVersion string                        `json:"protocol_version"`
+				Capabilities    *models.MCPServerCapabilities `json:"capabilities,omitempty"`
 			}{
 				Name:            "invalid-transport-server",
 				Version:         "0.9.5",
 				ProtocolVersion: "2024-11-05",
+				Capabilities:    &models.MCPServerCapabilities{Tools: true},
 			},
 			PingResponseTimeMs: &pingMs,
 		}, nil
@@ -388,13 +401,15 @@ func (m *MockMCPClient) CheckConnectionStatus(ctx context.Context, identity *int
 			Message:     "Connection successful",
 			LastChecked: timestamp,
 			ServerInfo: struct {
-				Name            string `json:"name"`
-				Version         string `json:"version"`
-				ProtocolVersion string `json:"protocol_version"`
+				Name            string                        `json:"name"`
+				Version         string                        `json:"version"`
+				ProtocolVersion string                        `json:"protocol_version"`
+				Capabilities    *models.MCPServerCapabilities `json:"capabilities,omitempty"`
 			}{
 				Name:            "generic-mcp-server",
 				Version:         "1.0.0",
 				ProtocolVersion: "2024-11-05",
+				Capabilities:    &models.MCPServerCapabilities{Tools: true, Resources: true, Prompts: true},
 			},
 			PingResponseTimeMs: &pingMs,
 		}, nil
@@ -561,3 +576,291 @@ func (m *MockMCPClient) ListToolsWithStatus(ctx context.Context, identity *integ
 		}, nil
 	}
 }
+
+// CallTool validates the arguments against the mock tool schema and echoes them back (mock implementation)
+func (m *MockMCPClient) CallTool(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig, toolName string, arguments map[string]interface{}) (*models.ToolCallResult, error) {
+	if m.logger != nil {
+		m.logger.Debug("Mock: Calling MCP tool", "server_url", serverConfig.URL, "tool_name", toolName)
+	}
+
+	result := &models.ToolCallResult{
+		ServerURL: serverConfig.URL,
+		ToolName:  toolName,
+		Content:   []models.MCPContent{},
+	}
+
+	switch serverConfig.URL {
+	case "https://mcp-unavailable:8080/sse":
+		result.Status = "error"
+		result.Message = "Server is not reachable"
+		result.ErrorDetails = &models.ErrorDetails{
+			Code:       "connection_error",
+			StatusCode: 503,
+			RawError:   "failed to create SSE transport: dial tcp: connection refused",
+		}
+		return result, nil
+
+	case "https://mcp-error:8080/mcp":
+		result.Status = "error"
+		result.Message = "Authentication failed"
+		result.ErrorDetails = &models.ErrorDetails{
+			Code:       "unauthorized",
+			StatusCode: 401,
+			RawError:   "MCP initialization failed: authentication failed",
+		}
+		return result, nil
+	}
+
+	var tool *models.Tool
+	for _, candidate := range m.getToolsForServer(serverConfig.URL) {
+		if candidate.Name == toolName {
+			tool = &candidate
+			break
+		}
+	}
+	if tool == nil {
+		return nil, mcp.NewToolNotFoundError(serverConfig.URL, toolName)
+	}
+
+	if violations := mcp.ValidateToolArguments(tool.InputSchema, arguments); len(violations) > 0 {
+		return nil, mcp.NewInvalidArgumentsError(serverConfig.URL, strings.Join(violations, "; "))
+	}
+
+	argumentsJSON, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Status = "success"
+	result.Message = fmt.Sprintf("Tool %s completed in %d ms", toolName, 5)
+	result.Content = []models.MCPContent{
+		{Type: "text", Text: fmt.Sprintf("Mock result of %s with arguments %s", toolName, argumentsJSON)},
+	}
+	result.Timing = models.ToolCallTiming{ConnectMs: 10, CallMs: 5, TotalMs: 15}
+	return result, nil
+}
+
+// getResourcesForServer returns mock resources based on server URL; servers without the resources capability have none
+func (m *MockMCPClient) getResourcesForServer(serverURL string) ([]models.Resource, []models.ResourceTemplate) {
+	switch serverURL {
+	case "http://localhost:9090/sse", "http://localhost:9092/default-transport", "http://localhost:9093/invalid-transport":
+		return []models.Resource{}, []models.ResourceTemplate{}
+	case "http://localhost:9091/mcp":
+		return []models.Resource{
+			{
+				URI:         "k8s://cluster/info",
+				Name:        "cluster_info",
+				Title:       "Cluster Information",
+				Description: "Kubernetes version and API endpoint of the cluster",
+				MimeType:    "application/json",
+			},
+			{
+				URI:         "k8s://namespaces",
+				Name:        "namespaces",
+				Description: "Namespaces visible to the current user",
+				MimeType:    "text/plain",
+			},
+		}, []models.ResourceTemplate{
+			{
+				URITemplate: "k8s://namespaces/{namespace}/pods",
+				Name:        "namespace_pods",
+				Description: "Pods in a namespace",
+				MimeType:    "application/json",
+			},
+		}
+	default:
+		return []models.Resource{
+			{
+				URI:         "file:///README.md",
+				Name:        "readme",
+				Description: "Project README",
+				MimeType:    "text/markdown",
+				Size:        42,
+			},
+		}, []models.ResourceTemplate{}
+	}
+}
+
+// getPromptsForServer returns mock prompts based on server URL; servers without the prompts capability have none
+func (m *MockMCPClient) getPromptsForServer(serverURL string) []models.Prompt {
+	switch serverURL {
+	case "http://localhost:9090/sse", "http://localhost:9092/default-transport", "http://localhost:9093/invalid-transport":
+		return []models.Prompt{}
+	case "http://localhost:9091/mcp":
+		return []models.Prompt{
+			{
+				Name:        "troubleshoot_pod",
+				Title:       "Troubleshoot a Pod",
+				Description: "Investigate why a pod is not running",
+				Arguments: []models.PromptArgument{
+					{Name: "pod", Description: "Pod name", Required: true},
+					{Name: "namespace", Description: "Namespace of the pod", Required: false},
+				},
+			},
+		}
+	default:
+		return []models.Prompt{
+			{
+				Name:        "summarize",
+				Description: "Summarize a text",
+				Arguments: []models.PromptArgument{
+					{Name: "text", Description: "Text to summarize", Required: true},
+				},
+			},
+		}
+	}
+}
+
+// getMockFailure returns the failure of the mock servers that cannot be reached or reject the credentials
+func (m *MockMCPClient) getMockFailure(serverURL string) (string, *models.ErrorDetails, bool) {
+	switch serverURL {
+	case "https://mcp-unavailable:8080/sse":
+		return "Server is not reachable", &models.ErrorDetails{
+			Code:       "connection_error",
+			StatusCode: 503,
+			RawError:   "failed to create SSE transport: dial tcp: connection refused",
+		}, true
+	case "https://mcp-error:8080/mcp":
+		return "Authentication failed", &models.ErrorDetails{
+			Code:       "unauthorized",
+			StatusCode: 401,
+			RawError:   "MCP initialization failed: authentication failed",
+		}, true
+	default:
+		return "", nil, false
+	}
+}
+
+// getMockServerInfo returns the server information reported by the mock servers
+func (m *MockMCPClient) getMockServerInfo(serverConfig models.MCPServerConfig) models.MCPServerInfo {
+	switch serverConfig.URL {
+	case "http://localhost:9090/sse":
+		return models.MCPServerInfo{Name: "brave-search-mcp-server", Version: "1.2.3", ProtocolVersion: "2024-11-05"}
+	case "http://localhost:9091/mcp":
+		return models.MCPServerInfo{Name: "kubernetes-mcp-server", Version: "2.0.1", ProtocolVersion: "2024-11-05"}
+	case "https://mcp-unavailable:8080/sse", "https://mcp-error:8080/mcp":
+		return models.MCPServerInfo{Name: serverConfig.Name, Version: "N/A"}
+	default:
+		return models.MCPServerInfo{Name: "generic-mcp-server", Version: "1.0.0", ProtocolVersion: "2024-11-05"}
+	}
+}
+
+// ListResources returns mock resources with status information
+func (m *MockMCPClient) ListResources(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig) (*models.ResourcesStatus, error) {
+	timestamp := time.Now().Unix()
+	if m.fixedTimestamp != nil {
+		timestamp = *m.fixedTimestamp
+	}
+
+	status := &models.ResourcesStatus{
+		ServerURL:         serverConfig.URL,
+		LastChecked:       timestamp,
+		ServerInfo:        m.getMockServerInfo(serverConfig),
+		Resources:         []models.Resource{},
+		ResourceTemplates: []models.ResourceTemplate{},
+	}
+
+	if message, errorDetails, failed := m.getMockFailure(serverConfig.URL); failed {
+		status.Status = "error"
+		status.Message = message
+		status.ErrorDetails = errorDetails
+		return status, nil
+	}
+
+	status.Resources, status.ResourceTemplates = m.getResourcesForServer(serverConfig.URL)
+	resourcesCount := len(status.Resources)
+	status.Status = "success"
+	status.Message = fmt.Sprintf("Successfully retrieved %d resources", resourcesCount)
+	status.ResourcesCount = &resourcesCount
+	return status, nil
+}
+
+// ReadResource returns mock contents for the resources of the mock servers
+func (m *MockMCPClient) ReadResource(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig, uri string) (*models.ResourceReadResult, error) {
+	if _, errorDetails, failed := m.getMockFailure(serverConfig.URL); failed {
+		return nil, mcp.NewMCPErrorWithServer(errorDetails.Code, errorDetails.RawError, serverConfig.URL, errorDetails.StatusCode)
+	}
+
+	resources, _ := m.getResourcesForServer(serverConfig.URL)
+	for _, resource := range resources {
+		if resource.URI == uri {
+			return &models.ResourceReadResult{
+				ServerURL: serverConfig.URL,
+				URI:       uri,
+				Contents: []models.ResourceContents{
+					{URI: uri, MimeType: resource.MimeType, Text: fmt.Sprintf("Mock contents of %s", resource.Name)},
+				},
+			}, nil
+		}
+	}
+
+	return nil, mcp.NewResourceNotFoundError(serverConfig.URL, uri)
+}
+
+// ListPrompts returns mock prompts with status information
+func (m *MockMCPClient) ListPrompts(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig) (*models.PromptsStatus, error) {
+	timestamp := time.Now().Unix()
+	if m.fixedTimestamp != nil {
+		timestamp = *m.fixedTimestamp
+	}
+
+	status := &models.PromptsStatus{
+		ServerURL:   serverConfig.URL,
+		LastChecked: timestamp,
+		ServerInfo:  m.getMockServerInfo(serverConfig),
+		Prompts:     []models.Prompt{},
+	}
+
+	if message, errorDetails, failed := m.getMockFailure(serverConfig.URL); failed {
+		status.Status = "error"
+		status.Message = message
+		status.ErrorDetails = errorDetails
+		return status, nil
+	}
+
+	status.Prompts = m.getPromptsForServer(serverConfig.URL)
+	promptsCount := len(status.Prompts)
+	status.Status = "success"
+	status.Message = fmt.Sprintf("Successfully retrieved %d prompts", promptsCount)
+	status.PromptsCount = &promptsCount
+	return status, nil
+}
+
+// GetPrompt checks the required arguments and renders the mock prompt with them (mock implementation)
+func (m *MockMCPClient) GetPrompt(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig, promptName string, arguments map[string]string) (*models.PromptResult, error) {
+	if _, errorDetails, failed := m.getMockFailure(serverConfig.URL); failed {
+		return nil, mcp.NewMCPErrorWithServer(errorDetails.Code, errorDetails.RawError, serverConfig.URL, errorDetails.StatusCode)
+	}
+
+	for _, prompt := range m.getPromptsForServer(serverConfig.URL) {
+		if prompt.Name != promptName {
+			continue
+		}
+
+		var missing []string
+		for _, argument := range prompt.Arguments {
+			if argument.Required && arguments[argument.Name] == "" {
+				missing = append(missing, fmt.Sprintf("missing required argument %q", argument.Name))
+			}
+		}
+		if len(missing) > 0 {
+			return nil, mcp.NewInvalidArgumentsError(serverConfig.URL, strings.Join(missing, "; "))
+		}
+
+		argumentsJSON, err := json.Marshal(arguments)
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.PromptResult{
+			ServerURL:   serverConfig.URL,
+			Name:        promptName,
+			Description: prompt.Description,
+			Messages: []models.PromptMessage{
+				{Role: "user", Content: models.MCPContent{Type: "text", Text: fmt.Sprintf("Mock prompt %s with arguments %s", promptName, argumentsJSON)}},
+			},
+		}, nil
+	}
+
+	return nil, mcp.NewPromptNotFoundError(serverConfig.URL, promptName)
+}
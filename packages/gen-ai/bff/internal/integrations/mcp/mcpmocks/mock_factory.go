@@ -74,3 +74,13 @@ func (f *MockedMCPClientFactory) GetClient(ctx context.Context) (mcp.MCPClientIn
 	}
 	return NewMockMCPClient(f.logger), nil
 }
+
+// GetOAuthClient returns an OAuth client with default settings, so tests can point it at local stub authorization servers
+func (f *MockedMCPClientFactory) GetOAuthClient() *mcp.OAuthClient {
+	return mcp.NewOAuthClient(nil)
+}
+
+// GetBridge returns nil; the mocked clients do not run stdio servers
+func (f *MockedMCPClientFactory) GetBridge() *mcp.Bridge {
+	return nil
+}
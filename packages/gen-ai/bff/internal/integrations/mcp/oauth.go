@@ -0,0 +1,501 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// OAuth support follows the MCP authorization specification: the MCP server advertises its
+// protected resource metadata (RFC 9728), which names the authorization server (RFC 8414).
+// The BFF registers itself dynamically when needed (RFC 7591) and runs the authorization code
+// flow with PKCE (RFC 7636), binding tokens to the MCP server with resource indicators (RFC 8707).
+
+// StatusAuthRequired is the status reported for MCP servers that need the user to authorize the BFF
+const StatusAuthRequired = "auth_required"
+
+// maxOAuthResponseSize limits metadata and token responses read from authorization servers
+const maxOAuthResponseSize = 1 << 20
+
+// AuthRequiredError is returned when an MCP server rejects a request with 401 and points to its
+// OAuth protected resource metadata in the WWW-Authenticate header
+type AuthRequiredError struct {
+	ResourceMetadataURL string
+	Scope               string
+	Message             string
+}
+
+func (e *AuthRequiredError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", http.StatusUnauthorized, e.Message)
+}
+
+// ProtectedResourceMetadata is the OAuth protected resource metadata of an MCP server (RFC 9728)
+type ProtectedResourceMetadata struct {
+	Resource             string   `json:"resource"`
+	AuthorizationServers []string `json:"authorization_servers"`
+	ScopesSupported      []string `json:"scopes_supported,omitempty"`
+}
+
+// AuthorizationServerMetadata is the metadata of an OAuth authorization server (RFC 8414)
+type AuthorizationServerMetadata struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	RegistrationEndpoint          string   `json:"registration_endpoint,omitempty"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// OAuthDiscovery describes how to obtain tokens for an MCP server
+type OAuthDiscovery struct {
+	Resource            string // Canonical URI of the MCP server that tokens are requested for
+	Scopes              []string
+	AuthorizationServer AuthorizationServerMetadata
+}
+
+// OAuthClientCredentials identifies the BFF to an authorization server
+type OAuthClientCredentials struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// AuthorizationRequest holds the parameters of an authorization code request
+type AuthorizationRequest struct {
+	ClientID      string
+	RedirectURI   string
+	State         string
+	CodeChallenge string
+	Scope         string
+	Resource      string
+}
+
+// OAuthToken is a token response from an authorization server
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// OAuthError is an error response from an authorization server
+type OAuthError struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("OAuth error %s (HTTP %d): %s", e.Code, e.StatusCode, e.Description)
+	}
+	return fmt.Sprintf("OAuth error %s (HTTP %d)", e.Code, e.StatusCode)
+}
+
+// OAuthClient discovers MCP authorization servers and runs the authorization code flow
+type OAuthClient struct {
+	httpClient *http.Client
+}
+
+// NewOAuthClient creates an OAuth client. A nil HTTP client uses a default client with a timeout.
+func NewOAuthClient(httpClient *http.Client) *OAuthClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &OAuthClient{httpClient: httpClient}
+}
+
+// NewOAuthHTTPClient creates the HTTP client used to reach authorization servers with the BFF TLS settings
+func NewOAuthHTTPClient(insecureSkipVerify bool, rootCAs *x509.CertPool) *http.Client {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+	if rootCAs != nil {
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}
+}
+
+// Discover resolves the authorization server of an MCP server from its protected resource metadata.
+// When the 401 challenge did not include a metadata URL, the well-known locations on the server are used.
+// The metadata must describe a resource with the origin of the MCP server, so that a server cannot have tokens
+// for another resource sent to it, nor send the user to an authorization server of another resource.
+func (c *OAuthClient) Discover(ctx context.Context, serverURL, resourceMetadataURL string) (*OAuthDiscovery, error) {
+	parsed, err := url.Parse(serverURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid MCP server URL: %s", serverURL)
+	}
+
+	candidates := []string{resourceMetadataURL}
+	if resourceMetadataURL == "" {
+		origin := parsed.Scheme + "://" + parsed.Host
+		candidates = []string{origin + "/.well-known/oauth-protected-resource"}
+		if resourcePath := strings.TrimSuffix(parsed.Path, "/"); resourcePath != "" {
+			candidates = append([]string{origin + "/.well-known/oauth-protected-resource" + resourcePath}, candidates...)
+		}
+	}
+
+	var resourceMetadata ProtectedResourceMetadata
+	for _, candidate := range candidates {
+		resourceMetadata = ProtectedResourceMetadata{}
+		if err = c.getJSON(ctx, candidate, &resourceMetadata); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch protected resource metadata: %w", err)
+	}
+	if resourceMetadata.Resource != "" && !sameOrigin(resourceMetadata.Resource, parsed) {
+		return nil, fmt.Errorf("protected resource metadata is for %s, not for the MCP server %s", resourceMetadata.Resource, serverURL)
+	}
+	if len(resourceMetadata.AuthorizationServers) == 0 {
+		return nil, errors.New("protected resource metadata does not list an authorization server")
+	}
+
+	serverMetadata, err := c.discoverAuthorizationServer(ctx, resourceMetadata.AuthorizationServers[0])
+	if err != nil {
+		return nil, err
+	}
+
+	resource := resourceMetadata.Resource
+	if resource == "" {
+		resource = serverURL
+	}
+
+	return &OAuthDiscovery{
+		Resource:            resource,
+		Scopes:              resourceMetadata.ScopesSupported,
+		AuthorizationServer: *serverMetadata,
+	}, nil
+}
+
+// discoverAuthorizationServer fetches authorization server metadata, trying the OAuth and OpenID Connect
+// well-known locations in the order required by the MCP specification
+func (c *OAuthClient) discoverAuthorizationServer(ctx context.Context, issuer string) (*AuthorizationServerMetadata, error) {
+	parsed, err := url.Parse(issuer)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid authorization server URL: %s", issuer)
+	}
+
+	origin := parsed.Scheme + "://" + parsed.Host
+	issuerPath := strings.TrimSuffix(parsed.Path, "/")
+
+	candidates := []string{
+		origin + "/.well-known/oauth-authorization-server" + issuerPath,
+		origin + "/.well-known/openid-configuration" + issuerPath,
+	}
+	if issuerPath != "" {
+		candidates = append(candidates, origin+issuerPath+"/.well-known/openid-configuration")
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		var metadata AuthorizationServerMetadata
+		if err := c.getJSON(ctx, candidate, &metadata); err != nil {
+			lastErr = err
+			continue
+		}
+		if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" {
+			lastErr = fmt.Errorf("metadata at %s is missing authorization or token endpoint", candidate)
+			continue
+		}
+		if len(metadata.CodeChallengeMethodsSupported) > 0 && !containsString(metadata.CodeChallengeMethodsSupported, "S256") {
+			return nil, errors.New("authorization server does not support the S256 PKCE code challenge method")
+		}
+		return &metadata, nil
+	}
+
+	return nil, fmt.Errorf("failed to fetch authorization server metadata for %s: %w", issuer, lastErr)
+}
+
+// RegisterClient registers the BFF as a public client with the authorization server (RFC 7591)
+func (c *OAuthClient) RegisterClient(ctx context.Context, registrationEndpoint, redirectURI, clientName string) (*OAuthClientCredentials, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"client_name":                clientName,
+		"redirect_uris":              []string{redirectURI},
+		"grant_types":                []string{"authorization_code", "refresh_token"},
+		"response_types":             []string{"code"},
+		"token_endpoint_auth_method": "none",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode client registration: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, registrationEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client registration request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	var credentials OAuthClientCredentials
+	if err := c.doJSON(req, &credentials); err != nil {
+		return nil, fmt.Errorf("client registration failed: %w", err)
+	}
+	if credentials.ClientID == "" {
+		return nil, errors.New("client registration response is missing client_id")
+	}
+	return &credentials, nil
+}
+
+// AuthorizationURL builds the URL the user opens to authorize the BFF with PKCE
+func (c *OAuthClient) AuthorizationURL(metadata AuthorizationServerMetadata, request AuthorizationRequest) (string, error) {
+	authorizationURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+
+	query := authorizationURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", request.ClientID)
+	query.Set("redirect_uri", request.RedirectURI)
+	query.Set("state", request.State)
+	query.Set("code_challenge", request.CodeChallenge)
+	query.Set("code_challenge_method", "S256")
+	if request.Resource != "" {
+		query.Set("resource", request.Resource)
+	}
+	if request.Scope != "" {
+		query.Set("scope", request.Scope)
+	}
+	authorizationURL.RawQuery = query.Encode()
+
+	return authorizationURL.String(), nil
+}
+
+// ExchangeCode exchanges an authorization code for tokens
+func (c *OAuthClient) ExchangeCode(
+	ctx context.Context,
+	tokenEndpoint string,
+	client OAuthClientCredentials,
+	code, codeVerifier, redirectURI, resource string,
+) (*OAuthToken, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"code_verifier": {codeVerifier},
+		"redirect_uri":  {redirectURI},
+	}
+	if resource != "" {
+		form.Set("resource", resource)
+	}
+	return c.requestToken(ctx, tokenEndpoint, client, form)
+}
+
+// RefreshToken obtains a new access token with a refresh token
+func (c *OAuthClient) RefreshToken(
+	ctx context.Context,
+	tokenEndpoint string,
+	client OAuthClientCredentials,
+	refreshToken, resource string,
+) (*OAuthToken, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	}
+	if resource != "" {
+		form.Set("resource", resource)
+	}
+	return c.requestToken(ctx, tokenEndpoint, client, form)
+}
+
+// requestToken sends a token request, authenticating confidential clients with HTTP Basic
+func (c *OAuthClient) requestToken(ctx context.Context, tokenEndpoint string, client OAuthClientCredentials, form url.Values) (*OAuthToken, error) {
+	if client.ClientSecret == "" {
+		form.Set("client_id", client.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if client.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(client.ClientID), url.QueryEscape(client.ClientSecret))
+	}
+
+	var token OAuthToken
+	if err := c.doJSON(req, &token); err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("token response is missing access_token")
+	}
+	return &token, nil
+}
+
+// getJSON fetches a JSON document
+func (c *OAuthClient) getJSON(ctx context.Context, target string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	return c.doJSON(req, out)
+}
+
+// doJSON sends a request and decodes a successful JSON response, mapping error responses to OAuthError
+func (c *OAuthClient) doJSON(req *http.Request, out interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOAuthResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read response from %s: %w", req.URL.Redacted(), err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		oauthErr := &OAuthError{StatusCode: resp.StatusCode}
+		if json.Unmarshal(body, oauthErr) == nil && oauthErr.Code != "" {
+			return oauthErr
+		}
+		return fmt.Errorf("HTTP %d from %s", resp.StatusCode, req.URL.Redacted())
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("invalid JSON response from %s: %w", req.URL.Redacted(), err)
+	}
+	return nil
+}
+
+// NewPKCEVerifier returns a random PKCE code verifier
+func NewPKCEVerifier() (string, error) {
+	return randomURLSafeString(32)
+}
+
+// PKCEChallenge derives the S256 code challenge of a code verifier
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// NewOAuthState returns a random value for the OAuth state parameter
+func NewOAuthState() (string, error) {
+	return randomURLSafeString(24)
+}
+
+func randomURLSafeString(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// parseBearerChallenge returns the parameters of the Bearer challenge in a WWW-Authenticate header.
+// The second result is false when the header does not contain a Bearer challenge.
+func parseBearerChallenge(header string) (map[string]string, bool) {
+	params := make(map[string]string)
+	found, inBearer := false, false
+
+	rest := header
+	for {
+		rest = strings.TrimLeft(rest, " \t,")
+		if rest == "" {
+			break
+		}
+
+		end := strings.IndexAny(rest, " \t,=")
+		if end < 0 {
+			end = len(rest)
+		}
+		name := rest[:end]
+		rest = strings.TrimLeft(rest[end:], " \t")
+
+		if !strings.HasPrefix(rest, "=") {
+			// A token that is not followed by "=" starts a new challenge
+			inBearer = strings.EqualFold(name, "Bearer")
+			found = found || inBearer
+			continue
+		}
+
+		var value string
+		value, rest = readChallengeValue(strings.TrimLeft(rest[1:], " \t"))
+		if inBearer {
+			params[strings.ToLower(name)] = value
+		}
+	}
+
+	return params, found
+}
+
+// readChallengeValue reads a quoted string or token from the start of s and returns it with the remainder
+func readChallengeValue(s string) (string, string) {
+	if !strings.HasPrefix(s, `"`) {
+		end := strings.IndexAny(s, " \t,")
+		if end < 0 {
+			return s, ""
+		}
+		return s[:end], s[end:]
+	}
+
+	var value strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				value.WriteByte(s[i])
+			}
+		case '"':
+			return value.String(), s[i+1:]
+		default:
+			value.WriteByte(s[i])
+		}
+	}
+	return value.String(), ""
+}
+
+// sameOrigin reports whether a URL has the scheme, host and port of another URL
+func sameOrigin(rawURL string, other *url.URL) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.Scheme, other.Scheme) && strings.EqualFold(originHost(parsed), originHost(other))
+}
+
+// originHost returns the host of a URL with its port, adding the default port of the scheme when it has none
+func originHost(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		switch strings.ToLower(u.Scheme) {
+		case "https":
+			port = "443"
+		case "http":
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/url"
+	"testing"
+
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/opendatahub-io/gen-ai/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBearerChallenge(t *testing.T) {
+	t.Run("should parse quoted and token parameters", func(t *testing.T) {
+		params, ok := parseBearerChallenge(`Bearer resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource", scope="files:read files:write", error=invalid_token`)
+		require.True(t, ok)
+		assert.Equal(t, "https://mcp.example.com/.well-known/oauth-protected-resource", params["resource_metadata"])
+		assert.Equal(t, "files:read files:write", params["scope"])
+		assert.Equal(t, "invalid_token", params["error"])
+	})
+
+	t.Run("should find the Bearer challenge among other schemes", func(t *testing.T) {
+		params, ok := parseBearerChallenge(`Basic realm="mcp", Bearer resource_metadata="https://mcp.example.com/meta"`)
+		require.True(t, ok)
+		assert.Equal(t, "https://mcp.example.com/meta", params["resource_metadata"])
+	})
+
+	t.Run("should reject headers without a Bearer challenge", func(t *testing.T) {
+		_, ok := parseBearerChallenge(`Basic realm="mcp"`)
+		assert.False(t, ok)
+
+		_, ok = parseBearerChallenge("")
+		assert.False(t, ok)
+	})
+}
+
+func TestOAuthAuthorizationFlow(t *testing.T) {
+	server := testutil.NewOAuthStubServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	mcpClient := NewSimpleMCPClient(logger)
+	oauthClient := NewOAuthClient(nil)
+	serverConfig := models.MCPServerConfig{Name: "oauth-server", URL: server.MCPURL(), Transport: string(TransportTypeStreamableHTTP)}
+	redirectURI := "https://bff.example.com/gen-ai/api/v1/mcp/oauth/callback?namespace=test-namespace"
+
+	// authorize runs discovery, registration and the login redirect, and exchanges the returned code
+	authorize := func(t *testing.T) (*OAuthToken, OAuthClientCredentials, string) {
+		t.Helper()
+
+		discovery, err := oauthClient.Discover(ctx, server.MCPURL(), server.URL+testutil.OAuthStubResourceMetadataPath)
+		require.NoError(t, err)
+
+		client, err := oauthClient.RegisterClient(ctx, discovery.AuthorizationServer.RegistrationEndpoint, redirectURI, "test client")
+		require.NoError(t, err)
+
+		verifier, err := NewPKCEVerifier()
+		require.NoError(t, err)
+		state, err := NewOAuthState()
+		require.NoError(t, err)
+
+		loginURL, err := oauthClient.AuthorizationURL(discovery.AuthorizationServer, AuthorizationRequest{
+			ClientID:      client.ClientID,
+			RedirectURI:   redirectURI,
+			State:         state,
+			CodeChallenge: PKCEChallenge(verifier),
+			Scope:         testutil.OAuthStubScope,
+			Resource:      discovery.Resource,
+		})
+		require.NoError(t, err)
+
+		callback, err := server.Authorize(loginURL)
+		require.NoError(t, err)
+		assert.Equal(t, state, callback.Query().Get("state"))
+		assert.Equal(t, "test-namespace", callback.Query().Get("namespace"))
+
+		token, err := oauthClient.ExchangeCode(ctx, discovery.AuthorizationServer.TokenEndpoint, *client, callback.Query().Get("code"), verifier, redirectURI, discovery.Resource)
+		require.NoError(t, err)
+		return token, *client, discovery.AuthorizationServer.TokenEndpoint
+	}
+
+	t.Run("should report auth_required from the WWW-Authenticate challenge", func(t *testing.T) {
+		status, err := mcpClient.CheckConnectionStatus(ctx, &integrations.RequestIdentity{}, serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, StatusAuthRequired, status.Status)
+		require.NotNil(t, status.Authorization)
+		assert.Equal(t, server.URL+testutil.OAuthStubResourceMetadataPath, status.Authorization.ResourceMetadataURL)
+		assert.Equal(t, testutil.OAuthStubScope, status.Authorization.Scope)
+		require.NotNil(t, status.ErrorDetails)
+		assert.Equal(t, StatusAuthRequired, status.ErrorDetails.Code)
+
+		toolsStatus, err := mcpClient.ListToolsWithStatus(ctx, &integrations.RequestIdentity{}, serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, StatusAuthRequired, toolsStatus.Status)
+		require.NotNil(t, toolsStatus.Authorization)
+		assert.Equal(t, testutil.OAuthStubScope, toolsStatus.Authorization.Scope)
+	})
+
+	t.Run("should discover the authorization server from the resource metadata", func(t *testing.T) {
+		discovery, err := oauthClient.Discover(ctx, server.MCPURL(), server.URL+testutil.OAuthStubResourceMetadataPath)
+		require.NoError(t, err)
+		assert.Equal(t, server.MCPURL(), discovery.Resource)
+		assert.Equal(t, []string{testutil.OAuthStubScope}, discovery.Scopes)
+		assert.Equal(t, server.URL+testutil.OAuthStubIssuerPath+"/token", discovery.AuthorizationServer.TokenEndpoint)
+	})
+
+	t.Run("should reject resource metadata of another origin", func(t *testing.T) {
+		_, err := oauthClient.Discover(ctx, "https://mcp.example.com/mcp", server.URL+testutil.OAuthStubResourceMetadataPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not for the MCP server")
+
+		// The default port of the scheme is the same origin
+		other, err := url.Parse("https://mcp.example.com:443/mcp")
+		require.NoError(t, err)
+		assert.True(t, sameOrigin("https://MCP.example.com/", other))
+		assert.False(t, sameOrigin("http://mcp.example.com/mcp", other))
+	})
+
+	t.Run("should fall back to the well-known resource metadata location", func(t *testing.T) {
+		discovery, err := oauthClient.Discover(ctx, server.MCPURL(), "")
+		require.NoError(t, err)
+		assert.Equal(t, server.MCPURL(), discovery.Resource)
+	})
+
+	t.Run("should connect with tokens from the authorization code flow", func(t *testing.T) {
+		token, _, _ := authorize(t)
+		assert.NotEmpty(t, token.AccessToken)
+		assert.NotEmpty(t, token.RefreshToken)
+		assert.Equal(t, int64(3600), token.ExpiresIn)
+
+		identity := &integrations.RequestIdentity{MCPToken: token.AccessToken}
+		status, err := mcpClient.CheckConnectionStatus(ctx, identity, serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "connected", status.Status)
+		assert.Nil(t, status.Authorization)
+
+		toolsStatus, err := mcpClient.ListToolsWithStatus(ctx, identity, serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "success", toolsStatus.Status)
+		require.Len(t, toolsStatus.Tools, 1)
+		assert.Equal(t, "echo", toolsStatus.Tools[0].Name)
+	})
+
+	t.Run("should refresh and rotate tokens", func(t *testing.T) {
+		token, client, tokenEndpoint := authorize(t)
+
+		refreshed, err := oauthClient.RefreshToken(ctx, tokenEndpoint, client, token.RefreshToken, server.MCPURL())
+		require.NoError(t, err)
+		assert.NotEqual(t, token.AccessToken, refreshed.AccessToken)
+		assert.NotEqual(t, token.RefreshToken, refreshed.RefreshToken)
+
+		status, err := mcpClient.CheckConnectionStatus(ctx, &integrations.RequestIdentity{MCPToken: refreshed.AccessToken}, serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "connected", status.Status)
+
+		// Rotated refresh tokens can only be used once
+		_, err = oauthClient.RefreshToken(ctx, tokenEndpoint, client, token.RefreshToken, server.MCPURL())
+		var oauthErr *OAuthError
+		require.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, "invalid_grant", oauthErr.Code)
+	})
+
+	t.Run("should reject a code exchange with the wrong verifier", func(t *testing.T) {
+		discovery, err := oauthClient.Discover(ctx, server.MCPURL(), "")
+		require.NoError(t, err)
+		client, err := oauthClient.RegisterClient(ctx, discovery.AuthorizationServer.RegistrationEndpoint, redirectURI, "test client")
+		require.NoError(t, err)
+
+		verifier, err := NewPKCEVerifier()
+		require.NoError(t, err)
+		loginURL, err := oauthClient.AuthorizationURL(discovery.AuthorizationServer, AuthorizationRequest{
+			ClientID:      client.ClientID,
+			RedirectURI:   redirectURI,
+			State:         "state",
+			CodeChallenge: PKCEChallenge(verifier),
+			Resource:      discovery.Resource,
+		})
+		require.NoError(t, err)
+
+		callback, err := server.Authorize(loginURL)
+		require.NoError(t, err)
+
+		otherVerifier, err := NewPKCEVerifier()
+		require.NoError(t, err)
+		_, err = oauthClient.ExchangeCode(ctx, discovery.AuthorizationServer.TokenEndpoint, *client, callback.Query().Get("code"), otherVerifier, redirectURI, discovery.Resource)
+		var oauthErr *OAuthError
+		require.True(t, errors.As(err, &oauthErr))
+		assert.Equal(t, "invalid_grant", oauthErr.Code)
+	})
+}
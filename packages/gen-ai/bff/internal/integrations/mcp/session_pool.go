@@ -0,0 +1,218 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+)
+
+// sessionKey identifies a pooled session; sessions are never shared between identities
+type sessionKey struct {
+	ServerURL string
+	Transport string
+	Namespace string // Only set for stdio servers, which the bridge runs once per namespace
+	Identity  string
+}
+
+// pooledSession is an initialized MCP session kept open between requests
+type pooledSession struct {
+	key          sessionKey
+	session      *mcp.ClientSession
+	initResult   *mcp.InitializeResult
+	serverConfig models.MCPServerConfig
+	identity     integrations.RequestIdentity // Kept so the health prober can act on behalf of the session owner
+	lastUsed     time.Time                    // Last use by a request; the prober does not count as use
+	lastVerified time.Time                    // Last successful handshake, request or probe
+}
+
+// SessionPool keeps initialized MCP sessions per server and identity, so requests reuse them
+// instead of repeating the initialize handshake. Sessions idle for longer than the idle timeout are closed.
+type SessionPool struct {
+	logger      *slog.Logger
+	idleTimeout time.Duration
+
+	mu       sync.Mutex
+	sessions map[sessionKey]*pooledSession
+}
+
+// NewSessionPool creates an empty session pool
+func NewSessionPool(logger *slog.Logger, idleTimeout time.Duration) *SessionPool {
+	return &SessionPool{
+		logger:      logger,
+		idleTimeout: idleTimeout,
+		sessions:    make(map[sessionKey]*pooledSession),
+	}
+}
+
+// identityKey derives a stable key from the credentials of a request, without keeping them in the key itself
+func identityKey(identity *integrations.RequestIdentity) string {
+	if identity == nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(identity.Token + "\x00" + identity.MCPToken))
+	return hex.EncodeToString(sum[:])
+}
+
+func newSessionKey(serverConfig models.MCPServerConfig, namespace string, identity *integrations.RequestIdentity) sessionKey {
+	return sessionKey{
+		ServerURL: serverConfig.URL,
+		Transport: serverConfig.Transport,
+		Namespace: namespace,
+		Identity:  identityKey(identity),
+	}
+}
+
+// get returns the pooled session for the key and marks it as used
+func (p *SessionPool) get(key sessionKey) (*pooledSession, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.sessions[key]
+	if ok {
+		entry.lastUsed = time.Now()
+	}
+	return entry, ok
+}
+
+// put adds a session to the pool. When a concurrent request pooled a session for the same key first,
+// the new session is closed and the pooled one is returned instead.
+func (p *SessionPool) put(entry *pooledSession) *pooledSession {
+	p.mu.Lock()
+	existing, ok := p.sessions[entry.key]
+	if !ok {
+		p.sessions[entry.key] = entry
+	}
+	p.mu.Unlock()
+
+	if ok {
+		_ = entry.session.Close()
+		return existing
+	}
+	return entry
+}
+
+// markVerified records that the session just completed an operation successfully
+func (p *SessionPool) markVerified(entry *pooledSession) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry.lastVerified = time.Now()
+}
+
+// verifiedWithin reports whether the session completed an operation successfully within the interval
+func (p *SessionPool) verifiedWithin(entry *pooledSession, interval time.Duration) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return time.Since(entry.lastVerified) < interval
+}
+
+// evict removes the session from the pool and closes it, unless it was already replaced
+func (p *SessionPool) evict(entry *pooledSession) {
+	p.mu.Lock()
+	current, ok := p.sessions[entry.key]
+	if ok && current == entry {
+		delete(p.sessions, entry.key)
+	}
+	p.mu.Unlock()
+
+	if ok && current == entry {
+		p.logger.Debug("Closing pooled MCP session", "server_url", entry.key.ServerURL)
+		_ = entry.session.Close()
+	}
+}
+
+// evictIdle closes the sessions that no request used within the idle timeout and returns how many were closed
+func (p *SessionPool) evictIdle(now time.Time) int {
+	p.mu.Lock()
+	var idle []*pooledSession
+	for key, entry := range p.sessions {
+		if now.Sub(entry.lastUsed) >= p.idleTimeout {
+			idle = append(idle, entry)
+			delete(p.sessions, key)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, entry := range idle {
+		p.logger.Debug("Closing idle MCP session", "server_url", entry.key.ServerURL)
+		_ = entry.session.Close()
+	}
+	return len(idle)
+}
+
+// snapshot returns the pooled sessions at the time of the call
+func (p *SessionPool) snapshot() []*pooledSession {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entries := make([]*pooledSession, 0, len(p.sessions))
+	for _, entry := range p.sessions {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Len returns the number of pooled sessions
+func (p *SessionPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.sessions)
+}
+
+// Close closes all pooled sessions
+func (p *SessionPool) Close() {
+	p.mu.Lock()
+	sessions := p.sessions
+	p.sessions = make(map[sessionKey]*pooledSession)
+	p.mu.Unlock()
+
+	for _, entry := range sessions {
+		_ = entry.session.Close()
+	}
+}
+
+// isSessionError reports whether an error means the session itself is unusable, for example because
+// the connection dropped or the server restarted and no longer knows the session
+func isSessionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, mcp.ErrConnectionClosed) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	errMsg := strings.ToLower(err.Error())
+	for _, marker := range []string{"connection closed", "connection reset", "connection refused", "broken pipe", "session not found", "404"} {
+		if strings.Contains(errMsg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRetryableConnectError reports whether connecting again may succeed; authorization and protocol
+// errors are returned immediately
+func isRetryableConnectError(err error) bool {
+	var authErr *AuthRequiredError
+	if err == nil || errors.As(err, &authErr) {
+		return false
+	}
+
+	errMsg := strings.ToLower(err.Error())
+	for _, marker := range []string{"connection refused", "connection reset", "eof", "502", "503", "504"} {
+		if strings.Contains(errMsg, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/cache"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// swappableHandler lets tests replace the MCP server behind a URL, as if it restarted or went down
+type swappableHandler struct {
+	handler atomic.Pointer[http.Handler]
+}
+
+func (h *swappableHandler) set(handler http.Handler) {
+	h.handler.Store(&handler)
+}
+
+func (h *swappableHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	(*h.handler.Load()).ServeHTTP(w, r)
+}
+
+// newCountingServer creates an MCP server with one tool that counts initialize handshakes
+func newCountingServer(handshakes *atomic.Int32) *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "pooled-server", Version: "1.0.0"}, &mcp.ServerOptions{
+		InitializedHandler: func(context.Context, *mcp.InitializedRequest) { handshakes.Add(1) },
+	})
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"}, func(ctx context.Context, req *mcp.CallToolRequest, in struct{}) (*mcp.CallToolResult, struct{}, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, struct{}{}, nil
+	})
+	return server
+}
+
+func newPooledTestClient(config *MCPClientConfig) *SimpleMCPClient {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	client := NewSimpleMCPClientWithConfig(logger, config)
+	client.sessions = NewSessionPool(logger, client.config.SessionIdleTimeout)
+	client.resultCache = cache.NewMemoryStore()
+	return client
+}
+
+func TestSimpleMCPClientSessionPool(t *testing.T) {
+	var handshakes atomic.Int32
+	server := newCountingServer(&handshakes)
+	handler := &swappableHandler{}
+	handler.set(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	client := newPooledTestClient(nil)
+	defer client.sessions.Close()
+
+	ctx := context.Background()
+	identity := &integrations.RequestIdentity{Token: "user-token"}
+	serverConfig := models.MCPServerConfig{Name: "pooled", URL: httpServer.URL, Transport: string(TransportTypeStreamableHTTP)}
+
+	t.Run("should reuse the session across requests", func(t *testing.T) {
+		for range 3 {
+			result, err := client.CallTool(ctx, identity, serverConfig, "echo", nil)
+			require.NoError(t, err)
+			require.Equal(t, "success", result.Status)
+		}
+		_, err := client.ListPrompts(ctx, identity, serverConfig)
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(1), handshakes.Load())
+		assert.Equal(t, 1, client.sessions.Len())
+	})
+
+	t.Run("should not share sessions between identities", func(t *testing.T) {
+		other := &integrations.RequestIdentity{Token: "user-token", MCPToken: "other-mcp-token"}
+		_, err := client.CallTool(ctx, other, serverConfig, "echo", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(2), handshakes.Load())
+		assert.Equal(t, 2, client.sessions.Len())
+	})
+
+	t.Run("should reconnect when the server no longer knows the session", func(t *testing.T) {
+		// A fresh handler has no sessions, like a restarted server
+		handler.set(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+		client.config.HealthCheckInterval = 0 // Verify pooled sessions before every use
+		defer func() { client.config.HealthCheckInterval = DefaultMCPClientConfig().HealthCheckInterval }()
+
+		result, err := client.CallTool(ctx, identity, serverConfig, "echo", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "success", result.Status)
+		assert.Equal(t, int32(3), handshakes.Load())
+	})
+
+	t.Run("should retry once on a new session when the pooled session was dropped", func(t *testing.T) {
+		handler.set(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+
+		result, err := client.CallTool(ctx, identity, serverConfig, "echo", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "success", result.Status)
+		assert.Nil(t, result.ErrorDetails)
+		assert.Equal(t, int32(4), handshakes.Load())
+	})
+
+	t.Run("should close idle sessions", func(t *testing.T) {
+		assert.Equal(t, 2, client.sessions.evictIdle(time.Now().Add(client.config.SessionIdleTimeout)))
+		assert.Equal(t, 0, client.sessions.Len())
+	})
+}
+
+func TestSimpleMCPClientSSESessionPool(t *testing.T) {
+	var handshakes atomic.Int32
+	server := newCountingServer(&handshakes)
+	httpServer := httptest.NewServer(mcp.NewSSEHandler(func(*http.Request) *mcp.Server { return server }))
+	defer httpServer.Close()
+
+	config := DefaultMCPClientConfig()
+	config.TransportTimeout = 200 * time.Millisecond // Shorter than the life of the pooled stream
+	client := newPooledTestClient(config)
+	defer client.sessions.Close()
+
+	identity := &integrations.RequestIdentity{Token: "user-token"}
+	serverConfig := models.MCPServerConfig{Name: "pooled-sse", URL: httpServer.URL, Transport: string(TransportTypeSSE)}
+
+	t.Run("should keep the session after the request that opened it ends", func(t *testing.T) {
+		requestCtx, cancel := context.WithCancel(context.Background())
+		result, err := client.CallTool(requestCtx, identity, serverConfig, "echo", nil)
+		require.NoError(t, err)
+		require.Equal(t, "success", result.Status)
+		cancel()
+
+		// Outlive the transport timeout, which must not end the stream
+		time.Sleep(2 * config.TransportTimeout)
+
+		for range 2 {
+			result, err = client.CallTool(context.Background(), identity, serverConfig, "echo", nil)
+			require.NoError(t, err)
+			require.Equal(t, "success", result.Status, result.Message)
+		}
+		assert.Equal(t, int32(1), handshakes.Load())
+		assert.Equal(t, 1, client.sessions.Len())
+	})
+}
+
+func TestHealthProber(t *testing.T) {
+	var handshakes atomic.Int32
+	server := newCountingServer(&handshakes)
+	handler := &swappableHandler{}
+	handler.set(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	config := DefaultMCPClientConfig()
+	client := newPooledTestClient(config)
+	defer client.sessions.Close()
+	prober := NewHealthProber(client)
+
+	ctx := context.Background()
+	identity := &integrations.RequestIdentity{Token: "user-token"}
+	serverConfig := models.MCPServerConfig{Name: "pooled", URL: httpServer.URL, Transport: string(TransportTypeStreamableHTTP)}
+
+	t.Run("should serve cached results until the prober refreshes them", func(t *testing.T) {
+		tools, err := client.ListToolsWithStatus(ctx, identity, serverConfig)
+		require.NoError(t, err)
+		require.Equal(t, 1, *tools.ToolsCount)
+
+		mcp.AddTool(server, &mcp.Tool{Name: "added"}, func(ctx context.Context, req *mcp.CallToolRequest, in struct{}) (*mcp.CallToolResult, struct{}, error) {
+			return nil, struct{}{}, nil
+		})
+
+		cached, err := client.ListToolsWithStatus(ctx, identity, serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, 1, *cached.ToolsCount)
+
+		prober.probeAll(ctx)
+
+		refreshed, err := client.ListToolsWithStatus(ctx, identity, serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, 2, *refreshed.ToolsCount)
+		assert.GreaterOrEqual(t, refreshed.LastChecked, tools.LastChecked)
+
+		status, err := client.CheckConnectionStatus(ctx, identity, serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "connected", status.Status)
+		assert.Equal(t, int32(1), handshakes.Load())
+	})
+
+	t.Run("should evict sessions of servers that fail the protocol check", func(t *testing.T) {
+		config.EnableProtocolHealthCheck = true
+		handler.set(http.NotFoundHandler())
+
+		prober.probeAll(ctx)
+
+		assert.Equal(t, 0, client.sessions.Len())
+		status, err := client.CheckConnectionStatus(ctx, identity, serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "error", status.Status)
+		require.NotNil(t, status.ErrorDetails)
+	})
+
+	t.Run("should stop", func(t *testing.T) {
+		prober.Start()
+		prober.Stop()
+		prober.Stop()
+	})
+}
+
+func TestIsRetryableConnectError(t *testing.T) {
+	assert.True(t, isRetryableConnectError(errors.New("failed to connect to MCP server: dial tcp 127.0.0.1:1: connect: connection refused")))
+	assert.True(t, isRetryableConnectError(errors.New("HTTP 503: Service Unavailable")))
+	assert.False(t, isRetryableConnectError(errors.New("HTTP 401: Missing credentials")))
+	assert.False(t, isRetryableConnectError(&AuthRequiredError{ResourceMetadataURL: "https://mcp.example.com/.well-known/oauth-protected-resource"}))
+	assert.False(t, isRetryableConnectError(nil))
+}
@@ -2,24 +2,33 @@ package mcp
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/cache"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
 	"github.com/opendatahub-io/gen-ai/internal/integrations"
 	"github.com/opendatahub-io/gen-ai/internal/models"
 )
 
-// SimpleMCPClient implements MCPClientInterface using one-shot connections
+// SimpleMCPClient implements MCPClientInterface. Without a session pool every operation uses a one-shot connection;
+// clients created by the factory share its pool and cache.
 type SimpleMCPClient struct {
 	logger           *slog.Logger
 	config           *MCPClientConfig
 	transportFactory TransportFactory
 	httpClient       *http.Client
+	sessions         *SessionPool      // Optional pool of initialized sessions
+	resultCache      cache.MemoryStore // Optional cache of connection statuses and tool lists
+	bridge           *Bridge           // Runs stdio servers; without it they cannot be used
 }
 
 // NewSimpleMCPClient creates a new simple MCP client with default configuration
@@ -66,6 +75,15 @@ func NewSimpleMCPClientWithConfigAndTLS(logger *slog.Logger, config *MCPClientCo
 	httpClient := &http.Client{
 		Timeout: config.HealthCheckTimeout,
 	}
+	if transportOpts != nil {
+		// Health checks reach the same servers as the transports, so they need the same TLS settings
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: transportOpts.InsecureSkipVerify,
+				RootCAs:            transportOpts.RootCAs,
+			},
+		}
+	}
 
 	return &SimpleMCPClient{
 		logger:           logger,
@@ -75,88 +93,42 @@ func NewSimpleMCPClientWithConfigAndTLS(logger *slog.Logger, config *MCPClientCo
 	}
 }
 
-// CheckConnectionStatus checks the connection status of an MCP server
+// CheckConnectionStatus checks the connection status of an MCP server.
+// Statuses refreshed by the health prober are served from the cache while they are fresh.
 func (c *SimpleMCPClient) CheckConnectionStatus(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig) (*models.ConnectionStatus, error) {
 	c.logger.Debug("Checking MCP server connection status", "server_url", serverConfig.URL)
 
-	// Create fresh MCP client session for this request
-	session, initResult, err := c.createMCPSessionWithInit(ctx, serverConfig, identity)
-	if err != nil {
-		c.logger.Error("Failed to create MCP session for status check", "error", err, "server_url", serverConfig.URL)
-
-		mcpError := c.mapMCPError(err, serverConfig.URL)
-		mcpErr, ok := mcpError.(*MCPError)
-		if !ok {
-			mcpErr = &MCPError{
-				Code:       "internal_error",
-				Message:    "MCP operation failed",
-				ServerURL:  serverConfig.URL,
-				StatusCode: http.StatusInternalServerError,
-			}
-		}
+	if cached, ok := c.cachedResult(constants.CacheMCPStatusCategory, bridgeNamespace(ctx, serverConfig), identity, serverConfig).(*models.ConnectionStatus); ok {
+		status := *cached
+		return &status, nil
+	}
 
-		return &models.ConnectionStatus{
-			ServerURL:   serverConfig.URL,
-			Status:      "error",
-			Message:     mcpErr.Message,
-			LastChecked: time.Now().Unix(),
-			ServerInfo: struct {
-				Name            string `json:"name"`
-				Version         string `json:"version"`
-				ProtocolVersion string `json:"protocol_version"`
-			}{
-				Name:            serverConfig.Name, // ConfigMap key fallback on error
-				Version:         "N/A",
-				ProtocolVersion: "",
-			},
-			ErrorDetails: &models.ErrorDetails{
-				Code:       mcpErr.Code,
-				StatusCode: mcpErr.StatusCode,
-				RawError:   err.Error(),
-			},
-		}, nil
+	var status *models.ConnectionStatus
+	err := c.withSession(ctx, serverConfig, identity, func(session *mcp.ClientSession, initResult *mcp.InitializeResult) error {
+		var err error
+		status, err = c.connectionStatusForSession(ctx, serverConfig, session, initResult)
+		return err
+	})
+	if connectErr := connectFailure(err); connectErr != nil {
+		c.logger.Error("Failed to create MCP session for status check", "error", connectErr, "server_url", serverConfig.URL)
+		return c.connectionFailureStatus(serverConfig, nil, connectErr, "MCP operation failed"), nil
+	}
+	if err == nil {
+		c.cacheResult(constants.CacheMCPStatusCategory, bridgeNamespace(ctx, serverConfig), identity, serverConfig, status)
 	}
-	defer session.Close()
+	return status, nil
+}
 
+// connectionStatusForSession pings an initialized session and reports the result; the ping error is returned for session bookkeeping
+func (c *SimpleMCPClient) connectionStatusForSession(ctx context.Context, serverConfig models.MCPServerConfig, session *mcp.ClientSession, initResult *mcp.InitializeResult) (*models.ConnectionStatus, error) {
 	pingStart := time.Now()
-	err = session.Ping(ctx, &mcp.PingParams{})
+	err := session.Ping(ctx, &mcp.PingParams{})
 	pingDuration := time.Since(pingStart)
 
 	if err != nil {
 		c.logger.Error("MCP ping failed", "error", err, "server_url", serverConfig.URL)
-
-		mcpError := c.mapMCPError(err, serverConfig.URL)
-		mcpErr, ok := mcpError.(*MCPError)
-		if !ok {
-			mcpErr = &MCPError{
-				Code:       "ping_failed",
-				Message:    "MCP server ping failed",
-				ServerURL:  serverConfig.URL,
-				StatusCode: http.StatusServiceUnavailable,
-			}
-		}
-
 		// Connection established but ping failed
-		return &models.ConnectionStatus{
-			ServerURL:   serverConfig.URL,
-			Status:      "error",
-			Message:     mcpErr.Message,
-			LastChecked: time.Now().Unix(),
-			ServerInfo: struct {
-				Name            string `json:"name"`
-				Version         string `json:"version"`
-				ProtocolVersion string `json:"protocol_version"`
-			}{
-				Name:            c.extractServerName(initResult, serverConfig.Name),
-				Version:         c.extractServerVersion(initResult),
-				ProtocolVersion: c.extractProtocolVersion(initResult),
-			},
-			ErrorDetails: &models.ErrorDetails{
-				Code:       mcpErr.Code,
-				StatusCode: mcpErr.StatusCode,
-				RawError:   err.Error(),
-			},
-		}, nil
+		return c.connectionFailureStatus(serverConfig, initResult, err, "MCP server ping failed"), err
 	}
 
 	pingMs := pingDuration.Milliseconds()
@@ -164,104 +136,78 @@ func (c *SimpleMCPClient) CheckConnectionStatus(ctx context.Context, identity *i
 		"server_url", serverConfig.URL,
 		"ping_ms", pingMs)
 
+	return c.connectedStatus(serverConfig, initResult, pingMs), nil
+}
+
+// connectedStatus builds the status of a server that answered a health check
+func (c *SimpleMCPClient) connectedStatus(serverConfig models.MCPServerConfig, initResult *mcp.InitializeResult, pingMs int64) *models.ConnectionStatus {
 	return &models.ConnectionStatus{
 		ServerURL:   serverConfig.URL,
 		Status:      "connected",
 		Message:     "Connection successful",
 		LastChecked: time.Now().Unix(),
 		ServerInfo: struct {
-			Name            string `json:"name"`
-			Version         string `json:"version"`
-			ProtocolVersion string `json:"protocol_version"`
+			Name            string                        `json:"name"`
+			Version         string                        `json:"version"`
+			ProtocolVersion string                        `json:"protocol_version"`
+			Capabilities    *models.MCPServerCapabilities `json:"capabilities,omitempty"`
 		}{
 			Name:            c.extractServerName(initResult, serverConfig.Name),
 			Version:         c.extractServerVersion(initResult),
 			ProtocolVersion: c.extractProtocolVersion(initResult),
+			Capabilities:    c.extractCapabilities(initResult),
 		},
 		PingResponseTimeMs: &pingMs,
-	}, nil
+	}
 }
 
-// ListToolsWithStatus provides comprehensive tools information with server metadata and error handling
+// connectionFailureStatus builds the status of a server that could not be reached; initResult is nil when no session was established
+func (c *SimpleMCPClient) connectionFailureStatus(serverConfig models.MCPServerConfig, initResult *mcp.InitializeResult, err error, fallbackMessage string) *models.ConnectionStatus {
+	status := &models.ConnectionStatus{
+		ServerURL:   serverConfig.URL,
+		LastChecked: time.Now().Unix(),
+	}
+	status.Status, status.Message, status.ErrorDetails, status.Authorization = c.describeFailure(err, serverConfig.URL, fallbackMessage)
+
+	// ConfigMap key fallback when no session was established
+	status.ServerInfo.Name = c.extractServerName(initResult, serverConfig.Name)
+	status.ServerInfo.Version = c.extractServerVersion(initResult)
+	status.ServerInfo.ProtocolVersion = c.extractProtocolVersion(initResult)
+	return status
+}
+
+// ListToolsWithStatus provides comprehensive tools information with server metadata and error handling.
+// Tool lists refreshed by the health prober are served from the cache while they are fresh.
 func (c *SimpleMCPClient) ListToolsWithStatus(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig) (*models.ToolsStatus, error) {
 	c.logger.Debug("Listing tools with status from MCP server", "server_url", serverConfig.URL)
 
-	// Create fresh MCP client session for this request
-	session, initResult, err := c.createMCPSessionWithInit(ctx, serverConfig, identity)
-	if err != nil {
-		c.logger.Error("Failed to create MCP session for tools listing", "error", err, "server_url", serverConfig.URL)
-
-		mcpError := c.mapMCPError(err, serverConfig.URL)
-		mcpErr, ok := mcpError.(*MCPError)
-		if !ok {
-			mcpErr = &MCPError{
-				Code:       "internal_error",
-				Message:    "Failed to connect to MCP server",
-				ServerURL:  serverConfig.URL,
-				StatusCode: 500,
-			}
-		}
+	if cached, ok := c.cachedResult(constants.CacheMCPToolsCategory, bridgeNamespace(ctx, serverConfig), identity, serverConfig).(*models.ToolsStatus); ok {
+		status := *cached
+		return &status, nil
+	}
 
-		return &models.ToolsStatus{
-			ServerURL:   serverConfig.URL,
-			Status:      "error",
-			Message:     mcpErr.Message,
-			LastChecked: time.Now().Unix(),
-			ServerInfo: struct {
-				Name            string `json:"name"`
-				Version         string `json:"version"`
-				ProtocolVersion string `json:"protocol_version"`
-			}{
-				Name:            serverConfig.Name,
-				Version:         "N/A",
-				ProtocolVersion: "",
-			},
-			Tools: []models.Tool{},
-			ErrorDetails: &models.ErrorDetails{
-				Code:       mcpErr.Code,
-				StatusCode: mcpErr.StatusCode,
-				RawError:   err.Error(),
-			},
-		}, nil
+	var status *models.ToolsStatus
+	err := c.withSession(ctx, serverConfig, identity, func(session *mcp.ClientSession, initResult *mcp.InitializeResult) error {
+		var err error
+		status, err = c.toolsStatusForSession(ctx, serverConfig, session, initResult)
+		return err
+	})
+	if connectErr := connectFailure(err); connectErr != nil {
+		c.logger.Error("Failed to create MCP session for tools listing", "error", connectErr, "server_url", serverConfig.URL)
+		return c.toolsFailureStatus(serverConfig, nil, connectErr, "Failed to connect to MCP server"), nil
+	}
+	if err == nil {
+		c.cacheResult(constants.CacheMCPToolsCategory, bridgeNamespace(ctx, serverConfig), identity, serverConfig, status)
 	}
-	defer session.Close()
+	return status, nil
+}
 
+// toolsStatusForSession lists the tools of an initialized session; the listing error is returned for session bookkeeping
+func (c *SimpleMCPClient) toolsStatusForSession(ctx context.Context, serverConfig models.MCPServerConfig, session *mcp.ClientSession, initResult *mcp.InitializeResult) (*models.ToolsStatus, error) {
 	toolsResponse, err := session.ListTools(ctx, &mcp.ListToolsParams{})
 	if err != nil {
 		c.logger.Error("Failed to list tools from MCP server", "error", err, "server_url", serverConfig.URL)
-
-		mcpError := c.mapMCPError(err, serverConfig.URL)
-		mcpErr, ok := mcpError.(*MCPError)
-		if !ok {
-			mcpErr = &MCPError{
-				Code:       "internal_error",
-				Message:    "Failed to list tools from MCP server",
-				ServerURL:  serverConfig.URL,
-				StatusCode: 500,
-			}
-		}
-
-		return &models.ToolsStatus{
-			ServerURL:   serverConfig.URL,
-			Status:      "error",
-			Message:     mcpErr.Message,
-			LastChecked: time.Now().Unix(),
-			ServerInfo: struct {
-				Name            string `json:"name"`
-				Version         string `json:"version"`
-				ProtocolVersion string `json:"protocol_version"`
-			}{
-				Name:            c.extractServerName(initResult, serverConfig.Name),
-				Version:         c.extractServerVersion(initResult),
-				ProtocolVersion: c.extractProtocolVersion(initResult),
-			},
-			Tools: []models.Tool{},
-			ErrorDetails: &models.ErrorDetails{
-				Code:       mcpErr.Code,
-				StatusCode: mcpErr.StatusCode,
-				RawError:   err.Error(),
-			},
-		}, nil
+		return c.toolsFailureStatus(serverConfig, initResult, err, "Failed to list tools from MCP server"), err
 	}
 
 	tools := make([]models.Tool, 0, len(toolsResponse.Tools))
@@ -298,8 +244,591 @@ func (c *SimpleMCPClient) ListToolsWithStatus(ctx context.Context, identity *int
 	}, nil
 }
 
-// createMCPSessionWithInit creates a fresh MCP session and returns both session and initialization result
-func (c *SimpleMCPClient) createMCPSessionWithInit(ctx context.Context, serverConfig models.MCPServerConfig, identity *integrations.RequestIdentity) (*mcp.ClientSession, *mcp.InitializeResult, error) {
+// toolsFailureStatus builds the tools status of a server whose tools could not be listed; initResult is nil when no session was established
+func (c *SimpleMCPClient) toolsFailureStatus(serverConfig models.MCPServerConfig, initResult *mcp.InitializeResult, err error, fallbackMessage string) *models.ToolsStatus {
+	status := &models.ToolsStatus{
+		ServerURL:   serverConfig.URL,
+		LastChecked: time.Now().Unix(),
+		Tools:       []models.Tool{},
+	}
+	status.Status, status.Message, status.ErrorDetails, status.Authorization = c.describeFailure(err, serverConfig.URL, fallbackMessage)
+
+	status.ServerInfo.Name = c.extractServerName(initResult, serverConfig.Name)
+	status.ServerInfo.Version = c.extractServerVersion(initResult)
+	status.ServerInfo.ProtocolVersion = c.extractProtocolVersion(initResult)
+	return status
+}
+
+// CallTool invokes a tool on the MCP server after validating the arguments against the tool's input schema.
+// Connection and protocol failures are reported in the result; unknown tools and invalid arguments are returned as *MCPError.
+func (c *SimpleMCPClient) CallTool(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig, toolName string, arguments map[string]interface{}) (*models.ToolCallResult, error) {
+	c.logger.Debug("Calling MCP tool", "server_url", serverConfig.URL, "tool_name", toolName)
+
+	start := time.Now()
+	result := &models.ToolCallResult{
+		ServerURL: serverConfig.URL,
+		ToolName:  toolName,
+		Content:   []models.MCPContent{},
+	}
+	defer func() {
+		result.Timing.TotalMs = time.Since(start).Milliseconds()
+	}()
+
+	err := c.withSession(ctx, serverConfig, identity, func(session *mcp.ClientSession, _ *mcp.InitializeResult) error {
+		// A retry on a new session starts over
+		*result = models.ToolCallResult{ServerURL: serverConfig.URL, ToolName: toolName, Content: []models.MCPContent{}}
+		result.Timing.ConnectMs = time.Since(start).Milliseconds()
+
+		var tool *mcp.Tool
+		for candidate, err := range session.Tools(ctx, &mcp.ListToolsParams{}) {
+			if err != nil {
+				c.logger.Error("Failed to list tools from MCP server", "error", err, "server_url", serverConfig.URL)
+				c.setToolCallFailure(result, err, serverConfig.URL, "Failed to list tools from MCP server")
+				return err
+			}
+			if candidate.Name == toolName {
+				tool = candidate
+				break
+			}
+		}
+		if tool == nil {
+			return NewToolNotFoundError(serverConfig.URL, toolName)
+		}
+
+		if violations := ValidateToolArguments(convertMCPInputSchema(tool.InputSchema), arguments); len(violations) > 0 {
+			return NewInvalidArgumentsError(serverConfig.URL, strings.Join(violations, "; "))
+		}
+		if arguments == nil {
+			arguments = map[string]interface{}{}
+		}
+
+		callStart := time.Now()
+		callResult, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      toolName,
+			Arguments: arguments,
+		})
+		result.Timing.CallMs = time.Since(callStart).Milliseconds()
+		if err != nil {
+			c.logger.Error("MCP tool call failed", "error", err, "server_url", serverConfig.URL, "tool_name", toolName)
+			c.setToolCallFailure(result, err, serverConfig.URL, "Tool call failed")
+			return err
+		}
+
+		result.Content = convertToolContent(callResult.Content)
+		result.StructuredContent = callResult.StructuredContent
+		result.IsError = callResult.IsError
+
+		// Errors raised by the tool itself are part of a successful call; the content describes them
+		if callResult.IsError {
+			result.Status = "error"
+			result.Message = "Tool returned an error"
+		} else {
+			result.Status = "success"
+			result.Message = fmt.Sprintf("Tool %s completed in %d ms", toolName, result.Timing.CallMs)
+		}
+
+		c.logger.Debug("MCP tool call completed",
+			"server_url", serverConfig.URL,
+			"tool_name", toolName,
+			"is_error", callResult.IsError,
+			"call_ms", result.Timing.CallMs)
+		return nil
+	})
+	if connectErr := connectFailure(err); connectErr != nil {
+		result.Timing.ConnectMs = time.Since(start).Milliseconds()
+		c.logger.Error("Failed to create MCP session for tool call", "error", connectErr, "server_url", serverConfig.URL)
+		c.setToolCallFailure(result, connectErr, serverConfig.URL, "Failed to connect to MCP server")
+		return result, nil
+	}
+
+	// Unknown tools and invalid arguments are rejected; connection and protocol failures are in the result
+	var mcpErr *MCPError
+	if errors.As(err, &mcpErr) {
+		return nil, err
+	}
+	return result, nil
+}
+
+// setToolCallFailure records a connection or protocol failure in a tool call result
+func (c *SimpleMCPClient) setToolCallFailure(result *models.ToolCallResult, err error, serverURL, fallbackMessage string) {
+	result.Status, result.Message, result.ErrorDetails, result.Authorization = c.describeFailure(err, serverURL, fallbackMessage)
+}
+
+// describeFailure returns the status, message, error details and authorization to report for a failed MCP operation
+func (c *SimpleMCPClient) describeFailure(err error, serverURL, fallbackMessage string) (string, string, *models.ErrorDetails, *models.MCPAuthorization) {
+	mcpErr, ok := c.mapMCPError(err, serverURL).(*MCPError)
+	if !ok {
+		mcpErr = &MCPError{
+			Code:       "internal_error",
+			Message:    fallbackMessage,
+			ServerURL:  serverURL,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	status, authorization := failureStatus(err)
+	return status, mcpErr.Message, &models.ErrorDetails{
+		Code:       mcpErr.Code,
+		StatusCode: mcpErr.StatusCode,
+		RawError:   err.Error(),
+	}, authorization
+}
+
+// convertToolContent converts MCP content items to our generic format
+func convertToolContent(content []mcp.Content) []models.MCPContent {
+	result := make([]models.MCPContent, 0, len(content))
+	for _, item := range content {
+		if converted, ok := convertMCPContent(item); ok {
+			result = append(result, converted)
+		}
+	}
+	return result
+}
+
+// convertMCPContent converts a single MCP content item; unknown content types are skipped
+func convertMCPContent(item mcp.Content) (models.MCPContent, bool) {
+	switch c := item.(type) {
+	case *mcp.TextContent:
+		return models.MCPContent{Type: "text", Text: c.Text}, true
+	case *mcp.ImageContent:
+		return models.MCPContent{Type: "image", Data: c.Data, MimeType: c.MIMEType}, true
+	case *mcp.AudioContent:
+		return models.MCPContent{Type: "audio", Data: c.Data, MimeType: c.MIMEType}, true
+	case *mcp.ResourceLink:
+		return models.MCPContent{Type: "resource_link", URI: c.URI, Name: c.Name, MimeType: c.MIMEType}, true
+	case *mcp.EmbeddedResource:
+		embedded := models.MCPContent{Type: "resource"}
+		if c.Resource != nil {
+			embedded.URI = c.Resource.URI
+			embedded.MimeType = c.Resource.MIMEType
+			embedded.Text = c.Resource.Text
+			embedded.Data = c.Resource.Blob
+		}
+		return embedded, true
+	default:
+		return models.MCPContent{}, false
+	}
+}
+
+// ListResources lists the resources and resource templates of an MCP server with status information
+func (c *SimpleMCPClient) ListResources(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig) (*models.ResourcesStatus, error) {
+	c.logger.Debug("Listing resources from MCP server", "server_url", serverConfig.URL)
+
+	status := &models.ResourcesStatus{
+		ServerURL:         serverConfig.URL,
+		LastChecked:       time.Now().Unix(),
+		ServerInfo:        c.extractServerInfo(nil, serverConfig.Name),
+		Resources:         []models.Resource{},
+		ResourceTemplates: []models.ResourceTemplate{},
+	}
+
+	err := c.withSession(ctx, serverConfig, identity, func(session *mcp.ClientSession, initResult *mcp.InitializeResult) error {
+		status.ServerInfo = c.extractServerInfo(initResult, serverConfig.Name)
+		status.Resources = []models.Resource{}
+		status.ResourceTemplates = []models.ResourceTemplate{}
+
+		// Servers without the resources capability would reject the request
+		if !c.extractCapabilities(initResult).Resources {
+			return nil
+		}
+		for resource, err := range session.Resources(ctx, &mcp.ListResourcesParams{}) {
+			if err != nil {
+				c.logger.Error("Failed to list resources from MCP server", "error", err, "server_url", serverConfig.URL)
+				return err
+			}
+			status.Resources = append(status.Resources, models.Resource{
+				URI:         resource.URI,
+				Name:        resource.Name,
+				Title:       resource.Title,
+				Description: resource.Description,
+				MimeType:    resource.MIMEType,
+				Size:        resource.Size,
+			})
+		}
+
+		// Resource templates are optional, so servers that do not implement them still list their resources
+		for template, err := range session.ResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{}) {
+			if err != nil {
+				c.logger.Warn("Failed to list resource templates from MCP server", "error", err, "server_url", serverConfig.URL)
+				status.ResourceTemplates = []models.ResourceTemplate{}
+				break
+			}
+			status.ResourceTemplates = append(status.ResourceTemplates, models.ResourceTemplate{
+				URITemplate: template.URITemplate,
+				Name:        template.Name,
+				Title:       template.Title,
+				Description: template.Description,
+				MimeType:    template.MIMEType,
+			})
+		}
+		return nil
+	})
+	if connectErr := connectFailure(err); connectErr != nil {
+		c.logger.Error("Failed to create MCP session for resources listing", "error", connectErr, "server_url", serverConfig.URL)
+		status.Status, status.Message, status.ErrorDetails, status.Authorization = c.describeFailure(connectErr, serverConfig.URL, "Failed to connect to MCP server")
+		return status, nil
+	}
+	if err != nil {
+		status.Status, status.Message, status.ErrorDetails, status.Authorization = c.describeFailure(err, serverConfig.URL, "Failed to list resources from MCP server")
+		status.Resources = []models.Resource{}
+		return status, nil
+	}
+
+	resourcesCount := len(status.Resources)
+	status.Status = "success"
+	status.Message = fmt.Sprintf("Successfully retrieved %d resources", resourcesCount)
+	status.ResourcesCount = &resourcesCount
+	return status, nil
+}
+
+// ReadResource reads the contents of a resource from an MCP server
+func (c *SimpleMCPClient) ReadResource(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig, uri string) (*models.ResourceReadResult, error) {
+	c.logger.Debug("Reading resource from MCP server", "server_url", serverConfig.URL, "uri", uri)
+
+	var readResult *mcp.ReadResourceResult
+	err := c.withSession(ctx, serverConfig, identity, func(session *mcp.ClientSession, _ *mcp.InitializeResult) error {
+		var err error
+		readResult, err = session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+		return err
+	})
+	if connectErr := connectFailure(err); connectErr != nil {
+		c.logger.Error("Failed to create MCP session for resource read", "error", connectErr, "server_url", serverConfig.URL)
+		return nil, c.mapMCPError(connectErr, serverConfig.URL)
+	}
+	if err != nil {
+		c.logger.Error("Failed to read resource from MCP server", "error", err, "server_url", serverConfig.URL, "uri", uri)
+		// The SDK reports the resource not found JSON-RPC error (-32002) by its message
+		if strings.Contains(err.Error(), "Resource not found") {
+			return nil, NewResourceNotFoundError(serverConfig.URL, uri)
+		}
+		return nil, c.mapMCPError(err, serverConfig.URL)
+	}
+
+	contents := make([]models.ResourceContents, 0, len(readResult.Contents))
+	for _, content := range readResult.Contents {
+		if content == nil {
+			continue
+		}
+		contents = append(contents, models.ResourceContents{
+			URI:      content.URI,
+			MimeType: content.MIMEType,
+			Text:     content.Text,
+			Blob:     content.Blob,
+		})
+	}
+
+	return &models.ResourceReadResult{
+		ServerURL: serverConfig.URL,
+		URI:       uri,
+		Contents:  contents,
+	}, nil
+}
+
+// ListPrompts lists the prompts of an MCP server with status information
+func (c *SimpleMCPClient) ListPrompts(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig) (*models.PromptsStatus, error) {
+	c.logger.Debug("Listing prompts from MCP server", "server_url", serverConfig.URL)
+
+	status := &models.PromptsStatus{
+		ServerURL:   serverConfig.URL,
+		LastChecked: time.Now().Unix(),
+		ServerInfo:  c.extractServerInfo(nil, serverConfig.Name),
+		Prompts:     []models.Prompt{},
+	}
+
+	err := c.withSession(ctx, serverConfig, identity, func(session *mcp.ClientSession, initResult *mcp.InitializeResult) error {
+		status.ServerInfo = c.extractServerInfo(initResult, serverConfig.Name)
+		status.Prompts = []models.Prompt{}
+
+		// Servers without the prompts capability would reject the request
+		if !c.extractCapabilities(initResult).Prompts {
+			return nil
+		}
+		for prompt, err := range session.Prompts(ctx, &mcp.ListPromptsParams{}) {
+			if err != nil {
+				c.logger.Error("Failed to list prompts from MCP server", "error", err, "server_url", serverConfig.URL)
+				return err
+			}
+			status.Prompts = append(status.Prompts, convertMCPPrompt(prompt))
+		}
+		return nil
+	})
+	if connectErr := connectFailure(err); connectErr != nil {
+		c.logger.Error("Failed to create MCP session for prompts listing", "error", connectErr, "server_url", serverConfig.URL)
+		status.Status, status.Message, status.ErrorDetails, status.Authorization = c.describeFailure(connectErr, serverConfig.URL, "Failed to connect to MCP server")
+		return status, nil
+	}
+	if err != nil {
+		status.Status, status.Message, status.ErrorDetails, status.Authorization = c.describeFailure(err, serverConfig.URL, "Failed to list prompts from MCP server")
+		status.Prompts = []models.Prompt{}
+		return status, nil
+	}
+
+	promptsCount := len(status.Prompts)
+	status.Status = "success"
+	status.Message = fmt.Sprintf("Successfully retrieved %d prompts", promptsCount)
+	status.PromptsCount = &promptsCount
+	return status, nil
+}
+
+// GetPrompt renders a prompt of an MCP server with the given arguments.
+// Unknown prompts and missing required arguments are returned as *MCPError before the prompt is requested.
+func (c *SimpleMCPClient) GetPrompt(ctx context.Context, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig, promptName string, arguments map[string]string) (*models.PromptResult, error) {
+	c.logger.Debug("Getting prompt from MCP server", "server_url", serverConfig.URL, "prompt_name", promptName)
+
+	var promptResult *mcp.GetPromptResult
+	err := c.withSession(ctx, serverConfig, identity, func(session *mcp.ClientSession, _ *mcp.InitializeResult) error {
+		var prompt *mcp.Prompt
+		for candidate, err := range session.Prompts(ctx, &mcp.ListPromptsParams{}) {
+			if err != nil {
+				c.logger.Error("Failed to list prompts from MCP server", "error", err, "server_url", serverConfig.URL)
+				return err
+			}
+			if candidate.Name == promptName {
+				prompt = candidate
+				break
+			}
+		}
+		if prompt == nil {
+			return NewPromptNotFoundError(serverConfig.URL, promptName)
+		}
+
+		var missing []string
+		for _, argument := range prompt.Arguments {
+			if argument != nil && argument.Required && arguments[argument.Name] == "" {
+				missing = append(missing, fmt.Sprintf("missing required argument %q", argument.Name))
+			}
+		}
+		if len(missing) > 0 {
+			return NewInvalidArgumentsError(serverConfig.URL, strings.Join(missing, "; "))
+		}
+
+		var err error
+		promptResult, err = session.GetPrompt(ctx, &mcp.GetPromptParams{
+			Name:      promptName,
+			Arguments: arguments,
+		})
+		if err != nil {
+			c.logger.Error("Failed to get prompt from MCP server", "error", err, "server_url", serverConfig.URL, "prompt_name", promptName)
+		}
+		return err
+	})
+	if connectErr := connectFailure(err); connectErr != nil {
+		c.logger.Error("Failed to create MCP session for prompt", "error", connectErr, "server_url", serverConfig.URL)
+		return nil, c.mapMCPError(connectErr, serverConfig.URL)
+	}
+	if err != nil {
+		return nil, c.mapMCPError(err, serverConfig.URL)
+	}
+
+	messages := make([]models.PromptMessage, 0, len(promptResult.Messages))
+	for _, message := range promptResult.Messages {
+		if message == nil {
+			continue
+		}
+		content, ok := convertMCPContent(message.Content)
+		if !ok {
+			continue
+		}
+		messages = append(messages, models.PromptMessage{
+			Role:    string(message.Role),
+			Content: content,
+		})
+	}
+
+	return &models.PromptResult{
+		ServerURL:   serverConfig.URL,
+		Name:        promptName,
+		Description: promptResult.Description,
+		Messages:    messages,
+	}, nil
+}
+
+// convertMCPPrompt converts an MCP prompt definition to our generic format
+func convertMCPPrompt(prompt *mcp.Prompt) models.Prompt {
+	arguments := make([]models.PromptArgument, 0, len(prompt.Arguments))
+	for _, argument := range prompt.Arguments {
+		if argument == nil {
+			continue
+		}
+		arguments = append(arguments, models.PromptArgument{
+			Name:        argument.Name,
+			Title:       argument.Title,
+			Description: argument.Description,
+			Required:    argument.Required,
+		})
+	}
+
+	return models.Prompt{
+		Name:        prompt.Name,
+		Title:       prompt.Title,
+		Description: prompt.Description,
+		Arguments:   arguments,
+	}
+}
+
+// sessionConnectError is returned by withSession when no session could be established, as opposed to an
+// operation that failed on an established session
+type sessionConnectError struct {
+	err error
+}
+
+func (e *sessionConnectError) Error() string {
+	return e.err.Error()
+}
+
+func (e *sessionConnectError) Unwrap() error {
+	return e.err
+}
+
+// connectFailure returns the connection error when withSession could not establish a session, or nil
+func connectFailure(err error) error {
+	var connectErr *sessionConnectError
+	if errors.As(err, &connectErr) {
+		return connectErr.err
+	}
+	return nil
+}
+
+// withSession runs op on an initialized session for the server and identity, reusing a pooled session when possible.
+// When a pooled session fails at the transport level, for example because the server dropped it, it is evicted and
+// op is retried once on a fresh session. Errors establishing a session are returned as *sessionConnectError.
+func (c *SimpleMCPClient) withSession(ctx context.Context, serverConfig models.MCPServerConfig, identity *integrations.RequestIdentity, op func(*mcp.ClientSession, *mcp.InitializeResult) error) error {
+	for attempt := 0; ; attempt++ {
+		session, initResult, reused, release, err := c.acquireSession(ctx, serverConfig, identity)
+		if err != nil {
+			return &sessionConnectError{err: err}
+		}
+
+		err = op(session, initResult)
+		release(err)
+		if err == nil || !reused || attempt > 0 || !isSessionError(err) || ctx.Err() != nil {
+			return err
+		}
+		c.logger.Debug("Pooled MCP session failed, retrying on a new session", "error", err, "server_url", serverConfig.URL)
+	}
+}
+
+// acquireSession returns an initialized session for the server and identity, reusing a pooled session when possible,
+// and whether the session was reused from the pool. The returned release function must be called with the error of
+// the operation performed on the session, if any: one-shot sessions are closed, and pooled sessions that failed at
+// the transport level are evicted. Calling it again has no effect.
+func (c *SimpleMCPClient) acquireSession(ctx context.Context, serverConfig models.MCPServerConfig, identity *integrations.RequestIdentity) (*mcp.ClientSession, *mcp.InitializeResult, bool, func(error), error) {
+	if c.sessions == nil {
+		session, initResult, err := c.connectWithRetry(ctx, serverConfig, identity, false)
+		if err != nil {
+			return nil, nil, false, nil, err
+		}
+		var once sync.Once
+		return session, initResult, false, func(error) { once.Do(func() { _ = session.Close() }) }, nil
+	}
+
+	key := newSessionKey(serverConfig, bridgeNamespace(ctx, serverConfig), identity)
+	entry, ok := c.sessions.get(key)
+	// Sessions that have not been used successfully for a while are pinged first, so a restarted server costs a reconnect rather than a failed request
+	if ok && !c.sessions.verifiedWithin(entry, c.config.HealthCheckInterval) {
+		if err := entry.session.Ping(ctx, &mcp.PingParams{}); err != nil {
+			c.logger.Debug("Pooled MCP session is stale, reconnecting", "error", err, "server_url", serverConfig.URL)
+			c.sessions.evict(entry)
+			ok = false
+		}
+	}
+
+	reused := ok
+	if !ok {
+		session, initResult, err := c.connectWithRetry(ctx, serverConfig, identity, true)
+		if err != nil {
+			return nil, nil, false, nil, err
+		}
+		now := time.Now()
+		entry = c.sessions.put(&pooledSession{
+			key:          key,
+			session:      session,
+			initResult:   initResult,
+			serverConfig: serverConfig,
+			identity:     *identity,
+			lastUsed:     now,
+			lastVerified: now,
+		})
+	}
+
+	var once sync.Once
+	release := func(err error) {
+		once.Do(func() {
+			switch {
+			case isSessionError(err):
+				c.sessions.evict(entry)
+			case err == nil:
+				c.sessions.markVerified(entry)
+			}
+		})
+	}
+	return entry.session, entry.initResult, reused, release, nil
+}
+
+// connectWithRetry creates a session, retrying with exponential backoff while the server is unreachable
+func (c *SimpleMCPClient) connectWithRetry(ctx context.Context, serverConfig models.MCPServerConfig, identity *integrations.RequestIdentity, pooled bool) (*mcp.ClientSession, *mcp.InitializeResult, error) {
+	delay := c.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		session, initResult, err := c.createMCPSessionWithInit(ctx, serverConfig, identity, pooled)
+		if err == nil || attempt >= c.config.MaxRetries || !isRetryableConnectError(err) {
+			return session, initResult, err
+		}
+
+		c.logger.Debug("Retrying MCP connection", "error", err, "server_url", serverConfig.URL, "attempt", attempt+1, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return nil, nil, err
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, c.config.RetryMaxDelay)
+	}
+}
+
+// cachedResult returns a result cached for the server and identity, or nil
+func (c *SimpleMCPClient) cachedResult(category, namespace string, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig) interface{} {
+	if c.resultCache == nil {
+		return nil
+	}
+	value, found := c.resultCache.Get(constants.CacheMCPNamespace, identityKey(identity), category, resultCacheKey(namespace, serverConfig))
+	if !found {
+		return nil
+	}
+	return value
+}
+
+// cacheResult caches a result for the server and identity until the health prober refreshes it
+func (c *SimpleMCPClient) cacheResult(category, namespace string, identity *integrations.RequestIdentity, serverConfig models.MCPServerConfig, value interface{}) {
+	if c.resultCache == nil {
+		return
+	}
+	// The prober runs every interval; the extra interval keeps results available while a probe is in flight
+	if err := c.resultCache.Set(constants.CacheMCPNamespace, identityKey(identity), category, resultCacheKey(namespace, serverConfig), value, 2*c.config.HealthCheckInterval); err != nil {
+		c.logger.Warn("Failed to cache MCP result", "error", err, "server_url", serverConfig.URL, "category", category)
+	}
+}
+
+// resultCacheKey is the cache key of the results of a server; results of stdio servers are kept per namespace
+func resultCacheKey(namespace string, serverConfig models.MCPServerConfig) string {
+	if namespace == "" {
+		return serverConfig.URL
+	}
+	return namespace + "/" + serverConfig.URL
+}
+
+// bridgeNamespace returns the namespace of the request for stdio servers, which the bridge runs once per namespace,
+// and an empty string for servers shared by all namespaces
+func bridgeNamespace(ctx context.Context, serverConfig models.MCPServerConfig) string {
+	if TransportType(serverConfig.Transport) != TransportTypeStdio {
+		return ""
+	}
+	namespace, _ := ctx.Value(constants.NamespaceQueryParameterKey).(string)
+	return namespace
+}
+
+// createMCPSessionWithInit creates a fresh MCP session and returns both session and initialization result.
+// Pooled sessions outlive the request that opens them, so they are connected independently of its context and
+// only the handshake is bounded by the request and the transport timeout.
+func (c *SimpleMCPClient) createMCPSessionWithInit(ctx context.Context, serverConfig models.MCPServerConfig, identity *integrations.RequestIdentity, pooled bool) (*mcp.ClientSession, *mcp.InitializeResult, error) {
 	client := mcp.NewClient(
 		c.config.ToMCPImplementation(),
 		c.config.ToMCPClientOptions(),
@@ -311,26 +840,68 @@ func (c *SimpleMCPClient) createMCPSessionWithInit(ctx context.Context, serverCo
 	var err error
 
 	transportOptions := c.config.ToTransportOptions()
+	httpTransportOptions := transportOptions
+	if pooled {
+		// The SSE stream of a pooled session stays open between requests, so only waiting for a response is bounded
+		streamOptions := *transportOptions
+		streamOptions.ResponseHeaderTimeout = streamOptions.Timeout
+		streamOptions.Timeout = 0
+		httpTransportOptions = &streamOptions
+	}
 
 	switch transportType {
 	case TransportTypeSSE:
-		transport, err = c.transportFactory.CreateSSETransport(serverConfig.URL, identity, transportOptions)
+		transport, err = c.transportFactory.CreateSSETransport(serverConfig.URL, identity, httpTransportOptions)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to create SSE transport: %w", err)
 		}
 	case TransportTypeStreamableHTTP:
-		transport, err = c.transportFactory.CreateStreamableHTTPTransport(serverConfig.URL, identity, transportOptions)
+		transport, err = c.transportFactory.CreateStreamableHTTPTransport(serverConfig.URL, identity, httpTransportOptions)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to create StreamableHTTP transport: %w", err)
 		}
+	case TransportTypeWebSocket:
+		transport, err = c.transportFactory.CreateWebSocketTransport(serverConfig.URL, identity, transportOptions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create WebSocket transport: %w", err)
+		}
+	case TransportTypeStdio:
+		if c.bridge == nil {
+			return nil, nil, fmt.Errorf("stdio MCP servers are not supported by this client")
+		}
+		namespace := bridgeNamespace(ctx, serverConfig)
+		if namespace == "" {
+			return nil, nil, fmt.Errorf("a namespace is required to run stdio MCP server %q", serverConfig.Name)
+		}
+		transport, err = c.bridge.ClientTransport(ctx, namespace, serverConfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to start stdio MCP server: %w", err)
+		}
 	default:
 		return nil, nil, fmt.Errorf("unsupported transport type: %s", transportType)
 	}
 
-	session, err := client.Connect(ctx, transport, nil)
+	connectCtx := ctx
+	if pooled {
+		var cancel context.CancelFunc
+		connectCtx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+		handshakeTimer := time.AfterFunc(c.config.TransportTimeout, cancel)
+		stopOnRequestDone := context.AfterFunc(ctx, cancel)
+		defer func() {
+			handshakeTimer.Stop()
+			stopOnRequestDone()
+		}()
+	}
+
+	session, err := client.Connect(connectCtx, transport, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to connect to MCP server: %w", err)
 	}
+	if pooled && connectCtx.Err() != nil {
+		// The handshake completed as the request ended or timed out, which would close the session later
+		_ = session.Close()
+		return nil, nil, fmt.Errorf("failed to connect to MCP server: %w", context.Cause(connectCtx))
+	}
 
 	initResult := session.InitializeResult()
 
@@ -402,6 +973,42 @@ func (c *SimpleMCPClient) extractProtocolVersion(initResult *mcp.InitializeResul
 	return ""
 }
 
+// extractServerInfo returns the server name, version and protocol version with the usual fallbacks
+func (c *SimpleMCPClient) extractServerInfo(initResult *mcp.InitializeResult, configMapKeyName string) models.MCPServerInfo {
+	return models.MCPServerInfo{
+		Name:            c.extractServerName(initResult, configMapKeyName),
+		Version:         c.extractServerVersion(initResult),
+		ProtocolVersion: c.extractProtocolVersion(initResult),
+	}
+}
+
+// extractCapabilities reports which features the server advertised during initialization
+func (c *SimpleMCPClient) extractCapabilities(initResult *mcp.InitializeResult) *models.MCPServerCapabilities {
+	if initResult == nil || initResult.Capabilities == nil {
+		return &models.MCPServerCapabilities{}
+	}
+	return &models.MCPServerCapabilities{
+		Tools:       initResult.Capabilities.Tools != nil,
+		Resources:   initResult.Capabilities.Resources != nil,
+		Prompts:     initResult.Capabilities.Prompts != nil,
+		Logging:     initResult.Capabilities.Logging != nil,
+		Completions: initResult.Capabilities.Completions != nil,
+	}
+}
+
+// failureStatus returns the status to report for a failed MCP operation and, for servers that
+// require OAuth, where the authorization flow starts
+func failureStatus(err error) (string, *models.MCPAuthorization) {
+	var authErr *AuthRequiredError
+	if errors.As(err, &authErr) {
+		return StatusAuthRequired, &models.MCPAuthorization{
+			ResourceMetadataURL: authErr.ResourceMetadataURL,
+			Scope:               authErr.Scope,
+		}
+	}
+	return "error", nil
+}
+
 // mapMCPError maps MCP SDK errors to our error format
 func (c *SimpleMCPClient) mapMCPError(err error, serverURL string) error {
 	if err == nil {
@@ -412,6 +1019,11 @@ func (c *SimpleMCPClient) mapMCPError(err error, serverURL string) error {
 		return mcpErr
 	}
 
+	var authErr *AuthRequiredError
+	if errors.As(err, &authErr) {
+		return NewMCPErrorWithServer(StatusAuthRequired, "Authorization required", serverURL, http.StatusUnauthorized)
+	}
+
 	// Map common error types
 	errMsg := err.Error()
 	switch {
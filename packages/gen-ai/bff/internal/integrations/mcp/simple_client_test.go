@@ -0,0 +1,151 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimpleMCPClientResourcesAndPrompts(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "docs-server", Version: "1.0.0"}, nil)
+	server.AddResource(&mcp.Resource{URI: "docs://guide", Name: "guide", Description: "User guide", MIMEType: "text/markdown"},
+		func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return &mcp.ReadResourceResult{
+				Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: "# Guide"}},
+			}, nil
+		})
+	server.AddResourceTemplate(&mcp.ResourceTemplate{URITemplate: "docs://pages/{page}", Name: "page"},
+		func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return &mcp.ReadResourceResult{
+				Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Blob: []byte{0x01, 0x02}}},
+			}, nil
+		})
+	server.AddPrompt(&mcp.Prompt{
+		Name:        "explain",
+		Description: "Explain a topic",
+		Arguments:   []*mcp.PromptArgument{{Name: "topic", Required: true}, {Name: "audience"}},
+	}, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{
+			Description: "Explanation prompt",
+			Messages: []*mcp.PromptMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: fmt.Sprintf("Explain %s", req.Params.Arguments["topic"])}},
+			},
+		}, nil
+	})
+
+	httpServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	identity := &integrations.RequestIdentity{}
+	client := NewSimpleMCPClient(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	serverConfig := models.MCPServerConfig{Name: "docs", URL: httpServer.URL, Transport: string(TransportTypeStreamableHTTP)}
+
+	t.Run("should report advertised capabilities", func(t *testing.T) {
+		status, err := client.CheckConnectionStatus(ctx, identity, serverConfig)
+		require.NoError(t, err)
+		require.NotNil(t, status.ServerInfo.Capabilities)
+		assert.Equal(t, models.MCPServerCapabilities{Resources: true, Prompts: true, Logging: true}, *status.ServerInfo.Capabilities)
+	})
+
+	t.Run("should list resources and resource templates", func(t *testing.T) {
+		status, err := client.ListResources(ctx, identity, serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "success", status.Status)
+		assert.Equal(t, "docs-server", status.ServerInfo.Name)
+		require.NotNil(t, status.ResourcesCount)
+		assert.Equal(t, 1, *status.ResourcesCount)
+		assert.Equal(t, []models.Resource{{URI: "docs://guide", Name: "guide", Description: "User guide", MimeType: "text/markdown"}}, status.Resources)
+		assert.Equal(t, []models.ResourceTemplate{{URITemplate: "docs://pages/{page}", Name: "page"}}, status.ResourceTemplates)
+	})
+
+	t.Run("should read text and binary resources", func(t *testing.T) {
+		result, err := client.ReadResource(ctx, identity, serverConfig, "docs://guide")
+		require.NoError(t, err)
+		assert.Equal(t, []models.ResourceContents{{URI: "docs://guide", MimeType: "text/markdown", Text: "# Guide"}}, result.Contents)
+
+		result, err = client.ReadResource(ctx, identity, serverConfig, "docs://pages/intro")
+		require.NoError(t, err)
+		require.Len(t, result.Contents, 1)
+		assert.Equal(t, []byte{0x01, 0x02}, result.Contents[0].Blob)
+	})
+
+	t.Run("should return not found for unknown resources", func(t *testing.T) {
+		_, err := client.ReadResource(ctx, identity, serverConfig, "docs://missing")
+		var mcpErr *MCPError
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Equal(t, ErrCodeResourceNotFound, mcpErr.Code)
+		assert.Equal(t, 404, mcpErr.StatusCode)
+	})
+
+	t.Run("should list prompts with their arguments", func(t *testing.T) {
+		status, err := client.ListPrompts(ctx, identity, serverConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "success", status.Status)
+		require.Len(t, status.Prompts, 1)
+		assert.Equal(t, "explain", status.Prompts[0].Name)
+		assert.Equal(t, []models.PromptArgument{{Name: "topic", Required: true}, {Name: "audience"}}, status.Prompts[0].Arguments)
+	})
+
+	t.Run("should render prompts", func(t *testing.T) {
+		result, err := client.GetPrompt(ctx, identity, serverConfig, "explain", map[string]string{"topic": "vector stores"})
+		require.NoError(t, err)
+		assert.Equal(t, "Explanation prompt", result.Description)
+		assert.Equal(t, []models.PromptMessage{{Role: "user", Content: models.MCPContent{Type: "text", Text: "Explain vector stores"}}}, result.Messages)
+	})
+
+	t.Run("should reject prompts with missing required arguments", func(t *testing.T) {
+		_, err := client.GetPrompt(ctx, identity, serverConfig, "explain", nil)
+		var mcpErr *MCPError
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Equal(t, ErrCodeInvalidArguments, mcpErr.Code)
+		assert.Contains(t, mcpErr.Message, `"topic"`)
+	})
+
+	t.Run("should return not found for unknown prompts", func(t *testing.T) {
+		_, err := client.GetPrompt(ctx, identity, serverConfig, "summarize", nil)
+		var mcpErr *MCPError
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Equal(t, ErrCodePromptNotFound, mcpErr.Code)
+	})
+
+	t.Run("should return empty lists for servers without the capability", func(t *testing.T) {
+		toolsOnly := mcp.NewServer(&mcp.Implementation{Name: "tools-only", Version: "1.0.0"}, nil)
+		mcp.AddTool(toolsOnly, &mcp.Tool{Name: "noop"}, func(ctx context.Context, req *mcp.CallToolRequest, in struct{}) (*mcp.CallToolResult, struct{}, error) {
+			return nil, struct{}{}, nil
+		})
+		toolsOnlyServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return toolsOnly }, nil))
+		defer toolsOnlyServer.Close()
+		toolsOnlyConfig := models.MCPServerConfig{Name: "tools-only", URL: toolsOnlyServer.URL, Transport: string(TransportTypeStreamableHTTP)}
+
+		resources, err := client.ListResources(ctx, identity, toolsOnlyConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "success", resources.Status)
+		assert.Empty(t, resources.Resources)
+		assert.NotNil(t, resources.ResourceTemplates)
+
+		prompts, err := client.ListPrompts(ctx, identity, toolsOnlyConfig)
+		require.NoError(t, err)
+		assert.Equal(t, "success", prompts.Status)
+		assert.Empty(t, prompts.Prompts)
+	})
+
+	t.Run("should report connection failures in the status", func(t *testing.T) {
+		unreachable := models.MCPServerConfig{Name: "down", URL: "http://127.0.0.1:1/mcp"}
+		status, err := client.ListResources(ctx, identity, unreachable)
+		require.NoError(t, err)
+		assert.Equal(t, "error", status.Status)
+		require.NotNil(t, status.ErrorDetails)
+		assert.Empty(t, status.Resources)
+	})
+}
@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidateToolArguments checks tool arguments against an input schema in the format produced by
+// convertMCPInputSchema (type, properties, required and enum). It returns one message per violation;
+// keywords outside that subset are not checked and are left to the MCP server.
+func ValidateToolArguments(schema map[string]interface{}, arguments map[string]interface{}) []string {
+	var args interface{} = arguments
+	if arguments == nil {
+		args = map[string]interface{}{}
+	}
+	return validateSchemaValue(schema, args, "arguments")
+}
+
+func validateSchemaValue(schema map[string]interface{}, value interface{}, path string) []string {
+	if len(schema) == 0 {
+		return nil
+	}
+
+	if schemaType, ok := schema["type"].(string); ok && schemaType != "" && !matchesSchemaType(schemaType, value) {
+		return []string{fmt.Sprintf("%s must be of type %s, got %s", path, schemaType, jsonTypeName(value))}
+	}
+
+	var violations []string
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 && !containsEnumValue(enum, value) {
+		violations = append(violations, fmt.Sprintf("%s must be one of %v", path, enum))
+	}
+
+	object, isObject := value.(map[string]interface{})
+	if !isObject {
+		return violations
+	}
+
+	for _, name := range requiredProperties(schema["required"]) {
+		if _, present := object[name]; !present {
+			violations = append(violations, fmt.Sprintf("%s is missing required property %q", path, name))
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertyValue, present := object[name]
+		propertySchema, ok := properties[name].(map[string]interface{})
+		if !present || !ok {
+			continue
+		}
+		violations = append(violations, validateSchemaValue(propertySchema, propertyValue, path+"."+name)...)
+	}
+
+	return violations
+}
+
+func matchesSchemaType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	default:
+		return strings.TrimPrefix(fmt.Sprintf("%T", value), "*")
+	}
+}
+
+// requiredProperties accepts both the []string produced by convertMCPInputSchema and decoded JSON arrays
+func requiredProperties(required interface{}) []string {
+	switch names := required.(type) {
+	case []string:
+		return names
+	case []interface{}:
+		result := make([]string, 0, len(names))
+		for _, name := range names {
+			if s, ok := name.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+func containsEnumValue(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if reflect.DeepEqual(normalizeJSONNumber(allowed), normalizeJSONNumber(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeJSONNumber converts integer enum values from schemas to float64, the type of decoded JSON numbers
+func normalizeJSONNumber(value interface{}) interface{} {
+	switch n := value.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	default:
+		return value
+	}
+}
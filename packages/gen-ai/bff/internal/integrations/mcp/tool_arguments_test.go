@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateToolArguments(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{"type": "string"},
+			"count": map[string]interface{}{"type": "integer"},
+			"units": map[string]interface{}{"type": "string", "enum": []interface{}{"metric", "imperial"}},
+			"filter": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"tags": map[string]interface{}{"type": "array"},
+				},
+				"required": []interface{}{"tags"},
+			},
+		},
+		"required": []string{"query"},
+	}
+
+	testCases := []struct {
+		name       string
+		arguments  map[string]interface{}
+		violations []string
+	}{
+		{
+			name:      "valid arguments",
+			arguments: map[string]interface{}{"query": "weather", "count": float64(3), "units": "metric", "filter": map[string]interface{}{"tags": []interface{}{"a"}}},
+		},
+		{
+			name:       "missing required property",
+			arguments:  map[string]interface{}{"count": float64(3)},
+			violations: []string{`arguments is missing required property "query"`},
+		},
+		{
+			name:       "nil arguments with required properties",
+			arguments:  nil,
+			violations: []string{`arguments is missing required property "query"`},
+		},
+		{
+			name:       "wrong types",
+			arguments:  map[string]interface{}{"query": 42.0, "count": 1.5},
+			violations: []string{"arguments.count must be of type integer, got number", "arguments.query must be of type string, got number"},
+		},
+		{
+			name:       "value outside enum",
+			arguments:  map[string]interface{}{"query": "weather", "units": "kelvin"},
+			violations: []string{"arguments.units must be one of [metric imperial]"},
+		},
+		{
+			name:       "nested object",
+			arguments:  map[string]interface{}{"query": "weather", "filter": map[string]interface{}{}},
+			violations: []string{`arguments.filter is missing required property "tags"`},
+		},
+		{
+			name:      "unknown properties are left to the server",
+			arguments: map[string]interface{}{"query": "weather", "extra": true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.violations, ValidateToolArguments(schema, tc.arguments))
+		})
+	}
+
+	t.Run("empty schema accepts anything", func(t *testing.T) {
+		assert.Empty(t, ValidateToolArguments(map[string]interface{}{}, map[string]interface{}{"any": "value"}))
+	})
+}
+
+func TestSimpleMCPClientCallTool(t *testing.T) {
+	type weatherInput struct {
+		City string `json:"city"`
+	}
+	type weatherOutput struct {
+		City        string  `json:"city"`
+		Temperature float64 `json:"temperature"`
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "weather-server", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "get_weather", Description: "Get the weather for a city"},
+		func(ctx context.Context, req *mcp.CallToolRequest, in weatherInput) (*mcp.CallToolResult, weatherOutput, error) {
+			if in.City == "Atlantis" {
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{&mcp.TextContent{Text: "unknown city"}},
+				}, weatherOutput{}, nil
+			}
+			return nil, weatherOutput{City: in.City, Temperature: 21.5}, nil
+		})
+
+	httpServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	defer httpServer.Close()
+
+	ctx := context.Background()
+	client := NewSimpleMCPClient(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	serverConfig := models.MCPServerConfig{Name: "weather", URL: httpServer.URL, Transport: string(TransportTypeStreamableHTTP)}
+
+	t.Run("should return structured content and timing", func(t *testing.T) {
+		result, err := client.CallTool(ctx, &integrations.RequestIdentity{}, serverConfig, "get_weather", map[string]interface{}{"city": "Boston"})
+		require.NoError(t, err)
+		assert.Equal(t, "success", result.Status)
+		assert.False(t, result.IsError)
+		assert.Equal(t, map[string]interface{}{"city": "Boston", "temperature": 21.5}, result.StructuredContent)
+		assert.NotNil(t, result.Content)
+		assert.GreaterOrEqual(t, result.Timing.TotalMs, result.Timing.ConnectMs+result.Timing.CallMs)
+	})
+
+	t.Run("should report errors returned by the tool", func(t *testing.T) {
+		result, err := client.CallTool(ctx, &integrations.RequestIdentity{}, serverConfig, "get_weather", map[string]interface{}{"city": "Atlantis"})
+		require.NoError(t, err)
+		assert.Equal(t, "error", result.Status)
+		assert.True(t, result.IsError)
+		require.Len(t, result.Content, 1)
+		assert.Equal(t, "unknown city", result.Content[0].Text)
+	})
+
+	t.Run("should reject arguments that do not match the input schema", func(t *testing.T) {
+		_, err := client.CallTool(ctx, &integrations.RequestIdentity{}, serverConfig, "get_weather", map[string]interface{}{"city": 42})
+		var mcpErr *MCPError
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Equal(t, ErrCodeInvalidArguments, mcpErr.Code)
+		assert.Equal(t, 400, mcpErr.StatusCode)
+	})
+
+	t.Run("should reject unknown tools", func(t *testing.T) {
+		_, err := client.CallTool(ctx, &integrations.RequestIdentity{}, serverConfig, "get_forecast", nil)
+		var mcpErr *MCPError
+		require.ErrorAs(t, err, &mcpErr)
+		assert.Equal(t, ErrCodeToolNotFound, mcpErr.Code)
+	})
+
+	t.Run("should report connection failures in the result", func(t *testing.T) {
+		unreachable := models.MCPServerConfig{Name: "down", URL: "http://127.0.0.1:1/mcp"}
+		result, err := client.CallTool(ctx, &integrations.RequestIdentity{}, unreachable, "get_weather", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "error", result.Status)
+		require.NotNil(t, result.ErrorDetails)
+		assert.Empty(t, result.Content)
+	})
+}
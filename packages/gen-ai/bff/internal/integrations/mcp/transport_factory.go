@@ -18,16 +18,18 @@ import (
 type TransportFactory interface {
 	CreateSSETransport(serverURL string, identity *integrations.RequestIdentity, opts *TransportOptions) (mcp.Transport, error)
 	CreateStreamableHTTPTransport(serverURL string, identity *integrations.RequestIdentity, opts *TransportOptions) (mcp.Transport, error)
+	CreateWebSocketTransport(serverURL string, identity *integrations.RequestIdentity, opts *TransportOptions) (mcp.Transport, error)
 }
 
 // TransportOptions contains configuration for transport creation
 type TransportOptions struct {
-	Timeout            time.Duration
-	KeepAlive          time.Duration
-	InsecureSkipVerify bool
-	MaxIdleConns       int
-	IdleConnTimeout    time.Duration
-	RootCAs            *x509.CertPool
+	Timeout               time.Duration // Bounds whole HTTP requests; zero for long-lived streams
+	ResponseHeaderTimeout time.Duration // Bounds waiting for response headers, also for long-lived streams
+	KeepAlive             time.Duration
+	InsecureSkipVerify    bool
+	MaxIdleConns          int
+	IdleConnTimeout       time.Duration
+	RootCAs               *x509.CertPool
 }
 
 // DefaultTransportOptions provides sensible defaults for transport configuration
@@ -94,6 +96,31 @@ func (f *MCPTransportFactory) CreateStreamableHTTPTransport(
 	}, nil
 }
 
+func (f *MCPTransportFactory) CreateWebSocketTransport(
+	serverURL string,
+	identity *integrations.RequestIdentity,
+	opts *TransportOptions,
+) (mcp.Transport, error) {
+	if opts == nil {
+		opts = f.defaultOptions
+	}
+
+	transport := &WebSocketClientTransport{
+		Endpoint: serverURL,
+		Timeout:  opts.Timeout,
+		TLSConfig: &tls.Config{
+			InsecureSkipVerify: opts.InsecureSkipVerify,
+			RootCAs:            opts.RootCAs,
+		},
+	}
+	if identity != nil && identity.MCPToken != "" {
+		transport.Header = http.Header{"Authorization": []string{"Bearer " + identity.MCPToken}}
+	}
+
+	slog.Debug("MCP WebSocket transport created", "url", serverURL)
+	return transport, nil
+}
+
 // AuthenticatedTransport wraps an HTTP transport to add authentication headers
 type AuthenticatedTransport struct {
 	Base  http.RoundTripper
@@ -145,22 +172,37 @@ func (t *MCPErrorHandlingTransport) handleErrorResponse(resp *http.Response) (*h
 	if err != nil {
 		return nil, fmt.Errorf("HTTP %d: failed to read error response", resp.StatusCode)
 	}
+	message := errorResponseMessage(body)
+
+	// MCP servers protected by OAuth point to their protected resource metadata in the challenge
+	if resp.StatusCode == http.StatusUnauthorized {
+		if challenge, ok := parseBearerChallenge(resp.Header.Get("WWW-Authenticate")); ok && challenge["resource_metadata"] != "" {
+			return nil, &AuthRequiredError{
+				ResourceMetadataURL: challenge["resource_metadata"],
+				Scope:               challenge["scope"],
+				Message:             message,
+			}
+		}
+	}
 
+	return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, message)
+}
+
+// errorResponseMessage extracts the error message from a JSON error body, falling back to the raw body
+func errorResponseMessage(body []byte) string {
 	var jsonError map[string]interface{}
 	if err := json.Unmarshal(body, &jsonError); err == nil {
 		if errorMsg, ok := jsonError["error"].(string); ok {
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, errorMsg)
+			return errorMsg
 		}
 		if message, ok := jsonError["message"].(string); ok {
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, message)
+			return message
 		}
 		if detail, ok := jsonError["detail"].(string); ok {
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, detail)
+			return detail
 		}
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
 	}
-
-	return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	return string(body)
 }
 
 // TransportType represents the different types of transports available
@@ -171,11 +213,15 @@ const (
 	TransportTypeSSE TransportType = "sse"
 	// TransportTypeStreamableHTTP represents StreamableHTTP transport
 	TransportTypeStreamableHTTP TransportType = "streamable-http"
+	// TransportTypeStdio represents a server process run by the BFF and reached over stdin/stdout
+	TransportTypeStdio TransportType = "stdio"
+	// TransportTypeWebSocket represents WebSocket transport
+	TransportTypeWebSocket TransportType = "websocket"
 )
 
 func ValidateTransportType(transportType TransportType) error {
 	switch transportType {
-	case TransportTypeSSE, TransportTypeStreamableHTTP:
+	case TransportTypeSSE, TransportTypeStreamableHTTP, TransportTypeStdio, TransportTypeWebSocket:
 		return nil
 	default:
 		return fmt.Errorf("unknown transport type: %s", transportType)
@@ -189,6 +235,10 @@ func ValidateAndNormalizeTransportType(transportType string, logger *slog.Logger
 		return TransportTypeSSE
 	case string(TransportTypeStreamableHTTP):
 		return TransportTypeStreamableHTTP
+	case string(TransportTypeStdio):
+		return TransportTypeStdio
+	case string(TransportTypeWebSocket):
+		return TransportTypeWebSocket
 	default:
 		logger.Info("Invalid or missing transport type, falling back to streamable-http",
 			"server", serverURL, "type", transportType)
@@ -196,6 +246,11 @@ func ValidateAndNormalizeTransportType(transportType string, logger *slog.Logger
 	}
 }
 
+// RequiresBridge reports whether servers with the transport type are only reachable by LlamaStack through the BFF bridge
+func RequiresBridge(transportType string) bool {
+	return transportType == string(TransportTypeStdio) || transportType == string(TransportTypeWebSocket)
+}
+
 // createMCPHTTPClient creates a configured HTTP client with authentication and MCP error handling
 func (f *MCPTransportFactory) createMCPHTTPClient(opts *TransportOptions, identity *integrations.RequestIdentity) *http.Client {
 	tlsConfig := &tls.Config{
@@ -206,9 +261,10 @@ func (f *MCPTransportFactory) createMCPHTTPClient(opts *TransportOptions, identi
 	}
 
 	baseTransport := &http.Transport{
-		TLSClientConfig: tlsConfig,
-		MaxIdleConns:    opts.MaxIdleConns,
-		IdleConnTimeout: opts.IdleConnTimeout,
+		TLSClientConfig:       tlsConfig,
+		MaxIdleConns:          opts.MaxIdleConns,
+		IdleConnTimeout:       opts.IdleConnTimeout,
+		ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
 	}
 
 	var transport http.RoundTripper = baseTransport
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/net/websocket"
+)
+
+// mcpWebSocketSubprotocol is the WebSocket subprotocol MCP servers expect
+const mcpWebSocketSubprotocol = "mcp"
+
+// WebSocketClientTransport connects to MCP servers over WebSocket, with one JSON-RPC message per text frame
+type WebSocketClientTransport struct {
+	Endpoint  string
+	Header    http.Header
+	TLSConfig *tls.Config
+	Timeout   time.Duration // Bounds the opening handshake
+}
+
+// Connect dials the server and performs the WebSocket handshake
+func (t *WebSocketClientTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	origin, err := webSocketOrigin(t.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := websocket.NewConfig(t.Endpoint, origin)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WebSocket endpoint: %w", err)
+	}
+	config.Protocol = []string{mcpWebSocketSubprotocol}
+	config.TlsConfig = t.TLSConfig
+	config.Dialer = &net.Dialer{Timeout: t.Timeout}
+	for key, values := range t.Header {
+		for _, value := range values {
+			config.Header.Add(key, value)
+		}
+	}
+
+	conn, err := config.DialContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to WebSocket endpoint: %w", err)
+	}
+	return NewWebSocketConnection(conn), nil
+}
+
+// webSocketOrigin derives the Origin header of the handshake from the endpoint
+func webSocketOrigin(endpoint string) (string, error) {
+	location, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid WebSocket endpoint: %w", err)
+	}
+
+	switch location.Scheme {
+	case "ws":
+		return "http://" + location.Host, nil
+	case "wss":
+		return "https://" + location.Host, nil
+	default:
+		return "", fmt.Errorf("invalid WebSocket endpoint: scheme must be ws or wss, got %q", location.Scheme)
+	}
+}
+
+// webSocketConnection carries JSON-RPC messages over an open WebSocket connection
+type webSocketConnection struct {
+	conn      *websocket.Conn
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewWebSocketConnection wraps an open WebSocket connection, client or server side, as an MCP connection
+func NewWebSocketConnection(conn *websocket.Conn) mcp.Connection {
+	return &webSocketConnection{conn: conn}
+}
+
+func (c *webSocketConnection) Read(ctx context.Context) (jsonrpc.Message, error) {
+	var data []byte
+	if err := websocket.Message.Receive(c.conn, &data); err != nil {
+		return nil, err
+	}
+	return jsonrpc.DecodeMessage(data)
+}
+
+func (c *webSocketConnection) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	// Text frames, since JSON-RPC messages are UTF-8 JSON
+	return websocket.Message.Send(c.conn, string(data))
+}
+
+func (c *webSocketConnection) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.conn.Close()
+	})
+	return c.closeErr
+}
+
+func (c *webSocketConnection) SessionID() string {
+	return ""
+}
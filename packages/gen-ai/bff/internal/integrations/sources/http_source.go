@@ -0,0 +1,76 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+)
+
+// HTTPSource is a document served over HTTP(S), such as an internal web page
+type HTTPSource struct {
+	url    *url.URL
+	client *http.Client
+}
+
+// NewHTTPSource creates a source for an http:// or https:// URL. URLs with credentials are refused,
+// since the URI of a source is recorded with the ingested document.
+func NewHTTPSource(rawURL string, client *http.Client) (*HTTPSource, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSource, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("%w: unsupported URL scheme %q", ErrInvalidSource, parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("%w: URL has no host", ErrInvalidSource)
+	}
+	if parsed.User != nil {
+		return nil, fmt.Errorf("%w: credentials in URLs are not supported", ErrInvalidSource)
+	}
+	parsed.Fragment = ""
+
+	if client == nil {
+		client = NewHTTPClient(false, nil, AddressPolicy{})
+	}
+	return &HTTPSource{url: parsed, client: client}, nil
+}
+
+// URI returns the URL of the document
+func (s *HTTPSource) URI() string {
+	return s.url.String()
+}
+
+// Filename returns the last element of the URL path, or the host for URLs without a path
+func (s *HTTPSource) Filename() string {
+	return filenameFromPath(s.url.Path, s.url.Hostname())
+}
+
+// Open requests the document
+func (s *HTTPSource) Open(ctx context.Context) (*llamastack.SourceDocument, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch document: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch document: server returned status %d", resp.StatusCode)
+	}
+	if err := checkContentLength(s.Filename(), resp.ContentLength); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return &llamastack.SourceDocument{
+		Body:        newLimitedBody(resp.Body, s.Filename()),
+		ContentType: mediaType(resp.Header.Get("Content-Type")),
+	}, nil
+}
@@ -0,0 +1,246 @@
+package sources
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty request body, which is signed for GET requests
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Connection holds the endpoint and credentials of S3-compatible storage, as kept in a data connection
+type S3Connection struct {
+	Endpoint        string // URL of the storage, such as https://s3.us-east-1.amazonaws.com or http://minio:9000
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Bucket          string // Used for s3:// URIs without a bucket
+}
+
+// S3Source is an object in S3-compatible storage. Objects are requested with path-style URLs, which every
+// S3-compatible storage supports, and signed with AWS Signature Version 4.
+type S3Source struct {
+	connection S3Connection
+	endpoint   *url.URL
+	bucket     string
+	key        string
+	client     *http.Client
+	now        func() time.Time
+}
+
+// NewS3Source creates a source for an s3://<bucket>/<key> URI. The bucket of the connection is used
+// for URIs of the form s3:///<key>.
+func NewS3Source(connection S3Connection, uri string, client *http.Client) (*S3Source, error) {
+	bucket, key, err := ParseS3URI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if bucket == "" {
+		bucket = connection.Bucket
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("%w: the URI has no bucket and the data connection has no default bucket", ErrInvalidSource)
+	}
+
+	endpoint := connection.Endpoint
+	if endpoint == "" {
+		return nil, fmt.Errorf("%w: the data connection has no endpoint", ErrInvalidSource)
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil || endpointURL.Host == "" {
+		return nil, fmt.Errorf("%w: invalid data connection endpoint %q", ErrInvalidSource, connection.Endpoint)
+	}
+	if connection.Region == "" {
+		connection.Region = constants.DataConnectionDefaultRegion
+	}
+
+	if client == nil {
+		client = NewHTTPClient(false, nil, AddressPolicy{})
+	}
+	return &S3Source{
+		connection: connection,
+		endpoint:   endpointURL,
+		bucket:     bucket,
+		key:        key,
+		client:     client,
+		now:        time.Now,
+	}, nil
+}
+
+// ParseS3URI splits an s3://<bucket>/<key> URI. The bucket is empty for URIs of the form s3:///<key>.
+func ParseS3URI(uri string) (bucket, key string, err error) {
+	rest, ok := strings.CutPrefix(uri, "s3://")
+	if !ok {
+		return "", "", fmt.Errorf("%w: S3 URIs start with s3://", ErrInvalidSource)
+	}
+	bucket, key, _ = strings.Cut(rest, "/")
+	if key == "" || strings.HasSuffix(key, "/") {
+		return "", "", fmt.Errorf("%w: the S3 URI does not name an object", ErrInvalidSource)
+	}
+	return bucket, key, nil
+}
+
+// URI returns the s3://<bucket>/<key> URI of the object
+func (s *S3Source) URI() string {
+	return "s3://" + s.bucket + "/" + s.key
+}
+
+// Filename returns the last element of the object key
+func (s *S3Source) Filename() string {
+	return filenameFromPath(s.key, s.bucket)
+}
+
+// Open requests the object
+func (s *S3Source) Open(ctx context.Context) (*llamastack.SourceDocument, error) {
+	basePath := strings.TrimSuffix(s.endpoint.Path, "/")
+	objectURL := *s.endpoint
+	objectURL.Path = basePath + "/" + s.bucket + "/" + s.key
+	objectURL.RawPath = basePath + "/" + s3URIEncode(s.bucket, false) + "/" + s3URIEncode(s.key, true)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, objectURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	signS3Request(req, s.connection, s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch object: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, s3ResponseError(resp)
+	}
+	if err := checkContentLength(s.Filename(), resp.ContentLength); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return &llamastack.SourceDocument{
+		Body:        newLimitedBody(resp.Body, s.Filename()),
+		ContentType: mediaType(resp.Header.Get("Content-Type")),
+	}, nil
+}
+
+// s3ResponseError describes an error response of S3-compatible storage, using its XML error when present
+func s3ResponseError(resp *http.Response) error {
+	var s3Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if xml.Unmarshal(body, &s3Error) == nil && s3Error.Code != "" {
+		return fmt.Errorf("failed to fetch object: %s: %s", s3Error.Code, s3Error.Message)
+	}
+	return fmt.Errorf("failed to fetch object: storage returned status %d", resp.StatusCode)
+}
+
+// signS3Request adds an AWS Signature Version 4 to a request without a body
+func signS3Request(req *http.Request, connection S3Connection, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "range" || name == "content-type" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+
+	scope := date + "/" + connection.Region + "/s3/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+connection.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, connection.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		connection.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes query parameters sorted by name as Signature Version 4 expects
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs []string
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, s3URIEncode(name, false)+"="+s3URIEncode(value, false))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// s3URIEncode percent-encodes everything but unreserved characters, and slashes when keepSlash is set
+func s3URIEncode(value string, keepSlash bool) string {
+	var encoded strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '.', b == '_', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && keepSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
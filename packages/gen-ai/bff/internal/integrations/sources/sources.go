@@ -0,0 +1,227 @@
+// Package sources provides documents that are ingested into vector stores from remote storage:
+// objects in S3-compatible storage and documents served over HTTP(S).
+// The sources implement llamastack.DocumentSource, so their content is streamed to LlamaStack Files.
+package sources
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+)
+
+// ErrInvalidSource is returned for source URIs that cannot be ingested
+var ErrInvalidSource = errors.New("invalid document source")
+
+// ErrForbiddenAddress is returned when a document source resolves to an address documents may not be fetched from
+var ErrForbiddenAddress = errors.New("document source address is not allowed")
+
+// AddressPolicy decides which addresses documents may be fetched from, so that users cannot make the BFF read
+// itself or the services around it. Loopback, link-local (including cloud metadata endpoints), unspecified and
+// multicast addresses are always refused. Private addresses are refused unless they are in one of the AllowedNetworks.
+type AddressPolicy struct {
+	AllowedNetworks []netip.Prefix // Private networks documents may be fetched from
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, which clusters commonly use for internal addresses
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// Check returns ErrForbiddenAddress when documents may not be fetched from the address
+func (p AddressPolicy) Check(addr netip.Addr) error {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() || addr.IsUnspecified() || (addr.Is4() && addr.As4()[0] == 0) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addr)
+	}
+	if !addr.IsPrivate() && !sharedAddressSpace.Contains(addr) {
+		return nil
+	}
+	for _, network := range p.AllowedNetworks {
+		if network.Contains(addr) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is a private address", ErrForbiddenAddress, addr)
+}
+
+// dialContext resolves the host of every connection and only dials the addresses the policy allows, so the check
+// also applies to the hosts of redirects and cannot be bypassed by a host that resolves differently later.
+// Connections to the proxies of the environment are not checked, since the proxy checks the document host instead.
+func (p AddressPolicy) dialContext(dialer *net.Dialer, proxies *sync.Map) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if _, ok := proxies.Load(address); ok {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		addrs, err := p.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var lastErr error
+		for _, addr := range addrs {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+}
+
+// resolve returns the addresses of a host that the policy allows, or the reason none is allowed
+func (p AddressPolicy) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]netip.Addr, 0, len(addrs))
+	var checkErr error
+	for _, addr := range addrs {
+		if err := p.Check(addr); err != nil {
+			checkErr = err
+			continue
+		}
+		allowed = append(allowed, addr)
+	}
+	if len(allowed) == 0 {
+		if checkErr == nil {
+			checkErr = fmt.Errorf("no addresses found for %s", host)
+		}
+		return nil, checkErr
+	}
+	return allowed, nil
+}
+
+// proxy uses the proxy of the environment, after checking the host of the document the proxy is asked to fetch
+func (p AddressPolicy) proxy(proxies *sync.Map) func(req *http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := http.ProxyFromEnvironment(req)
+		if err != nil || proxyURL == nil {
+			return proxyURL, err
+		}
+		if _, err := p.resolve(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+		proxies.Store(proxyAddress(proxyURL), struct{}{})
+		return proxyURL, nil
+	}
+}
+
+// proxyAddress returns the host:port the transport dials for a proxy URL
+func proxyAddress(proxyURL *url.URL) string {
+	port := proxyURL.Port()
+	if port == "" {
+		switch proxyURL.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
+
+// NewHTTPClient creates the client used to fetch documents, trusting the given root CAs when set. Only hosts the
+// policy allows are connected to, and at most constants.IngestionMaxRedirects redirects are followed.
+func NewHTTPClient(insecureSkipVerify bool, rootCAs *x509.CertPool, policy AddressPolicy) *http.Client {
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+	if rootCAs != nil {
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	// The body is streamed for as long as the ingestion job allows, so only the response headers are bounded
+	proxies := &sync.Map{}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 policy.proxy(proxies),
+			DialContext:           policy.dialContext(&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}, proxies),
+			TLSClientConfig:       tlsConfig,
+			ResponseHeaderTimeout: responseHeaderTimeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > constants.IngestionMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", constants.IngestionMaxRedirects)
+			}
+			return nil
+		},
+	}
+}
+
+// responseHeaderTimeout bounds how long a source can take to start sending a document
+const responseHeaderTimeout = time.Minute
+
+// limitedBody fails once a document exceeds the maximum file size, so a truncated document is never ingested
+type limitedBody struct {
+	io.ReadCloser
+	name      string
+	remaining int64
+}
+
+func newLimitedBody(body io.ReadCloser, name string) *limitedBody {
+	return &limitedBody{ReadCloser: body, name: name, remaining: constants.IngestionMaxFileBytes}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, b.tooLarge()
+	}
+	// Read one byte more than allowed to detect documents that are too large
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return 0, b.tooLarge()
+	}
+	return n, err
+}
+
+func (b *limitedBody) tooLarge() error {
+	return fmt.Errorf("%s exceeds the maximum file size of %d bytes", b.name, constants.IngestionMaxFileBytes)
+}
+
+// checkContentLength rejects documents whose announced size exceeds the maximum file size before reading them
+func checkContentLength(name string, contentLength int64) error {
+	if contentLength > constants.IngestionMaxFileBytes {
+		return fmt.Errorf("%s exceeds the maximum file size of %d bytes", name, constants.IngestionMaxFileBytes)
+	}
+	return nil
+}
+
+// mediaType returns the media type of a Content-Type header without its parameters
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// filenameFromPath returns the last element of a path, or the fallback for paths without one
+func filenameFromPath(p, fallback string) string {
+	name := path.Base(p)
+	if name == "." || name == "/" || name == "" {
+		return fallback
+	}
+	return name
+}
@@ -3,18 +3,36 @@ package models
 // ConnectionStatus represents the status of an MCP server connection
 type ConnectionStatus struct {
 	ServerURL   string `json:"server_url"`
-	Status      string `json:"status"`       // "connected", "error"
+	Status      string `json:"status"`       // "connected", "error", "auth_required"
 	Message     string `json:"message"`      // Clean error message or success message
 	LastChecked int64  `json:"last_checked"` // Unix timestamp
 
 	ServerInfo struct {
-		Name            string `json:"name"`             // MCP server name OR ConfigMap key fallback
-		Version         string `json:"version"`          // MCP version OR "N/A"
-		ProtocolVersion string `json:"protocol_version"` // MCP protocol version OR empty
+		Name            string                 `json:"name"`                   // MCP server name OR ConfigMap key fallback
+		Version         string                 `json:"version"`                // MCP version OR "N/A"
+		ProtocolVersion string                 `json:"protocol_version"`       // MCP protocol version OR empty
+		Capabilities    *MCPServerCapabilities `json:"capabilities,omitempty"` // Only present on successful connection
 	} `json:"server_info"`
 
-	ErrorDetails       *ErrorDetails `json:"error_details,omitempty"`         // Only present when status is "error"
-	PingResponseTimeMs *int64        `json:"ping_response_time_ms,omitempty"` // Only present on successful connection
+	ErrorDetails       *ErrorDetails     `json:"error_details,omitempty"`         // Only present when status is "error" or "auth_required"
+	PingResponseTimeMs *int64            `json:"ping_response_time_ms,omitempty"` // Only present on successful connection
+	Authorization      *MCPAuthorization `json:"authorization,omitempty"`         // Only present when status is "auth_required"
+}
+
+// MCPServerCapabilities lists the features an MCP server advertises in its initialize result
+type MCPServerCapabilities struct {
+	Tools       bool `json:"tools"`
+	Resources   bool `json:"resources"`
+	Prompts     bool `json:"prompts"`
+	Logging     bool `json:"logging"`
+	Completions bool `json:"completions"`
+}
+
+// MCPAuthorization describes how the user can authorize the BFF for an MCP server that requires OAuth
+type MCPAuthorization struct {
+	ResourceMetadataURL string `json:"resource_metadata_url"` // Protected resource metadata advertised by the MCP server
+	Scope               string `json:"scope,omitempty"`       // Scope requested by the MCP server challenge
+	LoginURL            string `json:"login_url,omitempty"`   // Authorization URL to open in the browser; empty when the flow cannot be started
 }
 
 // ErrorDetails provides structured error information for debugging and categorization
@@ -34,7 +52,7 @@ type Tool struct {
 // ToolsStatus represents the comprehensive status and tools from an MCP server
 type ToolsStatus struct {
 	ServerURL   string `json:"server_url"`
-	Status      string `json:"status"`       // "success", "error"
+	Status      string `json:"status"`       // "success", "error", "auth_required"
 	Message     string `json:"message"`      // Success message or clean error message
 	LastChecked int64  `json:"last_checked"` // Unix timestamp
 
@@ -44,16 +62,156 @@ type ToolsStatus struct {
 		ProtocolVersion string `json:"protocol_version"` // MCP protocol version OR empty
 	} `json:"server_info"`
 
-	ToolsCount   *int          `json:"tools_count,omitempty"`   // Only present on successful connection
-	Tools        []Tool        `json:"tools"`                   // List of tools (empty array on error)
-	ErrorDetails *ErrorDetails `json:"error_details,omitempty"` // Only present when status is "error"
+	ToolsCount    *int              `json:"tools_count,omitempty"`   // Only present on successful connection
+	Tools         []Tool            `json:"tools"`                   // List of tools (empty array on error)
+	ErrorDetails  *ErrorDetails     `json:"error_details,omitempty"` // Only present when status is "error" or "auth_required"
+	Authorization *MCPAuthorization `json:"authorization,omitempty"` // Only present when status is "auth_required"
+}
+
+// ToolCallResult represents the outcome of a direct MCP tool invocation
+type ToolCallResult struct {
+	ServerURL string `json:"server_url"`
+	ToolName  string `json:"tool_name"`
+	Status    string `json:"status"`  // "success", "error", "auth_required"
+	Message   string `json:"message"` // Success message or clean error message
+	IsError   bool   `json:"is_error"`
+
+	Content           []MCPContent   `json:"content"`                      // Unstructured tool output (empty array when the call did not complete)
+	StructuredContent interface{}    `json:"structured_content,omitempty"` // Structured tool output, when the tool returns one
+	Timing            ToolCallTiming `json:"timing"`
+
+	ErrorDetails  *ErrorDetails     `json:"error_details,omitempty"` // Present when status is "error" or "auth_required"
+	Authorization *MCPAuthorization `json:"authorization,omitempty"` // Only present when status is "auth_required"
+}
+
+// MCPContent is one content item returned by an MCP tool or prompt
+type MCPContent struct {
+	Type     string `json:"type"`                // "text", "image", "audio", "resource_link" or "resource"
+	Text     string `json:"text,omitempty"`      // Text content, or the text of an embedded resource
+	Data     []byte `json:"data,omitempty"`      // Image or audio data, or the blob of an embedded resource (base64 in JSON)
+	MimeType string `json:"mime_type,omitempty"` // MIME type of data or resources
+	URI      string `json:"uri,omitempty"`       // URI of linked or embedded resources
+	Name     string `json:"name,omitempty"`      // Name of linked resources
+}
+
+// ToolCallTiming reports where the time of a tool invocation was spent, in milliseconds
+type ToolCallTiming struct {
+	ConnectMs int64 `json:"connect_ms"` // Session initialization including authentication
+	CallMs    int64 `json:"call_ms"`    // The tools/call request itself
+	TotalMs   int64 `json:"total_ms"`   // Everything, including argument validation
+}
+
+// MCPServerInfo identifies the MCP server that answered a request
+type MCPServerInfo struct {
+	Name            string `json:"name"`             // MCP server name OR ConfigMap key fallback
+	Version         string `json:"version"`          // MCP version OR "N/A"
+	ProtocolVersion string `json:"protocol_version"` // MCP protocol version OR empty
+}
+
+// Resource represents a resource published by an MCP server
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mime_type,omitempty"`
+	Size        int64  `json:"size,omitempty"` // Size in bytes, when the server reports it
+}
+
+// ResourceTemplate represents a parameterized resource (RFC 6570 URI template) published by an MCP server
+type ResourceTemplate struct {
+	URITemplate string `json:"uri_template"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mime_type,omitempty"`
+}
+
+// ResourcesStatus represents the resources of an MCP server with status information
+type ResourcesStatus struct {
+	ServerURL   string        `json:"server_url"`
+	Status      string        `json:"status"`       // "success", "error", "auth_required"
+	Message     string        `json:"message"`      // Success message or clean error message
+	LastChecked int64         `json:"last_checked"` // Unix timestamp
+	ServerInfo  MCPServerInfo `json:"server_info"`
+
+	ResourcesCount    *int               `json:"resources_count,omitempty"` // Only present on successful connection
+	Resources         []Resource         `json:"resources"`                 // List of resources (empty array on error)
+	ResourceTemplates []ResourceTemplate `json:"resource_templates"`        // List of resource templates (empty array on error)
+	ErrorDetails      *ErrorDetails      `json:"error_details,omitempty"`   // Only present when status is "error" or "auth_required"
+	Authorization     *MCPAuthorization  `json:"authorization,omitempty"`   // Only present when status is "auth_required"
+}
+
+// ResourceContents is the content of a resource; text resources set Text, binary resources set Blob
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mime_type,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     []byte `json:"blob,omitempty"` // base64 in JSON
+}
+
+// ResourceReadResult represents the contents of a resource read from an MCP server
+type ResourceReadResult struct {
+	ServerURL string             `json:"server_url"`
+	URI       string             `json:"uri"`
+	Contents  []ResourceContents `json:"contents"`
+}
+
+// PromptArgument describes an argument of an MCP prompt
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+}
+
+// Prompt represents a prompt template published by an MCP server
+type Prompt struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments"`
+}
+
+// PromptsStatus represents the prompts of an MCP server with status information
+type PromptsStatus struct {
+	ServerURL   string        `json:"server_url"`
+	Status      string        `json:"status"`       // "success", "error", "auth_required"
+	Message     string        `json:"message"`      // Success message or clean error message
+	LastChecked int64         `json:"last_checked"` // Unix timestamp
+	ServerInfo  MCPServerInfo `json:"server_info"`
+
+	PromptsCount  *int              `json:"prompts_count,omitempty"` // Only present on successful connection
+	Prompts       []Prompt          `json:"prompts"`                 // List of prompts (empty array on error)
+	ErrorDetails  *ErrorDetails     `json:"error_details,omitempty"` // Only present when status is "error" or "auth_required"
+	Authorization *MCPAuthorization `json:"authorization,omitempty"` // Only present when status is "auth_required"
+}
+
+// PromptMessage is one message of a rendered MCP prompt
+type PromptMessage struct {
+	Role    string     `json:"role"` // "user" or "assistant"
+	Content MCPContent `json:"content"`
+}
+
+// PromptResult represents a prompt rendered by an MCP server with the given arguments
+type PromptResult struct {
+	ServerURL   string          `json:"server_url"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
 }
 
 // MCPServerConfig represents the configuration for an MCP server from ConfigMap
 type MCPServerConfig struct {
-	Name        string `json:"name"`                  // ConfigMap key name for the server
-	URL         string `json:"url"`                   // Full URL with endpoint path included
-	Transport   string `json:"transport,omitempty"`   // "sse" or "streamable-http" (defaults to "streamable-http")
-	Description string `json:"description,omitempty"` // Optional description of the MCP server functionality
-	Logo        string `json:"logo,omitempty"`        // Optional logo URL for the MCP server
+	Name          string `json:"name"`                      // ConfigMap key name for the server
+	URL           string `json:"url"`                       // Full URL with endpoint path included
+	Transport     string `json:"transport,omitempty"`       // "sse", "streamable-http", "stdio" or "websocket" (defaults to "streamable-http")
+	Description   string `json:"description,omitempty"`     // Optional description of the MCP server functionality
+	Logo          string `json:"logo,omitempty"`            // Optional logo URL for the MCP server
+	OAuthClientID string `json:"oauth_client_id,omitempty"` // Optional pre-registered OAuth client ID, for authorization servers without dynamic client registration
+
+	// stdio servers are started by the BFF; the URL is set to stdio://<name>
+	Command string            `json:"command,omitempty"` // Executable to run
+	Args    []string          `json:"args,omitempty"`    // Arguments of the command
+	Env     map[string]string `json:"env,omitempty"`     // Environment variables of the process, in addition to PATH and HOME
 }
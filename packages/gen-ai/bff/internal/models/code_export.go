@@ -1,5 +1,10 @@
 package models
 
+import (
+	"encoding/json"
+	"strconv"
+)
+
 // MCPServer represents MCP server configuration
 type MCPServer struct {
 	ServerLabel string            `json:"server_label"`
@@ -37,6 +42,101 @@ type CodeExportRequest struct {
 	Tools        []CodeExportTool   `json:"tools,omitempty"`
 	VectorStore  *VectorStoreConfig `json:"vector_store,omitempty"`
 	Files        []FileUpload       `json:"files,omitempty"`
+
+	// Sampling and generation settings, as in the responses endpoint
+	TopP              *float64    `json:"top_p,omitempty"`
+	MaxOutputTokens   *int64      `json:"max_output_tokens,omitempty"`
+	Stop              []string    `json:"stop,omitempty"`
+	Seed              *int64      `json:"seed,omitempty"`
+	FrequencyPenalty  *float64    `json:"frequency_penalty,omitempty"`
+	PresencePenalty   *float64    `json:"presence_penalty,omitempty"`
+	ParallelToolCalls *bool       `json:"parallel_tool_calls,omitempty"`
+	ToolChoice        *ToolChoice `json:"tool_choice,omitempty"`
+	Truncation        string      `json:"truncation,omitempty"`
+
+	ResponseFormat *CodeExportResponseFormat `json:"response_format,omitempty"`
+}
+
+// CodeExportSetting is a generation setting of the exported code, with its value as a Python literal
+type CodeExportSetting struct {
+	Name  string
+	Value string
+}
+
+// GenerationSettings returns the settings that are parameters of the Responses API
+func (r CodeExportRequest) GenerationSettings() []CodeExportSetting {
+	var settings []CodeExportSetting
+	if r.TopP != nil {
+		settings = append(settings, CodeExportSetting{"top_p", pythonFloat(*r.TopP)})
+	}
+	if r.MaxOutputTokens != nil {
+		settings = append(settings, CodeExportSetting{"max_output_tokens", strconv.FormatInt(*r.MaxOutputTokens, 10)})
+	}
+	if r.ParallelToolCalls != nil {
+		value := "False"
+		if *r.ParallelToolCalls {
+			value = "True"
+		}
+		settings = append(settings, CodeExportSetting{"parallel_tool_calls", value})
+	}
+	if r.ToolChoice != nil {
+		// Tool choices only hold strings, so their JSON is a Python literal
+		if data, err := json.Marshal(r.ToolChoice); err == nil {
+			settings = append(settings, CodeExportSetting{"tool_choice", string(data)})
+		}
+	}
+	if r.Truncation != "" {
+		settings = append(settings, CodeExportSetting{"truncation", strconv.Quote(r.Truncation)})
+	}
+	return settings
+}
+
+// ExtraBodySettings returns the settings that are not parameters of the Responses API, which the BFF sends
+// as extra fields of the request body
+func (r CodeExportRequest) ExtraBodySettings() []CodeExportSetting {
+	var settings []CodeExportSetting
+	if len(r.Stop) > 0 {
+		if data, err := json.Marshal(r.Stop); err == nil {
+			settings = append(settings, CodeExportSetting{"stop", string(data)})
+		}
+	}
+	if r.Seed != nil {
+		settings = append(settings, CodeExportSetting{"seed", strconv.FormatInt(*r.Seed, 10)})
+	}
+	if r.FrequencyPenalty != nil {
+		settings = append(settings, CodeExportSetting{"frequency_penalty", pythonFloat(*r.FrequencyPenalty)})
+	}
+	if r.PresencePenalty != nil {
+		settings = append(settings, CodeExportSetting{"presence_penalty", pythonFloat(*r.PresencePenalty)})
+	}
+	return settings
+}
+
+// pythonFloat formats a float as a Python float literal
+func pythonFloat(value float64) string {
+	literal := strconv.FormatFloat(value, 'f', -1, 64)
+	if _, err := strconv.ParseInt(literal, 10, 64); err == nil {
+		literal += ".0"
+	}
+	return literal
+}
+
+// CodeExportResponseFormat represents the JSON output format of the response, as in the responses endpoint
+type CodeExportResponseFormat struct {
+	Type        string                 `json:"type"`
+	Name        string                 `json:"name,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Schema      map[string]interface{} `json:"schema,omitempty"`
+	Strict      bool                   `json:"strict,omitempty"`
+}
+
+// JSON returns the format as the indented JSON of a Responses API text format
+func (f CodeExportResponseFormat) JSON() (string, error) {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
 }
 
 type CodeExportResponse struct {
@@ -0,0 +1,52 @@
+package models
+
+import "encoding/json"
+
+// Conversation represents a persisted chat thread owned by a user in a namespace
+type Conversation struct {
+	ID             string `json:"id"`
+	Title          string `json:"title"`
+	Namespace      string `json:"namespace"`
+	Owner          string `json:"owner"`
+	Model          string `json:"model,omitempty"`            // Model used for the most recent turn
+	LastResponseID string `json:"last_response_id,omitempty"` // Response ID to continue the thread with previous_response_id
+	MessageCount   int    `json:"message_count"`
+	CreatedAt      int64  `json:"created_at"` // Unix timestamp
+	UpdatedAt      int64  `json:"updated_at"` // Unix timestamp
+}
+
+// ConversationSettings captures the model and tool settings used for a single turn
+type ConversationSettings struct {
+	Model          string                  `json:"model"`
+	Temperature    *float64                `json:"temperature,omitempty"`
+	TopP           *float64                `json:"top_p,omitempty"`
+	Instructions   string                  `json:"instructions,omitempty"`
+	VectorStoreIDs []string                `json:"vector_store_ids,omitempty"`
+	MCPServers     []ConversationMCPServer `json:"mcp_servers,omitempty"`
+	Stream         bool                    `json:"stream,omitempty"`
+}
+
+// ConversationMCPServer records which MCP server was used for a turn.
+// Headers are intentionally not recorded to avoid persisting credentials.
+type ConversationMCPServer struct {
+	ServerLabel string `json:"server_label"`
+	ServerURL   string `json:"server_url"`
+}
+
+// ConversationMessage represents a single turn (user input and assistant response) in a conversation
+type ConversationMessage struct {
+	ID             string               `json:"id"`
+	ConversationID string               `json:"conversation_id"`
+	Input          string               `json:"input"`
+	OutputText     string               `json:"output_text,omitempty"` // Concatenated assistant text, used for display and search
+	ResponseID     string               `json:"response_id,omitempty"`
+	Settings       ConversationSettings `json:"settings"`
+	Response       json.RawMessage      `json:"response,omitempty"` // Full ResponseData as returned by the responses endpoint
+	CreatedAt      int64                `json:"created_at"`         // Unix timestamp
+}
+
+// ConversationListOptions controls filtering of conversation listings
+type ConversationListOptions struct {
+	Query string // Case-insensitive text matched against titles, inputs and outputs
+	Limit int    // Maximum number of conversations to return (0 means no limit)
+}
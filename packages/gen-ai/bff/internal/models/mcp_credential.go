@@ -0,0 +1,9 @@
+package models
+
+// MCPCredential describes a stored MCP server credential. The secret value itself is never exposed.
+type MCPCredential struct {
+	ServerName string `json:"server_name"`          // ConfigMap key of the MCP server
+	AuthType   string `json:"auth_type"`            // "token" for user-provided tokens, "oauth" for tokens from the OAuth flow
+	UpdatedAt  int64  `json:"updated_at"`           // Unix timestamp of the last change
+	ExpiresAt  int64  `json:"expires_at,omitempty"` // Unix timestamp when the access token expires; only for OAuth tokens that expire
+}
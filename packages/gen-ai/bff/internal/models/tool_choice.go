@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ToolChoice controls which tools the model calls when generating a response.
+// In JSON it is either "auto", "none" or "required" for all tools, or an object naming the tool the model
+// must call: {"type": "file_search"} or {"type": "mcp", "server_label": "github", "name": "get_latest_release"}.
+type ToolChoice struct {
+	Mode        string `json:"-"`
+	Type        string `json:"type,omitempty"`
+	ServerLabel string `json:"server_label,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// UnmarshalJSON accepts both the mode and the tool object form
+func (c *ToolChoice) UnmarshalJSON(data []byte) error {
+	var mode string
+	if err := json.Unmarshal(data, &mode); err == nil {
+		*c = ToolChoice{Mode: mode}
+		return nil
+	}
+
+	type tool ToolChoice
+	var choice tool
+	if err := json.Unmarshal(data, &choice); err != nil {
+		return fmt.Errorf("tool_choice must be \"auto\", \"none\", \"required\" or an object with the type of a tool: %w", err)
+	}
+	*c = ToolChoice(choice)
+	return nil
+}
+
+// MarshalJSON writes the choice in the same form it was given
+func (c ToolChoice) MarshalJSON() ([]byte, error) {
+	if c.Mode != "" {
+		return json.Marshal(c.Mode)
+	}
+	type tool ToolChoice
+	return json.Marshal(tool(c))
+}
+
+// IsZero reports whether the choice is empty, as SDK structs marshal it for responses without a tool choice
+func (c ToolChoice) IsZero() bool {
+	return c == ToolChoice{}
+}
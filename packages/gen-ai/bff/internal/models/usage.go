@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+// UsageRecord is the token usage and latency of a single response
+type UsageRecord struct {
+	Namespace          string
+	Username           string
+	Model              string
+	InputTokens        int64
+	OutputTokens       int64
+	TotalTokens        int64
+	LatencyMs          int64
+	TimeToFirstTokenMs *int64 // Only measured for streaming responses
+	Failed             bool   // The response failed or did not complete
+	CreatedAt          time.Time
+}
+
+// UsageTotals aggregates the usage of responses, of one model when Model is set. The latency and time to first
+// token sums are kept so that totals can be merged, and the averages are derived from them.
+type UsageTotals struct {
+	Model                     string `json:"model,omitempty"`
+	Requests                  int64  `json:"requests"`
+	FailedRequests            int64  `json:"failed_requests"`
+	InputTokens               int64  `json:"input_tokens"`
+	OutputTokens              int64  `json:"output_tokens"`
+	TotalTokens               int64  `json:"total_tokens"`
+	LatencyMs                 int64  `json:"latency_ms"` // Sum over all requests
+	AverageLatencyMs          int64  `json:"average_latency_ms"`
+	StreamedRequests          int64  `json:"streamed_requests"`      // Requests with a measured time to first token
+	TimeToFirstTokenMs        int64  `json:"time_to_first_token_ms"` // Sum over the streamed requests
+	AverageTimeToFirstTokenMs int64  `json:"average_time_to_first_token_ms"`
+}
+
+// Add adds a response to the totals
+func (t *UsageTotals) Add(record UsageRecord) {
+	t.Requests++
+	if record.Failed {
+		t.FailedRequests++
+	}
+	t.InputTokens += record.InputTokens
+	t.OutputTokens += record.OutputTokens
+	t.TotalTokens += record.TotalTokens
+	t.LatencyMs += record.LatencyMs
+	if record.TimeToFirstTokenMs != nil {
+		t.StreamedRequests++
+		t.TimeToFirstTokenMs += *record.TimeToFirstTokenMs
+	}
+	t.updateAverages()
+}
+
+// Merge adds other totals to the totals
+func (t *UsageTotals) Merge(other UsageTotals) {
+	t.Requests += other.Requests
+	t.FailedRequests += other.FailedRequests
+	t.InputTokens += other.InputTokens
+	t.OutputTokens += other.OutputTokens
+	t.TotalTokens += other.TotalTokens
+	t.LatencyMs += other.LatencyMs
+	t.StreamedRequests += other.StreamedRequests
+	t.TimeToFirstTokenMs += other.TimeToFirstTokenMs
+	t.updateAverages()
+}
+
+func (t *UsageTotals) updateAverages() {
+	t.AverageLatencyMs = 0
+	if t.Requests > 0 {
+		t.AverageLatencyMs = t.LatencyMs / t.Requests
+	}
+	t.AverageTimeToFirstTokenMs = 0
+	if t.StreamedRequests > 0 {
+		t.AverageTimeToFirstTokenMs = t.TimeToFirstTokenMs / t.StreamedRequests
+	}
+}
+
+// UsageSummary is the usage of responses in total and per model, the models ordered by name
+type UsageSummary struct {
+	Total  UsageTotals   `json:"total"`
+	Models []UsageTotals `json:"models"`
+}
+
+// UsageReport is the usage of responses in a namespace since a day, for the caller and for all users
+type UsageReport struct {
+	Namespace string       `json:"namespace"`
+	Username  string       `json:"username"`
+	Since     string       `json:"since"`     // First day of the report, YYYY-MM-DD in UTC
+	User      UsageSummary `json:"user"`      // Responses of the caller
+	AllUsers  UsageSummary `json:"all_users"` // Responses of every user of the namespace
+}
+
+// UsageReportOptions controls the period and models of a usage report
+type UsageReportOptions struct {
+	Days  int    // Number of days to report, including today
+	Model string // Only report this model when set
+}
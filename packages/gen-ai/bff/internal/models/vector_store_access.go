@@ -0,0 +1,111 @@
+package models
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+)
+
+// VectorStoreACL is the ownership of a vector store, kept in its metadata
+type VectorStoreACL struct {
+	Owner        string   `json:"owner"`
+	Namespace    string   `json:"namespace"`
+	Visibility   string   `json:"visibility"`
+	SharedGroups []string `json:"shared_groups,omitempty"`
+}
+
+// VectorStoreCaller is the user a vector store request is made for
+type VectorStoreCaller struct {
+	Username  string
+	Groups    []string
+	Namespace string
+}
+
+// VectorStoreACLFromMetadata reads the ownership from vector store metadata. Stores of the removed per-user
+// auto-provisioning that were not migrated yet are private to the user they were provisioned for.
+// Other stores created before ownership was recorded, including stores the BFF did not create, have no owner;
+// they stay shared with the namespace but are read-only, and false is returned for them.
+func VectorStoreACLFromMetadata(metadata map[string]string) (VectorStoreACL, bool) {
+	owner := metadata[constants.VectorStoreOwnerKey]
+	if owner == "" {
+		username := metadata[constants.VectorStoreAutoProvisionedUsernameKey]
+		if metadata[constants.VectorStoreCreatedByKey] == constants.VectorStoreAutoProvisionedCreatedBy && username != "" {
+			return VectorStoreACL{Owner: username, Visibility: constants.VectorStoreVisibilityPrivate}, true
+		}
+		return VectorStoreACL{Visibility: constants.VectorStoreVisibilityNamespace}, false
+	}
+
+	acl := VectorStoreACL{
+		Owner:      owner,
+		Namespace:  metadata[constants.VectorStoreNamespaceKey],
+		Visibility: metadata[constants.VectorStoreVisibilityKey],
+	}
+	if !IsValidVectorStoreVisibility(acl.Visibility) {
+		acl.Visibility = constants.VectorStoreVisibilityPrivate
+	}
+	for _, group := range strings.Split(metadata[constants.VectorStoreSharedGroupsKey], ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			acl.SharedGroups = append(acl.SharedGroups, group)
+		}
+	}
+	return acl, true
+}
+
+// ApplyTo writes the ownership into vector store metadata, replacing any previous ownership
+func (acl VectorStoreACL) ApplyTo(metadata map[string]string) {
+	metadata[constants.VectorStoreOwnerKey] = acl.Owner
+	metadata[constants.VectorStoreNamespaceKey] = acl.Namespace
+	metadata[constants.VectorStoreVisibilityKey] = acl.Visibility
+	if len(acl.SharedGroups) > 0 {
+		metadata[constants.VectorStoreSharedGroupsKey] = strings.Join(acl.SharedGroups, ",")
+	} else {
+		delete(metadata, constants.VectorStoreSharedGroupsKey)
+	}
+}
+
+// CanRead reports whether the caller may see, list the files of and search the vector store
+func (acl VectorStoreACL) CanRead(caller VectorStoreCaller) bool {
+	if acl.CanManage(caller) {
+		return true
+	}
+	// LlamaStack is deployed per namespace, but the recorded namespace guards against stores moved between deployments
+	if acl.Namespace != "" && acl.Namespace != caller.Namespace {
+		return false
+	}
+	switch acl.Visibility {
+	case constants.VectorStoreVisibilityNamespace:
+		return true
+	case constants.VectorStoreVisibilityGroups:
+		return slices.ContainsFunc(acl.SharedGroups, func(group string) bool { return slices.Contains(caller.Groups, group) })
+	default:
+		return false
+	}
+}
+
+// CanManage reports whether the caller may change the vector store, its files and its sharing, or delete it
+func (acl VectorStoreACL) CanManage(caller VectorStoreCaller) bool {
+	return acl.Owner != "" && acl.Owner == caller.Username
+}
+
+// IsValidVectorStoreVisibility reports whether the visibility is one of the supported values
+func IsValidVectorStoreVisibility(visibility string) bool {
+	switch visibility {
+	case constants.VectorStoreVisibilityPrivate, constants.VectorStoreVisibilityNamespace, constants.VectorStoreVisibilityGroups:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsReservedVectorStoreMetadataKey reports whether the metadata key holds ownership or the embedding model and
+// cannot be set directly
+func IsReservedVectorStoreMetadataKey(key string) bool {
+	switch key {
+	case constants.VectorStoreOwnerKey, constants.VectorStoreNamespaceKey, constants.VectorStoreVisibilityKey, constants.VectorStoreSharedGroupsKey,
+		constants.VectorStoreEmbeddingModelKey, constants.VectorStoreEmbeddingDimensionKey:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,332 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+)
+
+// ErrConversationNotFound is returned when a conversation does not exist or is not owned by the caller
+var ErrConversationNotFound = errors.New("conversation not found")
+
+// ErrConversationLimitExceeded is returned when the caller has as many conversations, or a conversation has as many
+// messages, as can be kept
+var ErrConversationLimitExceeded = errors.New("conversation limit exceeded")
+
+// ErrConversationMessageTooLarge is returned for messages larger than constants.ConversationMessageMaxBytes
+var ErrConversationMessageTooLarge = errors.New("conversation message is too large")
+
+// ConversationStore is the persistence interface for conversation history.
+// All operations are scoped by namespace and owner so that users can only see their own conversations.
+// Implementations must be safe for concurrent use.
+type ConversationStore interface {
+	// CreateConversation persists a new conversation
+	CreateConversation(ctx context.Context, conversation models.Conversation) error
+
+	// GetConversation retrieves a conversation by ID
+	GetConversation(ctx context.Context, namespace, owner, id string) (*models.Conversation, error)
+
+	// ListConversations returns conversations ordered by most recently updated first
+	ListConversations(ctx context.Context, namespace, owner string, opts models.ConversationListOptions) ([]models.Conversation, error)
+
+	// UpdateConversation changes the stored conversation metadata with update and returns the result.
+	// The conversation is not changed by other operations while update runs.
+	UpdateConversation(ctx context.Context, namespace, owner, id string, update func(*models.Conversation)) (*models.Conversation, error)
+
+	// DeleteConversation removes a conversation and all of its messages
+	DeleteConversation(ctx context.Context, namespace, owner, id string) error
+
+	// AppendMessage adds a message to the end of a conversation and changes the conversation metadata with update
+	// in the same operation, so that concurrent messages are all accounted for
+	AppendMessage(ctx context.Context, namespace, owner string, message models.ConversationMessage, update func(*models.Conversation)) error
+
+	// ListMessages returns the messages of a conversation in chronological order
+	ListMessages(ctx context.Context, namespace, owner, conversationID string) ([]models.ConversationMessage, error)
+}
+
+// ConversationsRepository handles conversation history operations.
+type ConversationsRepository struct {
+	store ConversationStore
+}
+
+// NewConversationsRepository creates a new conversations repository backed by the given store.
+func NewConversationsRepository(store ConversationStore) *ConversationsRepository {
+	return &ConversationsRepository{store: store}
+}
+
+// CreateConversation creates a new, empty conversation owned by the given user.
+func (r *ConversationsRepository) CreateConversation(ctx context.Context, namespace, owner, title string) (*models.Conversation, error) {
+	now := time.Now().Unix()
+	conversation := models.Conversation{
+		ID:        uuid.NewString(),
+		Title:     title,
+		Namespace: namespace,
+		Owner:     owner,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := r.store.CreateConversation(ctx, conversation); err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// GetConversation retrieves a conversation owned by the given user.
+func (r *ConversationsRepository) GetConversation(ctx context.Context, namespace, owner, id string) (*models.Conversation, error) {
+	return r.store.GetConversation(ctx, namespace, owner, id)
+}
+
+// ListConversations lists the conversations owned by the given user.
+func (r *ConversationsRepository) ListConversations(ctx context.Context, namespace, owner string, opts models.ConversationListOptions) ([]models.Conversation, error) {
+	return r.store.ListConversations(ctx, namespace, owner, opts)
+}
+
+// RenameConversation updates the title of a conversation.
+func (r *ConversationsRepository) RenameConversation(ctx context.Context, namespace, owner, id, title string) (*models.Conversation, error) {
+	return r.store.UpdateConversation(ctx, namespace, owner, id, func(conversation *models.Conversation) {
+		conversation.Title = title
+		conversation.UpdatedAt = time.Now().Unix()
+	})
+}
+
+// DeleteConversation deletes a conversation and its messages.
+func (r *ConversationsRepository) DeleteConversation(ctx context.Context, namespace, owner, id string) error {
+	return r.store.DeleteConversation(ctx, namespace, owner, id)
+}
+
+// CheckMessageLimit returns ErrConversationLimitExceeded when no more messages can be added to the conversation.
+func (r *ConversationsRepository) CheckMessageLimit(conversation *models.Conversation) error {
+	if conversation.MessageCount >= constants.ConversationMaxMessages {
+		return errConversationFull()
+	}
+	return nil
+}
+
+// ListMessages lists the messages of a conversation.
+func (r *ConversationsRepository) ListMessages(ctx context.Context, namespace, owner, conversationID string) ([]models.ConversationMessage, error) {
+	return r.store.ListMessages(ctx, namespace, owner, conversationID)
+}
+
+// AddMessage records a turn in a conversation and updates the conversation metadata.
+// If the conversation has no title yet, one is derived from the first input.
+func (r *ConversationsRepository) AddMessage(ctx context.Context, namespace, owner, conversationID string, message models.ConversationMessage) (*models.ConversationMessage, error) {
+	if message.ID == "" {
+		message.ID = uuid.NewString()
+	}
+	if message.CreatedAt == 0 {
+		message.CreatedAt = time.Now().Unix()
+	}
+	message.ConversationID = conversationID
+	if size := len(message.Input) + len(message.OutputText) + len(message.Response); size > constants.ConversationMessageMaxBytes {
+		return nil, fmt.Errorf("%w: the message has %d bytes, at most %d can be kept", ErrConversationMessageTooLarge, size, constants.ConversationMessageMaxBytes)
+	}
+
+	err := r.store.AppendMessage(ctx, namespace, owner, message, func(conversation *models.Conversation) {
+		conversation.MessageCount++
+		conversation.UpdatedAt = message.CreatedAt
+		if message.Settings.Model != "" {
+			conversation.Model = message.Settings.Model
+		}
+		if message.ResponseID != "" {
+			conversation.LastResponseID = message.ResponseID
+		}
+		if conversation.Title == "" {
+			conversation.Title = deriveConversationTitle(message.Input)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// errConversationFull is the error for conversations that have as many messages as can be kept
+func errConversationFull() error {
+	return fmt.Errorf("%w: a conversation can have at most %d messages", ErrConversationLimitExceeded, constants.ConversationMaxMessages)
+}
+
+// maxDerivedTitleLength is the maximum number of characters used when deriving a title from the first input
+const maxDerivedTitleLength = 80
+
+// deriveConversationTitle builds a short single-line title from a message input
+func deriveConversationTitle(input string) string {
+	title := strings.Join(strings.Fields(input), " ")
+	runes := []rune(title)
+	if len(runes) > maxDerivedTitleLength {
+		title = strings.TrimSpace(string(runes[:maxDerivedTitleLength])) + "…"
+	}
+	return title
+}
+
+// inMemoryConversationStore is the default ConversationStore implementation.
+// It keeps all data in process memory, so history is lost when the BFF restarts and is not shared between replicas.
+// The number of conversations of each owner and of messages of each conversation is capped to bound that memory.
+type inMemoryConversationStore struct {
+	mu sync.RWMutex
+
+	// conversations is keyed by namespace -> owner -> conversation ID
+	conversations map[string]map[string]map[string]*conversationRecord
+}
+
+// conversationRecord holds a conversation together with its messages
+type conversationRecord struct {
+	conversation models.Conversation
+	messages     []models.ConversationMessage
+}
+
+// NewInMemoryConversationStore creates a ConversationStore that does not require an external database.
+func NewInMemoryConversationStore() ConversationStore {
+	return &inMemoryConversationStore{
+		conversations: make(map[string]map[string]map[string]*conversationRecord),
+	}
+}
+
+// getRecord returns the record for a conversation. The caller must hold the lock.
+func (s *inMemoryConversationStore) getRecord(namespace, owner, id string) (*conversationRecord, error) {
+	record, ok := s.conversations[namespace][owner][id]
+	if !ok {
+		return nil, ErrConversationNotFound
+	}
+	return record, nil
+}
+
+func (s *inMemoryConversationStore) CreateConversation(_ context.Context, conversation models.Conversation) error {
+	if conversation.Namespace == "" || conversation.Owner == "" || conversation.ID == "" {
+		return fmt.Errorf("conversation namespace, owner and id are required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.conversations[conversation.Namespace]; !ok {
+		s.conversations[conversation.Namespace] = make(map[string]map[string]*conversationRecord)
+	}
+	if _, ok := s.conversations[conversation.Namespace][conversation.Owner]; !ok {
+		s.conversations[conversation.Namespace][conversation.Owner] = make(map[string]*conversationRecord)
+	}
+	if _, exists := s.conversations[conversation.Namespace][conversation.Owner][conversation.ID]; exists {
+		return fmt.Errorf("conversation %s already exists", conversation.ID)
+	}
+	if len(s.conversations[conversation.Namespace][conversation.Owner]) >= constants.ConversationMaxPerOwner {
+		return fmt.Errorf("%w: at most %d conversations can be kept, delete older ones first", ErrConversationLimitExceeded, constants.ConversationMaxPerOwner)
+	}
+
+	s.conversations[conversation.Namespace][conversation.Owner][conversation.ID] = &conversationRecord{
+		conversation: conversation,
+	}
+	return nil
+}
+
+func (s *inMemoryConversationStore) GetConversation(_ context.Context, namespace, owner, id string) (*models.Conversation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, err := s.getRecord(namespace, owner, id)
+	if err != nil {
+		return nil, err
+	}
+	conversation := record.conversation
+	return &conversation, nil
+}
+
+func (s *inMemoryConversationStore) ListConversations(_ context.Context, namespace, owner string, opts models.ConversationListOptions) ([]models.Conversation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := strings.ToLower(strings.TrimSpace(opts.Query))
+	result := []models.Conversation{}
+	for _, record := range s.conversations[namespace][owner] {
+		if query != "" && !record.matches(query) {
+			continue
+		}
+		result = append(result, record.conversation)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].UpdatedAt == result[j].UpdatedAt {
+			return result[i].CreatedAt > result[j].CreatedAt
+		}
+		return result[i].UpdatedAt > result[j].UpdatedAt
+	})
+
+	if opts.Limit > 0 && len(result) > opts.Limit {
+		result = result[:opts.Limit]
+	}
+	return result, nil
+}
+
+// matches reports whether the conversation title or any message contains the lower-cased query
+func (r *conversationRecord) matches(query string) bool {
+	if strings.Contains(strings.ToLower(r.conversation.Title), query) {
+		return true
+	}
+	for _, message := range r.messages {
+		if strings.Contains(strings.ToLower(message.Input), query) ||
+			strings.Contains(strings.ToLower(message.OutputText), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *inMemoryConversationStore) UpdateConversation(_ context.Context, namespace, owner, id string, update func(*models.Conversation)) (*models.Conversation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, err := s.getRecord(namespace, owner, id)
+	if err != nil {
+		return nil, err
+	}
+	update(&record.conversation)
+	conversation := record.conversation
+	return &conversation, nil
+}
+
+func (s *inMemoryConversationStore) DeleteConversation(_ context.Context, namespace, owner, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.getRecord(namespace, owner, id); err != nil {
+		return err
+	}
+	delete(s.conversations[namespace][owner], id)
+	return nil
+}
+
+func (s *inMemoryConversationStore) AppendMessage(_ context.Context, namespace, owner string, message models.ConversationMessage, update func(*models.Conversation)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, err := s.getRecord(namespace, owner, message.ConversationID)
+	if err != nil {
+		return err
+	}
+	if len(record.messages) >= constants.ConversationMaxMessages {
+		return errConversationFull()
+	}
+	record.messages = append(record.messages, message)
+	update(&record.conversation)
+	return nil
+}
+
+func (s *inMemoryConversationStore) ListMessages(_ context.Context, namespace, owner, conversationID string) ([]models.ConversationMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, err := s.getRecord(namespace, owner, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]models.ConversationMessage, len(record.messages))
+	copy(messages, record.messages)
+	return messages, nil
+}
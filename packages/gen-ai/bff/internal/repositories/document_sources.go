@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	kubernetes "github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/sources"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ErrDataConnectionNotFound is returned when the namespace has no S3 data connection with the given name
+var ErrDataConnectionNotFound = errors.New("S3 data connection not found")
+
+// DocumentSourcesRepository resolves documents that are ingested from S3-compatible storage or URLs.
+type DocumentSourcesRepository struct {
+	urlClient *http.Client // Fetches URLs given by users
+	s3Client  *http.Client // Reads from the endpoints of data connections, which administrators configure
+}
+
+// NewDocumentSourcesRepository creates a new document sources repository that fetches URLs with urlClient and
+// reads from S3-compatible storage with s3Client.
+func NewDocumentSourcesRepository(urlClient, s3Client *http.Client) *DocumentSourcesRepository {
+	return &DocumentSourcesRepository{urlClient: urlClient, s3Client: s3Client}
+}
+
+// HTTPSource returns the document at an http:// or https:// URL.
+func (r *DocumentSourcesRepository) HTTPSource(uri string) (llamastack.DocumentSource, error) {
+	return sources.NewHTTPSource(uri, r.urlClient)
+}
+
+// S3Source returns the object at an s3:// URI, read with the credentials of a data connection in the namespace.
+// The data connection Secret is read with the identity of the user, so only data connections the user can read
+// can be used.
+func (r *DocumentSourcesRepository) S3Source(
+	ctx context.Context,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace, dataConnection, uri string,
+) (llamastack.DocumentSource, error) {
+	secret, err := k8sClient.GetSecret(ctx, identity, namespace, dataConnection)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ErrDataConnectionNotFound
+		}
+		return nil, fmt.Errorf("failed to get data connection: %w", err)
+	}
+
+	// Data connections of other types, and Secrets that are not data connections, are not used
+	connectionType := secret.Annotations[constants.DataConnectionTypeRefAnnotation]
+	if connectionType == "" {
+		connectionType = secret.Annotations[constants.DataConnectionTypeAnnotation]
+	}
+	if connectionType != constants.DataConnectionTypeS3 || secret.Labels[kubernetes.OpenDataHubDashboardLabelKey] != "true" {
+		return nil, ErrDataConnectionNotFound
+	}
+
+	return sources.NewS3Source(sources.S3Connection{
+		Endpoint:        string(secret.Data[constants.DataConnectionEndpointKey]),
+		Region:          string(secret.Data[constants.DataConnectionRegionKey]),
+		AccessKeyID:     string(secret.Data[constants.DataConnectionAccessKeyIDKey]),
+		SecretAccessKey: string(secret.Data[constants.DataConnectionSecretKeyKey]),
+		Bucket:          string(secret.Data[constants.DataConnectionBucketKey]),
+	}, uri, r.s3Client)
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+
+	helper "github.com/opendatahub-io/gen-ai/internal/helpers"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
+)
+
+// IngestionJobsRepository handles asynchronous document ingestion into vector stores.
+type IngestionJobsRepository struct {
+	manager *llamastack.IngestionManager
+}
+
+// NewIngestionJobsRepository creates a new ingestion jobs repository backed by the given manager.
+func NewIngestionJobsRepository(manager *llamastack.IngestionManager) *IngestionJobsRepository {
+	return &IngestionJobsRepository{manager: manager}
+}
+
+// SubmitJob queues documents for ingestion and returns the queued job.
+// The LlamaStack client is expected to be in the context (created by AttachLlamaStackClient middleware),
+// and is used by the background worker that performs the ingestion.
+func (r *IngestionJobsRepository) SubmitJob(ctx context.Context, params llamastack.IngestionJobParams) (*llamastack.IngestionJob, error) {
+	client, err := helper.GetContextLlamaStackClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.manager.Submit(client, params)
+}
+
+// GetJob retrieves an ingestion job owned by the given user.
+func (r *IngestionJobsRepository) GetJob(namespace, owner, id string) (*llamastack.IngestionJob, error) {
+	return r.manager.Get(namespace, owner, id)
+}
+
+// WatchJob notifies about changes of an ingestion job owned by the given user until the returned function is called.
+func (r *IngestionJobsRepository) WatchJob(namespace, owner, id string) (<-chan struct{}, func(), error) {
+	return r.manager.Watch(namespace, owner, id)
+}
+
+// PendingDocuments returns how many documents of the ingestion jobs of the namespace are not in a vector store yet.
+func (r *IngestionJobsRepository) PendingDocuments(namespace string) int {
+	return r.manager.PendingDocuments(namespace)
+}
+
+// CancelJob stops an ingestion job owned by the given user.
+func (r *IngestionJobsRepository) CancelJob(namespace, owner, id string) (*llamastack.IngestionJob, error) {
+	return r.manager.Cancel(namespace, owner, id)
+}
+
+// RetryJob queues a failed or cancelled ingestion job owned by the given user again.
+// The LlamaStack client is expected to be in the context (created by AttachLlamaStackClient middleware).
+func (r *IngestionJobsRepository) RetryJob(ctx context.Context, namespace, owner, id string) (*llamastack.IngestionJob, error) {
+	client, err := helper.GetContextLlamaStackClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.manager.Retry(client, namespace, owner, id)
+}
+
+// Close stops the ingestion workers.
+func (r *IngestionJobsRepository) Close() error {
+	return r.manager.Close()
+}
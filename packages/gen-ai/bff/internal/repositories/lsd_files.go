@@ -60,6 +60,18 @@ func (r *FilesRepository) GetFile(ctx context.Context, fileID string) (*openai.F
 	return client.GetFile(ctx, fileID)
 }
 
+// GetFileContent retrieves the original content of a file. The caller must close the content.
+// The LlamaStack client is expected to be in the context (created by AttachLlamaStackClient middleware).
+func (r *FilesRepository) GetFileContent(ctx context.Context, fileID string) (*llamastack.FileContent, error) {
+	// Get ready-to-use LlamaStack client from context using helper
+	client, err := helper.GetContextLlamaStackClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetFileContent(ctx, fileID)
+}
+
 // DeleteFile deletes a file by ID.
 // The LlamaStack client is expected to be in the context (created by AttachLlamaStackClient middleware).
 func (r *FilesRepository) DeleteFile(ctx context.Context, fileID string) error {
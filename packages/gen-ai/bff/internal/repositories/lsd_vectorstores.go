@@ -32,6 +32,17 @@ func (r *VectorStoresRepository) ListVectorStores(ctx context.Context, params ll
 	return client.ListVectorStores(ctx, params)
 }
 
+// ListVectorIOProviders retrieves the vector_io providers vector stores can be created with.
+// The LlamaStack client is expected to be in the context (created by AttachLlamaStackClient middleware).
+func (r *VectorStoresRepository) ListVectorIOProviders(ctx context.Context) ([]llamastack.Provider, error) {
+	client, err := helper.GetContextLlamaStackClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ListProviders(ctx, llamastack.VectorIOAPI)
+}
+
 // CreateVectorStore creates a new vector store and transforms the result for BFF use.
 // The LlamaStack client is expected to be in the context (created by AttachLlamaStackClient middleware).
 func (r *VectorStoresRepository) CreateVectorStore(ctx context.Context, params llamastack.CreateVectorStoreParams) (*openai.VectorStore, error) {
@@ -74,6 +85,30 @@ func (r *VectorStoresRepository) ListVectorStoreFiles(ctx context.Context, vecto
 	return client.ListVectorStoreFiles(ctx, vectorStoreID, params)
 }
 
+// GetVectorStoreFile retrieves a file of a vector store, which fails with not found when the file is not in the store.
+// The LlamaStack client is expected to be in the context (created by AttachLlamaStackClient middleware).
+func (r *VectorStoresRepository) GetVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) (*openai.VectorStoreFile, error) {
+	// Get ready-to-use LlamaStack client from context using helper
+	client, err := helper.GetContextLlamaStackClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetVectorStoreFile(ctx, vectorStoreID, fileID)
+}
+
+// ListVectorStoreFileContent retrieves the chunks a vector store file was split into, in document order.
+// The LlamaStack client is expected to be in the context (created by AttachLlamaStackClient middleware).
+func (r *VectorStoresRepository) ListVectorStoreFileContent(ctx context.Context, vectorStoreID, fileID string) ([]openai.VectorStoreFileContentResponse, error) {
+	// Get ready-to-use LlamaStack client from context using helper
+	client, err := helper.GetContextLlamaStackClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ListVectorStoreFileContent(ctx, vectorStoreID, fileID)
+}
+
 // DeleteVectorStoreFile removes a file from a vector store.
 // The LlamaStack client is expected to be in the context (created by AttachLlamaStackClient middleware).
 func (r *VectorStoresRepository) DeleteVectorStoreFile(ctx context.Context, vectorStoreID, fileID string) error {
@@ -87,3 +122,39 @@ func (r *VectorStoresRepository) DeleteVectorStoreFile(ctx context.Context, vect
 	// For now, direct passthrough from client to handler
 	return client.DeleteVectorStoreFile(ctx, vectorStoreID, fileID)
 }
+
+// GetVectorStore retrieves a vector store by ID.
+// The LlamaStack client is expected to be in the context (created by AttachLlamaStackClient middleware).
+func (r *VectorStoresRepository) GetVectorStore(ctx context.Context, vectorStoreID string) (*openai.VectorStore, error) {
+	// Get ready-to-use LlamaStack client from context using helper
+	client, err := helper.GetContextLlamaStackClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetVectorStore(ctx, vectorStoreID)
+}
+
+// UpdateVectorStore changes the name and metadata of a vector store.
+// The LlamaStack client is expected to be in the context (created by AttachLlamaStackClient middleware).
+func (r *VectorStoresRepository) UpdateVectorStore(ctx context.Context, vectorStoreID string, params llamastack.UpdateVectorStoreParams) (*openai.VectorStore, error) {
+	// Get ready-to-use LlamaStack client from context using helper
+	client, err := helper.GetContextLlamaStackClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.UpdateVectorStore(ctx, vectorStoreID, params)
+}
+
+// SearchVectorStore retrieves the chunks of a vector store most relevant to a query.
+// The LlamaStack client is expected to be in the context (created by AttachLlamaStackClient middleware).
+func (r *VectorStoresRepository) SearchVectorStore(ctx context.Context, vectorStoreID string, params llamastack.SearchVectorStoreParams) ([]openai.VectorStoreSearchResponse, error) {
+	// Get ready-to-use LlamaStack client from context using helper
+	client, err := helper.GetContextLlamaStackClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SearchVectorStore(ctx, vectorStoreID, params)
+}
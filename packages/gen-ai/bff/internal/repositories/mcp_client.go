@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/opendatahub-io/gen-ai/internal/constants"
 	"github.com/opendatahub-io/gen-ai/internal/integrations"
 	kubernetes "github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes"
 	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
@@ -78,6 +79,10 @@ func (r *MCPClientRepository) GetMCPServersFromConfig(
 		}
 
 		config.Name = serverName
+		// Stdio servers have no URL of their own; the placeholder identifies them wherever servers are selected by URL
+		if config.Transport == constants.TransportTypeStdio && config.URL == "" {
+			config.URL = constants.MCPStdioURLScheme + serverName
+		}
 
 		servers = append(servers, MCPServerInfo{
 			Name:   serverName,
@@ -113,6 +118,10 @@ func (r *MCPClientRepository) GetMCPServersFromConfigWithMetadata(
 		}
 
 		config.Name = serverName
+		// Stdio servers have no URL of their own; the placeholder identifies them wherever servers are selected by URL
+		if config.Transport == constants.TransportTypeStdio && config.URL == "" {
+			config.URL = constants.MCPStdioURLScheme + serverName
+		}
 
 		servers = append(servers, MCPServerInfo{
 			Name:   serverName,
@@ -163,3 +172,78 @@ func (r *MCPClientRepository) ListMCPServerToolsWithStatus(
 
 	return mcpClient.ListToolsWithStatus(ctx, identity, serverConfig)
 }
+
+// CallMCPServerTool invokes a tool on an MCP server and returns its output with timing information
+func (r *MCPClientRepository) CallMCPServerTool(
+	ctx context.Context,
+	identity *integrations.RequestIdentity,
+	serverConfig models.MCPServerConfig,
+	toolName string,
+	arguments map[string]interface{},
+) (*models.ToolCallResult, error) {
+	mcpClient, err := r.mcpClientFactory.GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP client: %w", err)
+	}
+
+	return mcpClient.CallTool(ctx, identity, serverConfig, toolName, arguments)
+}
+
+// ListMCPServerResources lists the resources and resource templates of an MCP server with status information
+func (r *MCPClientRepository) ListMCPServerResources(
+	ctx context.Context,
+	identity *integrations.RequestIdentity,
+	serverConfig models.MCPServerConfig,
+) (*models.ResourcesStatus, error) {
+	mcpClient, err := r.mcpClientFactory.GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP client: %w", err)
+	}
+
+	return mcpClient.ListResources(ctx, identity, serverConfig)
+}
+
+// ReadMCPServerResource reads the contents of a resource from an MCP server
+func (r *MCPClientRepository) ReadMCPServerResource(
+	ctx context.Context,
+	identity *integrations.RequestIdentity,
+	serverConfig models.MCPServerConfig,
+	uri string,
+) (*models.ResourceReadResult, error) {
+	mcpClient, err := r.mcpClientFactory.GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP client: %w", err)
+	}
+
+	return mcpClient.ReadResource(ctx, identity, serverConfig, uri)
+}
+
+// ListMCPServerPrompts lists the prompts of an MCP server with status information
+func (r *MCPClientRepository) ListMCPServerPrompts(
+	ctx context.Context,
+	identity *integrations.RequestIdentity,
+	serverConfig models.MCPServerConfig,
+) (*models.PromptsStatus, error) {
+	mcpClient, err := r.mcpClientFactory.GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP client: %w", err)
+	}
+
+	return mcpClient.ListPrompts(ctx, identity, serverConfig)
+}
+
+// GetMCPServerPrompt renders a prompt of an MCP server with the given arguments
+func (r *MCPClientRepository) GetMCPServerPrompt(
+	ctx context.Context,
+	identity *integrations.RequestIdentity,
+	serverConfig models.MCPServerConfig,
+	promptName string,
+	arguments map[string]string,
+) (*models.PromptResult, error) {
+	mcpClient, err := r.mcpClientFactory.GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MCP client: %w", err)
+	}
+
+	return mcpClient.GetPrompt(ctx, identity, serverConfig, promptName, arguments)
+}
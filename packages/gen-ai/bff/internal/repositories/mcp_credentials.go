@@ -0,0 +1,287 @@
+package repositories
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/integrations"
+	kubernetes "github.com/opendatahub-io/gen-ai/internal/integrations/kubernetes"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrMCPCredentialNotFound is returned when the user has no stored credential for an MCP server
+var ErrMCPCredentialNotFound = errors.New("MCP credential not found")
+
+// MCPCredentialsRepository stores per-user MCP server credentials as Kubernetes Secrets in the user's namespace.
+// Each user and MCP server pair maps to one Secret whose name is derived from both, so lookups need no listing.
+type MCPCredentialsRepository struct{}
+
+// NewMCPCredentialsRepository creates a new MCP credentials repository.
+func NewMCPCredentialsRepository() *MCPCredentialsRepository {
+	return &MCPCredentialsRepository{}
+}
+
+// MCPStoredCredential is an MCP server credential as kept in the user's Secret
+type MCPStoredCredential struct {
+	AuthType string // constants.MCPCredentialAuthTypeToken or constants.MCPCredentialAuthTypeOAuth
+	Token    string
+
+	// OAuth tokens keep what is needed to refresh them
+	RefreshToken  string
+	ExpiresAt     int64 // Unix timestamp, 0 when the token does not expire
+	TokenEndpoint string
+	ClientID      string
+	ClientSecret  string
+	Resource      string
+}
+
+// ExpiresWithin reports whether the access token expires within the given duration
+func (c *MCPStoredCredential) ExpiresWithin(now time.Time, leeway time.Duration) bool {
+	return c.ExpiresAt != 0 && now.Add(leeway).Unix() >= c.ExpiresAt
+}
+
+// SetCredential stores the token for an MCP server, replacing any existing credential.
+func (r *MCPCredentialsRepository) SetCredential(
+	ctx context.Context,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace, owner, serverName, token string,
+) (*models.MCPCredential, error) {
+	return r.storeCredential(ctx, k8sClient, identity, namespace, owner, serverName, &MCPStoredCredential{
+		AuthType: constants.MCPCredentialAuthTypeToken,
+		Token:    token,
+	})
+}
+
+// SetOAuthCredential stores tokens obtained with the OAuth flow for an MCP server, replacing any existing credential.
+func (r *MCPCredentialsRepository) SetOAuthCredential(
+	ctx context.Context,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace, owner, serverName string,
+	credential MCPStoredCredential,
+) (*models.MCPCredential, error) {
+	credential.AuthType = constants.MCPCredentialAuthTypeOAuth
+	return r.storeCredential(ctx, k8sClient, identity, namespace, owner, serverName, &credential)
+}
+
+// GetCredential returns the stored credential for an MCP server, or ErrMCPCredentialNotFound.
+func (r *MCPCredentialsRepository) GetCredential(
+	ctx context.Context,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace, owner, serverName string,
+) (*MCPStoredCredential, error) {
+	secret, err := r.getSecret(ctx, k8sClient, identity, namespace, owner, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	token := string(secret.Data[constants.MCPCredentialTokenKey])
+	if token == "" {
+		return nil, ErrMCPCredentialNotFound
+	}
+
+	metadata := credentialFromSecret(secret)
+	return &MCPStoredCredential{
+		AuthType:      metadata.AuthType,
+		Token:         token,
+		RefreshToken:  string(secret.Data[constants.MCPCredentialRefreshTokenKey]),
+		ExpiresAt:     metadata.ExpiresAt,
+		TokenEndpoint: string(secret.Data[constants.MCPCredentialTokenEndpointKey]),
+		ClientID:      string(secret.Data[constants.MCPCredentialClientIDKey]),
+		ClientSecret:  string(secret.Data[constants.MCPCredentialClientSecretKey]),
+		Resource:      string(secret.Data[constants.MCPCredentialResourceKey]),
+	}, nil
+}
+
+// GetToken returns the stored token for an MCP server, or ErrMCPCredentialNotFound.
+func (r *MCPCredentialsRepository) GetToken(
+	ctx context.Context,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace, owner, serverName string,
+) (string, error) {
+	credential, err := r.GetCredential(ctx, k8sClient, identity, namespace, owner, serverName)
+	if err != nil {
+		return "", err
+	}
+	return credential.Token, nil
+}
+
+// ListCredentials returns the MCP servers the user has stored credentials for, ordered by server name.
+func (r *MCPCredentialsRepository) ListCredentials(
+	ctx context.Context,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace, owner string,
+) ([]models.MCPCredential, error) {
+	secrets, err := k8sClient.ListSecrets(ctx, identity, namespace, map[string]string{
+		constants.MCPCredentialLabelKey:      "true",
+		constants.MCPCredentialOwnerLabelKey: hashValue(owner),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCP credentials: %w", err)
+	}
+
+	credentials := make([]models.MCPCredential, 0, len(secrets))
+	for i := range secrets {
+		// The owner label is a hash, so confirm the full owner to rule out collisions
+		if secrets[i].Annotations[constants.MCPCredentialOwnerAnnotation] != owner {
+			continue
+		}
+		credentials = append(credentials, credentialFromSecret(&secrets[i]))
+	}
+
+	sort.Slice(credentials, func(i, j int) bool {
+		return credentials[i].ServerName < credentials[j].ServerName
+	})
+	return credentials, nil
+}
+
+// DeleteCredential revokes the stored credential for an MCP server.
+func (r *MCPCredentialsRepository) DeleteCredential(
+	ctx context.Context,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace, owner, serverName string,
+) error {
+	if _, err := r.getSecret(ctx, k8sClient, identity, namespace, owner, serverName); err != nil {
+		return err
+	}
+
+	err := k8sClient.DeleteSecret(ctx, identity, namespace, mcpCredentialSecretName(owner, serverName))
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ErrMCPCredentialNotFound
+		}
+		return fmt.Errorf("failed to delete MCP credential: %w", err)
+	}
+	return nil
+}
+
+// storeCredential writes the credential Secret, replacing any existing one
+func (r *MCPCredentialsRepository) storeCredential(
+	ctx context.Context,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace, owner, serverName string,
+	credential *MCPStoredCredential,
+) (*models.MCPCredential, error) {
+	now := time.Now().Unix()
+	annotations := map[string]string{
+		constants.MCPCredentialOwnerAnnotation:   owner,
+		constants.MCPCredentialServerAnnotation:  serverName,
+		constants.MCPCredentialUpdatedAnnotation: strconv.FormatInt(now, 10),
+		constants.MCPCredentialAuthAnnotation:    credential.AuthType,
+	}
+	if credential.ExpiresAt != 0 {
+		annotations[constants.MCPCredentialExpiresAnnotation] = strconv.FormatInt(credential.ExpiresAt, 10)
+	}
+
+	data := map[string][]byte{
+		constants.MCPCredentialTokenKey: []byte(credential.Token),
+	}
+	for key, value := range map[string]string{
+		constants.MCPCredentialRefreshTokenKey:  credential.RefreshToken,
+		constants.MCPCredentialTokenEndpointKey: credential.TokenEndpoint,
+		constants.MCPCredentialClientIDKey:      credential.ClientID,
+		constants.MCPCredentialClientSecretKey:  credential.ClientSecret,
+		constants.MCPCredentialResourceKey:      credential.Resource,
+	} {
+		if value != "" {
+			data[key] = []byte(value)
+		}
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpCredentialSecretName(owner, serverName),
+			Namespace: namespace,
+			Labels: map[string]string{
+				constants.MCPCredentialLabelKey:      "true",
+				constants.MCPCredentialOwnerLabelKey: hashValue(owner),
+			},
+			Annotations: annotations,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+
+	if _, err := k8sClient.ApplySecret(ctx, identity, secret); err != nil {
+		return nil, fmt.Errorf("failed to store MCP credential: %w", err)
+	}
+
+	return &models.MCPCredential{
+		ServerName: serverName,
+		AuthType:   credential.AuthType,
+		UpdatedAt:  now,
+		ExpiresAt:  credential.ExpiresAt,
+	}, nil
+}
+
+// getSecret fetches the credential Secret and checks that it belongs to the owner and server
+func (r *MCPCredentialsRepository) getSecret(
+	ctx context.Context,
+	k8sClient kubernetes.KubernetesClientInterface,
+	identity *integrations.RequestIdentity,
+	namespace, owner, serverName string,
+) (*corev1.Secret, error) {
+	secret, err := k8sClient.GetSecret(ctx, identity, namespace, mcpCredentialSecretName(owner, serverName))
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ErrMCPCredentialNotFound
+		}
+		return nil, fmt.Errorf("failed to get MCP credential: %w", err)
+	}
+
+	if secret.Annotations[constants.MCPCredentialOwnerAnnotation] != owner ||
+		secret.Annotations[constants.MCPCredentialServerAnnotation] != serverName {
+		return nil, ErrMCPCredentialNotFound
+	}
+	return secret, nil
+}
+
+// credentialFromSecret converts a credential Secret to its public metadata
+func credentialFromSecret(secret *corev1.Secret) models.MCPCredential {
+	updatedAt, err := strconv.ParseInt(secret.Annotations[constants.MCPCredentialUpdatedAnnotation], 10, 64)
+	if err != nil {
+		updatedAt = secret.CreationTimestamp.Unix()
+	}
+	// Missing or malformed expiry means the token does not expire
+	expiresAt, _ := strconv.ParseInt(secret.Annotations[constants.MCPCredentialExpiresAnnotation], 10, 64)
+
+	authType := secret.Annotations[constants.MCPCredentialAuthAnnotation]
+	if authType == "" {
+		authType = constants.MCPCredentialAuthTypeToken
+	}
+
+	return models.MCPCredential{
+		ServerName: secret.Annotations[constants.MCPCredentialServerAnnotation],
+		AuthType:   authType,
+		UpdatedAt:  updatedAt,
+		ExpiresAt:  expiresAt,
+	}
+}
+
+// mcpCredentialSecretName derives a valid Secret name from the owner and server name.
+// Usernames and ConfigMap keys may contain characters that are not allowed in object names.
+func mcpCredentialSecretName(owner, serverName string) string {
+	return constants.MCPCredentialSecretPrefix + hashValue(owner+"\x00"+serverName)
+}
+
+// hashValue returns a short hex digest usable in object names and label values
+func hashValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])[:32]
+}
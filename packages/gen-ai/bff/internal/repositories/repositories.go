@@ -3,7 +3,10 @@ package repositories
 import (
 	"log/slog"
 
+	"github.com/opendatahub-io/gen-ai/internal/cache"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/llamastack"
 	"github.com/opendatahub-io/gen-ai/internal/integrations/mcp"
+	"github.com/opendatahub-io/gen-ai/internal/integrations/sources"
 )
 
 // Repositories struct is a single convenient container to hold and represent all our repositories.
@@ -19,6 +22,11 @@ type Repositories struct {
 	Namespace              *NamespaceRepository
 	LlamaStackDistribution *LlamaStackDistributionRepository
 	MCPClient              *MCPClientRepository
+	Conversations          *ConversationsRepository
+	MCPCredentials         *MCPCredentialsRepository
+	IngestionJobs          *IngestionJobsRepository
+	DocumentSources        *DocumentSourcesRepository
+	Usage                  *UsageRepository
 }
 
 // NewRepositories creates domain-specific repositories.
@@ -35,6 +43,11 @@ func NewRepositories() *Repositories {
 		Namespace:              NewNamespaceRepository(),
 		LlamaStackDistribution: NewLlamaStackDistributionRepository(),
 		MCPClient:              nil, // Will be initialized separately with MCP client factory
+		Conversations:          NewConversationsRepository(NewInMemoryConversationStore()),
+		MCPCredentials:         NewMCPCredentialsRepository(),
+		IngestionJobs:          NewIngestionJobsRepository(llamastack.NewIngestionManager(llamastack.DefaultIngestionManagerConfig(0), nil)),
+		DocumentSources:        NewDocumentSourcesRepository(sources.NewHTTPClient(false, nil, sources.AddressPolicy{}), sources.NewHTTPClient(false, nil, sources.AddressPolicy{})),
+		Usage:                  NewUsageRepository(NewMemoryUsageStore(cache.NewMemoryStore())),
 	}
 }
 
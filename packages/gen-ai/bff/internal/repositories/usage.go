@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opendatahub-io/gen-ai/internal/cache"
+	"github.com/opendatahub-io/gen-ai/internal/constants"
+	"github.com/opendatahub-io/gen-ai/internal/models"
+)
+
+// usageDayFormat is the format of the days usage is aggregated by
+const usageDayFormat = time.DateOnly
+
+// UsageStore is the persistence interface for the usage of responses, aggregated per day, user and model.
+// Implementations must be safe for concurrent use.
+type UsageStore interface {
+	// AddUsage adds a response to the usage of its user and model on the day it was created
+	AddUsage(ctx context.Context, record models.UsageRecord) error
+
+	// ListUsage returns the usage per model and day of a user since the given day, or of every user of the
+	// namespace when username is empty. The Model of the returned totals is set.
+	ListUsage(ctx context.Context, namespace, username string, since time.Time) ([]models.UsageTotals, error)
+}
+
+// UsageRepository records the token usage and latency of responses and reports them.
+type UsageRepository struct {
+	store UsageStore
+}
+
+// NewUsageRepository creates a new usage repository backed by the given store.
+func NewUsageRepository(store UsageStore) *UsageRepository {
+	return &UsageRepository{store: store}
+}
+
+// RecordUsage adds the usage of a response.
+func (r *UsageRepository) RecordUsage(ctx context.Context, record models.UsageRecord) error {
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	return r.store.AddUsage(ctx, record)
+}
+
+// GetUsageReport reports the usage of a user and of every user of the namespace over the last days.
+func (r *UsageRepository) GetUsageReport(ctx context.Context, namespace, username string, opts models.UsageReportOptions) (*models.UsageReport, error) {
+	days := opts.Days
+	if days < 1 || days > constants.UsageRetentionDays {
+		days = constants.UsageRetentionDays
+	}
+	since := usageDay(time.Now()).AddDate(0, 0, 1-days)
+
+	userUsage, err := r.store.ListUsage(ctx, namespace, username, since)
+	if err != nil {
+		return nil, err
+	}
+	allUsage, err := r.store.ListUsage(ctx, namespace, "", since)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UsageReport{
+		Namespace: namespace,
+		Username:  username,
+		Since:     since.Format(usageDayFormat),
+		User:      summarizeUsage(userUsage, opts.Model),
+		AllUsers:  summarizeUsage(allUsage, opts.Model),
+	}, nil
+}
+
+// summarizeUsage merges usage per model and in total, skipping other models than model when it is set
+func summarizeUsage(usage []models.UsageTotals, model string) models.UsageSummary {
+	perModel := map[string]*models.UsageTotals{}
+	summary := models.UsageSummary{Models: []models.UsageTotals{}}
+	for _, totals := range usage {
+		if model != "" && totals.Model != model {
+			continue
+		}
+		if perModel[totals.Model] == nil {
+			perModel[totals.Model] = &models.UsageTotals{Model: totals.Model}
+		}
+		perModel[totals.Model].Merge(totals)
+		summary.Total.Merge(totals)
+	}
+
+	for _, totals := range perModel {
+		summary.Models = append(summary.Models, *totals)
+	}
+	sort.Slice(summary.Models, func(i, j int) bool {
+		return summary.Models[i].Model < summary.Models[j].Model
+	})
+	return summary
+}
+
+// usageDay returns the UTC day of a time
+func usageDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// memoryUsageStore is the default UsageStore implementation. It keeps the usage of each user and of the whole
+// namespace in the memory store, one entry per day and model that expires after constants.UsageRetentionDays,
+// so usage is lost when the BFF restarts.
+type memoryUsageStore struct {
+	store cache.MemoryStore
+	mu    sync.Mutex // Serializes the read-modify-write of entries
+}
+
+// usageEntry is the usage of a model on a day. Entries are replaced rather than modified, so that they can be
+// read while usage is added.
+type usageEntry struct {
+	day    time.Time
+	totals models.UsageTotals
+}
+
+// NewMemoryUsageStore creates a UsageStore kept in the given memory store.
+func NewMemoryUsageStore(store cache.MemoryStore) UsageStore {
+	return &memoryUsageStore{store: store}
+}
+
+func (s *memoryUsageStore) AddUsage(_ context.Context, record models.UsageRecord) error {
+	if record.Namespace == "" || record.Username == "" || record.Model == "" {
+		return fmt.Errorf("usage namespace, username and model are required")
+	}
+
+	// Usage older than the retention is not kept
+	day := usageDay(record.CreatedAt)
+	ttl := time.Until(day.AddDate(0, 0, constants.UsageRetentionDays))
+	if ttl <= 0 {
+		return nil
+	}
+	key := day.Format(usageDayFormat) + "/" + record.Model
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, username := range []string{record.Username, constants.CacheUsageAllUsers} {
+		entry := usageEntry{day: day, totals: models.UsageTotals{Model: record.Model}}
+		if cached, found := s.store.Get(record.Namespace, username, constants.CacheResponseUsageCategory, key); found {
+			if existing, ok := cached.(usageEntry); ok {
+				entry = existing
+			}
+		}
+		entry.totals.Add(record)
+
+		if err := s.store.Set(record.Namespace, username, constants.CacheResponseUsageCategory, key, entry, ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryUsageStore) ListUsage(_ context.Context, namespace, username string, since time.Time) ([]models.UsageTotals, error) {
+	if username == "" {
+		username = constants.CacheUsageAllUsers
+	}
+
+	entries, _ := s.store.GetCategory(namespace, username, constants.CacheResponseUsageCategory)
+	usage := []models.UsageTotals{}
+	for _, cached := range entries {
+		if entry, ok := cached.(usageEntry); ok && !entry.day.Before(since) {
+			usage = append(usage, entry.totals)
+		}
+	}
+	return usage, nil
+}
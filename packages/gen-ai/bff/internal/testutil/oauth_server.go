@@ -0,0 +1,245 @@
+package testutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Paths served by OAuthStubServer
+const (
+	OAuthStubMCPPath              = "/mcp"
+	OAuthStubResourceMetadataPath = "/.well-known/oauth-protected-resource/mcp"
+	OAuthStubIssuerPath           = "/oauth"
+	OAuthStubScope                = "mcp:tools"
+)
+
+// OAuthStubServer is a local MCP server protected by a stub OAuth 2.1 authorization server.
+// The authorize endpoint approves every request immediately and redirects back with a code,
+// so tests can run the full authorization code flow with PKCE without a browser.
+type OAuthStubServer struct {
+	*httptest.Server
+
+	// AccessTokenLifetime is the expires_in of issued access tokens, in seconds
+	AccessTokenLifetime int64
+
+	mu            sync.Mutex
+	counter       int
+	clients       map[string]string // client ID -> registered redirect URI
+	codes         map[string]oauthStubGrant
+	accessTokens  map[string]bool
+	refreshTokens map[string]string // refresh token -> client ID
+}
+
+type oauthStubGrant struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	resource      string
+}
+
+// NewOAuthStubServer starts an OAuth-protected MCP server with one "echo" tool
+func NewOAuthStubServer() *OAuthStubServer {
+	stub := &OAuthStubServer{
+		AccessTokenLifetime: 3600,
+		clients:             make(map[string]string),
+		codes:               make(map[string]oauthStubGrant),
+		accessTokens:        make(map[string]bool),
+		refreshTokens:       make(map[string]string),
+	}
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "oauth-stub-mcp-server", Version: "1.0.0"}, nil)
+	mcp.AddTool(mcpServer, &mcp.Tool{Name: "echo", Description: "Echo the message"},
+		func(ctx context.Context, req *mcp.CallToolRequest, in struct {
+			Message string `json:"message"`
+		}) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: in.Message}}}, nil, nil
+		})
+	mcpHandler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return mcpServer }, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(OAuthStubMCPPath, func(w http.ResponseWriter, r *http.Request) {
+		if !stub.validAccessToken(r.Header.Get("Authorization")) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer resource_metadata="%s", scope="%s"`, stub.URL+OAuthStubResourceMetadataPath, OAuthStubScope))
+			writeStubJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_token"})
+			return
+		}
+		mcpHandler.ServeHTTP(w, r)
+	})
+	mux.HandleFunc(OAuthStubResourceMetadataPath, func(w http.ResponseWriter, r *http.Request) {
+		writeStubJSON(w, http.StatusOK, map[string]interface{}{
+			"resource":              stub.MCPURL(),
+			"authorization_servers": []string{stub.URL + OAuthStubIssuerPath},
+			"scopes_supported":      []string{OAuthStubScope},
+		})
+	})
+	mux.HandleFunc("/.well-known/oauth-authorization-server"+OAuthStubIssuerPath, func(w http.ResponseWriter, r *http.Request) {
+		issuer := stub.URL + OAuthStubIssuerPath
+		writeStubJSON(w, http.StatusOK, map[string]interface{}{
+			"issuer":                           issuer,
+			"authorization_endpoint":           issuer + "/authorize",
+			"token_endpoint":                   issuer + "/token",
+			"registration_endpoint":            issuer + "/register",
+			"code_challenge_methods_supported": []string{"S256"},
+		})
+	})
+	mux.HandleFunc(OAuthStubIssuerPath+"/register", stub.handleRegister)
+	mux.HandleFunc(OAuthStubIssuerPath+"/authorize", stub.handleAuthorize)
+	mux.HandleFunc(OAuthStubIssuerPath+"/token", stub.handleToken)
+
+	stub.Server = httptest.NewServer(mux)
+	return stub
+}
+
+// MCPURL returns the URL of the protected MCP endpoint
+func (s *OAuthStubServer) MCPURL() string {
+	return s.URL + OAuthStubMCPPath
+}
+
+// Authorize opens a login URL like a browser whose user approves the request, and returns the callback URL
+func (s *OAuthStubServer) Authorize(loginURL string) (*url.URL, error) {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Get(loginURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return nil, fmt.Errorf("authorization failed with HTTP %d", resp.StatusCode)
+	}
+	return url.Parse(resp.Header.Get("Location"))
+}
+
+func (s *OAuthStubServer) validAccessToken(header string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accessTokens[strings.TrimPrefix(header, "Bearer ")]
+}
+
+func (s *OAuthStubServer) nextID(prefix string) string {
+	s.counter++
+	return fmt.Sprintf("%s-%d", prefix, s.counter)
+}
+
+func (s *OAuthStubServer) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var registration struct {
+		RedirectURIs []string `json:"redirect_uris"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&registration); err != nil || len(registration.RedirectURIs) != 1 {
+		writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client_metadata"})
+		return
+	}
+
+	s.mu.Lock()
+	clientID := s.nextID("client")
+	s.clients[clientID] = registration.RedirectURIs[0]
+	s.mu.Unlock()
+
+	writeStubJSON(w, http.StatusCreated, map[string]interface{}{
+		"client_id":     clientID,
+		"redirect_uris": registration.RedirectURIs,
+	})
+}
+
+func (s *OAuthStubServer) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	clientID, redirectURI := query.Get("client_id"), query.Get("redirect_uri")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if registered, ok := s.clients[clientID]; !ok || registered != redirectURI {
+		writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" ||
+		query.Get("code_challenge") == "" || query.Get("resource") != s.MCPURL() {
+		writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	code := s.nextID("code")
+	s.codes[code] = oauthStubGrant{
+		clientID:      clientID,
+		redirectURI:   redirectURI,
+		codeChallenge: query.Get("code_challenge"),
+		resource:      query.Get("resource"),
+	}
+
+	callback, err := url.Parse(redirectURI)
+	if err != nil {
+		writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	callbackQuery := callback.Query()
+	callbackQuery.Set("code", code)
+	callbackQuery.Set("state", query.Get("state"))
+	callback.RawQuery = callbackQuery.Encode()
+
+	http.Redirect(w, r, callback.String(), http.StatusFound)
+}
+
+func (s *OAuthStubServer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clientID := r.PostForm.Get("client_id")
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		grant, ok := s.codes[r.PostForm.Get("code")]
+		delete(s.codes, r.PostForm.Get("code"))
+
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+		if !ok || grant.clientID != clientID || grant.redirectURI != r.PostForm.Get("redirect_uri") ||
+			grant.codeChallenge != challenge || grant.resource != r.PostForm.Get("resource") {
+			writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "authorization code is invalid"})
+			return
+		}
+	case "refresh_token":
+		refreshToken := r.PostForm.Get("refresh_token")
+		if owner, ok := s.refreshTokens[refreshToken]; !ok || owner != clientID {
+			writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "refresh token is invalid"})
+			return
+		}
+		delete(s.refreshTokens, refreshToken)
+	default:
+		writeStubJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	accessToken, refreshToken := s.nextID("access"), s.nextID("refresh")
+	s.accessTokens[accessToken] = true
+	s.refreshTokens[refreshToken] = clientID
+
+	writeStubJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  accessToken,
+		"token_type":    "Bearer",
+		"refresh_token": refreshToken,
+		"expires_in":    s.AccessTokenLifetime,
+		"scope":         OAuthStubScope,
+	})
+}
+
+func writeStubJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}